@@ -188,3 +188,28 @@ func TestLookup(t *testing.T) {
 	value, has = set.Value("D")
 	require.False(t, has)
 }
+
+func TestSliceEquivalence(t *testing.T) {
+	for _, kvs := range [][2]attribute.KeyValue{
+		{attribute.StringSlice("A", []string{"a", "b"}), attribute.StringSlice("A", []string{"a", "b"})},
+		{attribute.BoolSlice("A", []bool{true}), attribute.BoolSlice("A", []bool{true})},
+		{attribute.IntSlice("A", []int{1, 2}), attribute.Int64Slice("A", []int64{1, 2})},
+		{attribute.Float64Slice("A", nil), attribute.Float64Slice("A", []float64{})},
+	} {
+		s1, s2 := attribute.NewSet(kvs[0]), attribute.NewSet(kvs[1])
+		require.Equal(t, s1.Equivalent(), s2.Equivalent(), kvs[0].Value.Emit())
+		require.True(t, s1.Equals(&s2))
+	}
+
+	s1 := attribute.NewSet(attribute.StringSlice("A", []string{"a", "b"}))
+	s2 := attribute.NewSet(attribute.StringSlice("A", []string{"b", "a"}))
+	require.NotEqual(t, s1.Equivalent(), s2.Equivalent())
+
+	// Modifying the slice does not modify the attribute.
+	values := []string{"a"}
+	kv := attribute.StringSlice("A", values)
+	values[0] = "b"
+	require.Equal(t, []string{"a"}, kv.Value.AsStringSlice())
+	kv.Value.AsStringSlice()[0] = "c"
+	require.Equal(t, []string{"a"}, kv.Value.AsStringSlice())
+}
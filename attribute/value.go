@@ -17,6 +17,7 @@ package attribute // import "go.opentelemetry.io/otel/attribute"
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
 
 	"go.opentelemetry.io/otel/internal"
@@ -66,11 +67,9 @@ func BoolValue(v bool) Value {
 
 // BoolSliceValue creates a BOOLSLICE Value.
 func BoolSliceValue(v []bool) Value {
-	cp := make([]bool, len(v))
-	copy(cp, v)
 	return Value{
 		vtype: BOOLSLICE,
-		slice: &cp,
+		slice: sliceValue(v),
 	}
 }
 
@@ -87,7 +86,7 @@ func IntSliceValue(v []int) Value {
 	}
 	return Value{
 		vtype: INT64SLICE,
-		slice: &cp,
+		slice: sliceValue(cp),
 	}
 }
 
@@ -101,11 +100,9 @@ func Int64Value(v int64) Value {
 
 // Int64SliceValue creates an INT64SLICE Value.
 func Int64SliceValue(v []int64) Value {
-	cp := make([]int64, len(v))
-	copy(cp, v)
 	return Value{
 		vtype: INT64SLICE,
-		slice: &cp,
+		slice: sliceValue(v),
 	}
 }
 
@@ -119,11 +116,9 @@ func Float64Value(v float64) Value {
 
 // Float64SliceValue creates a FLOAT64SLICE Value.
 func Float64SliceValue(v []float64) Value {
-	cp := make([]float64, len(v))
-	copy(cp, v)
 	return Value{
 		vtype: FLOAT64SLICE,
-		slice: &cp,
+		slice: sliceValue(v),
 	}
 }
 
@@ -137,11 +132,9 @@ func StringValue(v string) Value {
 
 // StringSliceValue creates a STRINGSLICE Value.
 func StringSliceValue(v []string) Value {
-	cp := make([]string, len(v))
-	copy(cp, v)
 	return Value{
 		vtype: STRINGSLICE,
-		slice: &cp,
+		slice: sliceValue(v),
 	}
 }
 
@@ -159,10 +152,8 @@ func (v Value) AsBool() bool {
 // AsBoolSlice returns the []bool value. Make sure that the Value's type is
 // BOOLSLICE.
 func (v Value) AsBoolSlice() []bool {
-	if s, ok := v.slice.(*[]bool); ok {
-		return *s
-	}
-	return nil
+	s, _ := asSlice(v.slice, reflect.TypeOf([]bool(nil))).([]bool)
+	return s
 }
 
 // AsInt64 returns the int64 value. Make sure that the Value's type is
@@ -174,10 +165,8 @@ func (v Value) AsInt64() int64 {
 // AsInt64Slice returns the []int64 value. Make sure that the Value's type is
 // INT64SLICE.
 func (v Value) AsInt64Slice() []int64 {
-	if s, ok := v.slice.(*[]int64); ok {
-		return *s
-	}
-	return nil
+	s, _ := asSlice(v.slice, reflect.TypeOf([]int64(nil))).([]int64)
+	return s
 }
 
 // AsFloat64 returns the float64 value. Make sure that the Value's
@@ -189,10 +178,8 @@ func (v Value) AsFloat64() float64 {
 // AsFloat64Slice returns the []float64 value. Make sure that the Value's type is
 // FLOAT64SLICE.
 func (v Value) AsFloat64Slice() []float64 {
-	if s, ok := v.slice.(*[]float64); ok {
-		return *s
-	}
-	return nil
+	s, _ := asSlice(v.slice, reflect.TypeOf([]float64(nil))).([]float64)
+	return s
 }
 
 // AsString returns the string value. Make sure that the Value's type
@@ -204,10 +191,30 @@ func (v Value) AsString() string {
 // AsStringSlice returns the []string value. Make sure that the Value's type is
 // STRINGSLICE.
 func (v Value) AsStringSlice() []string {
-	if s, ok := v.slice.(*[]string); ok {
-		return *s
+	s, _ := asSlice(v.slice, reflect.TypeOf([]string(nil))).([]string)
+	return s
+}
+
+// sliceValue returns a copy of the elements of the slice v in an array,
+// so that Values of equal slices, and the attribute sets holding them,
+// compare equal.
+func sliceValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	cp := reflect.New(reflect.ArrayOf(rv.Len(), rv.Type().Elem())).Elem()
+	reflect.Copy(cp, rv)
+	return cp.Interface()
+}
+
+// asSlice returns a copy of the elements of the array v in a slice of
+// type sliceType, or nil if v is not an array of its elements.
+func asSlice(v interface{}, sliceType reflect.Type) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Array || rv.Type().Elem() != sliceType.Elem() {
+		return nil
 	}
-	return nil
+	cp := reflect.MakeSlice(sliceType, rv.Len(), rv.Len())
+	reflect.Copy(cp, rv)
+	return cp.Interface()
 }
 
 type unknownValueType struct{}
@@ -239,19 +246,19 @@ func (v Value) AsInterface() interface{} {
 func (v Value) Emit() string {
 	switch v.Type() {
 	case BOOLSLICE:
-		return fmt.Sprint(*(v.slice.(*[]bool)))
+		return fmt.Sprint(v.AsBoolSlice())
 	case BOOL:
 		return strconv.FormatBool(v.AsBool())
 	case INT64SLICE:
-		return fmt.Sprint(*(v.slice.(*[]int64)))
+		return fmt.Sprint(v.AsInt64Slice())
 	case INT64:
 		return strconv.FormatInt(v.AsInt64(), 10)
 	case FLOAT64SLICE:
-		return fmt.Sprint(*(v.slice.(*[]float64)))
+		return fmt.Sprint(v.AsFloat64Slice())
 	case FLOAT64:
 		return fmt.Sprint(v.AsFloat64())
 	case STRINGSLICE:
-		return fmt.Sprint(*(v.slice.(*[]string)))
+		return fmt.Sprint(v.AsStringSlice())
 	case STRING:
 		return v.stringly
 	default:
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package metricshim translates the measurements of metrics libraries such as
+hashicorp/go-metrics and uber-go/tally into OpenTelemetry instruments, so
+that applications instrumented with those libraries can export their metrics
+through an OpenTelemetry pipeline by swapping a constructor.
+
+A Shim joins the key paths of those libraries into instrument names, maps
+their labels or tags to attributes, and creates the instruments on first
+use.  Counters are recorded with Counter instruments, samples and timers
+with Histogram instruments, and gauges, set to absolute values, are reported
+by Gauge observers.
+
+The gometricsshim and tallyshim packages, modules of their own so that this
+module does not depend on either library, implement the go-metrics
+MetricSink and the tally StatsReporter on top of a Shim.  A Shim may also
+back the interfaces of other libraries.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track
+the evolving OpenTelemetry specification and user feedback.
+*/
+package metricshim // import "go.opentelemetry.io/otel/bridge/metricshim"
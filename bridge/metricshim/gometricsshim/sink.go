@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gometricsshim // import "go.opentelemetry.io/otel/bridge/metricshim/gometricsshim"
+
+import (
+	"context"
+
+	metrics "github.com/armon/go-metrics"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/bridge/metricshim"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Sink is a go-metrics MetricSink recording with the instruments of a
+// Meter.  It is safe for concurrent use.
+type Sink struct {
+	shim *metricshim.Shim
+}
+
+var _ metrics.MetricSink = &Sink{}
+
+// New returns a Sink that creates its instruments with meter.
+func New(meter metric.Meter, opts ...metricshim.Option) *Sink {
+	return &Sink{shim: metricshim.New(meter, opts...)}
+}
+
+// SetGauge implements metrics.MetricSink.
+func (s *Sink) SetGauge(key []string, val float32) {
+	s.SetGaugeWithLabels(key, val, nil)
+}
+
+// SetGaugeWithLabels implements metrics.MetricSink.
+func (s *Sink) SetGaugeWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.shim.Set(s.shim.Name(key), float64(val), s.attributes(labels)...)
+}
+
+// EmitKey implements metrics.MetricSink.  Every emitted value is recorded
+// as a sample.
+func (s *Sink) EmitKey(key []string, val float32) {
+	s.shim.Record(context.Background(), s.shim.Name(key), float64(val))
+}
+
+// IncrCounter implements metrics.MetricSink.
+func (s *Sink) IncrCounter(key []string, val float32) {
+	s.IncrCounterWithLabels(key, val, nil)
+}
+
+// IncrCounterWithLabels implements metrics.MetricSink.
+func (s *Sink) IncrCounterWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.shim.Add(context.Background(), s.shim.Name(key), float64(val), s.attributes(labels)...)
+}
+
+// AddSample implements metrics.MetricSink.
+func (s *Sink) AddSample(key []string, val float32) {
+	s.AddSampleWithLabels(key, val, nil)
+}
+
+// AddSampleWithLabels implements metrics.MetricSink.
+func (s *Sink) AddSampleWithLabels(key []string, val float32, labels []metrics.Label) {
+	s.shim.Record(context.Background(), s.shim.Name(key), float64(val), s.attributes(labels)...)
+}
+
+func (s *Sink) attributes(labels []metrics.Label) []attribute.KeyValue {
+	if len(labels) == 0 {
+		return nil
+	}
+	attrs := make([]attribute.KeyValue, 0, len(labels))
+	for _, l := range labels {
+		attrs = s.shim.AppendAttribute(attrs, l.Name, l.Value)
+	}
+	return attrs
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gometricsshim_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/bridge/metricshim"
+	"go.opentelemetry.io/otel/bridge/metricshim/gometricsshim"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func collect(t *testing.T, exp *metrictest.Exporter) map[string]*metrictest.ExportRecord {
+	require.NoError(t, exp.Collect(context.Background()))
+	records := map[string]*metrictest.ExportRecord{}
+	for i := range exp.Records {
+		rec := &exp.Records[i]
+		name := rec.InstrumentName
+		if set := attribute.NewSet(rec.Attributes...); set.Len() > 0 {
+			name += "/" + string(set.Encoded(attribute.DefaultEncoder()))
+		}
+		records[name] = rec
+	}
+	return records
+}
+
+func TestSink(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	sink := gometricsshim.New(mp.Meter("test"))
+
+	labels := []metrics.Label{{Name: "method", Value: "GET"}}
+	sink.IncrCounter([]string{"requests"}, 1)
+	sink.IncrCounterWithLabels([]string{"requests"}, 2, labels)
+	sink.IncrCounterWithLabels([]string{"requests"}, 3, labels)
+	sink.AddSample([]string{"latency"}, 0.5)
+	sink.AddSampleWithLabels([]string{"latency"}, 1.5, labels)
+	sink.EmitKey([]string{"latency"}, 2.5)
+	sink.SetGauge([]string{"queue", "depth"}, 7)
+	sink.SetGaugeWithLabels([]string{"queue", "depth"}, 3, labels)
+
+	records := collect(t, exp)
+	require.Len(t, records, 6)
+
+	counter := records["requests"]
+	assert.Equal(t, aggregation.SumKind, counter.AggregationKind)
+	assert.Equal(t, 1.0, counter.Sum.AsFloat64())
+	assert.Equal(t, 5.0, records["requests/method=GET"].Sum.AsFloat64())
+
+	histogram := records["latency"]
+	assert.Equal(t, aggregation.HistogramKind, histogram.AggregationKind)
+	assert.Equal(t, uint64(2), histogram.Count)
+	assert.Equal(t, 3.0, histogram.Sum.AsFloat64())
+	assert.Equal(t, uint64(1), records["latency/method=GET"].Count)
+
+	gauge := records["queue.depth"]
+	assert.Equal(t, aggregation.LastValueKind, gauge.AggregationKind)
+	assert.Equal(t, 7.0, gauge.LastValue.AsFloat64())
+	assert.Equal(t, 3.0, records["queue.depth/method=GET"].LastValue.AsFloat64())
+}
+
+func TestSinkThroughMetrics(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	cfg := metrics.DefaultConfig("app")
+	cfg.EnableHostname = false
+	cfg.EnableRuntimeMetrics = false
+	m, err := metrics.New(cfg, gometricsshim.New(mp.Meter("test"), metricshim.WithSeparator("_")))
+	require.NoError(t, err)
+
+	m.IncrCounterWithLabels([]string{"jobs", "done"}, 4, []metrics.Label{{Name: "queue", Value: "q"}})
+	m.MeasureSince([]string{"jobs", "duration"}, time.Now().Add(-time.Second))
+	m.SetGauge([]string{"jobs", "pending"}, 2)
+
+	records := collect(t, exp)
+	assert.Equal(t, 4.0, records["app_jobs_done/queue=q"].Sum.AsFloat64())
+	assert.Equal(t, uint64(1), records["app_jobs_duration"].Count)
+	assert.Equal(t, 2.0, records["app_jobs_pending"].LastValue.AsFloat64())
+}
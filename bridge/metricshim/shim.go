@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricshim // import "go.opentelemetry.io/otel/bridge/metricshim"
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+)
+
+// AttributeMapper maps a label or tag of a metrics library to an attribute.
+// The label is dropped when the mapper returns false.
+type AttributeMapper func(name, value string) (attribute.KeyValue, bool)
+
+// config contains the configuration of a Shim.
+type config struct {
+	prefix     string
+	separator  string
+	attributes AttributeMapper
+}
+
+// Option configures a Shim.
+type Option interface {
+	apply(config) config
+}
+
+type optionFunc func(config) config
+
+func (fn optionFunc) apply(cfg config) config {
+	return fn(cfg)
+}
+
+// WithPrefix sets a prefix prepended to the name of every instrument.
+func WithPrefix(prefix string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.prefix = prefix
+		return cfg
+	})
+}
+
+// WithSeparator sets the separator that joins the elements of key paths
+// into instrument names, "." by default.
+func WithSeparator(separator string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.separator = separator
+		return cfg
+	})
+}
+
+// WithAttributeMapper sets the mapping of labels and tags to attributes.
+// By default each label becomes a string attribute with the label name as
+// key.
+func WithAttributeMapper(mapper AttributeMapper) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.attributes = mapper
+		return cfg
+	})
+}
+
+func stringAttribute(name, value string) (attribute.KeyValue, bool) {
+	return attribute.String(name, value), true
+}
+
+// Shim records the measurements of a metrics library with the instruments
+// of a Meter.  It is safe for concurrent use.
+type Shim struct {
+	meter metric.Meter
+	cfg   config
+
+	lock       sync.Mutex
+	counters   map[string]syncfloat64.Counter
+	histograms map[string]syncfloat64.Histogram
+	gauges     map[string]*gauge
+}
+
+// gauge holds the values set for a Gauge observer, by attribute set.
+type gauge struct {
+	instrument asyncfloat64.Gauge
+
+	lock   sync.Mutex
+	values map[attribute.Distinct]gaugeValue
+}
+
+type gaugeValue struct {
+	attrs attribute.Set
+	value float64
+}
+
+// New returns a Shim that creates its instruments with meter.
+func New(meter metric.Meter, opts ...Option) *Shim {
+	cfg := config{
+		separator:  ".",
+		attributes: stringAttribute,
+	}
+	for _, opt := range opts {
+		cfg = opt.apply(cfg)
+	}
+	return &Shim{
+		meter:      meter,
+		cfg:        cfg,
+		counters:   map[string]syncfloat64.Counter{},
+		histograms: map[string]syncfloat64.Histogram{},
+		gauges:     map[string]*gauge{},
+	}
+}
+
+// Name joins a key path into an instrument name.
+func (s *Shim) Name(key []string) string {
+	return strings.Join(key, s.cfg.separator)
+}
+
+// AppendAttribute appends the attribute mapped from a label to attrs.
+func (s *Shim) AppendAttribute(attrs []attribute.KeyValue, name, value string) []attribute.KeyValue {
+	if kv, ok := s.cfg.attributes(name, value); ok {
+		attrs = append(attrs, kv)
+	}
+	return attrs
+}
+
+// Attributes returns the attributes mapped from tags, in order of the tag
+// names.
+func (s *Shim) Attributes(tags map[string]string) []attribute.KeyValue {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]attribute.KeyValue, 0, len(tags))
+	for _, name := range names {
+		attrs = s.AppendAttribute(attrs, name, tags[name])
+	}
+	return attrs
+}
+
+// Add adds value to the counter name.
+func (s *Shim) Add(ctx context.Context, name string, value float64, attrs ...attribute.KeyValue) {
+	if c := s.counter(name); c != nil {
+		c.Add(ctx, value, attrs...)
+	}
+}
+
+// Record records a sample or timing value in the histogram name.
+func (s *Shim) Record(ctx context.Context, name string, value float64, attrs ...attribute.KeyValue) {
+	if h := s.histogram(name); h != nil {
+		h.Record(ctx, value, attrs...)
+	}
+}
+
+// Set sets the gauge name to value.  The value is reported by every
+// collection until it is set again.
+func (s *Shim) Set(name string, value float64, attrs ...attribute.KeyValue) {
+	g := s.gauge(name)
+	if g == nil {
+		return
+	}
+	set := attribute.NewSet(attrs...)
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.values[set.Equivalent()] = gaugeValue{attrs: set, value: value}
+}
+
+func (s *Shim) counter(name string) syncfloat64.Counter {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if c, ok := s.counters[name]; ok {
+		return c
+	}
+	c, err := s.meter.SyncFloat64().Counter(s.cfg.prefix + name)
+	if err != nil {
+		otel.Handle(err)
+		c = nil
+	}
+	s.counters[name] = c
+	return c
+}
+
+func (s *Shim) histogram(name string) syncfloat64.Histogram {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if h, ok := s.histograms[name]; ok {
+		return h
+	}
+	h, err := s.meter.SyncFloat64().Histogram(s.cfg.prefix + name)
+	if err != nil {
+		otel.Handle(err)
+		h = nil
+	}
+	s.histograms[name] = h
+	return h
+}
+
+func (s *Shim) gauge(name string) *gauge {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if g, ok := s.gauges[name]; ok {
+		return g
+	}
+	// An instrument that cannot be created or observed is remembered
+	// as nil, so that the error is reported once.
+	s.gauges[name] = nil
+
+	inst, err := s.meter.AsyncFloat64().Gauge(s.cfg.prefix + name)
+	if err != nil {
+		otel.Handle(err)
+		return nil
+	}
+	g := &gauge{
+		instrument: inst,
+		values:     map[attribute.Distinct]gaugeValue{},
+	}
+	if err := s.meter.RegisterCallback([]instrument.Asynchronous{inst}, g.observe); err != nil {
+		otel.Handle(err)
+		return nil
+	}
+	s.gauges[name] = g
+	return g
+}
+
+func (g *gauge) observe(ctx context.Context) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for _, v := range g.values {
+		g.instrument.Observe(ctx, v.value, v.attrs.ToSlice()...)
+	}
+}
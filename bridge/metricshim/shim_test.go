@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricshim_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/bridge/metricshim"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func collect(t *testing.T, exp *metrictest.Exporter) map[string]metrictest.ExportRecord {
+	require.NoError(t, exp.Collect(context.Background()))
+	records := map[string]metrictest.ExportRecord{}
+	for _, rec := range exp.Records {
+		name := rec.InstrumentName
+		if set := attribute.NewSet(rec.Attributes...); set.Len() > 0 {
+			name += "/" + string(set.Encoded(attribute.DefaultEncoder()))
+		}
+		records[name] = rec
+	}
+	return records
+}
+
+func TestShim(t *testing.T) {
+	ctx := context.Background()
+	mp, exp := metrictest.NewTestMeterProvider()
+	shim := metricshim.New(mp.Meter("test"), metricshim.WithPrefix("app."))
+
+	name := shim.Name([]string{"http", "requests"})
+	assert.Equal(t, "http.requests", name)
+
+	attrs := shim.Attributes(map[string]string{"method": "GET", "code": "200"})
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("code", "200"),
+		attribute.String("method", "GET"),
+	}, attrs)
+
+	shim.Add(ctx, name, 1, attrs...)
+	shim.Add(ctx, name, 2, attrs...)
+	shim.Record(ctx, "latency", 0.5)
+	shim.Set("queue", 7)
+	shim.Set("queue", 3)
+
+	records := collect(t, exp)
+	require.Len(t, records, 3)
+
+	counter := records["app.http.requests/code=200,method=GET"]
+	assert.Equal(t, aggregation.SumKind, counter.AggregationKind)
+	assert.Equal(t, 3.0, counter.Sum.AsFloat64())
+
+	histogram := records["app.latency"]
+	assert.Equal(t, aggregation.HistogramKind, histogram.AggregationKind)
+	assert.Equal(t, uint64(1), histogram.Count)
+
+	gauge := records["app.queue"]
+	assert.Equal(t, aggregation.LastValueKind, gauge.AggregationKind)
+	assert.Equal(t, 3.0, gauge.LastValue.AsFloat64())
+
+	// Gauges are reported until they are set again.
+	gauge = collect(t, exp)["app.queue"]
+	assert.Equal(t, 3.0, gauge.LastValue.AsFloat64())
+}
+
+func TestShimOptions(t *testing.T) {
+	ctx := context.Background()
+	mp, exp := metrictest.NewTestMeterProvider()
+	shim := metricshim.New(
+		mp.Meter("test"),
+		metricshim.WithSeparator("_"),
+		metricshim.WithAttributeMapper(func(name, value string) (attribute.KeyValue, bool) {
+			if name == "host" {
+				return attribute.KeyValue{}, false
+			}
+			return attribute.String("lib."+name, value), true
+		}),
+	)
+
+	var attrs []attribute.KeyValue
+	attrs = shim.AppendAttribute(attrs, "host", "a")
+	attrs = shim.AppendAttribute(attrs, "region", "b")
+	assert.Equal(t, []attribute.KeyValue{attribute.String("lib.region", "b")}, attrs)
+
+	shim.Add(ctx, shim.Name([]string{"jobs", "done"}), 1, attrs...)
+	records := collect(t, exp)
+	assert.Contains(t, records, "jobs_done/lib.region=b")
+}
@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package tallyshim provides a StatsReporter of the uber-go/tally library that
+records into OpenTelemetry instruments.  Applications migrate by passing
+the Reporter where they constructed another reporter:
+
+	scope, closer := tally.NewRootScope(tally.ScopeOptions{
+		Reporter: tallyshim.New(provider.Meter("app")),
+	}, time.Second)
+
+Tags become attributes, as described by the
+go.opentelemetry.io/otel/bridge/metricshim package.  Counters are recorded
+by Counter instruments, timers and histograms by Histogram instruments, in
+seconds for durations, and gauges by Gauge observers.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track
+the evolving OpenTelemetry specification and user feedback.
+*/
+package tallyshim // import "go.opentelemetry.io/otel/bridge/metricshim/tallyshim"
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tallyshim // import "go.opentelemetry.io/otel/bridge/metricshim/tallyshim"
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/uber-go/tally/v4"
+
+	"go.opentelemetry.io/otel/bridge/metricshim"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Reporter is a tally StatsReporter recording with the instruments of a
+// Meter.  It is safe for concurrent use.
+type Reporter struct {
+	shim *metricshim.Shim
+}
+
+var (
+	_ tally.StatsReporter = &Reporter{}
+	_ tally.Capabilities  = &Reporter{}
+)
+
+// New returns a Reporter that creates its instruments with meter.
+func New(meter metric.Meter, opts ...metricshim.Option) *Reporter {
+	return &Reporter{shim: metricshim.New(meter, opts...)}
+}
+
+// Capabilities implements tally.BaseStatsReporter.
+func (r *Reporter) Capabilities() tally.Capabilities {
+	return r
+}
+
+// Reporting implements tally.Capabilities.
+func (r *Reporter) Reporting() bool {
+	return true
+}
+
+// Tagging implements tally.Capabilities.
+func (r *Reporter) Tagging() bool {
+	return true
+}
+
+// Flush implements tally.BaseStatsReporter.  The measurements are
+// exported by the OpenTelemetry pipeline of the Meter, there is nothing to
+// flush.
+func (r *Reporter) Flush() {}
+
+// ReportCounter implements tally.StatsReporter.  tally reports the change
+// of a counter since its last report.
+func (r *Reporter) ReportCounter(name string, tags map[string]string, value int64) {
+	r.shim.Add(context.Background(), name, float64(value), r.shim.Attributes(tags)...)
+}
+
+// ReportGauge implements tally.StatsReporter.
+func (r *Reporter) ReportGauge(name string, tags map[string]string, value float64) {
+	r.shim.Set(name, value, r.shim.Attributes(tags)...)
+}
+
+// ReportTimer implements tally.StatsReporter.
+func (r *Reporter) ReportTimer(name string, tags map[string]string, interval time.Duration) {
+	r.shim.Record(context.Background(), name, interval.Seconds(), r.shim.Attributes(tags)...)
+}
+
+// ReportHistogramValueSamples implements tally.StatsReporter.  The samples
+// of a bucket are recorded at its upper bound, or at its lower bound for
+// the last bucket, which has no upper bound.
+func (r *Reporter) ReportHistogramValueSamples(name string, tags map[string]string, _ tally.Buckets, bucketLowerBound, bucketUpperBound float64, samples int64) {
+	value := bucketUpperBound
+	if bucketUpperBound == math.MaxFloat64 {
+		value = bucketLowerBound
+		if bucketLowerBound == -math.MaxFloat64 {
+			value = 0
+		}
+	}
+	r.recordSamples(name, tags, value, samples)
+}
+
+// ReportHistogramDurationSamples implements tally.StatsReporter, like
+// ReportHistogramValueSamples.
+func (r *Reporter) ReportHistogramDurationSamples(name string, tags map[string]string, _ tally.Buckets, bucketLowerBound, bucketUpperBound time.Duration, samples int64) {
+	value := bucketUpperBound
+	if bucketUpperBound == math.MaxInt64 {
+		value = bucketLowerBound
+		if bucketLowerBound == math.MinInt64 {
+			value = 0
+		}
+	}
+	r.recordSamples(name, tags, value.Seconds(), samples)
+}
+
+// recordSamples records value the number of times tally counted it in a
+// bucket.
+func (r *Reporter) recordSamples(name string, tags map[string]string, value float64, samples int64) {
+	attrs := r.shim.Attributes(tags)
+	for i := int64(0); i < samples; i++ {
+		r.shim.Record(context.Background(), name, value, attrs...)
+	}
+}
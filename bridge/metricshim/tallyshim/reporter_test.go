@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tallyshim_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally/v4"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/bridge/metricshim/tallyshim"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func collect(t *testing.T, exp *metrictest.Exporter) map[string]*metrictest.ExportRecord {
+	require.NoError(t, exp.Collect(context.Background()))
+	records := map[string]*metrictest.ExportRecord{}
+	for i := range exp.Records {
+		rec := &exp.Records[i]
+		name := rec.InstrumentName
+		if set := attribute.NewSet(rec.Attributes...); set.Len() > 0 {
+			name += "/" + string(set.Encoded(attribute.DefaultEncoder()))
+		}
+		records[name] = rec
+	}
+	return records
+}
+
+func TestReporter(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	reporter := tallyshim.New(mp.Meter("test"))
+	require.True(t, reporter.Capabilities().Reporting())
+	require.True(t, reporter.Capabilities().Tagging())
+
+	tags := map[string]string{"method": "GET", "code": "200"}
+	reporter.ReportCounter("requests", tags, 2)
+	reporter.ReportCounter("requests", tags, 3)
+	reporter.ReportGauge("queue", nil, 7)
+	reporter.ReportTimer("latency", nil, 500*time.Millisecond)
+	buckets := tally.ValueBuckets{1, 10}
+	reporter.ReportHistogramValueSamples("size", nil, buckets, -math.MaxFloat64, 1, 2)
+	reporter.ReportHistogramValueSamples("size", nil, buckets, 10, math.MaxFloat64, 1)
+	durations := tally.DurationBuckets{time.Second}
+	reporter.ReportHistogramDurationSamples("wait", nil, durations, math.MinInt64, time.Second, 3)
+	reporter.Flush()
+
+	records := collect(t, exp)
+	require.Len(t, records, 5)
+
+	counter := records["requests/code=200,method=GET"]
+	assert.Equal(t, aggregation.SumKind, counter.AggregationKind)
+	assert.Equal(t, 5.0, counter.Sum.AsFloat64())
+
+	gauge := records["queue"]
+	assert.Equal(t, aggregation.LastValueKind, gauge.AggregationKind)
+	assert.Equal(t, 7.0, gauge.LastValue.AsFloat64())
+
+	timer := records["latency"]
+	assert.Equal(t, aggregation.HistogramKind, timer.AggregationKind)
+	assert.Equal(t, 0.5, timer.Sum.AsFloat64())
+
+	size := records["size"]
+	assert.Equal(t, uint64(3), size.Count)
+	assert.Equal(t, 12.0, size.Sum.AsFloat64())
+
+	wait := records["wait"]
+	assert.Equal(t, uint64(3), wait.Count)
+	assert.Equal(t, 3.0, wait.Sum.AsFloat64())
+}
+
+func TestReporterThroughScope(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	scope, closer := tally.NewRootScope(tally.ScopeOptions{
+		Prefix:   "app",
+		Reporter: tallyshim.New(mp.Meter("test")),
+	}, time.Hour)
+
+	tagged := scope.Tagged(map[string]string{"queue": "q"})
+	tagged.Counter("jobs").Inc(4)
+	tagged.Gauge("pending").Update(2)
+	tagged.Timer("duration").Record(time.Second)
+	require.NoError(t, closer.Close())
+
+	records := collect(t, exp)
+	assert.Equal(t, 4.0, records["app.jobs/queue=q"].Sum.AsFloat64())
+	assert.Equal(t, 2.0, records["app.pending/queue=q"].LastValue.AsFloat64())
+	assert.Equal(t, 1.0, records["app.duration/queue=q"].Sum.AsFloat64())
+}
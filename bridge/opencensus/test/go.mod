@@ -73,3 +73,11 @@ replace go.opentelemetry.io/otel/example/fib => ../../../example/fib
 replace go.opentelemetry.io/otel/schema => ../../../schema
 
 replace go.opentelemetry.io/otel/exporters/otlp/internal/retry => ../../../exporters/otlp/internal/retry
+
+replace go.opentelemetry.io/otel/instrumentation/process => ../../../instrumentation/process
+
+replace go.opentelemetry.io/otel/bridge/metricshim => ../../metricshim
+
+replace go.opentelemetry.io/otel/bridge/metricshim/gometricsshim => ../../metricshim/gometricsshim
+
+replace go.opentelemetry.io/otel/bridge/metricshim/tallyshim => ../../metricshim/tallyshim
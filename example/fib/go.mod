@@ -72,3 +72,11 @@ replace go.opentelemetry.io/otel/example/fib => ./
 replace go.opentelemetry.io/otel/schema => ../../schema
 
 replace go.opentelemetry.io/otel/exporters/otlp/internal/retry => ../../exporters/otlp/internal/retry
+
+replace go.opentelemetry.io/otel/instrumentation/process => ../../instrumentation/process
+
+replace go.opentelemetry.io/otel/bridge/metricshim => ../../bridge/metricshim
+
+replace go.opentelemetry.io/otel/bridge/metricshim/gometricsshim => ../../bridge/metricshim/gometricsshim
+
+replace go.opentelemetry.io/otel/bridge/metricshim/tallyshim => ../../bridge/metricshim/tallyshim
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetric // import "go.opentelemetry.io/otel/exporters/otlp/otlpmetric"
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/internal/metrictransform"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/export/encoding"
+	"go.opentelemetry.io/otel/sdk/resource"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+func init() {
+	encoding.Register("otlp-proto", NewProtoEncoder())
+	encoding.Register("otlp-json", NewJSONEncoder())
+}
+
+// NewProtoEncoder returns an encoding.Encoder of OTLP
+// ExportMetricsServiceRequest messages in the binary protobuf format,
+// translated the same way the Exporter translates them.  It is
+// registered as "otlp-proto".
+func NewProtoEncoder() encoding.Encoder {
+	return otlpEncoder{
+		contentType: "application/x-protobuf",
+		marshal:     marshalProto,
+	}
+}
+
+// NewJSONEncoder returns an encoding.Encoder of OTLP
+// ExportMetricsServiceRequest messages in the OTLP/JSON format.  It is
+// registered as "otlp-json".  Messages are written by a hand-written
+// encoder rather than protojson, which avoids linking the protobuf
+// reflection-based JSON support into binaries that only need OTLP/JSON.
+func NewJSONEncoder() encoding.Encoder {
+	return otlpEncoder{
+		contentType: "application/json",
+		marshal:     marshalJSON,
+	}
+}
+
+func marshalProto(req *colmetricpb.ExportMetricsServiceRequest) ([]byte, error) {
+	return proto.Marshal(req)
+}
+
+type otlpEncoder struct {
+	contentType string
+	marshal     func(*colmetricpb.ExportMetricsServiceRequest) ([]byte, error)
+}
+
+// ContentType implements encoding.Encoder.
+func (e otlpEncoder) ContentType() string {
+	return e.contentType
+}
+
+// Encode implements encoding.Encoder.
+func (e otlpEncoder) Encode(ctx context.Context, res *resource.Resource, reader export.InstrumentationLibraryReader, sel aggregation.TemporalitySelector) ([]byte, error) {
+	rm, err := metrictransform.InstrumentationLibraryReader(ctx, sel, res, reader, 1, nil)
+	if err != nil {
+		return nil, err
+	}
+	req := &colmetricpb.ExportMetricsServiceRequest{}
+	if rm != nil {
+		req.ResourceMetrics = []*metricpb.ResourceMetrics{rm}
+	}
+	return e.marshal(req)
+}
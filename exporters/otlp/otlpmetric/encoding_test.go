@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetric_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/export/encoding"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+)
+
+func TestEncoders(t *testing.T) {
+	ctx := context.Background()
+	desc := metrictest.NewDescriptor("int64-count", sdkapi.CounterInstrumentKind, number.Int64Kind)
+	agg := &sum.New(1)[0]
+	require.NoError(t, agg.Update(ctx, number.NewInt64Number(11), &desc))
+	attrs := attribute.NewSet(attribute.String("host", "test.com"))
+	reader := processortest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		{Name: "lib"}: {export.NewRecord(&desc, &attrs, agg.Aggregation(), intervalStart, intervalEnd)},
+	})
+	res := resource.NewSchemaless(attribute.String("service.name", "test"))
+	sel := aggregation.CumulativeTemporalitySelector()
+
+	exp, client := newExporter(t)
+	require.NoError(t, exp.Export(ctx, res, reader))
+	want := &colmetricpb.ExportMetricsServiceRequest{ResourceMetrics: client.rm}
+
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		unmarshal   func([]byte, proto.Message) error
+	}{
+		{"otlp-proto", "application/x-protobuf", proto.Unmarshal},
+		{"otlp-json", "application/json", protojson.Unmarshal},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			enc, err := encoding.Lookup(tc.name)
+			require.NoError(t, err)
+			require.Equal(t, tc.contentType, enc.ContentType())
+
+			payload, err := enc.Encode(ctx, res, reader, sel)
+			require.NoError(t, err)
+			got := &colmetricpb.ExportMetricsServiceRequest{}
+			require.NoError(t, tc.unmarshal(payload, got))
+			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+				t.Fatalf("encoded request differs from exported metrics (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
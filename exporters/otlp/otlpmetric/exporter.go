@@ -34,6 +34,8 @@ var (
 type Exporter struct {
 	client              Client
 	temporalitySelector aggregation.TemporalitySelector
+	dedupAttributes     bool
+	sparseHistograms    bool
 
 	mu      sync.RWMutex
 	started bool
@@ -44,13 +46,20 @@ type Exporter struct {
 
 // Export exports a batch of metrics.
 func (e *Exporter) Export(ctx context.Context, res *resource.Resource, ilr export.InstrumentationLibraryReader) error {
-	rm, err := metrictransform.InstrumentationLibraryReader(ctx, e, res, ilr, 1)
+	var cache *metrictransform.AttributeCache
+	if e.dedupAttributes {
+		cache = metrictransform.NewAttributeCache()
+	}
+	rm, err := metrictransform.InstrumentationLibraryReader(ctx, e, res, ilr, 1, cache)
 	if err != nil {
 		return err
 	}
 	if rm == nil {
 		return nil
 	}
+	if e.sparseHistograms {
+		metrictransform.TrimDeltaHistograms(rm)
+	}
 
 	// TODO: There is never more than one resource emitted by this
 	// call, as per the specification.  We can change the
@@ -126,6 +135,8 @@ func NewUnstarted(client Client, opts ...Option) *Exporter {
 	e := &Exporter{
 		client:              client,
 		temporalitySelector: cfg.temporalitySelector,
+		dedupAttributes:     cfg.dedupAttributes,
+		sparseHistograms:    cfg.sparseHistograms,
 	}
 
 	return e
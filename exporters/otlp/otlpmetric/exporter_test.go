@@ -225,6 +225,71 @@ func TestNoGroupingExport(t *testing.T) {
 	)
 }
 
+func TestAttributeDeduplicationExport(t *testing.T) {
+	sumMetric := func(name string, value *metricpb.NumberDataPoint_AsInt) *metricpb.Metric {
+		return &metricpb.Metric{
+			Name: name,
+			Data: &metricpb.Metric_Sum{
+				Sum: &metricpb.Sum{
+					IsMonotonic:            true,
+					AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+					DataPoints: []*metricpb.NumberDataPoint{
+						{
+							Value:             value,
+							Attributes:        cpu1Attrs,
+							StartTimeUnixNano: startTime(),
+							TimeUnixNano:      pointTime(),
+						},
+					},
+				},
+			},
+		}
+	}
+	runMetricExportTests(
+		t,
+		[]otlpmetric.Option{otlpmetric.WithAttributeDeduplication()},
+		resource.Empty(),
+		[]testRecord{
+			record(
+				"int64-count",
+				sdkapi.CounterInstrumentKind,
+				number.Int64Kind,
+				append(baseKeyValues, cpuKey.Int(1)),
+				testLibName,
+			),
+			// The metrics of one scope are exported in no particular
+			// order, use another scope to share the attributes.
+			record(
+				"other-int64-count",
+				sdkapi.CounterInstrumentKind,
+				number.Int64Kind,
+				append(baseKeyValues, cpuKey.Int(1)),
+				"other-lib",
+			),
+		},
+		[]*metricpb.ResourceMetrics{
+			{
+				Resource: nil,
+				ScopeMetrics: []*metricpb.ScopeMetrics{
+					{
+						Metrics: []*metricpb.Metric{
+							sumMetric("int64-count", &metricpb.NumberDataPoint_AsInt{AsInt: 11}),
+						},
+					},
+					{
+						Scope: &commonpb.InstrumentationScope{
+							Name: "other-lib",
+						},
+						Metrics: []*metricpb.Metric{
+							sumMetric("other-int64-count", &metricpb.NumberDataPoint_AsInt{AsInt: 11}),
+						},
+					},
+				},
+			},
+		},
+	)
+}
+
 func TestHistogramInt64MetricGroupingExport(t *testing.T) {
 	r := record(
 		"int64-histogram",
@@ -327,6 +392,53 @@ func TestHistogramFloat64MetricGroupingExport(t *testing.T) {
 	runMetricExportTests(t, nil, resource.Empty(), []testRecord{r, r}, expected)
 }
 
+func TestSparseDeltaHistogramExport(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		selector   aggregation.TemporalitySelector
+		wantBounds []float64
+		wantCounts []uint64
+	}{
+		{
+			name:       "delta",
+			selector:   aggregation.DeltaTemporalitySelector(),
+			wantBounds: []float64{4},
+			wantCounts: []uint64{1, 1},
+		},
+		{
+			name:       "cumulative",
+			selector:   aggregation.CumulativeTemporalitySelector(),
+			wantBounds: []float64{1, 2, 4, 8, 16},
+			wantCounts: []uint64{0, 0, 1, 1, 0, 0},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exp, driver := newExporter(t,
+				otlpmetric.WithSparseDeltaHistograms(),
+				otlpmetric.WithMetricAggregationTemporalitySelector(tc.selector),
+			)
+			desc := metrictest.NewDescriptor("latency", sdkapi.HistogramInstrumentKind, number.Float64Kind)
+			aggs := histogram.New(2, &desc, histogram.WithExplicitBoundaries([]float64{1, 2, 4, 8, 16}))
+			agg, ckpt := &aggs[0], &aggs[1]
+			require.NoError(t, agg.Update(context.Background(), number.NewFloat64Number(3), &desc))
+			require.NoError(t, agg.Update(context.Background(), number.NewFloat64Number(5), &desc))
+			require.NoError(t, agg.SynchronizedMove(ckpt, &desc))
+
+			attrs := attribute.NewSet()
+			recs := map[instrumentation.Library][]export.Record{
+				{Name: testLibName}: {export.NewRecord(&desc, &attrs, ckpt.Aggregation(), intervalStart, intervalEnd)},
+			}
+			require.NoError(t, exp.Export(context.Background(), resource.Empty(), processortest.MultiInstrumentationLibraryReader(recs)))
+
+			require.Len(t, driver.rm, 1)
+			point := driver.rm[0].ScopeMetrics[0].Metrics[0].GetHistogram().DataPoints[0]
+			assert.Equal(t, tc.wantBounds, point.ExplicitBounds)
+			assert.Equal(t, tc.wantCounts, point.BucketCounts)
+			assert.Equal(t, uint64(2), point.Count)
+		})
+	}
+}
+
 func TestCountInt64MetricGroupingExport(t *testing.T) {
 	r := record(
 		"int64-count",
@@ -661,26 +773,38 @@ func TestResourceInstLibMetricGroupingExport(t *testing.T) {
 }
 
 func TestStatelessAggregationTemporality(t *testing.T) {
-	type testcase struct {
-		name           string
-		instrumentKind sdkapi.InstrumentKind
-		aggTemporality metricpb.AggregationTemporality
-		monotonic      bool
-	}
-
-	for _, k := range []testcase{
+	testAggregationTemporality(t, aggregation.StatelessTemporalitySelector(), []aggregationTemporalityCase{
 		{"counter", sdkapi.CounterInstrumentKind, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, true},
 		{"updowncounter", sdkapi.UpDownCounterInstrumentKind, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, false},
 		{"counterobserver", sdkapi.CounterObserverInstrumentKind, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, true},
 		{"updowncounterobserver", sdkapi.UpDownCounterObserverInstrumentKind, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, false},
-	} {
+	})
+}
+
+func TestDeltaAggregationTemporality(t *testing.T) {
+	// The sums of UpDownCounterObservers are exported as cumulative
+	// even when delta is selected.
+	testAggregationTemporality(t, aggregation.DeltaTemporalitySelector(), []aggregationTemporalityCase{
+		{"counter", sdkapi.CounterInstrumentKind, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, true},
+		{"updowncounter", sdkapi.UpDownCounterInstrumentKind, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, false},
+		{"updowncounterobserver", sdkapi.UpDownCounterObserverInstrumentKind, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, false},
+	})
+}
+
+type aggregationTemporalityCase struct {
+	name           string
+	instrumentKind sdkapi.InstrumentKind
+	aggTemporality metricpb.AggregationTemporality
+	monotonic      bool
+}
+
+func testAggregationTemporality(t *testing.T, selector aggregation.TemporalitySelector, cases []aggregationTemporalityCase) {
+	for _, k := range cases {
 		t.Run(k.name, func(t *testing.T) {
 			runMetricExportTests(
 				t,
 				[]otlpmetric.Option{
-					otlpmetric.WithMetricAggregationTemporalitySelector(
-						aggregation.StatelessTemporalitySelector(),
-					),
+					otlpmetric.WithMetricAggregationTemporalitySelector(selector),
 				},
 				testerAResource,
 				[]testRecord{
@@ -15,6 +15,8 @@
 package metrictransform // import "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/internal/metrictransform"
 
 import (
+	"sync"
+
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/resource"
 	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
@@ -47,6 +49,60 @@ func Iterator(iter attribute.Iterator) []*commonpb.KeyValue {
 	return out
 }
 
+// AttributeCache deduplicates the OTLP key-values produced for the data
+// points of a single export.  Data points with equal attribute sets
+// share one slice of key-values, and equal key-values are shared across
+// attribute sets, which avoids transforming and allocating them once per
+// data point.
+//
+// The key-values it returns are shared and must not be modified.
+type AttributeCache struct {
+	lock sync.Mutex
+	sets map[attribute.Distinct][]*commonpb.KeyValue
+	kvs  map[attribute.KeyValue]*commonpb.KeyValue
+}
+
+// NewAttributeCache returns an empty AttributeCache.
+func NewAttributeCache() *AttributeCache {
+	return &AttributeCache{
+		sets: map[attribute.Distinct][]*commonpb.KeyValue{},
+		kvs:  map[attribute.KeyValue]*commonpb.KeyValue{},
+	}
+}
+
+// Attributes transforms an attribute set into OTLP key-values, reusing
+// the result of a previous call for an equal set.  A nil AttributeCache
+// transforms the set without deduplication.
+func (c *AttributeCache) Attributes(set *attribute.Set) []*commonpb.KeyValue {
+	if c == nil {
+		return Iterator(set.Iter())
+	}
+	l := set.Len()
+	if l == 0 {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := set.Equivalent()
+	if out, ok := c.sets[key]; ok {
+		return out
+	}
+	out := make([]*commonpb.KeyValue, 0, l)
+	for iter := set.Iter(); iter.Next(); {
+		kv := iter.Attribute()
+		pb, ok := c.kvs[kv]
+		if !ok {
+			pb = KeyValue(kv)
+			c.kvs[kv] = pb
+		}
+		out = append(out, pb)
+	}
+	c.sets[key] = out
+	return out
+}
+
 // ResourceAttributes transforms a Resource OTLP key-values.
 func ResourceAttributes(resource *resource.Resource) []*commonpb.KeyValue {
 	return Iterator(resource.Iter())
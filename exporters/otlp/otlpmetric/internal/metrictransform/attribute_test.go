@@ -257,3 +257,25 @@ func newOTelArray(key string, arrayValues []*commonpb.AnyValue) *commonpb.KeyVal
 		},
 	}
 }
+
+func TestAttributeCache(t *testing.T) {
+	cache := NewAttributeCache()
+	host := attribute.String("host", "test.com")
+	set1 := attribute.NewSet(host, attribute.Int("CPU", 1))
+	set1Copy := attribute.NewSet(attribute.Int("CPU", 1), host)
+	set2 := attribute.NewSet(host, attribute.Int("CPU", 2))
+	empty := attribute.NewSet()
+
+	got1 := cache.Attributes(&set1)
+	assert.Equal(t, Iterator(set1.Iter()), got1)
+	assert.Equal(t, Iterator(set2.Iter()), cache.Attributes(&set2))
+	assert.Nil(t, cache.Attributes(&empty))
+
+	// Equal sets share the slice, equal key-values share the element.
+	gotCopy := cache.Attributes(&set1Copy)
+	assert.Same(t, &got1[0], &gotCopy[0])
+	assert.Same(t, got1[1], cache.Attributes(&set2)[1])
+
+	var noCache *AttributeCache
+	assert.Equal(t, Iterator(set1.Iter()), noCache.Attributes(&set1))
+}
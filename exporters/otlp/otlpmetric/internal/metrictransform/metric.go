@@ -70,8 +70,9 @@ func toNanos(t time.Time) uint64 {
 }
 
 // InstrumentationLibraryReader transforms all records contained in a checkpoint into
-// batched OTLP ResourceMetrics.
-func InstrumentationLibraryReader(ctx context.Context, temporalitySelector aggregation.TemporalitySelector, res *resource.Resource, ilmr export.InstrumentationLibraryReader, numWorkers uint) (*metricpb.ResourceMetrics, error) {
+// batched OTLP ResourceMetrics.  When cache is not nil, it is used to
+// deduplicate the attributes of the data points.
+func InstrumentationLibraryReader(ctx context.Context, temporalitySelector aggregation.TemporalitySelector, res *resource.Resource, ilmr export.InstrumentationLibraryReader, numWorkers uint, cache *AttributeCache) (*metricpb.ResourceMetrics, error) {
 	var sms []*metricpb.ScopeMetrics
 
 	err := ilmr.ForEach(func(lib instrumentation.Library, mr export.Reader) error {
@@ -85,7 +86,7 @@ func InstrumentationLibraryReader(ctx context.Context, temporalitySelector aggre
 		for i := uint(0); i < numWorkers; i++ {
 			go func() {
 				defer wg.Done()
-				transformer(ctx, temporalitySelector, records, transformed)
+				transformer(ctx, temporalitySelector, cache, records, transformed)
 			}()
 		}
 		go func() {
@@ -154,9 +155,9 @@ func source(ctx context.Context, temporalitySelector aggregation.TemporalitySele
 
 // transformer transforms records read from the passed in chan into
 // OTLP Metrics which are sent on the out chan.
-func transformer(ctx context.Context, temporalitySelector aggregation.TemporalitySelector, in <-chan export.Record, out chan<- result) {
+func transformer(ctx context.Context, temporalitySelector aggregation.TemporalitySelector, cache *AttributeCache, in <-chan export.Record, out chan<- result) {
 	for r := range in {
-		m, err := Record(temporalitySelector, r)
+		m, err := record(temporalitySelector, cache, r)
 		// Propagate errors, but do not send empty results.
 		if err == nil && m == nil {
 			continue
@@ -208,6 +209,8 @@ func sink(ctx context.Context, in <-chan result) ([]*metricpb.Metric, error) {
 			m.GetSum().DataPoints = append(m.GetSum().DataPoints, res.Metric.GetSum().DataPoints...)
 		case *metricpb.Metric_Histogram:
 			m.GetHistogram().DataPoints = append(m.GetHistogram().DataPoints, res.Metric.GetHistogram().DataPoints...)
+		case *metricpb.Metric_ExponentialHistogram:
+			m.GetExponentialHistogram().DataPoints = append(m.GetExponentialHistogram().DataPoints, res.Metric.GetExponentialHistogram().DataPoints...)
 		case *metricpb.Metric_Summary:
 			m.GetSummary().DataPoints = append(m.GetSummary().DataPoints, res.Metric.GetSummary().DataPoints...)
 		default:
@@ -235,6 +238,10 @@ func sink(ctx context.Context, in <-chan result) ([]*metricpb.Metric, error) {
 // Record transforms a Record into an OTLP Metric. An ErrIncompatibleAgg
 // error is returned if the Record Aggregator is not supported.
 func Record(temporalitySelector aggregation.TemporalitySelector, r export.Record) (*metricpb.Metric, error) {
+	return record(temporalitySelector, nil, r)
+}
+
+func record(temporalitySelector aggregation.TemporalitySelector, cache *AttributeCache, r export.Record) (*metricpb.Metric, error) {
 	agg := r.Aggregation()
 	switch agg.Kind() {
 	case aggregation.HistogramKind:
@@ -242,7 +249,28 @@ func Record(temporalitySelector aggregation.TemporalitySelector, r export.Record
 		if !ok {
 			return nil, fmt.Errorf("%w: %T", ErrIncompatibleAgg, agg)
 		}
-		return histogramPoint(r, temporalitySelector.TemporalityFor(r.Descriptor(), aggregation.HistogramKind), h)
+		return histogramPoint(r, cache, aggregation.ResolveTemporality(temporalitySelector, r.Descriptor(), aggregation.HistogramKind), h)
+
+	case aggregation.ExponentialHistogramKind:
+		h, ok := agg.(aggregation.ExponentialHistogram)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrIncompatibleAgg, agg)
+		}
+		return exponentialHistogramPoint(r, cache, aggregation.ResolveTemporality(temporalitySelector, r.Descriptor(), aggregation.ExponentialHistogramKind), h)
+
+	case aggregation.MinMaxSumCountKind:
+		mmsc, ok := agg.(aggregation.MinMaxSumCount)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrIncompatibleAgg, agg)
+		}
+		return minMaxSumCountPoint(r, cache, mmsc)
+
+	case aggregation.TDigestKind, aggregation.DDSketchKind:
+		summary, ok := agg.(aggregation.Summary)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrIncompatibleAgg, agg)
+		}
+		return summaryPoint(r, cache, summary)
 
 	case aggregation.SumKind:
 		s, ok := agg.(aggregation.Sum)
@@ -253,7 +281,7 @@ func Record(temporalitySelector aggregation.TemporalitySelector, r export.Record
 		if err != nil {
 			return nil, err
 		}
-		return sumPoint(r, sum, r.StartTime(), r.EndTime(), temporalitySelector.TemporalityFor(r.Descriptor(), aggregation.SumKind), r.Descriptor().InstrumentKind().Monotonic())
+		return sumPoint(r, cache, sum, r.StartTime(), r.EndTime(), aggregation.ResolveTemporality(temporalitySelector, r.Descriptor(), aggregation.SumKind), r.Descriptor().InstrumentKind().Monotonic())
 
 	case aggregation.LastValueKind:
 		lv, ok := agg.(aggregation.LastValue)
@@ -264,14 +292,14 @@ func Record(temporalitySelector aggregation.TemporalitySelector, r export.Record
 		if err != nil {
 			return nil, err
 		}
-		return gaugePoint(r, value, time.Time{}, tm)
+		return gaugePoint(r, cache, value, time.Time{}, tm)
 
 	default:
 		return nil, fmt.Errorf("%w: %T", ErrUnimplementedAgg, agg)
 	}
 }
 
-func gaugePoint(record export.Record, num number.Number, start, end time.Time) (*metricpb.Metric, error) {
+func gaugePoint(record export.Record, cache *AttributeCache, num number.Number, start, end time.Time) (*metricpb.Metric, error) {
 	desc := record.Descriptor()
 	attrs := record.Attributes()
 
@@ -290,7 +318,7 @@ func gaugePoint(record export.Record, num number.Number, start, end time.Time) (
 						Value: &metricpb.NumberDataPoint_AsInt{
 							AsInt: num.CoerceToInt64(n),
 						},
-						Attributes:        Iterator(attrs.Iter()),
+						Attributes:        cache.Attributes(attrs),
 						StartTimeUnixNano: toNanos(start),
 						TimeUnixNano:      toNanos(end),
 					},
@@ -305,7 +333,7 @@ func gaugePoint(record export.Record, num number.Number, start, end time.Time) (
 						Value: &metricpb.NumberDataPoint_AsDouble{
 							AsDouble: num.CoerceToFloat64(n),
 						},
-						Attributes:        Iterator(attrs.Iter()),
+						Attributes:        cache.Attributes(attrs),
 						StartTimeUnixNano: toNanos(start),
 						TimeUnixNano:      toNanos(end),
 					},
@@ -329,7 +357,7 @@ func sdkTemporalityToTemporality(temporality aggregation.Temporality) metricpb.A
 	return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED
 }
 
-func sumPoint(record export.Record, num number.Number, start, end time.Time, temporality aggregation.Temporality, monotonic bool) (*metricpb.Metric, error) {
+func sumPoint(record export.Record, cache *AttributeCache, num number.Number, start, end time.Time, temporality aggregation.Temporality, monotonic bool) (*metricpb.Metric, error) {
 	desc := record.Descriptor()
 	attrs := record.Attributes()
 
@@ -350,7 +378,7 @@ func sumPoint(record export.Record, num number.Number, start, end time.Time, tem
 						Value: &metricpb.NumberDataPoint_AsInt{
 							AsInt: num.CoerceToInt64(n),
 						},
-						Attributes:        Iterator(attrs.Iter()),
+						Attributes:        cache.Attributes(attrs),
 						StartTimeUnixNano: toNanos(start),
 						TimeUnixNano:      toNanos(end),
 					},
@@ -367,7 +395,7 @@ func sumPoint(record export.Record, num number.Number, start, end time.Time, tem
 						Value: &metricpb.NumberDataPoint_AsDouble{
 							AsDouble: num.CoerceToFloat64(n),
 						},
-						Attributes:        Iterator(attrs.Iter()),
+						Attributes:        cache.Attributes(attrs),
 						StartTimeUnixNano: toNanos(start),
 						TimeUnixNano:      toNanos(end),
 					},
@@ -395,7 +423,7 @@ func histogramValues(a aggregation.Histogram) (boundaries []float64, counts []ui
 }
 
 // histogram transforms a Histogram Aggregator into an OTLP Metric.
-func histogramPoint(record export.Record, temporality aggregation.Temporality, a aggregation.Histogram) (*metricpb.Metric, error) {
+func histogramPoint(record export.Record, cache *AttributeCache, temporality aggregation.Temporality, a aggregation.Histogram) (*metricpb.Metric, error) {
 	desc := record.Descriptor()
 	attrs := record.Attributes()
 	boundaries, counts, err := histogramValues(a)
@@ -424,7 +452,7 @@ func histogramPoint(record export.Record, temporality aggregation.Temporality, a
 				DataPoints: []*metricpb.HistogramDataPoint{
 					{
 						Sum:               &sumFloat64,
-						Attributes:        Iterator(attrs.Iter()),
+						Attributes:        cache.Attributes(attrs),
 						StartTimeUnixNano: toNanos(record.StartTime()),
 						TimeUnixNano:      toNanos(record.EndTime()),
 						Count:             uint64(count),
@@ -437,3 +465,169 @@ func histogramPoint(record export.Record, temporality aggregation.Temporality, a
 	}
 	return m, nil
 }
+
+// exponentialBuckets transforms exponential histogram buckets into OTLP
+// buckets.
+func exponentialBuckets(b aggregation.ExponentialBuckets) *metricpb.ExponentialHistogramDataPoint_Buckets {
+	counts := make([]uint64, b.Len())
+	for i := range counts {
+		counts[i] = b.At(uint32(i))
+	}
+	return &metricpb.ExponentialHistogramDataPoint_Buckets{
+		Offset:       b.Offset(),
+		BucketCounts: counts,
+	}
+}
+
+// exponentialHistogramPoint transforms an ExponentialHistogram
+// Aggregator into an OTLP Metric.
+func exponentialHistogramPoint(record export.Record, cache *AttributeCache, temporality aggregation.Temporality, a aggregation.ExponentialHistogram) (*metricpb.Metric, error) {
+	desc := record.Descriptor()
+	attrs := record.Attributes()
+
+	count, err := a.Count()
+	if err != nil {
+		return nil, err
+	}
+	sum, err := a.Sum()
+	if err != nil {
+		return nil, err
+	}
+	scale, err := a.Scale()
+	if err != nil {
+		return nil, err
+	}
+	zeroCount, err := a.ZeroCount()
+	if err != nil {
+		return nil, err
+	}
+	positive, err := a.Positive()
+	if err != nil {
+		return nil, err
+	}
+	negative, err := a.Negative()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &metricpb.Metric{
+		Name:        desc.Name(),
+		Description: desc.Description(),
+		Unit:        string(desc.Unit()),
+		Data: &metricpb.Metric_ExponentialHistogram{
+			ExponentialHistogram: &metricpb.ExponentialHistogram{
+				AggregationTemporality: sdkTemporalityToTemporality(temporality),
+				DataPoints: []*metricpb.ExponentialHistogramDataPoint{
+					{
+						Attributes:        cache.Attributes(attrs),
+						StartTimeUnixNano: toNanos(record.StartTime()),
+						TimeUnixNano:      toNanos(record.EndTime()),
+						Count:             count,
+						Sum:               sum.CoerceToFloat64(desc.NumberKind()),
+						Scale:             scale,
+						ZeroCount:         zeroCount,
+						Positive:          exponentialBuckets(positive),
+						Negative:          exponentialBuckets(negative),
+					},
+				},
+			},
+		},
+	}
+	return m, nil
+}
+
+// minMaxSumCountPoint transforms a MinMaxSumCount Aggregator into an
+// OTLP Summary Metric, with the minimum and maximum as the 0 and 1
+// quantiles.
+func minMaxSumCountPoint(record export.Record, cache *AttributeCache, a aggregation.MinMaxSumCount) (*metricpb.Metric, error) {
+	minimum, err := a.Min()
+	if err != nil {
+		return nil, err
+	}
+	maximum, err := a.Max()
+	if err != nil {
+		return nil, err
+	}
+	sum, err := a.Sum()
+	if err != nil {
+		return nil, err
+	}
+	count, err := a.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	n := record.Descriptor().NumberKind()
+	return summaryMetric(record, cache, count, sum.CoerceToFloat64(n), []*metricpb.SummaryDataPoint_ValueAtQuantile{
+		{Quantile: 0, Value: minimum.CoerceToFloat64(n)},
+		{Quantile: 1, Value: maximum.CoerceToFloat64(n)},
+	}), nil
+}
+
+// summaryPoint transforms a Summary Aggregator into an OTLP Summary
+// Metric, with the minimum as the 0 quantile, the estimates of the
+// quantiles of the Aggregator in between, and the maximum as the 1
+// quantile.
+func summaryPoint(record export.Record, cache *AttributeCache, a aggregation.Summary) (*metricpb.Metric, error) {
+	minimum, err := a.Min()
+	if err != nil {
+		return nil, err
+	}
+	maximum, err := a.Max()
+	if err != nil {
+		return nil, err
+	}
+	sum, err := a.Sum()
+	if err != nil {
+		return nil, err
+	}
+	count, err := a.Count()
+	if err != nil {
+		return nil, err
+	}
+	quantiles, err := a.Quantiles()
+	if err != nil {
+		return nil, err
+	}
+
+	n := record.Descriptor().NumberKind()
+	values := make([]*metricpb.SummaryDataPoint_ValueAtQuantile, 0, len(quantiles)+2)
+	values = append(values, &metricpb.SummaryDataPoint_ValueAtQuantile{Quantile: 0, Value: minimum.CoerceToFloat64(n)})
+	for _, q := range quantiles {
+		if q <= 0 || q >= 1 {
+			continue
+		}
+		v, err := a.Quantile(q)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, &metricpb.SummaryDataPoint_ValueAtQuantile{Quantile: q, Value: v})
+	}
+	values = append(values, &metricpb.SummaryDataPoint_ValueAtQuantile{Quantile: 1, Value: maximum.CoerceToFloat64(n)})
+
+	return summaryMetric(record, cache, count, sum.CoerceToFloat64(n), values), nil
+}
+
+// summaryMetric returns an OTLP Summary Metric with one data point.
+func summaryMetric(record export.Record, cache *AttributeCache, count uint64, sum float64, values []*metricpb.SummaryDataPoint_ValueAtQuantile) *metricpb.Metric {
+	desc := record.Descriptor()
+	return &metricpb.Metric{
+		Name:        desc.Name(),
+		Description: desc.Description(),
+		Unit:        string(desc.Unit()),
+		Data: &metricpb.Metric_Summary{
+			Summary: &metricpb.Summary{
+				DataPoints: []*metricpb.SummaryDataPoint{
+					{
+						Attributes:        cache.Attributes(record.Attributes()),
+						StartTimeUnixNano: toNanos(record.StartTime()),
+						TimeUnixNano:      toNanos(record.EndTime()),
+						Count:             count,
+						Sum:               sum,
+						QuantileValues:    values,
+					},
+				},
+			},
+		},
+	}
+}
@@ -26,8 +26,12 @@ import (
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/tdigest"
 	"go.opentelemetry.io/otel/sdk/metric/export"
 	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 	"go.opentelemetry.io/otel/sdk/metric/metrictest"
@@ -109,7 +113,7 @@ func TestSumIntDataPoints(t *testing.T) {
 	value, err := ckpt.Sum()
 	require.NoError(t, err)
 
-	if m, err := sumPoint(record, value, record.StartTime(), record.EndTime(), aggregation.CumulativeTemporality, true); assert.NoError(t, err) {
+	if m, err := sumPoint(record, nil, value, record.StartTime(), record.EndTime(), aggregation.CumulativeTemporality, true); assert.NoError(t, err) {
 		assert.Nil(t, m.GetGauge())
 		assert.Equal(t, &metricpb.Sum{
 			AggregationTemporality: otelCumulative,
@@ -145,7 +149,7 @@ func TestSumFloatDataPoints(t *testing.T) {
 	value, err := ckpt.Sum()
 	require.NoError(t, err)
 
-	if m, err := sumPoint(record, value, record.StartTime(), record.EndTime(), aggregation.DeltaTemporality, false); assert.NoError(t, err) {
+	if m, err := sumPoint(record, nil, value, record.StartTime(), record.EndTime(), aggregation.DeltaTemporality, false); assert.NoError(t, err) {
 		assert.Nil(t, m.GetGauge())
 		assert.Equal(t, &metricpb.Sum{
 			IsMonotonic:            false,
@@ -181,7 +185,7 @@ func TestLastValueIntDataPoints(t *testing.T) {
 	value, timestamp, err := ckpt.LastValue()
 	require.NoError(t, err)
 
-	if m, err := gaugePoint(record, value, time.Time{}, timestamp); assert.NoError(t, err) {
+	if m, err := gaugePoint(record, nil, value, time.Time{}, timestamp); assert.NoError(t, err) {
 		assert.Equal(t, []*metricpb.NumberDataPoint{{
 			StartTimeUnixNano: 0,
 			TimeUnixNano:      uint64(timestamp.UnixNano()),
@@ -201,6 +205,139 @@ func TestLastValueIntDataPoints(t *testing.T) {
 	}
 }
 
+func TestExponentialHistogramDataPoints(t *testing.T) {
+	desc := metrictest.NewDescriptor("", sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	attrs := attribute.NewSet(attribute.String("one", "1"))
+	aggs := exponential.New(2, &desc, exponential.WithMaxScale(0))
+	h, ckpt := &aggs[0], &aggs[1]
+
+	for _, v := range []float64{0, 2, 3, -1} {
+		assert.NoError(t, h.Update(context.Background(), number.NewFloat64Number(v), &desc))
+	}
+	require.NoError(t, h.SynchronizedMove(ckpt, &desc))
+	record := export.NewRecord(&desc, &attrs, ckpt.Aggregation(), intervalStart, intervalEnd)
+
+	m, err := Record(aggregation.DeltaTemporalitySelector(), record)
+	require.NoError(t, err)
+	assert.Nil(t, m.GetHistogram())
+	assert.Equal(t, &metricpb.ExponentialHistogram{
+		AggregationTemporality: otelDelta,
+		DataPoints: []*metricpb.ExponentialHistogramDataPoint{{
+			StartTimeUnixNano: uint64(intervalStart.UnixNano()),
+			TimeUnixNano:      uint64(intervalEnd.UnixNano()),
+			Attributes: []*commonpb.KeyValue{
+				{
+					Key:   "one",
+					Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "1"}},
+				},
+			},
+			Count:     4,
+			Sum:       4,
+			ZeroCount: 1,
+			Positive: &metricpb.ExponentialHistogramDataPoint_Buckets{
+				Offset:       1,
+				BucketCounts: []uint64{2},
+			},
+			Negative: &metricpb.ExponentialHistogramDataPoint_Buckets{
+				BucketCounts: []uint64{1},
+			},
+		}},
+	}, m.GetExponentialHistogram())
+}
+
+func TestMinMaxSumCountDataPoints(t *testing.T) {
+	desc := metrictest.NewDescriptor("", sdkapi.HistogramInstrumentKind, number.Int64Kind)
+	attrs := attribute.NewSet()
+	aggs := minmaxsumcount.New(2)
+	mmsc, ckpt := &aggs[0], &aggs[1]
+
+	for _, v := range []int64{4, -2, 7} {
+		assert.NoError(t, mmsc.Update(context.Background(), number.NewInt64Number(v), &desc))
+	}
+	require.NoError(t, mmsc.SynchronizedMove(ckpt, &desc))
+	record := export.NewRecord(&desc, &attrs, ckpt.Aggregation(), intervalStart, intervalEnd)
+
+	m, err := Record(aggregation.CumulativeTemporalitySelector(), record)
+	require.NoError(t, err)
+	assert.Nil(t, m.GetHistogram())
+	assert.Equal(t, &metricpb.Summary{
+		DataPoints: []*metricpb.SummaryDataPoint{{
+			StartTimeUnixNano: uint64(intervalStart.UnixNano()),
+			TimeUnixNano:      uint64(intervalEnd.UnixNano()),
+			Count:             3,
+			Sum:               9,
+			QuantileValues: []*metricpb.SummaryDataPoint_ValueAtQuantile{
+				{Quantile: 0, Value: -2},
+				{Quantile: 1, Value: 7},
+			},
+		}},
+	}, m.GetSummary())
+
+	// An empty checkpoint has no minimum, as a LastValue without data.
+	require.NoError(t, mmsc.SynchronizedMove(ckpt, &desc))
+	_, err = Record(aggregation.CumulativeTemporalitySelector(), export.NewRecord(&desc, &attrs, ckpt.Aggregation(), intervalStart, intervalEnd))
+	require.ErrorIs(t, err, aggregation.ErrNoData)
+}
+
+func TestTDigestDataPoints(t *testing.T) {
+	desc := metrictest.NewDescriptor("", sdkapi.HistogramInstrumentKind, number.Int64Kind)
+	attrs := attribute.NewSet()
+	aggs := tdigest.New(2, &desc, tdigest.WithQuantiles(0, 0.5, 0.75))
+	digest, ckpt := &aggs[0], &aggs[1]
+
+	for _, v := range []int64{4, 1, 3, 2} {
+		assert.NoError(t, digest.Update(context.Background(), number.NewInt64Number(v), &desc))
+	}
+	require.NoError(t, digest.SynchronizedMove(ckpt, &desc))
+	record := export.NewRecord(&desc, &attrs, ckpt.Aggregation(), intervalStart, intervalEnd)
+
+	m, err := Record(aggregation.CumulativeTemporalitySelector(), record)
+	require.NoError(t, err)
+	assert.Equal(t, &metricpb.Summary{
+		DataPoints: []*metricpb.SummaryDataPoint{{
+			StartTimeUnixNano: uint64(intervalStart.UnixNano()),
+			TimeUnixNano:      uint64(intervalEnd.UnixNano()),
+			Count:             4,
+			Sum:               10,
+			QuantileValues: []*metricpb.SummaryDataPoint_ValueAtQuantile{
+				{Quantile: 0, Value: 1},
+				{Quantile: 0.5, Value: 2.5},
+				{Quantile: 0.75, Value: 3.5},
+				{Quantile: 1, Value: 4},
+			},
+		}},
+	}, m.GetSummary())
+
+	require.NoError(t, digest.SynchronizedMove(ckpt, &desc))
+	_, err = Record(aggregation.CumulativeTemporalitySelector(), export.NewRecord(&desc, &attrs, ckpt.Aggregation(), intervalStart, intervalEnd))
+	require.ErrorIs(t, err, aggregation.ErrNoData)
+}
+
+func TestDDSketchDataPoints(t *testing.T) {
+	desc := metrictest.NewDescriptor("", sdkapi.HistogramInstrumentKind, number.Int64Kind)
+	attrs := attribute.NewSet()
+	aggs := ddsketch.New(2, &desc, ddsketch.WithQuantiles(0.5), ddsketch.WithRelativeAccuracy(0.01))
+	sketch, ckpt := &aggs[0], &aggs[1]
+
+	for _, v := range []int64{100, 1, 10000, 10} {
+		assert.NoError(t, sketch.Update(context.Background(), number.NewInt64Number(v), &desc))
+	}
+	require.NoError(t, sketch.SynchronizedMove(ckpt, &desc))
+	record := export.NewRecord(&desc, &attrs, ckpt.Aggregation(), intervalStart, intervalEnd)
+
+	m, err := Record(aggregation.CumulativeTemporalitySelector(), record)
+	require.NoError(t, err)
+	require.Len(t, m.GetSummary().DataPoints, 1)
+	dp := m.GetSummary().DataPoints[0]
+	assert.Equal(t, uint64(4), dp.Count)
+	assert.Equal(t, 10111.0, dp.Sum)
+	require.Len(t, dp.QuantileValues, 3)
+	assert.Equal(t, &metricpb.SummaryDataPoint_ValueAtQuantile{Quantile: 0, Value: 1}, dp.QuantileValues[0])
+	assert.Equal(t, 0.5, dp.QuantileValues[1].Quantile)
+	assert.InEpsilon(t, 10, dp.QuantileValues[1].Value, 0.01)
+	assert.Equal(t, &metricpb.SummaryDataPoint_ValueAtQuantile{Quantile: 1, Value: 10000}, dp.QuantileValues[2])
+}
+
 func TestSumErrUnknownValueType(t *testing.T) {
 	desc := metrictest.NewDescriptor("", sdkapi.HistogramInstrumentKind, number.Kind(-1))
 	attrs := attribute.NewSet()
@@ -209,7 +346,7 @@ func TestSumErrUnknownValueType(t *testing.T) {
 	value, err := s.Sum()
 	require.NoError(t, err)
 
-	_, err = sumPoint(record, value, record.StartTime(), record.EndTime(), aggregation.CumulativeTemporality, true)
+	_, err = sumPoint(record, nil, value, record.StartTime(), record.EndTime(), aggregation.CumulativeTemporality, true)
 	assert.Error(t, err)
 	if !errors.Is(err, ErrUnknownValueType) {
 		t.Errorf("expected ErrUnknownValueType, got %v", err)
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrictransform // import "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/internal/metrictransform"
+
+import (
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+// TrimDeltaHistograms removes the empty buckets below the lowest and
+// above the highest non-empty bucket of the delta histogram data points
+// of rm.  Explicit-boundary histograms drop the boundaries of the
+// removed buckets, so that their first and last buckets extend to the
+// infinities, and exponential histograms advance the offset of their
+// buckets instead.  Cumulative histograms are left unchanged, since
+// consumers expect their buckets to be the same in every export.
+func TrimDeltaHistograms(rm *metricpb.ResourceMetrics) {
+	for _, sm := range rm.GetScopeMetrics() {
+		for _, m := range sm.GetMetrics() {
+			if h := m.GetHistogram(); h != nil && isDelta(h.AggregationTemporality) {
+				for _, p := range h.DataPoints {
+					trimHistogramPoint(p)
+				}
+			}
+			if h := m.GetExponentialHistogram(); h != nil && isDelta(h.AggregationTemporality) {
+				for _, p := range h.DataPoints {
+					trimExponentialBuckets(p.Positive)
+					trimExponentialBuckets(p.Negative)
+				}
+			}
+		}
+	}
+}
+
+func isDelta(temporality metricpb.AggregationTemporality) bool {
+	return temporality == metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+}
+
+// trimHistogramPoint removes the leading and trailing empty buckets of
+// p, keeping at least one bucket.
+func trimHistogramPoint(p *metricpb.HistogramDataPoint) {
+	counts := p.BucketCounts
+	if len(counts) != len(p.ExplicitBounds)+1 {
+		return
+	}
+	first, last := 0, len(counts)-1
+	for first < last && counts[first] == 0 {
+		first++
+	}
+	for last > first && counts[last] == 0 {
+		last--
+	}
+	p.BucketCounts = counts[first : last+1]
+	p.ExplicitBounds = p.ExplicitBounds[first:last]
+}
+
+// trimExponentialBuckets removes the leading and trailing empty buckets
+// of b.
+func trimExponentialBuckets(b *metricpb.ExponentialHistogramDataPoint_Buckets) {
+	if b == nil {
+		return
+	}
+	counts := b.BucketCounts
+	first, last := 0, len(counts)
+	for first < last && counts[first] == 0 {
+		first++
+	}
+	for last > first && counts[last-1] == 0 {
+		last--
+	}
+	if first == last {
+		b.Offset = 0
+		b.BucketCounts = nil
+		return
+	}
+	b.Offset += int32(first)
+	b.BucketCounts = counts[first:last]
+}
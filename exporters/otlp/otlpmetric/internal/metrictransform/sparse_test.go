@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrictransform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+func histogramMetrics(temporality metricpb.AggregationTemporality, bounds []float64, counts ...uint64) *metricpb.ResourceMetrics {
+	return &metricpb.ResourceMetrics{
+		ScopeMetrics: []*metricpb.ScopeMetrics{{
+			Metrics: []*metricpb.Metric{{
+				Data: &metricpb.Metric_Histogram{
+					Histogram: &metricpb.Histogram{
+						AggregationTemporality: temporality,
+						DataPoints: []*metricpb.HistogramDataPoint{{
+							ExplicitBounds: bounds,
+							BucketCounts:   counts,
+						}},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+func TestTrimDeltaHistograms(t *testing.T) {
+	bounds := []float64{1, 2, 4, 8, 16}
+	for _, tc := range []struct {
+		name       string
+		counts     []uint64
+		wantBounds []float64
+		wantCounts []uint64
+	}{
+		{
+			name:       "both ends",
+			counts:     []uint64{0, 0, 1, 3, 0, 0},
+			wantBounds: []float64{4},
+			wantCounts: []uint64{1, 3},
+		},
+		{
+			name:       "inner zeros are kept",
+			counts:     []uint64{0, 2, 0, 0, 5, 0},
+			wantBounds: []float64{2, 4, 8},
+			wantCounts: []uint64{2, 0, 0, 5},
+		},
+		{
+			name:       "full",
+			counts:     []uint64{1, 0, 0, 0, 0, 1},
+			wantBounds: bounds,
+			wantCounts: []uint64{1, 0, 0, 0, 0, 1},
+		},
+		{
+			name:       "single bucket",
+			counts:     []uint64{0, 0, 0, 7, 0, 0},
+			wantBounds: []float64{},
+			wantCounts: []uint64{7},
+		},
+		{
+			name:       "empty",
+			counts:     []uint64{0, 0, 0, 0, 0, 0},
+			wantBounds: []float64{},
+			wantCounts: []uint64{0},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rm := histogramMetrics(otelDelta, bounds, tc.counts...)
+			TrimDeltaHistograms(rm)
+			assert.Equal(t, histogramMetrics(otelDelta, tc.wantBounds, tc.wantCounts...), rm)
+		})
+	}
+
+	rm := histogramMetrics(otelCumulative, bounds, 0, 0, 1, 3, 0, 0)
+	TrimDeltaHistograms(rm)
+	assert.Equal(t, histogramMetrics(otelCumulative, bounds, 0, 0, 1, 3, 0, 0), rm)
+}
+
+func TestTrimDeltaExponentialHistograms(t *testing.T) {
+	point := &metricpb.ExponentialHistogramDataPoint{
+		Positive: &metricpb.ExponentialHistogramDataPoint_Buckets{
+			Offset:       -2,
+			BucketCounts: []uint64{0, 0, 1, 0, 4, 0},
+		},
+		Negative: &metricpb.ExponentialHistogramDataPoint_Buckets{
+			Offset:       3,
+			BucketCounts: []uint64{0, 0},
+		},
+	}
+	rm := &metricpb.ResourceMetrics{
+		ScopeMetrics: []*metricpb.ScopeMetrics{{
+			Metrics: []*metricpb.Metric{{
+				Data: &metricpb.Metric_ExponentialHistogram{
+					ExponentialHistogram: &metricpb.ExponentialHistogram{
+						AggregationTemporality: otelDelta,
+						DataPoints:             []*metricpb.ExponentialHistogramDataPoint{point},
+					},
+				},
+			}},
+		}},
+	}
+	TrimDeltaHistograms(rm)
+	assert.Equal(t, &metricpb.ExponentialHistogramDataPoint_Buckets{
+		BucketCounts: []uint64{1, 0, 4},
+	}, point.Positive)
+	assert.Equal(t, &metricpb.ExponentialHistogramDataPoint_Buckets{}, point.Negative)
+}
@@ -24,6 +24,7 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
 
 	"go.opentelemetry.io/otel/exporters/otlp/internal"
 	"go.opentelemetry.io/otel/exporters/otlp/internal/retry"
@@ -65,11 +66,19 @@ type (
 
 		RetryConfig retry.Config
 
+		// MaxConcurrentExports limits the exports in flight at once,
+		// zero means no limit.
+		MaxConcurrentExports int
+
+		// HTTP configurations
+		ConnectionPool ConnectionPool
+
 		// gRPC configurations
 		ReconnectionPeriod time.Duration
 		ServiceConfig      string
 		DialOptions        []grpc.DialOption
 		GRPCConn           *grpc.ClientConn
+		Keepalive          *keepalive.ClientParameters
 	}
 )
 
@@ -137,6 +146,9 @@ func NewGRPCConfig(opts ...GRPCOption) Config {
 		}
 		cfg.DialOptions = append(cfg.DialOptions, grpc.WithConnectParams(p))
 	}
+	if cfg.Keepalive != nil {
+		cfg.DialOptions = append(cfg.DialOptions, grpc.WithKeepaliveParams(*cfg.Keepalive))
+	}
 
 	return cfg
 }
@@ -312,3 +324,10 @@ func WithTimeout(duration time.Duration) GenericOption {
 		return cfg
 	})
 }
+
+func WithMaxConcurrentExports(n int) GenericOption {
+	return newGenericOption(func(cfg Config) Config {
+		cfg.MaxConcurrentExports = n
+		return cfg
+	})
+}
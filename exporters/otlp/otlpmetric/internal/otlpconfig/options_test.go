@@ -383,6 +383,23 @@ func TestConfigs(t *testing.T) {
 				assert.Equal(t, c.Metrics.Timeout, 5*time.Second)
 			},
 		},
+
+		// Concurrency Tests
+		{
+			name: "Test Default Max Concurrent Exports",
+			asserts: func(t *testing.T, c *otlpconfig.Config, grpcOption bool) {
+				assert.Equal(t, 0, c.MaxConcurrentExports)
+			},
+		},
+		{
+			name: "Test With Max Concurrent Exports",
+			opts: []otlpconfig.GenericOption{
+				otlpconfig.WithMaxConcurrentExports(4),
+			},
+			asserts: func(t *testing.T, c *otlpconfig.Config, grpcOption bool) {
+				assert.Equal(t, 4, c.MaxConcurrentExports)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -53,3 +53,18 @@ type RetrySettings struct {
 	// Once this value is reached, the data is discarded.
 	MaxElapsedTime time.Duration
 }
+
+// ConnectionPool configures the pool of connections the HTTP driver keeps
+// to the collector.  Zero fields keep the defaults of the driver.
+type ConnectionPool struct {
+	// MaxIdleConns limits the idle connections kept across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost limits the idle connections kept to the
+	// collector.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the connections to the collector, including
+	// those in use.  Requests beyond the limit wait for a connection.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept.
+	IdleConnTimeout time.Duration
+}
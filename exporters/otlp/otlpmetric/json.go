@@ -0,0 +1,559 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetric // import "go.opentelemetry.io/otel/exporters/otlp/otlpmetric"
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// marshalJSON encodes req in the OTLP/JSON format without going through
+// protobuf reflection.  Field names are lowerCamelCase, 64-bit integers
+// are quoted, enums are written as integers and fields holding their
+// default value are omitted, so the output is accepted by any OTLP/JSON
+// receiver as well as by protojson.Unmarshal.  Byte fields are base64
+// encoded the same way protojson encodes them.
+func marshalJSON(req *colmetricpb.ExportMetricsServiceRequest) ([]byte, error) {
+	e := jsonEncoder{buf: make([]byte, 0, 1024)}
+	e.open()
+	if len(req.GetResourceMetrics()) != 0 {
+		e.field("resourceMetrics")
+		e.buf = append(e.buf, '[')
+		for i, rm := range req.GetResourceMetrics() {
+			e.elem(i)
+			e.resourceMetrics(rm)
+		}
+		e.buf = append(e.buf, ']')
+	}
+	e.close()
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.buf, nil
+}
+
+// jsonEncoder appends OTLP/JSON to buf.  first tracks whether the object
+// being written has had a field yet.
+type jsonEncoder struct {
+	buf   []byte
+	first bool
+	err   error
+}
+
+func (e *jsonEncoder) open() {
+	e.buf = append(e.buf, '{')
+	e.first = true
+}
+
+func (e *jsonEncoder) close() {
+	e.buf = append(e.buf, '}')
+	e.first = false
+}
+
+func (e *jsonEncoder) elem(i int) {
+	if i > 0 {
+		e.buf = append(e.buf, ',')
+	}
+}
+
+func (e *jsonEncoder) field(name string) {
+	if !e.first {
+		e.buf = append(e.buf, ',')
+	}
+	e.first = false
+	e.buf = append(e.buf, '"')
+	e.buf = append(e.buf, name...)
+	e.buf = append(e.buf, '"', ':')
+}
+
+func (e *jsonEncoder) stringField(name, v string) {
+	if v == "" {
+		return
+	}
+	e.field(name)
+	e.string(v)
+}
+
+func (e *jsonEncoder) uint32Field(name string, v uint32) {
+	if v == 0 {
+		return
+	}
+	e.field(name)
+	e.buf = strconv.AppendUint(e.buf, uint64(v), 10)
+}
+
+func (e *jsonEncoder) int32Field(name string, v int32) {
+	if v == 0 {
+		return
+	}
+	e.field(name)
+	e.buf = strconv.AppendInt(e.buf, int64(v), 10)
+}
+
+func (e *jsonEncoder) uint64Field(name string, v uint64) {
+	if v == 0 {
+		return
+	}
+	e.field(name)
+	e.uint64(v)
+}
+
+func (e *jsonEncoder) doubleField(name string, v float64) {
+	if v == 0 && !math.Signbit(v) {
+		return
+	}
+	e.field(name)
+	e.double(v)
+}
+
+func (e *jsonEncoder) boolField(name string, v bool) {
+	if !v {
+		return
+	}
+	e.field(name)
+	e.buf = strconv.AppendBool(e.buf, v)
+}
+
+func (e *jsonEncoder) bytesField(name string, v []byte) {
+	if len(v) == 0 {
+		return
+	}
+	e.field(name)
+	e.bytes(v)
+}
+
+func (e *jsonEncoder) bytes(v []byte) {
+	e.buf = append(e.buf, '"')
+	n := len(e.buf)
+	e.buf = append(e.buf, make([]byte, base64.StdEncoding.EncodedLen(len(v)))...)
+	base64.StdEncoding.Encode(e.buf[n:], v)
+	e.buf = append(e.buf, '"')
+}
+
+func (e *jsonEncoder) uint64(v uint64) {
+	e.buf = append(e.buf, '"')
+	e.buf = strconv.AppendUint(e.buf, v, 10)
+	e.buf = append(e.buf, '"')
+}
+
+func (e *jsonEncoder) int64(v int64) {
+	e.buf = append(e.buf, '"')
+	e.buf = strconv.AppendInt(e.buf, v, 10)
+	e.buf = append(e.buf, '"')
+}
+
+func (e *jsonEncoder) double(v float64) {
+	switch {
+	case math.IsNaN(v):
+		e.buf = append(e.buf, `"NaN"`...)
+	case math.IsInf(v, 1):
+		e.buf = append(e.buf, `"Infinity"`...)
+	case math.IsInf(v, -1):
+		e.buf = append(e.buf, `"-Infinity"`...)
+	default:
+		e.buf = strconv.AppendFloat(e.buf, v, 'g', -1, 64)
+	}
+}
+
+const hex = "0123456789abcdef"
+
+// string appends s as a JSON string.  Invalid UTF-8 is replaced with
+// U+FFFD rather than failing the whole export.
+func (e *jsonEncoder) string(s string) {
+	e.buf = append(e.buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			e.buf = append(e.buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				e.buf = append(e.buf, '\\', c)
+			case '\n':
+				e.buf = append(e.buf, '\\', 'n')
+			case '\r':
+				e.buf = append(e.buf, '\\', 'r')
+			case '\t':
+				e.buf = append(e.buf, '\\', 't')
+			default:
+				e.buf = append(e.buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			e.buf = append(e.buf, s[start:i]...)
+			e.buf = append(e.buf, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	e.buf = append(e.buf, s[start:]...)
+	e.buf = append(e.buf, '"')
+}
+
+func (e *jsonEncoder) resourceMetrics(rm *metricpb.ResourceMetrics) {
+	e.open()
+	if r := rm.GetResource(); r != nil {
+		e.field("resource")
+		e.resource(r)
+	}
+	if len(rm.GetScopeMetrics()) != 0 {
+		e.field("scopeMetrics")
+		e.buf = append(e.buf, '[')
+		for i, sm := range rm.GetScopeMetrics() {
+			e.elem(i)
+			e.scopeMetrics(sm)
+		}
+		e.buf = append(e.buf, ']')
+	}
+	e.stringField("schemaUrl", rm.GetSchemaUrl())
+	e.close()
+}
+
+func (e *jsonEncoder) resource(r *resourcepb.Resource) {
+	e.open()
+	e.attributes("attributes", r.GetAttributes())
+	e.uint32Field("droppedAttributesCount", r.GetDroppedAttributesCount())
+	e.close()
+}
+
+func (e *jsonEncoder) scopeMetrics(sm *metricpb.ScopeMetrics) {
+	e.open()
+	if s := sm.GetScope(); s != nil {
+		e.field("scope")
+		e.open()
+		e.stringField("name", s.GetName())
+		e.stringField("version", s.GetVersion())
+		e.close()
+	}
+	if len(sm.GetMetrics()) != 0 {
+		e.field("metrics")
+		e.buf = append(e.buf, '[')
+		for i, m := range sm.GetMetrics() {
+			e.elem(i)
+			e.metric(m)
+		}
+		e.buf = append(e.buf, ']')
+	}
+	e.stringField("schemaUrl", sm.GetSchemaUrl())
+	e.close()
+}
+
+func (e *jsonEncoder) metric(m *metricpb.Metric) {
+	e.open()
+	e.stringField("name", m.GetName())
+	e.stringField("description", m.GetDescription())
+	e.stringField("unit", m.GetUnit())
+	switch d := m.GetData().(type) {
+	case nil:
+	case *metricpb.Metric_Gauge:
+		e.field("gauge")
+		e.open()
+		e.numberDataPoints(d.Gauge.GetDataPoints())
+		e.close()
+	case *metricpb.Metric_Sum:
+		e.field("sum")
+		e.open()
+		e.numberDataPoints(d.Sum.GetDataPoints())
+		e.temporality(d.Sum.GetAggregationTemporality())
+		e.boolField("isMonotonic", d.Sum.GetIsMonotonic())
+		e.close()
+	case *metricpb.Metric_Histogram:
+		e.field("histogram")
+		e.open()
+		if pts := d.Histogram.GetDataPoints(); len(pts) != 0 {
+			e.field("dataPoints")
+			e.buf = append(e.buf, '[')
+			for i, pt := range pts {
+				e.elem(i)
+				e.histogramDataPoint(pt)
+			}
+			e.buf = append(e.buf, ']')
+		}
+		e.temporality(d.Histogram.GetAggregationTemporality())
+		e.close()
+	case *metricpb.Metric_ExponentialHistogram:
+		e.field("exponentialHistogram")
+		e.open()
+		if pts := d.ExponentialHistogram.GetDataPoints(); len(pts) != 0 {
+			e.field("dataPoints")
+			e.buf = append(e.buf, '[')
+			for i, pt := range pts {
+				e.elem(i)
+				e.exponentialHistogramDataPoint(pt)
+			}
+			e.buf = append(e.buf, ']')
+		}
+		e.temporality(d.ExponentialHistogram.GetAggregationTemporality())
+		e.close()
+	case *metricpb.Metric_Summary:
+		e.field("summary")
+		e.open()
+		if pts := d.Summary.GetDataPoints(); len(pts) != 0 {
+			e.field("dataPoints")
+			e.buf = append(e.buf, '[')
+			for i, pt := range pts {
+				e.elem(i)
+				e.summaryDataPoint(pt)
+			}
+			e.buf = append(e.buf, ']')
+		}
+		e.close()
+	default:
+		e.fail(d)
+	}
+	e.close()
+}
+
+func (e *jsonEncoder) temporality(t metricpb.AggregationTemporality) {
+	e.int32Field("aggregationTemporality", int32(t))
+}
+
+func (e *jsonEncoder) timestamps(start, end uint64) {
+	e.uint64Field("startTimeUnixNano", start)
+	e.uint64Field("timeUnixNano", end)
+}
+
+func (e *jsonEncoder) numberDataPoints(pts []*metricpb.NumberDataPoint) {
+	if len(pts) == 0 {
+		return
+	}
+	e.field("dataPoints")
+	e.buf = append(e.buf, '[')
+	for i, pt := range pts {
+		e.elem(i)
+		e.open()
+		e.attributes("attributes", pt.GetAttributes())
+		e.timestamps(pt.GetStartTimeUnixNano(), pt.GetTimeUnixNano())
+		switch v := pt.GetValue().(type) {
+		case nil:
+		case *metricpb.NumberDataPoint_AsDouble:
+			e.field("asDouble")
+			e.double(v.AsDouble)
+		case *metricpb.NumberDataPoint_AsInt:
+			e.field("asInt")
+			e.int64(v.AsInt)
+		default:
+			e.fail(v)
+		}
+		e.exemplars(pt.GetExemplars())
+		e.uint32Field("flags", pt.GetFlags())
+		e.close()
+	}
+	e.buf = append(e.buf, ']')
+}
+
+func (e *jsonEncoder) histogramDataPoint(pt *metricpb.HistogramDataPoint) {
+	e.open()
+	e.attributes("attributes", pt.GetAttributes())
+	e.timestamps(pt.GetStartTimeUnixNano(), pt.GetTimeUnixNano())
+	e.uint64Field("count", pt.GetCount())
+	if pt.Sum != nil {
+		e.field("sum")
+		e.double(*pt.Sum)
+	}
+	e.uint64s("bucketCounts", pt.GetBucketCounts())
+	if len(pt.GetExplicitBounds()) != 0 {
+		e.field("explicitBounds")
+		e.buf = append(e.buf, '[')
+		for i, b := range pt.GetExplicitBounds() {
+			e.elem(i)
+			e.double(b)
+		}
+		e.buf = append(e.buf, ']')
+	}
+	e.exemplars(pt.GetExemplars())
+	e.uint32Field("flags", pt.GetFlags())
+	e.close()
+}
+
+func (e *jsonEncoder) exponentialHistogramDataPoint(pt *metricpb.ExponentialHistogramDataPoint) {
+	e.open()
+	e.attributes("attributes", pt.GetAttributes())
+	e.timestamps(pt.GetStartTimeUnixNano(), pt.GetTimeUnixNano())
+	e.uint64Field("count", pt.GetCount())
+	e.doubleField("sum", pt.GetSum())
+	e.int32Field("scale", pt.GetScale())
+	e.uint64Field("zeroCount", pt.GetZeroCount())
+	e.buckets("positive", pt.GetPositive())
+	e.buckets("negative", pt.GetNegative())
+	e.uint32Field("flags", pt.GetFlags())
+	e.exemplars(pt.GetExemplars())
+	e.close()
+}
+
+func (e *jsonEncoder) buckets(name string, b *metricpb.ExponentialHistogramDataPoint_Buckets) {
+	if b == nil {
+		return
+	}
+	e.field(name)
+	e.open()
+	e.int32Field("offset", b.GetOffset())
+	e.uint64s("bucketCounts", b.GetBucketCounts())
+	e.close()
+}
+
+func (e *jsonEncoder) summaryDataPoint(pt *metricpb.SummaryDataPoint) {
+	e.open()
+	e.attributes("attributes", pt.GetAttributes())
+	e.timestamps(pt.GetStartTimeUnixNano(), pt.GetTimeUnixNano())
+	e.uint64Field("count", pt.GetCount())
+	e.doubleField("sum", pt.GetSum())
+	if len(pt.GetQuantileValues()) != 0 {
+		e.field("quantileValues")
+		e.buf = append(e.buf, '[')
+		for i, q := range pt.GetQuantileValues() {
+			e.elem(i)
+			e.open()
+			e.doubleField("quantile", q.GetQuantile())
+			e.doubleField("value", q.GetValue())
+			e.close()
+		}
+		e.buf = append(e.buf, ']')
+	}
+	e.uint32Field("flags", pt.GetFlags())
+	e.close()
+}
+
+func (e *jsonEncoder) exemplars(exs []*metricpb.Exemplar) {
+	if len(exs) == 0 {
+		return
+	}
+	e.field("exemplars")
+	e.buf = append(e.buf, '[')
+	for i, ex := range exs {
+		e.elem(i)
+		e.open()
+		e.attributes("filteredAttributes", ex.GetFilteredAttributes())
+		e.uint64Field("timeUnixNano", ex.GetTimeUnixNano())
+		switch v := ex.GetValue().(type) {
+		case nil:
+		case *metricpb.Exemplar_AsDouble:
+			e.field("asDouble")
+			e.double(v.AsDouble)
+		case *metricpb.Exemplar_AsInt:
+			e.field("asInt")
+			e.int64(v.AsInt)
+		default:
+			e.fail(v)
+		}
+		e.bytesField("spanId", ex.GetSpanId())
+		e.bytesField("traceId", ex.GetTraceId())
+		e.close()
+	}
+	e.buf = append(e.buf, ']')
+}
+
+func (e *jsonEncoder) uint64s(name string, vs []uint64) {
+	if len(vs) == 0 {
+		return
+	}
+	e.field(name)
+	e.buf = append(e.buf, '[')
+	for i, v := range vs {
+		e.elem(i)
+		e.uint64(v)
+	}
+	e.buf = append(e.buf, ']')
+}
+
+func (e *jsonEncoder) attributes(name string, kvs []*commonpb.KeyValue) {
+	if len(kvs) == 0 {
+		return
+	}
+	e.field(name)
+	e.keyValues(kvs)
+}
+
+func (e *jsonEncoder) keyValues(kvs []*commonpb.KeyValue) {
+	e.buf = append(e.buf, '[')
+	for i, kv := range kvs {
+		e.elem(i)
+		e.open()
+		e.stringField("key", kv.GetKey())
+		if v := kv.GetValue(); v != nil {
+			e.field("value")
+			e.anyValue(v)
+		}
+		e.close()
+	}
+	e.buf = append(e.buf, ']')
+}
+
+func (e *jsonEncoder) anyValue(v *commonpb.AnyValue) {
+	e.open()
+	switch v := v.GetValue().(type) {
+	case nil:
+	case *commonpb.AnyValue_StringValue:
+		e.field("stringValue")
+		e.string(v.StringValue)
+	case *commonpb.AnyValue_BoolValue:
+		e.field("boolValue")
+		e.buf = strconv.AppendBool(e.buf, v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		e.field("intValue")
+		e.int64(v.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		e.field("doubleValue")
+		e.double(v.DoubleValue)
+	case *commonpb.AnyValue_ArrayValue:
+		e.field("arrayValue")
+		e.open()
+		if vs := v.ArrayValue.GetValues(); len(vs) != 0 {
+			e.field("values")
+			e.buf = append(e.buf, '[')
+			for i, av := range vs {
+				e.elem(i)
+				e.anyValue(av)
+			}
+			e.buf = append(e.buf, ']')
+		}
+		e.close()
+	case *commonpb.AnyValue_KvlistValue:
+		e.field("kvlistValue")
+		e.open()
+		e.attributes("values", v.KvlistValue.GetValues())
+		e.close()
+	case *commonpb.AnyValue_BytesValue:
+		e.field("bytesValue")
+		e.bytes(v.BytesValue)
+	default:
+		e.fail(v)
+	}
+	e.close()
+}
+
+func (e *jsonEncoder) fail(v interface{}) {
+	if e.err == nil {
+		e.err = fmt.Errorf("otlp-json: unsupported value %T", v)
+	}
+}
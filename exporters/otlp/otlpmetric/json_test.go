@@ -0,0 +1,194 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetric
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+func str(k, v string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: k, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}}
+}
+
+func jsonTestRequest() *colmetricpb.ExportMetricsServiceRequest {
+	sum := 12.5
+	attrs := []*commonpb.KeyValue{
+		str("host", "test.com"),
+		str("quote", "a \"b\" \\ c\n\t\x01 é \xff"),
+		{Key: "bool", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: true}}},
+		{Key: "int", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: math.MinInt64}}},
+		{Key: "double", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: 1e-300}}},
+		{Key: "zero", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{}}},
+		{Key: "bytes", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: []byte{0, 1, 254}}}},
+		{Key: "array", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{
+			Values: []*commonpb.AnyValue{
+				{Value: &commonpb.AnyValue_StringValue{StringValue: "x"}},
+				{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: math.Inf(-1)}},
+			},
+		}}}},
+		{Key: "kvlist", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{
+			Values: []*commonpb.KeyValue{str("nested", "y")},
+		}}}},
+	}
+	return &colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricpb.ResourceMetrics{{
+			Resource:  &resourcepb.Resource{Attributes: []*commonpb.KeyValue{str("service.name", "test")}, DroppedAttributesCount: 2},
+			SchemaUrl: "https://opentelemetry.io/schemas/1.7.0",
+			ScopeMetrics: []*metricpb.ScopeMetrics{{
+				Scope: &commonpb.InstrumentationScope{Name: "lib", Version: "v1"},
+				Metrics: []*metricpb.Metric{
+					{
+						Name:        "sum",
+						Description: "a sum",
+						Unit:        "ms",
+						Data: &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+							AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+							IsMonotonic:            true,
+							DataPoints: []*metricpb.NumberDataPoint{{
+								Attributes:        attrs,
+								StartTimeUnixNano: 1,
+								TimeUnixNano:      math.MaxUint64,
+								Value:             &metricpb.NumberDataPoint_AsInt{AsInt: 0},
+								Exemplars: []*metricpb.Exemplar{{
+									FilteredAttributes: []*commonpb.KeyValue{str("user", "u")},
+									TimeUnixNano:       5,
+									Value:              &metricpb.Exemplar_AsDouble{AsDouble: 3},
+									SpanId:             []byte{1, 2, 3, 4, 5, 6, 7, 8},
+									TraceId:            []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+								}},
+							}},
+						}},
+					},
+					{
+						Name: "gauge",
+						Data: &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{
+							DataPoints: []*metricpb.NumberDataPoint{
+								{TimeUnixNano: 2, Value: &metricpb.NumberDataPoint_AsDouble{AsDouble: math.NaN()}},
+								{TimeUnixNano: 3, Value: &metricpb.NumberDataPoint_AsDouble{AsDouble: 1.5e21}, Flags: 1},
+							},
+						}},
+					},
+					{
+						Name: "histogram",
+						Data: &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{
+							AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+							DataPoints: []*metricpb.HistogramDataPoint{{
+								StartTimeUnixNano: 1,
+								TimeUnixNano:      2,
+								Count:             3,
+								Sum:               &sum,
+								BucketCounts:      []uint64{1, 0, 2},
+								ExplicitBounds:    []float64{-1, 10.25},
+							}},
+						}},
+					},
+					{
+						Name: "exponential",
+						Data: &metricpb.Metric_ExponentialHistogram{ExponentialHistogram: &metricpb.ExponentialHistogram{
+							AggregationTemporality: metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+							DataPoints: []*metricpb.ExponentialHistogramDataPoint{{
+								Count:     4,
+								Sum:       -2,
+								Scale:     -3,
+								ZeroCount: 1,
+								Positive:  &metricpb.ExponentialHistogramDataPoint_Buckets{Offset: -2, BucketCounts: []uint64{1, 1}},
+								Negative:  &metricpb.ExponentialHistogramDataPoint_Buckets{},
+							}},
+						}},
+					},
+					{
+						Name: "summary",
+						Data: &metricpb.Metric_Summary{Summary: &metricpb.Summary{
+							DataPoints: []*metricpb.SummaryDataPoint{{
+								Count: 2,
+								Sum:   4,
+								QuantileValues: []*metricpb.SummaryDataPoint_ValueAtQuantile{
+									{Quantile: 0, Value: 1},
+									{Quantile: 1, Value: 3},
+								},
+							}},
+						}},
+					},
+					{Name: "empty"},
+				},
+			}},
+		}},
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	want := jsonTestRequest()
+	payload, err := marshalJSON(want)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(payload), "invalid JSON: %s", payload)
+
+	got := &colmetricpb.ExportMetricsServiceRequest{}
+	require.NoError(t, protojson.Unmarshal(payload, got))
+	// Invalid UTF-8 is replaced rather than preserved.
+	want.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].GetSum().DataPoints[0].Attributes[1] = str("quote", "a \"b\" \\ c\n\t\x01 é \ufffd")
+	if diff := cmp.Diff(want, got, protocmp.Transform(), cmpopts.EquateNaNs()); diff != "" {
+		t.Fatalf("decoded request differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestMarshalJSONEmpty(t *testing.T) {
+	payload, err := marshalJSON(&colmetricpb.ExportMetricsServiceRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(payload))
+}
+
+func TestMarshalJSONEnumsAsIntegers(t *testing.T) {
+	payload, err := marshalJSON(jsonTestRequest())
+	require.NoError(t, err)
+	assert.Contains(t, string(payload), `"aggregationTemporality":2`)
+	assert.Contains(t, string(payload), `"timeUnixNano":"18446744073709551615"`)
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	req := jsonTestRequest()
+	// Drop the invalid UTF-8 value, which protojson refuses to encode.
+	req.ResourceMetrics[0].ScopeMetrics[0].Metrics[0].GetSum().DataPoints[0].Attributes[1] = str("quote", "a")
+
+	b.Run("Native", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := marshalJSON(req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Protojson", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := protojson.Marshal(req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -29,6 +29,8 @@ func (fn exporterOptionFunc) apply(cfg config) config {
 
 type config struct {
 	temporalitySelector aggregation.TemporalitySelector
+	dedupAttributes     bool
+	sparseHistograms    bool
 }
 
 // WithMetricAggregationTemporalitySelector defines the aggregation.TemporalitySelector used
@@ -41,3 +43,31 @@ func WithMetricAggregationTemporalitySelector(selector aggregation.TemporalitySe
 		return cfg
 	})
 }
+
+// WithAttributeDeduplication enables sharing the transformed attributes
+// of data points that have equal attribute sets within one export.  This
+// reduces the CPU and memory used to export large batches in which
+// attribute sets repeat across metrics, at the cost of a lookup per data
+// point.
+func WithAttributeDeduplication() Option {
+	return exporterOptionFunc(func(cfg config) config {
+		cfg.dedupAttributes = true
+		return cfg
+	})
+}
+
+// WithSparseDeltaHistograms enables omitting the empty buckets below the
+// lowest and above the highest non-empty bucket of delta histograms.
+// Explicit-boundary histograms are exported without the boundaries of
+// the omitted buckets, so that their first and last buckets extend to
+// the infinities, and exponential histograms with the offset of their
+// first non-empty bucket.  This reduces the size of exports for
+// histograms with many buckets and narrow traffic, at the cost of
+// consumers not seeing the full bucket layout in every export.
+// Cumulative histograms are always exported with all their buckets.
+func WithSparseDeltaHistograms() Option {
+	return exporterOptionFunc(func(cfg config) config {
+		cfg.sparseHistograms = true
+		return cfg
+	})
+}
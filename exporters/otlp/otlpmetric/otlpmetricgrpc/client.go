@@ -54,6 +54,10 @@ type client struct {
 	conn    *grpc.ClientConn
 	mscMu   sync.RWMutex
 	msc     colmetricpb.MetricsServiceClient
+
+	// inflight holds a token for each export in flight when the
+	// concurrent exports are limited, otherwise it is nil.
+	inflight chan struct{}
 }
 
 // Compile time check *client implements otlpmetric.Client.
@@ -82,6 +86,9 @@ func newClient(opts ...Option) *client {
 	if len(cfg.Metrics.Headers) > 0 {
 		c.metadata = metadata.New(cfg.Metrics.Headers)
 	}
+	if cfg.MaxConcurrentExports > 0 {
+		c.inflight = make(chan struct{}, cfg.MaxConcurrentExports)
+	}
 
 	return c
 }
@@ -195,6 +202,15 @@ func (c *client) UploadMetrics(ctx context.Context, protoMetrics *metricpb.Resou
 	ctx, cancel := c.exportContext(ctx)
 	defer cancel()
 
+	if c.inflight != nil {
+		select {
+		case c.inflight <- struct{}{}:
+			defer func() { <-c.inflight }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
 	return c.requestFunc(ctx, func(iCtx context.Context) error {
 		_, err := c.msc.Export(iCtx, &colmetricpb.ExportMetricsServiceRequest{
 			ResourceMetrics: []*metricpb.ResourceMetrics{protoMetrics},
@@ -209,7 +225,8 @@ func (c *client) UploadMetrics(ctx context.Context, protoMetrics *metricpb.Resou
 }
 
 // exportContext returns a copy of parent with an appropriate deadline and
-// cancellation function.
+// cancellation function.  The outgoing metadata of parent is sent along
+// with the configured headers.
 //
 // It is the callers responsibility to cancel the returned context once its
 // use is complete, via the parent or directly with the returned CancelFunc, to
@@ -227,7 +244,17 @@ func (c *client) exportContext(parent context.Context) (context.Context, context
 	}
 
 	if c.metadata.Len() > 0 {
-		ctx = metadata.NewOutgoingContext(ctx, c.metadata)
+		md := c.metadata
+		// Outgoing metadata set by the caller, for example to route
+		// an on-demand flush to a different tenant, takes precedence
+		// over the configured headers.
+		if callerMD, ok := metadata.FromOutgoingContext(parent); ok {
+			md = c.metadata.Copy()
+			for k, v := range callerMD {
+				md[k] = v
+			}
+		}
+		ctx = metadata.NewOutgoingContext(ctx, md)
 	}
 
 	// Unify the client stopCtx with the parent.
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,6 +28,8 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric"
@@ -330,3 +333,63 @@ func TestFailedMetricTransform(t *testing.T) {
 
 	assert.Error(t, exp.Export(ctx, testResource, otlpmetrictest.FailReader{}))
 }
+
+func TestExporterContextMetadata(t *testing.T) {
+	mc := runMockCollector(t)
+	defer func() {
+		_ = mc.stop()
+	}()
+
+	ctx := context.Background()
+	exp := newGRPCExporter(t, ctx, mc.endpoint,
+		otlpmetricgrpc.WithHeaders(map[string]string{
+			"header1":  "value1",
+			"x-tenant": "default",
+		}))
+	defer func() {
+		_ = exp.Shutdown(ctx)
+	}()
+
+	flushCtx := metadata.AppendToOutgoingContext(ctx, "x-tenant", "flush", "x-reason", "on-demand")
+	require.NoError(t, exp.Export(flushCtx, testResource, oneRecord))
+
+	headers := mc.getHeaders()
+	assert.Equal(t, []string{"value1"}, headers.Get("header1"))
+	assert.Equal(t, []string{"flush"}, headers.Get("x-tenant"))
+	assert.Equal(t, []string{"on-demand"}, headers.Get("x-reason"))
+
+	require.NoError(t, exp.Export(ctx, testResource, oneRecord))
+	headers = mc.getHeaders()
+	assert.Equal(t, []string{"default"}, headers.Get("x-tenant"))
+	assert.Empty(t, headers.Get("x-reason"))
+}
+
+func TestNewExporterWithMaxConcurrentExports(t *testing.T) {
+	const exports = 3
+	mc := runMockCollector(t)
+	defer func() {
+		_ = mc.stop()
+	}()
+	mc.metricSvc.delay = 50 * time.Millisecond
+
+	ctx := context.Background()
+	exp := newGRPCExporter(t, ctx, mc.endpoint,
+		otlpmetricgrpc.WithMaxConcurrentExports(1),
+		otlpmetricgrpc.WithKeepalive(keepalive.ClientParameters{Time: time.Minute}))
+	defer func() {
+		_ = exp.Shutdown(ctx)
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(exports)
+	for i := 0; i < exports; i++ {
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, exp.Export(ctx, testResource, oneRecord))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, mc.metricSvc.getMaxInFlight())
+	assert.Len(t, mc.getMetrics(), exports)
+}
@@ -50,6 +50,9 @@ type mockMetricService struct {
 	mu      sync.RWMutex
 	storage otlpmetrictest.MetricsStorage
 	delay   time.Duration
+
+	inFlight    int
+	maxInFlight int
 }
 
 func (mms *mockMetricService) getHeaders() metadata.MD {
@@ -64,7 +67,25 @@ func (mms *mockMetricService) getMetrics() []*metricpb.Metric {
 	return mms.storage.GetMetrics()
 }
 
+func (mms *mockMetricService) getMaxInFlight() int {
+	mms.mu.RLock()
+	defer mms.mu.RUnlock()
+	return mms.maxInFlight
+}
+
 func (mms *mockMetricService) Export(ctx context.Context, exp *collectormetricpb.ExportMetricsServiceRequest) (*collectormetricpb.ExportMetricsServiceResponse, error) {
+	mms.mu.Lock()
+	mms.inFlight++
+	if mms.inFlight > mms.maxInFlight {
+		mms.maxInFlight = mms.inFlight
+	}
+	mms.mu.Unlock()
+	defer func() {
+		mms.mu.Lock()
+		mms.inFlight--
+		mms.mu.Unlock()
+	}()
+
 	if mms.delay > 0 {
 		time.Sleep(mms.delay)
 	}
@@ -20,6 +20,7 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/internal/retry"
@@ -106,6 +107,10 @@ func WithCompressor(compressor string) Option {
 }
 
 // WithHeaders will send the provided headers with each gRPC requests.
+//
+// Outgoing gRPC metadata of the context passed to Export, for example
+// added with metadata.AppendToOutgoingContext, is sent as well and takes
+// precedence over these headers for the same key.
 func WithHeaders(headers map[string]string) Option {
 	return wrappedOption{otlpconfig.WithHeaders(headers)}
 }
@@ -188,3 +193,23 @@ func WithTimeout(duration time.Duration) Option {
 func WithRetry(settings RetryConfig) Option {
 	return wrappedOption{otlpconfig.WithRetry(retry.Config(settings))}
 }
+
+// WithMaxConcurrentExports limits the exports sent to the collector at once
+// to n.  Further exports wait until an export in flight completes, or fail
+// when their context is done or the timeout set with WithTimeout expires
+// first.  If unset or zero, exports are not limited.
+func WithMaxConcurrentExports(n int) Option {
+	return wrappedOption{otlpconfig.WithMaxConcurrentExports(n)}
+}
+
+// WithKeepalive sets the keepalive parameters of the connection to the
+// collector, so that idle connections broken by intermediaries are
+// detected.  If unset, no keepalive pings are sent.
+//
+// This option has no effect if WithGRPCConn is used.
+func WithKeepalive(params keepalive.ClientParameters) Option {
+	return wrappedOption{otlpconfig.NewGRPCOption(func(cfg otlpconfig.Config) otlpconfig.Config {
+		cfg.Keepalive = &params
+		return cfg
+	})}
+}
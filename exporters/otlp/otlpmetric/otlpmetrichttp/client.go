@@ -71,6 +71,10 @@ type client struct {
 	client      *http.Client
 	stopCh      chan struct{}
 	stopOnce    sync.Once
+
+	// inflight holds a token for each export in flight when the
+	// concurrent exports are limited, otherwise it is nil.
+	inflight chan struct{}
 }
 
 // NewClient creates a new HTTP metric client.
@@ -81,14 +85,17 @@ func NewClient(opts ...Option) otlpmetric.Client {
 		Transport: ourTransport,
 		Timeout:   cfg.Metrics.Timeout,
 	}
-	if cfg.Metrics.TLSCfg != nil {
+	if cfg.Metrics.TLSCfg != nil || cfg.ConnectionPool != (otlpconfig.ConnectionPool{}) {
 		transport := ourTransport.Clone()
-		transport.TLSClientConfig = cfg.Metrics.TLSCfg
+		if cfg.Metrics.TLSCfg != nil {
+			transport.TLSClientConfig = cfg.Metrics.TLSCfg
+		}
+		applyConnectionPool(transport, cfg.ConnectionPool)
 		httpClient.Transport = transport
 	}
 
 	stopCh := make(chan struct{})
-	return &client{
+	c := &client{
 		name:        "metrics",
 		cfg:         cfg.Metrics,
 		generalCfg:  cfg,
@@ -96,6 +103,26 @@ func NewClient(opts ...Option) otlpmetric.Client {
 		stopCh:      stopCh,
 		client:      httpClient,
 	}
+	if cfg.MaxConcurrentExports > 0 {
+		c.inflight = make(chan struct{}, cfg.MaxConcurrentExports)
+	}
+	return c
+}
+
+// applyConnectionPool sets the non-zero pool settings on transport.
+func applyConnectionPool(transport *http.Transport, pool otlpconfig.ConnectionPool) {
+	if pool.MaxIdleConns > 0 {
+		transport.MaxIdleConns = pool.MaxIdleConns
+	}
+	if pool.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = pool.MaxIdleConnsPerHost
+	}
+	if pool.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = pool.MaxConnsPerHost
+	}
+	if pool.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = pool.IdleConnTimeout
+	}
 }
 
 // Start does nothing in a HTTP client.
@@ -135,6 +162,15 @@ func (d *client) UploadMetrics(ctx context.Context, protoMetrics *metricpb.Resou
 	ctx, cancel := d.contextWithStop(ctx)
 	defer cancel()
 
+	if d.inflight != nil {
+		select {
+		case d.inflight <- struct{}{}:
+			defer func() { <-d.inflight }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
 	request, err := d.newRequest(rawRequest)
 	if err != nil {
 		return err
@@ -18,6 +18,7 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -164,6 +165,80 @@ func TestTimeout(t *testing.T) {
 	assert.Equalf(t, true, os.IsTimeout(err), "expected timeout error, got: %v", err)
 }
 
+func TestMaxConcurrentExports(t *testing.T) {
+	const exports = 3
+	delay := make(chan struct{})
+	mc := runMockCollector(t, mockCollectorConfig{Delay: delay})
+	defer mc.MustStop(t)
+	client := otlpmetrichttp.NewClient(
+		otlpmetrichttp.WithEndpoint(mc.Endpoint()),
+		otlpmetrichttp.WithInsecure(),
+		otlpmetrichttp.WithMaxConcurrentExports(1),
+		otlpmetrichttp.WithConnectionPool(otlpmetrichttp.ConnectionPoolConfig{
+			MaxConnsPerHost: 1,
+		}),
+	)
+	ctx := context.Background()
+	exporter, err := otlpmetric.New(ctx, client)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, exporter.Shutdown(ctx))
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(exports)
+	for i := 0; i < exports; i++ {
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, exporter.Export(ctx, testResource, oneRecord))
+		}()
+	}
+	// Give the exports time to reach the collector if they were not
+	// limited, then release them one at a time.
+	<-time.After(50 * time.Millisecond)
+	for i := 0; i < exports; i++ {
+		delay <- struct{}{}
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, mc.MaxInFlight())
+	assert.Len(t, mc.GetMetrics(), exports)
+}
+
+func TestMaxConcurrentExportsContext(t *testing.T) {
+	delay := make(chan struct{})
+	mc := runMockCollector(t, mockCollectorConfig{Delay: delay})
+	defer mc.MustStop(t)
+	client := otlpmetrichttp.NewClient(
+		otlpmetrichttp.WithEndpoint(mc.Endpoint()),
+		otlpmetrichttp.WithInsecure(),
+		otlpmetrichttp.WithMaxConcurrentExports(1),
+	)
+	ctx := context.Background()
+	exporter, err := otlpmetric.New(ctx, client)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, exporter.Shutdown(ctx))
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, exporter.Export(ctx, testResource, oneRecord))
+	}()
+	<-time.After(50 * time.Millisecond)
+
+	// The second export waits for the first and gives up with its context.
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, exporter.Export(waitCtx, testResource, oneRecord), context.DeadlineExceeded)
+
+	delay <- struct{}{}
+	<-done
+	assert.Equal(t, 1, mc.MaxInFlight())
+	assert.Len(t, mc.GetMetrics(), 1)
+}
+
 func TestEmptyData(t *testing.T) {
 	mcCfg := mockCollectorConfig{}
 	mc := runMockCollector(t, mcCfg)
@@ -48,6 +48,9 @@ type mockCollector struct {
 	injectContentType string
 	delay             <-chan struct{}
 
+	inFlight    int
+	maxInFlight int
+
 	clientTLSConfig *tls.Config
 	expectedHeaders map[string]string
 }
@@ -74,7 +77,26 @@ func (c *mockCollector) ClientTLSConfig() *tls.Config {
 	return c.clientTLSConfig
 }
 
+// MaxInFlight returns the most requests the collector served at once.
+func (c *mockCollector) MaxInFlight() int {
+	c.spanLock.Lock()
+	defer c.spanLock.Unlock()
+	return c.maxInFlight
+}
+
 func (c *mockCollector) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	c.spanLock.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.spanLock.Unlock()
+	defer func() {
+		c.spanLock.Lock()
+		c.inFlight--
+		c.spanLock.Unlock()
+	}()
+
 	if c.delay != nil {
 		select {
 		case <-c.delay:
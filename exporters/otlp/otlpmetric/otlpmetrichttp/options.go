@@ -52,6 +52,10 @@ func asHTTPOptions(opts []Option) []otlpconfig.HTTPOption {
 // failure using an exponential backoff.
 type RetryConfig retry.Config
 
+// ConnectionPoolConfig configures the pool of connections kept to the
+// collector.  Zero fields keep the defaults.
+type ConnectionPoolConfig otlpconfig.ConnectionPool
+
 type wrappedOption struct {
 	otlpconfig.HTTPOption
 }
@@ -183,3 +187,23 @@ func WithTimeout(duration time.Duration) Option {
 func WithRetry(rc RetryConfig) Option {
 	return wrappedOption{otlpconfig.WithRetry(retry.Config(rc))}
 }
+
+// WithMaxConcurrentExports limits the exports sent to the collector at once
+// to n.  Further exports wait until an export in flight completes, or fail
+// when their context is done first.  This bounds the connections opened by
+// bursts of exports, such as concurrent ForceFlush calls.  If unset or zero,
+// exports are not limited.
+func WithMaxConcurrentExports(n int) Option {
+	return wrappedOption{otlpconfig.WithMaxConcurrentExports(n)}
+}
+
+// WithConnectionPool configures the pool of HTTP connections the driver
+// keeps to the collector.  Setting MaxConnsPerHost bounds the file
+// descriptors used by the driver.  If unset, the defaults of the Go
+// http.DefaultTransport are used.
+func WithConnectionPool(pool ConnectionPoolConfig) Option {
+	return wrappedOption{otlpconfig.NewHTTPOption(func(cfg otlpconfig.Config) otlpconfig.Config {
+		cfg.ConnectionPool = otlpconfig.ConnectionPool(pool)
+		return cfg
+	})}
+}
@@ -78,3 +78,11 @@ replace go.opentelemetry.io/otel/example/fib => ../../../../example/fib
 replace go.opentelemetry.io/otel/schema => ../../../../schema
 
 replace go.opentelemetry.io/otel/exporters/otlp/internal/retry => ../../internal/retry
+
+replace go.opentelemetry.io/otel/instrumentation/process => ../../../../instrumentation/process
+
+replace go.opentelemetry.io/otel/bridge/metricshim => ../../../../bridge/metricshim
+
+replace go.opentelemetry.io/otel/bridge/metricshim/gometricsshim => ../../../../bridge/metricshim/gometricsshim
+
+replace go.opentelemetry.io/otel/bridge/metricshim/tallyshim => ../../../../bridge/metricshim/tallyshim
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus // import "go.opentelemetry.io/otel/exporters/prometheus"
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/export/encoding"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func init() {
+	encoding.Register("prometheus", NewTextEncoder())
+}
+
+// NewTextEncoder returns an encoding.Encoder of the Prometheus text
+// exposition format, translating records the same way the Exporter
+// does on scrape.  It is registered as "prometheus".
+//
+// The Prometheus format describes cumulative values, so the encoder
+// should be used with aggregation.CumulativeTemporalitySelector.
+func NewTextEncoder() encoding.Encoder {
+	return textEncoder{}
+}
+
+type textEncoder struct{}
+
+// ContentType implements encoding.Encoder.
+func (textEncoder) ContentType() string {
+	return string(expfmt.FmtText)
+}
+
+// Encode implements encoding.Encoder.
+func (textEncoder) Encode(_ context.Context, res *resource.Resource, reader export.InstrumentationLibraryReader, sel aggregation.TemporalitySelector) ([]byte, error) {
+	rc := &readerCollector{res: res, reader: reader, sel: sel}
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(rc); err != nil {
+		return nil, err
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+	if rc.err != nil {
+		return nil, rc.err
+	}
+
+	var buf bytes.Buffer
+	enc := expfmt.NewEncoder(&buf, expfmt.FmtText)
+	for _, mf := range families {
+		if err := enc.Encode(mf); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// readerCollector implements prometheus.Collector for a single
+// collection.  It describes no metrics, which makes it an unchecked
+// collector.
+type readerCollector struct {
+	res    *resource.Resource
+	reader export.InstrumentationLibraryReader
+	sel    aggregation.TemporalitySelector
+	err    error
+}
+
+var _ prometheus.Collector = (*readerCollector)(nil)
+
+// Describe implements prometheus.Collector.
+func (*readerCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c *readerCollector) Collect(ch chan<- prometheus.Metric) {
+	c.err = collectReader(ch, c.reader, c.res, c.sel, defaultValueEncoder, StaleGaugeLastValue)
+}
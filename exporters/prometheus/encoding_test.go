@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/export/encoding"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestTextEncoder(t *testing.T) {
+	ctx := context.Background()
+	desc := sdkapi.NewDescriptor("requests.total", sdkapi.CounterInstrumentKind, number.Int64Kind, "Number of requests", "")
+	agg := &sum.New(1)[0]
+	require.NoError(t, agg.Update(ctx, number.NewInt64Number(15), &desc))
+	attrs := attribute.NewSet(attribute.String("A", "B"))
+	now := time.Now()
+	reader := processortest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		{Name: "test"}: {export.NewRecord(&desc, &attrs, agg.Aggregation(), now, now)},
+	})
+
+	enc, err := encoding.Lookup("prometheus")
+	require.NoError(t, err)
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", enc.ContentType())
+
+	res := resource.NewSchemaless(attribute.String("R", "V"))
+	payload, err := enc.Encode(ctx, res, reader, aggregation.CumulativeTemporalitySelector())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"# HELP requests_total Number of requests",
+		"# TYPE requests_total counter",
+		`requests_total{A="B",R="V"} 15`,
+	}, strings.Split(strings.TrimSpace(string(payload)), "\n"))
+}
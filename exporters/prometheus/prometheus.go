@@ -20,6 +20,7 @@ package prometheus // import "go.opentelemetry.io/otel/exporters/prometheus"
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"sync"
 
@@ -53,6 +54,9 @@ type Exporter struct {
 	// controllers (e.g., with different resources).
 	lock       sync.RWMutex
 	controller *controller.Controller
+
+	encodeValue func(attribute.Value) string
+	staleGauges StaleGaugePolicy
 }
 
 // ErrUnsupportedAggregator is returned for unrepresentable aggregator
@@ -60,6 +64,7 @@ type Exporter struct {
 var ErrUnsupportedAggregator = fmt.Errorf("unsupported aggregator type")
 
 var _ http.Handler = &Exporter{}
+var _ export.CapabilityReporter = &Exporter{}
 
 // Config is a set of configs for the tally reporter.
 type Config struct {
@@ -84,8 +89,42 @@ type Config struct {
 	// DefaultHistogramBoundaries defines the default histogram bucket
 	// boundaries.
 	DefaultHistogramBoundaries []float64
+
+	// AttributeValueEncoder encodes attribute values as label values.
+	//
+	// If not specified, JoinSlices(",") is used, which joins the
+	// elements of slice values with commas.
+	AttributeValueEncoder func(attribute.Value) string
+
+	// StaleGauges selects how the gauges of GaugeObserver instruments
+	// that were not observed in the latest collection are served.
+	//
+	// If not specified, StaleGaugeLastValue is used.
+	StaleGauges StaleGaugePolicy
 }
 
+// StaleGaugePolicy is how the Exporter serves the gauges of GaugeObserver
+// instruments whose attribute sets were observed in an earlier
+// collection but not in the latest one.  Such gauges are only exported by
+// processors with memory, see the WithMemory option of the basic
+// processor, without which they are never served.
+type StaleGaugePolicy int
+
+const (
+	// StaleGaugeLastValue serves stale gauges with their last observed
+	// value, as if they had been observed again.
+	StaleGaugeLastValue StaleGaugePolicy = iota
+
+	// StaleGaugeOmit does not serve stale gauges, so that Prometheus
+	// marks their series stale once a scrape misses them, and serves
+	// them again once they are observed.
+	StaleGaugeOmit
+
+	// StaleGaugeNaN serves stale gauges with a NaN value, keeping their
+	// series present while queries and alerts see no usable value.
+	StaleGaugeNaN
+)
+
 // New returns a new Prometheus exporter using the configured metric
 // controller.  See controller.New().
 func New(config Config, controller *controller.Controller) (*Exporter, error) {
@@ -101,11 +140,17 @@ func New(config Config, controller *controller.Controller) (*Exporter, error) {
 		config.Gatherer = config.Registry
 	}
 
+	if config.AttributeValueEncoder == nil {
+		config.AttributeValueEncoder = defaultValueEncoder
+	}
+
 	e := &Exporter{
-		handler:    promhttp.HandlerFor(config.Gatherer, promhttp.HandlerOpts{}),
-		registerer: config.Registerer,
-		gatherer:   config.Gatherer,
-		controller: controller,
+		handler:     promhttp.HandlerFor(config.Gatherer, promhttp.HandlerOpts{}),
+		registerer:  config.Registerer,
+		gatherer:    config.Gatherer,
+		controller:  controller,
+		encodeValue: config.AttributeValueEncoder,
+		staleGauges: config.StaleGauges,
 	}
 
 	c := &collector{
@@ -134,6 +179,19 @@ func (e *Exporter) TemporalityFor(desc *sdkapi.Descriptor, kind aggregation.Kind
 	return aggregation.CumulativeTemporalitySelector().TemporalityFor(desc, kind)
 }
 
+// Capabilities implements export.CapabilityReporter.  Prometheus
+// exposes cumulative sums, gauges and histograms.
+func (e *Exporter) Capabilities() export.Capabilities {
+	return export.Capabilities{
+		Aggregations: []aggregation.Kind{
+			aggregation.SumKind,
+			aggregation.LastValueKind,
+			aggregation.HistogramKind,
+		},
+		Temporalities: []aggregation.Temporality{aggregation.CumulativeTemporality},
+	}
+}
+
 // ServeHTTP implements http.Handler.
 func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	e.handler.ServeHTTP(w, r)
@@ -154,8 +212,8 @@ func (c *collector) Describe(ch chan<- *prometheus.Desc) {
 	_ = c.exp.Controller().ForEach(func(_ instrumentation.Library, reader export.Reader) error {
 		return reader.ForEach(c.exp, func(record export.Record) error {
 			var attrKeys []string
-			mergeAttrs(record, c.exp.controller.Resource(), &attrKeys, nil)
-			ch <- c.toDesc(record, attrKeys)
+			mergeAttrs(record, c.exp.controller.Resource(), nil, &attrKeys, nil)
+			ch <- toDesc(record, attrKeys)
 			return nil
 		})
 	})
@@ -174,32 +232,49 @@ func (c *collector) Collect(ch chan<- prometheus.Metric) {
 		otel.Handle(err)
 	}
 
-	err := ctrl.ForEach(func(_ instrumentation.Library, reader export.Reader) error {
-		return reader.ForEach(c.exp, func(record export.Record) error {
+	if err := collectReader(ch, ctrl, c.exp.controller.Resource(), c.exp, c.exp.encodeValue, c.exp.staleGauges); err != nil {
+		otel.Handle(err)
+	}
+}
+
+// collectReader converts every record of reader to a Prometheus metric
+// sent to ch, encoding attribute values with encode and serving stale
+// gauges according to staleGauges.
+func collectReader(ch chan<- prometheus.Metric, ilr export.InstrumentationLibraryReader, res *resource.Resource, sel aggregation.TemporalitySelector, encode func(attribute.Value) string, staleGauges StaleGaugePolicy) error {
+	return ilr.ForEach(func(_ instrumentation.Library, reader export.Reader) error {
+		return reader.ForEach(sel, func(record export.Record) error {
 
 			agg := record.Aggregation()
 			numberKind := record.Descriptor().NumberKind()
 			instrumentKind := record.Descriptor().InstrumentKind()
+			staleGauge := record.Stale() && instrumentKind == sdkapi.GaugeObserverInstrumentKind
+			if staleGauge && staleGauges == StaleGaugeOmit {
+				return nil
+			}
 
 			var attrKeys, attrs []string
-			mergeAttrs(record, c.exp.controller.Resource(), &attrKeys, &attrs)
+			mergeAttrs(record, res, encode, &attrKeys, &attrs)
 
-			desc := c.toDesc(record, attrKeys)
+			desc := toDesc(record, attrKeys)
 
 			if hist, ok := agg.(aggregation.Histogram); ok {
-				if err := c.exportHistogram(ch, hist, numberKind, desc, attrs); err != nil {
+				if err := exportHistogram(ch, hist, numberKind, desc, attrs); err != nil {
 					return fmt.Errorf("exporting histogram: %w", err)
 				}
 			} else if sum, ok := agg.(aggregation.Sum); ok && instrumentKind.Monotonic() {
-				if err := c.exportMonotonicCounter(ch, sum, numberKind, desc, attrs); err != nil {
+				if err := exportMonotonicCounter(ch, sum, numberKind, desc, attrs); err != nil {
 					return fmt.Errorf("exporting monotonic counter: %w", err)
 				}
 			} else if sum, ok := agg.(aggregation.Sum); ok && !instrumentKind.Monotonic() {
-				if err := c.exportNonMonotonicCounter(ch, sum, numberKind, desc, attrs); err != nil {
+				if err := exportNonMonotonicCounter(ch, sum, numberKind, desc, attrs); err != nil {
 					return fmt.Errorf("exporting non monotonic counter: %w", err)
 				}
+			} else if _, ok := agg.(aggregation.LastValue); ok && staleGauge && staleGauges == StaleGaugeNaN {
+				if err := exportNaN(ch, desc, attrs); err != nil {
+					return fmt.Errorf("exporting stale last value: %w", err)
+				}
 			} else if lastValue, ok := agg.(aggregation.LastValue); ok {
-				if err := c.exportLastValue(ch, lastValue, numberKind, desc, attrs); err != nil {
+				if err := exportLastValue(ch, lastValue, numberKind, desc, attrs); err != nil {
 					return fmt.Errorf("exporting last value: %w", err)
 				}
 			} else {
@@ -208,12 +283,9 @@ func (c *collector) Collect(ch chan<- prometheus.Metric) {
 			return nil
 		})
 	})
-	if err != nil {
-		otel.Handle(err)
-	}
 }
 
-func (c *collector) exportLastValue(ch chan<- prometheus.Metric, lvagg aggregation.LastValue, kind number.Kind, desc *prometheus.Desc, attrs []string) error {
+func exportLastValue(ch chan<- prometheus.Metric, lvagg aggregation.LastValue, kind number.Kind, desc *prometheus.Desc, attrs []string) error {
 	lv, _, err := lvagg.LastValue()
 	if err != nil {
 		return fmt.Errorf("error retrieving last value: %w", err)
@@ -228,7 +300,17 @@ func (c *collector) exportLastValue(ch chan<- prometheus.Metric, lvagg aggregati
 	return nil
 }
 
-func (c *collector) exportNonMonotonicCounter(ch chan<- prometheus.Metric, sum aggregation.Sum, kind number.Kind, desc *prometheus.Desc, attrs []string) error {
+func exportNaN(ch chan<- prometheus.Metric, desc *prometheus.Desc, attrs []string) error {
+	m, err := prometheus.NewConstMetric(desc, prometheus.GaugeValue, math.NaN(), attrs...)
+	if err != nil {
+		return fmt.Errorf("error creating constant metric: %w", err)
+	}
+
+	ch <- m
+	return nil
+}
+
+func exportNonMonotonicCounter(ch chan<- prometheus.Metric, sum aggregation.Sum, kind number.Kind, desc *prometheus.Desc, attrs []string) error {
 	v, err := sum.Sum()
 	if err != nil {
 		return fmt.Errorf("error retrieving counter: %w", err)
@@ -243,7 +325,7 @@ func (c *collector) exportNonMonotonicCounter(ch chan<- prometheus.Metric, sum a
 	return nil
 }
 
-func (c *collector) exportMonotonicCounter(ch chan<- prometheus.Metric, sum aggregation.Sum, kind number.Kind, desc *prometheus.Desc, attrs []string) error {
+func exportMonotonicCounter(ch chan<- prometheus.Metric, sum aggregation.Sum, kind number.Kind, desc *prometheus.Desc, attrs []string) error {
 	v, err := sum.Sum()
 	if err != nil {
 		return fmt.Errorf("error retrieving counter: %w", err)
@@ -258,7 +340,7 @@ func (c *collector) exportMonotonicCounter(ch chan<- prometheus.Metric, sum aggr
 	return nil
 }
 
-func (c *collector) exportHistogram(ch chan<- prometheus.Metric, hist aggregation.Histogram, kind number.Kind, desc *prometheus.Desc, attrs []string) error {
+func exportHistogram(ch chan<- prometheus.Metric, hist aggregation.Histogram, kind number.Kind, desc *prometheus.Desc, attrs []string) error {
 	buckets, err := hist.Histogram()
 	if err != nil {
 		return fmt.Errorf("error retrieving histogram: %w", err)
@@ -289,7 +371,7 @@ func (c *collector) exportHistogram(ch chan<- prometheus.Metric, hist aggregatio
 	return nil
 }
 
-func (c *collector) toDesc(record export.Record, attrKeys []string) *prometheus.Desc {
+func toDesc(record export.Record, attrKeys []string) *prometheus.Desc {
 	desc := record.Descriptor()
 	return prometheus.NewDesc(sanitize(desc.Name()), desc.Description(), attrKeys, nil)
 }
@@ -298,8 +380,8 @@ func (c *collector) toDesc(record export.Record, attrKeys []string) *prometheus.
 // single set, giving precedence to the record's attributes in case of
 // duplicate keys.  This outputs one or both of the keys and the values as a
 // slice, and either argument may be nil to avoid allocating an unnecessary
-// slice.
-func mergeAttrs(record export.Record, res *resource.Resource, keys, values *[]string) {
+// slice.  Values are encoded with encode.
+func mergeAttrs(record export.Record, res *resource.Resource, encode func(attribute.Value) string, keys, values *[]string) {
 	if keys != nil {
 		*keys = make([]string, 0, record.Attributes().Len()+res.Len())
 	}
@@ -316,7 +398,7 @@ func mergeAttrs(record export.Record, res *resource.Resource, keys, values *[]st
 			*keys = append(*keys, sanitize(string(attr.Key)))
 		}
 		if values != nil {
-			*values = append(*values, attr.Value.Emit())
+			*values = append(*values, encode(attr.Value))
 		}
 	}
 }
@@ -29,6 +29,7 @@ import (
 	"go.opentelemetry.io/otel/metric/instrument"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
 	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	"go.opentelemetry.io/otel/sdk/metric/export"
 	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
 	selector "go.opentelemetry.io/otel/sdk/metric/selector/simple"
@@ -226,3 +227,111 @@ func TestPrometheusStatefulness(t *testing.T) {
 		expectCounterWithHelp("a_counter", "Counts things", `a_counter{key="value"} 200`),
 	})
 }
+
+func TestPrometheusStaleGauges(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy prometheus.StaleGaugePolicy
+		stale  []string
+	}{
+		{
+			name:   "last value",
+			policy: prometheus.StaleGaugeLastValue,
+			stale:  []string{`gauge{key="a"} 1`},
+		},
+		{
+			name:   "omit",
+			policy: prometheus.StaleGaugeOmit,
+		},
+		{
+			name:   "NaN",
+			policy: prometheus.StaleGaugeNaN,
+			stale:  []string{`gauge{key="a"} NaN`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exporter, err := newPipeline(
+				prometheus.Config{StaleGauges: tc.policy},
+				controller.WithCollectPeriod(0),
+				controller.WithResource(resource.Empty()),
+			)
+			require.NoError(t, err)
+
+			meter := exporter.MeterProvider().Meter("test")
+			gauge, err := meter.AsyncInt64().Gauge("gauge")
+			require.NoError(t, err)
+			counter, err := meter.SyncInt64().Counter("counter")
+			require.NoError(t, err)
+
+			observed := []string{"a", "b"}
+			err = meter.RegisterCallback([]instrument.Asynchronous{gauge}, func(ctx context.Context) {
+				for _, key := range observed {
+					gauge.Observe(ctx, 1, attribute.String("key", key))
+				}
+			})
+			require.NoError(t, err)
+			counter.Add(context.Background(), 1)
+
+			compareExport(t, exporter, []expectedMetric{
+				expectCounter("counter", "counter 1"),
+				{kind: "gauge", name: "gauge", values: []string{`gauge{key="a"} 1`, `gauge{key="b"} 1`}},
+			})
+
+			// Synchronous instruments are not affected by the
+			// policy, the counter keeps its cumulative value.
+			observed = []string{"b"}
+			compareExport(t, exporter, []expectedMetric{
+				expectCounter("counter", "counter 1"),
+				{kind: "gauge", name: "gauge", values: append([]string{`gauge{key="b"} 1`}, tc.stale...)},
+			})
+
+			observed = []string{"a", "b"}
+			compareExport(t, exporter, []expectedMetric{
+				expectCounter("counter", "counter 1"),
+				{kind: "gauge", name: "gauge", values: []string{`gauge{key="a"} 1`, `gauge{key="b"} 1`}},
+			})
+		})
+	}
+}
+
+func TestPrometheusSliceAttributes(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config prometheus.Config
+		labels string
+	}{
+		{"default", prometheus.Config{}, `ports="80,443",tags="a,b"`},
+		{"separator", prometheus.Config{AttributeValueEncoder: prometheus.JoinSlices("|")}, `ports="80|443",tags="a|b"`},
+		{"emit", prometheus.Config{AttributeValueEncoder: attribute.Value.Emit}, `ports="[80 443]",tags="[a b]"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exporter, err := newPipeline(
+				tc.config,
+				controller.WithCollectPeriod(0),
+				controller.WithResource(resource.Empty()),
+			)
+			require.NoError(t, err)
+
+			counter, err := exporter.MeterProvider().Meter("test").SyncInt64().Counter("counter")
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			// Equal slices are one attribute set.
+			counter.Add(ctx, 1, attribute.StringSlice("tags", []string{"a", "b"}), attribute.IntSlice("ports", []int{80, 443}))
+			counter.Add(ctx, 1, attribute.StringSlice("tags", []string{"a", "b"}), attribute.IntSlice("ports", []int{80, 443}))
+
+			compareExport(t, exporter, []expectedMetric{
+				expectCounter("counter", "counter{"+tc.labels+"} 2"),
+			})
+		})
+	}
+}
+
+func TestPrometheusCapabilities(t *testing.T) {
+	exporter, err := newPipeline(prometheus.Config{})
+	require.NoError(t, err)
+
+	caps := export.CapabilitiesOf(exporter)
+	require.True(t, caps.SupportsAggregation(aggregation.HistogramKind))
+	require.False(t, caps.SupportsTemporality(aggregation.DeltaTemporality))
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus // import "go.opentelemetry.io/otel/exporters/prometheus"
+
+import (
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// defaultValueEncoder is the AttributeValueEncoder used when none is
+// configured.
+var defaultValueEncoder = JoinSlices(",")
+
+// JoinSlices returns an AttributeValueEncoder that joins the elements of
+// slice values with sep, for example encoding
+// attribute.StringSlice("k", []string{"a", "b"}) as "a,b" for sep ",".
+// Other values are encoded as by attribute.Value.Emit.
+func JoinSlices(sep string) func(attribute.Value) string {
+	return func(v attribute.Value) string {
+		var elems []string
+		switch v.Type() {
+		case attribute.BOOLSLICE:
+			for _, b := range v.AsBoolSlice() {
+				elems = append(elems, strconv.FormatBool(b))
+			}
+		case attribute.INT64SLICE:
+			for _, i := range v.AsInt64Slice() {
+				elems = append(elems, strconv.FormatInt(i, 10))
+			}
+		case attribute.FLOAT64SLICE:
+			for _, f := range v.AsFloat64Slice() {
+				elems = append(elems, strconv.FormatFloat(f, 'g', -1, 64))
+			}
+		case attribute.STRINGSLICE:
+			elems = v.AsStringSlice()
+		default:
+			return v.Emit()
+		}
+		return strings.Join(elems, sep)
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package process provides instrumentation that reports metrics about the
+running process: its CPU time, resident memory, open file descriptors and
+uptime, named according to the OpenTelemetry process semantic conventions.
+
+A single call to Start registers the instruments:
+
+	if err := process.Start(process.WithMeterProvider(provider)); err != nil {
+		// handle the error
+	}
+
+The metrics a platform does not expose are not reported.  At this time the
+resident memory and open file descriptors are reported on Linux, and the
+CPU time on Unix systems.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track
+the evolving OpenTelemetry specification and user feedback.
+*/
+package process // import "go.opentelemetry.io/otel/instrumentation/process"
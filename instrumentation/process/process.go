@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process // import "go.opentelemetry.io/otel/instrumentation/process"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// ScopeName is the instrumentation scope name of the process
+// instruments.
+const ScopeName = "go.opentelemetry.io/otel/instrumentation/process"
+
+const seconds unit.Unit = "s"
+
+var (
+	// errUnsupported is returned by the readers of metrics the platform
+	// does not expose.
+	errUnsupported = errors.New("not supported on this platform")
+
+	// startTime approximates the start time of the process with the
+	// time this package is initialized.
+	startTime = time.Now()
+
+	stateUser   = attribute.String("state", "user")
+	stateSystem = attribute.String("state", "system")
+)
+
+// config contains the configuration of the process instrumentation.
+type config struct {
+	meterProvider metric.MeterProvider
+}
+
+// Option configures the process instrumentation.
+type Option interface {
+	apply(*config)
+}
+
+type meterProviderOption struct {
+	metric.MeterProvider
+}
+
+func (o meterProviderOption) apply(cfg *config) {
+	if o.MeterProvider != nil {
+		cfg.meterProvider = o.MeterProvider
+	}
+}
+
+// WithMeterProvider sets the MeterProvider the instruments are created
+// with.  If unset or nil, the global MeterProvider is used.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return meterProviderOption{mp}
+}
+
+// Start registers instruments that report the following metrics of the
+// current process:
+//
+//	process.cpu.time                    CPU seconds, by state (user or system)
+//	process.memory.usage                resident memory in bytes
+//	process.open_file_descriptor.count  open file descriptors
+//	process.uptime                      seconds since the process started
+//
+// The uptime is measured from the initialization of this package, which
+// happens while the process starts.  The other metrics are reported only on
+// the platforms that expose them.
+func Start(opts ...Option) error {
+	cfg := config{
+		meterProvider: global.MeterProvider(),
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	meter := cfg.meterProvider.Meter(ScopeName, metric.WithInstrumentationVersion(otel.Version()))
+	return register(meter)
+}
+
+func register(meter metric.Meter) error {
+	cpuTime, err := meter.AsyncFloat64().Counter(
+		"process.cpu.time",
+		instrument.WithUnit(seconds),
+		instrument.WithDescription("CPU time used by the process, by state"),
+	)
+	if err != nil {
+		return err
+	}
+	memory, err := meter.AsyncInt64().UpDownCounter(
+		"process.memory.usage",
+		instrument.WithUnit(unit.Bytes),
+		instrument.WithDescription("Physical memory resident in the process"),
+	)
+	if err != nil {
+		return err
+	}
+	fds, err := meter.AsyncInt64().UpDownCounter(
+		"process.open_file_descriptor.count",
+		instrument.WithUnit(unit.Dimensionless),
+		instrument.WithDescription("File descriptors open in the process"),
+	)
+	if err != nil {
+		return err
+	}
+	uptime, err := meter.AsyncFloat64().Gauge(
+		"process.uptime",
+		instrument.WithUnit(seconds),
+		instrument.WithDescription("Time since the process started"),
+	)
+	if err != nil {
+		return err
+	}
+
+	return meter.RegisterCallback(
+		[]instrument.Asynchronous{cpuTime, memory, fds, uptime},
+		func(ctx context.Context) {
+			if user, system, err := cpuTimes(); err == nil {
+				cpuTime.Observe(ctx, user, stateUser)
+				cpuTime.Observe(ctx, system, stateSystem)
+			} else {
+				handle(err)
+			}
+			if rss, err := residentMemory(); err == nil {
+				memory.Observe(ctx, rss)
+			} else {
+				handle(err)
+			}
+			if n, err := openFileDescriptors(); err == nil {
+				fds.Observe(ctx, n)
+			} else {
+				handle(err)
+			}
+			uptime.Observe(ctx, time.Since(startTime).Seconds())
+		},
+	)
+}
+
+// handle reports err unless it indicates a metric the platform does not
+// expose.
+func handle(err error) {
+	if !errors.Is(err, errUnsupported) {
+		otel.Handle(err)
+	}
+}
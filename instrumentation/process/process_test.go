@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func TestStart(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+	require.NoError(t, Start(WithMeterProvider(mp)))
+	require.NoError(t, exp.Collect(context.Background()))
+
+	records := map[string][]metrictest.ExportRecord{}
+	for _, rec := range exp.Records {
+		assert.Equal(t, ScopeName, rec.InstrumentationLibrary.InstrumentationName)
+		records[rec.InstrumentName] = append(records[rec.InstrumentName], rec)
+	}
+
+	require.Len(t, records["process.uptime"], 1)
+	assert.Greater(t, records["process.uptime"][0].LastValue.AsFloat64(), 0.0)
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	states := map[string]float64{}
+	for _, rec := range records["process.cpu.time"] {
+		require.Len(t, rec.Attributes, 1)
+		assert.Equal(t, attribute.Key("state"), rec.Attributes[0].Key)
+		states[rec.Attributes[0].Value.AsString()] = rec.Sum.AsFloat64()
+	}
+	assert.Len(t, states, 2)
+	assert.Contains(t, states, "user")
+	assert.Contains(t, states, "system")
+
+	require.Len(t, records["process.memory.usage"], 1)
+	assert.Greater(t, records["process.memory.usage"][0].Sum.AsInt64(), int64(0))
+
+	require.Len(t, records["process.open_file_descriptor.count"], 1)
+	assert.Greater(t, records["process.open_file_descriptor.count"][0].Sum.AsInt64(), int64(0))
+}
@@ -14,12 +14,16 @@
 
 package instrument // import "go.opentelemetry.io/otel/metric/instrument"
 
-import "go.opentelemetry.io/otel/metric/unit"
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/unit"
+)
 
 // Config contains options for metric instrument descriptors.
 type Config struct {
-	description string
-	unit        unit.Unit
+	description   string
+	unit          unit.Unit
+	attributeKeys []attribute.Key
 }
 
 // Description describes the instrument in human-readable terms.
@@ -32,6 +36,11 @@ func (cfg Config) Unit() unit.Unit {
 	return cfg.unit
 }
 
+// AttributeKeys returns the attribute keys recommended for the instrument.
+func (cfg Config) AttributeKeys() []attribute.Key {
+	return cfg.attributeKeys
+}
+
 // Option is an interface for applying metric instrument options.
 type Option interface {
 	applyInstrument(Config) Config
@@ -67,3 +76,13 @@ func WithUnit(unit unit.Unit) Option {
 		return cfg
 	})
 }
+
+// WithAttributeKeys advises the SDK of the attribute keys that are
+// recommended for the instrument.  An SDK may use this advice to drop
+// other attributes by default.
+func WithAttributeKeys(keys ...attribute.Key) Option {
+	return optionFunc(func(cfg Config) Config {
+		cfg.attributeKeys = append(cfg.attributeKeys[:0:0], keys...)
+		return cfg
+	})
+}
@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package duration provides instruments that record time.Duration values,
+// converted to the unit of the underlying float64 instrument.
+//
+// This package is currently in a pre-GA phase. Backwards incompatible changes
+// may be introduced in subsequent minor version releases as we work to track the
+// evolving OpenTelemetry specification and user feedback.
+//
+// A Timer measures the duration of an operation and records it into a
+// Histogram when it is stopped:
+//
+//	latency, err := duration.NewHistogram(meter.SyncFloat64(), "rpc.server.duration",
+//	        instrument.WithUnit(unit.Milliseconds))
+//	...
+//	func handle(ctx context.Context) {
+//	        defer latency.Start(ctx, attribute.String("rpc.method", "Get")).Stop()
+//	        ...
+//	}
+package duration // import "go.opentelemetry.io/otel/metric/instrument/duration"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// ErrUnsupportedUnit is returned for an instrument whose unit is not a
+// unit of time.
+var ErrUnsupportedUnit = fmt.Errorf("unsupported duration unit")
+
+// scales maps the supported units to the duration of one unit.
+var scales = map[unit.Unit]time.Duration{
+	unit.Seconds:      time.Second,
+	unit.Milliseconds: time.Millisecond,
+	"us":              time.Microsecond,
+	"ns":              time.Nanosecond,
+}
+
+// Converter converts durations to values in a unit of time.
+type Converter struct {
+	unit  unit.Unit
+	scale float64
+}
+
+// NewConverter returns a Converter to u, which must be one of "s", "ms",
+// "us" or "ns".
+func NewConverter(u unit.Unit) (Converter, error) {
+	d, ok := scales[u]
+	if !ok {
+		return Converter{}, fmt.Errorf("%w: %q", ErrUnsupportedUnit, u)
+	}
+	return Converter{unit: u, scale: float64(d)}, nil
+}
+
+// Unit returns the unit of the converted values.
+func (c Converter) Unit() unit.Unit {
+	return c.unit
+}
+
+// Convert returns d in the unit of the Converter.
+func (c Converter) Convert(d time.Duration) float64 {
+	return float64(d) / c.scale
+}
+
+// config returns the configuration of an instrument created with opts,
+// and opts with the default unit of seconds added when none is set.
+func config(opts []instrument.Option) (Converter, []instrument.Option, error) {
+	u := instrument.NewConfig(opts...).Unit()
+	if u == "" {
+		u = unit.Seconds
+		opts = append(opts[:len(opts):len(opts)], instrument.WithUnit(u))
+	}
+	conv, err := NewConverter(u)
+	return conv, opts, err
+}
+
+// Histogram records durations into a float64 histogram.
+type Histogram struct {
+	hist syncfloat64.Histogram
+	conv Converter
+}
+
+// NewHistogram creates a float64 histogram through provider and returns
+// a Histogram recording durations into it.  The unit of the histogram,
+// set with instrument.WithUnit, is seconds by default and may be "ms",
+// "us" or "ns".
+func NewHistogram(provider syncfloat64.InstrumentProvider, name string, opts ...instrument.Option) (Histogram, error) {
+	conv, opts, err := config(opts)
+	if err != nil {
+		return Histogram{}, err
+	}
+	hist, err := provider.Histogram(name, opts...)
+	if err != nil {
+		return Histogram{}, err
+	}
+	return Histogram{hist: hist, conv: conv}, nil
+}
+
+// Record records d into the histogram.
+func (h Histogram) Record(ctx context.Context, d time.Duration, attrs ...attribute.KeyValue) {
+	h.hist.Record(ctx, h.conv.Convert(d), attrs...)
+}
+
+// Start returns a Timer that records the time elapsed since now into the
+// histogram when it is stopped.
+func (h Histogram) Start(ctx context.Context, attrs ...attribute.KeyValue) Timer {
+	return Timer{
+		hist:  h,
+		ctx:   ctx,
+		start: time.Now(),
+		attrs: attrs,
+	}
+}
+
+// Timer measures the duration of an operation, see Histogram.Start.
+type Timer struct {
+	hist  Histogram
+	ctx   context.Context
+	start time.Time
+	attrs []attribute.KeyValue
+}
+
+// Stop records the time elapsed since the Timer was started, with the
+// attributes given to Start followed by attrs, and returns it.  Stop
+// should be called once.
+func (t Timer) Stop(attrs ...attribute.KeyValue) time.Duration {
+	elapsed := time.Since(t.start)
+	if len(attrs) != 0 {
+		attrs = append(t.attrs[:len(t.attrs):len(t.attrs)], attrs...)
+	} else {
+		attrs = t.attrs
+	}
+	t.hist.Record(t.ctx, elapsed, attrs...)
+	return elapsed
+}
+
+// Counter adds durations to a float64 counter, for example the time
+// spent in an activity.
+type Counter struct {
+	counter syncfloat64.Counter
+	conv    Converter
+}
+
+// NewCounter creates a float64 counter through provider and returns a
+// Counter adding durations to it.  The unit of the counter, set with
+// instrument.WithUnit, is seconds by default and may be "ms", "us" or
+// "ns".
+func NewCounter(provider syncfloat64.InstrumentProvider, name string, opts ...instrument.Option) (Counter, error) {
+	conv, opts, err := config(opts)
+	if err != nil {
+		return Counter{}, err
+	}
+	counter, err := provider.Counter(name, opts...)
+	if err != nil {
+		return Counter{}, err
+	}
+	return Counter{counter: counter, conv: conv}, nil
+}
+
+// Add adds d to the counter.
+func (c Counter) Add(ctx context.Context, d time.Duration, attrs ...attribute.KeyValue) {
+	c.counter.Add(ctx, c.conv.Convert(d), attrs...)
+}
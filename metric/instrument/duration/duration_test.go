@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+type measurement struct {
+	value float64
+	attrs []attribute.KeyValue
+}
+
+type testInstrument struct {
+	instrument.Synchronous
+	cfg          instrument.Config
+	measurements []measurement
+}
+
+func (i *testInstrument) Add(_ context.Context, v float64, attrs ...attribute.KeyValue) {
+	i.measurements = append(i.measurements, measurement{v, attrs})
+}
+
+func (i *testInstrument) Record(_ context.Context, v float64, attrs ...attribute.KeyValue) {
+	i.measurements = append(i.measurements, measurement{v, attrs})
+}
+
+type testProvider struct {
+	instruments map[string]*testInstrument
+}
+
+func (p *testProvider) create(name string, opts []instrument.Option) *testInstrument {
+	inst := &testInstrument{cfg: instrument.NewConfig(opts...)}
+	p.instruments[name] = inst
+	return inst
+}
+
+func (p *testProvider) Counter(name string, opts ...instrument.Option) (syncfloat64.Counter, error) {
+	return p.create(name, opts), nil
+}
+
+func (p *testProvider) UpDownCounter(name string, opts ...instrument.Option) (syncfloat64.UpDownCounter, error) {
+	return p.create(name, opts), nil
+}
+
+func (p *testProvider) Histogram(name string, opts ...instrument.Option) (syncfloat64.Histogram, error) {
+	return p.create(name, opts), nil
+}
+
+func TestConverter(t *testing.T) {
+	for _, tc := range []struct {
+		unit     unit.Unit
+		expected float64
+	}{
+		{unit.Seconds, 1.5},
+		{unit.Milliseconds, 1500},
+		{"us", 1500000},
+		{"ns", 1500000000},
+	} {
+		conv, err := NewConverter(tc.unit)
+		require.NoError(t, err)
+		require.Equal(t, tc.unit, conv.Unit())
+		require.Equal(t, tc.expected, conv.Convert(1500*time.Millisecond))
+	}
+
+	_, err := NewConverter(unit.Bytes)
+	require.ErrorIs(t, err, ErrUnsupportedUnit)
+}
+
+func TestHistogram(t *testing.T) {
+	ctx := context.Background()
+	provider := &testProvider{instruments: map[string]*testInstrument{}}
+
+	hist, err := NewHistogram(provider, "seconds", instrument.WithDescription("default unit"))
+	require.NoError(t, err)
+	require.Equal(t, unit.Seconds, provider.instruments["seconds"].cfg.Unit())
+	require.Equal(t, "default unit", provider.instruments["seconds"].cfg.Description())
+	hist.Record(ctx, 250*time.Millisecond)
+	require.Equal(t, []measurement{{0.25, nil}}, provider.instruments["seconds"].measurements)
+
+	hist, err = NewHistogram(provider, "millis", instrument.WithUnit(unit.Milliseconds))
+	require.NoError(t, err)
+	method := attribute.String("method", "Get")
+	status := attribute.String("status", "ok")
+	timer := hist.Start(ctx, method)
+	time.Sleep(time.Millisecond)
+	elapsed := timer.Stop(status)
+	require.GreaterOrEqual(t, elapsed, time.Millisecond)
+	require.Equal(t, []measurement{
+		{float64(elapsed) / float64(time.Millisecond), []attribute.KeyValue{method, status}},
+	}, provider.instruments["millis"].measurements)
+
+	_, err = NewHistogram(provider, "bytes", instrument.WithUnit(unit.Bytes))
+	require.ErrorIs(t, err, ErrUnsupportedUnit)
+	require.NotContains(t, provider.instruments, "bytes")
+}
+
+func TestCounter(t *testing.T) {
+	provider := &testProvider{instruments: map[string]*testInstrument{}}
+	counter, err := NewCounter(provider, "busy", instrument.WithUnit("us"))
+	require.NoError(t, err)
+	counter.Add(context.Background(), 2*time.Millisecond)
+	require.Equal(t, []measurement{{2000, nil}}, provider.instruments["busy"].measurements)
+}
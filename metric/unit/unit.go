@@ -21,4 +21,5 @@ const (
 	Dimensionless Unit = "1"
 	Bytes         Unit = "By"
 	Milliseconds  Unit = "ms"
+	Seconds       Unit = "s"
 )
@@ -86,6 +86,31 @@ type Aggregator interface {
 	Merge(aggregator Aggregator, descriptor *sdkapi.Descriptor) error
 }
 
+// SliceUpdater is an optional interface implemented by Aggregators that
+// can incorporate many measured values in a single call at a lower cost
+// than calling Update() once per value.
+type SliceUpdater interface {
+	// UpdateSlice is equivalent to calling Update() once for each
+	// value, in order.  UpdateSlice() calls may be called
+	// concurrently with Update() and SynchronizedMove().
+	//
+	// The values are expected to have passed RangeTest.  The
+	// Aggregator does not retain the slice.
+	UpdateSlice(ctx context.Context, numbers []number.Number, descriptor *sdkapi.Descriptor) error
+}
+
+// Compressor is an optional interface implemented by Aggregators that
+// can keep their state in a compact encoding, for example the
+// cumulative state of a series that is rarely updated.
+type Compressor interface {
+	// Compress encodes the state of the Aggregator compactly.  The
+	// state is decoded again by the next Update(), UpdateSlice() or
+	// Merge() into the Aggregator, and reading a compressed state
+	// through Aggregation() decodes a copy of it.  Compress() must
+	// not be called concurrently with Merge().
+	Compress()
+}
+
 // NewInconsistentAggregatorError formats an error describing an attempt to
 // Checkpoint or Merge different-type aggregators.  The result can be unwrapped as
 // an ErrInconsistentType.
@@ -0,0 +1,478 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddsketch // import "go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// Aggregator observes events and counts them in logarithmically
+	// sized buckets, whose width is set by the relative accuracy, so
+	// that every quantile it estimates is within the relative accuracy
+	// of the value of that quantile, whatever the range of the values.
+	// When the values span more buckets than the maximum number of
+	// buckets, the buckets of the values closest to zero are collapsed.
+	// It also calculates the sum, count, minimum and maximum of all
+	// events.
+	Aggregator struct {
+		lock      sync.Mutex
+		kind      number.Kind
+		gamma     float64
+		logGamma  float64
+		maxBins   int32
+		quantiles []float64
+		state
+	}
+
+	// config describes how the sketch is aggregated.
+	config struct {
+		relativeAccuracy float64
+		maxBins          int32
+		quantiles        []float64
+	}
+
+	// Option configures a DDSketch config.
+	Option interface {
+		// apply sets one or more config fields.
+		apply(*config)
+	}
+
+	// state is the sketch of the aggregated values.  The buckets of
+	// negative values are indexed by the magnitude of the values.
+	// min and max are only set when count is not zero.
+	state struct {
+		positive  store
+		negative  store
+		zeroCount uint64
+		count     uint64
+		sum       number.Number
+		min       number.Number
+		max       number.Number
+	}
+
+	// store holds the counts of consecutive bucket indexes starting
+	// at offset.  The bucket with index i counts the magnitudes in
+	// (gamma**(i-1), gamma**i].
+	store struct {
+		offset int32
+		counts []uint64
+	}
+)
+
+const (
+	// DefaultRelativeAccuracy is the default relative accuracy of the
+	// quantiles, one percent.
+	DefaultRelativeAccuracy = 0.01
+
+	// MinRelativeAccuracy is the finest relative accuracy.
+	MinRelativeAccuracy = 1e-4
+
+	// MaxRelativeAccuracy is the coarsest relative accuracy.
+	MaxRelativeAccuracy = 0.5
+
+	// DefaultMaxBins is the default maximum number of buckets for
+	// each of the positive and negative values.  At the default
+	// relative accuracy, it covers more than 17 orders of magnitude.
+	DefaultMaxBins int32 = 2048
+
+	// MinBins is the smallest maximum number of buckets.
+	MinBins int32 = 16
+)
+
+// DefaultQuantiles are the quantiles reported by default, the median,
+// 95th and 99th percentiles.
+var DefaultQuantiles = []float64{0.5, 0.95, 0.99}
+
+// WithRelativeAccuracy sets the relative accuracy of the sketch: the
+// quantiles it estimates are within accuracy times their value of the
+// value of the quantile.  Finer accuracies use more buckets for the same
+// range of values.  Accuracies outside [MinRelativeAccuracy,
+// MaxRelativeAccuracy] are clamped to that range.
+//
+// The default is DefaultRelativeAccuracy.
+func WithRelativeAccuracy(accuracy float64) Option {
+	return relativeAccuracyOption(accuracy)
+}
+
+type relativeAccuracyOption float64
+
+func (o relativeAccuracyOption) apply(config *config) {
+	config.relativeAccuracy = float64(o)
+}
+
+// WithMaxBins sets the maximum number of buckets for each of the
+// positive and negative values.  Sizes below MinBins are raised to
+// MinBins.
+//
+// The default is DefaultMaxBins.
+func WithMaxBins(bins int32) Option {
+	return maxBinsOption(bins)
+}
+
+type maxBinsOption int32
+
+func (o maxBinsOption) apply(config *config) {
+	config.maxBins = int32(o)
+}
+
+// WithQuantiles sets the quantiles reported by Quantiles, which the
+// exporters export.  Quantiles outside [0, 1] are ignored.
+//
+// The default is DefaultQuantiles.
+func WithQuantiles(quantiles ...float64) Option {
+	return quantilesOption(quantiles)
+}
+
+type quantilesOption []float64
+
+func (o quantilesOption) apply(config *config) {
+	config.quantiles = nil
+	for _, q := range o {
+		if q >= 0 && q <= 1 {
+			config.quantiles = append(config.quantiles, q)
+		}
+	}
+	sort.Float64s(config.quantiles)
+}
+
+var _ aggregator.Aggregator = &Aggregator{}
+var _ aggregation.Summary = &Aggregator{}
+var _ aggregation.Quantile = &Aggregator{}
+var _ aggregator.SliceUpdater = &Aggregator{}
+
+// New returns a new aggregator for estimating quantiles with a DDSketch.
+// Unlike a histogram with fixed boundaries, its accuracy is relative to
+// the values, which suits values with a wide dynamic range.
+func New(cnt int, desc *sdkapi.Descriptor, opts ...Option) []Aggregator {
+	cfg := config{
+		relativeAccuracy: DefaultRelativeAccuracy,
+		maxBins:          DefaultMaxBins,
+		quantiles:        DefaultQuantiles,
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if !(cfg.relativeAccuracy >= MinRelativeAccuracy) {
+		cfg.relativeAccuracy = MinRelativeAccuracy
+	}
+	if cfg.relativeAccuracy > MaxRelativeAccuracy {
+		cfg.relativeAccuracy = MaxRelativeAccuracy
+	}
+	if cfg.maxBins < MinBins {
+		cfg.maxBins = MinBins
+	}
+
+	gamma := (1 + cfg.relativeAccuracy) / (1 - cfg.relativeAccuracy)
+	aggs := make([]Aggregator, cnt)
+	for i := range aggs {
+		aggs[i] = Aggregator{
+			kind:      desc.NumberKind(),
+			gamma:     gamma,
+			logGamma:  math.Log(gamma),
+			maxBins:   cfg.maxBins,
+			quantiles: cfg.quantiles,
+		}
+	}
+	return aggs
+}
+
+// Aggregation returns an interface for reading the state of this aggregator.
+func (c *Aggregator) Aggregation() aggregation.Aggregation {
+	return c
+}
+
+// Kind returns aggregation.DDSketchKind.
+func (c *Aggregator) Kind() aggregation.Kind {
+	return aggregation.DDSketchKind
+}
+
+// Sum returns the sum of values in the checkpoint.
+func (c *Aggregator) Sum() (number.Number, error) {
+	return c.sum, nil
+}
+
+// Count returns the number of values in the checkpoint.
+func (c *Aggregator) Count() (uint64, error) {
+	return c.count, nil
+}
+
+// Min returns the minimum value in the checkpoint.  The error value
+// aggregation.ErrNoData will be returned if there were no measurements
+// recorded during the checkpoint.
+func (c *Aggregator) Min() (number.Number, error) {
+	if c.count == 0 {
+		return 0, aggregation.ErrNoData
+	}
+	return c.min, nil
+}
+
+// Max returns the maximum value in the checkpoint.  The error value
+// aggregation.ErrNoData will be returned if there were no measurements
+// recorded during the checkpoint.
+func (c *Aggregator) Max() (number.Number, error) {
+	if c.count == 0 {
+		return 0, aggregation.ErrNoData
+	}
+	return c.max, nil
+}
+
+// Quantiles returns the quantiles configured to be reported, in
+// increasing order.
+func (c *Aggregator) Quantiles() ([]float64, error) {
+	return c.quantiles, nil
+}
+
+// Quantile returns an estimate of the q-quantile of the values in the
+// checkpoint, for q in [0, 1], within the relative accuracy of the
+// sketch unless the bucket holding it was collapsed.  The 0 and 1
+// quantiles are the minimum and maximum.  It returns ErrInvalidQuantile
+// for q outside [0, 1], and ErrNoData when no value was recorded.
+func (c *Aggregator) Quantile(q float64) (float64, error) {
+	if !(q >= 0 && q <= 1) {
+		return 0, aggregation.ErrInvalidQuantile
+	}
+	if c.count == 0 {
+		return 0, aggregation.ErrNoData
+	}
+	minimum := c.min.CoerceToFloat64(c.kind)
+	maximum := c.max.CoerceToFloat64(c.kind)
+	switch q {
+	case 0:
+		return minimum, nil
+	case 1:
+		return maximum, nil
+	}
+
+	v := c.valueAtRank(q * float64(c.count-1))
+	return math.Max(minimum, math.Min(maximum, v)), nil
+}
+
+// valueAtRank returns the estimate of the value of the given rank,
+// counting from zero, in increasing order of the values.
+func (c *Aggregator) valueAtRank(rank float64) float64 {
+	var below uint64
+	for i := len(c.negative.counts) - 1; i >= 0; i-- {
+		below += c.negative.counts[i]
+		if float64(below) > rank {
+			return -c.value(c.negative.offset + int32(i))
+		}
+	}
+	below += c.zeroCount
+	if float64(below) > rank {
+		return 0
+	}
+	for i, n := range c.positive.counts {
+		below += n
+		if float64(below) > rank {
+			return c.value(c.positive.offset + int32(i))
+		}
+	}
+	// The rank is below the count, this is only reached by rounding
+	// errors, which the maximum corrects.
+	return math.Inf(+1)
+}
+
+// index returns the index of the bucket of a positive magnitude.
+func (c *Aggregator) index(magnitude float64) int32 {
+	return int32(math.Ceil(math.Log(magnitude) / c.logGamma))
+}
+
+// value returns the estimate of the magnitudes in the bucket with index
+// i, whose relative error is at most the relative accuracy.
+func (c *Aggregator) value(i int32) float64 {
+	return 2 * math.Pow(c.gamma, float64(i)) / (c.gamma + 1)
+}
+
+// SynchronizedMove saves the current state into oa and resets the
+// current state to the empty set, reusing the memory of the previous
+// checkpoint.
+func (c *Aggregator) SynchronizedMove(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+
+	if oa != nil && o == nil {
+		return aggregator.NewInconsistentAggregatorError(c, oa)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if o != nil {
+		c.state, o.state = o.state, c.state
+	}
+	c.clear()
+	return nil
+}
+
+// clear resets the state, keeping the allocated buckets.
+func (s *state) clear() {
+	s.positive.clear()
+	s.negative.clear()
+	s.zeroCount = 0
+	s.count = 0
+	s.sum = 0
+	s.min = 0
+	s.max = 0
+}
+
+// Update adds the recorded measurement to the current data set.
+// Infinite values are only added to the sum.
+func (c *Aggregator) Update(_ context.Context, num number.Number, desc *sdkapi.Descriptor) error {
+	kind := desc.NumberKind()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.update(kind, num)
+	return nil
+}
+
+// UpdateSlice adds the recorded measurements to the current data set,
+// acquiring the lock once for all of them.
+func (c *Aggregator) UpdateSlice(_ context.Context, nums []number.Number, desc *sdkapi.Descriptor) error {
+	kind := desc.NumberKind()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, num := range nums {
+		c.update(kind, num)
+	}
+	return nil
+}
+
+// update adds num to the state.  The lock must be held.
+func (c *Aggregator) update(kind number.Kind, num number.Number) {
+	c.sum.AddNumber(kind, num)
+
+	value := num.CoerceToFloat64(kind)
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return
+	}
+	if c.count == 0 || num.CompareNumber(kind, c.min) < 0 {
+		c.min = num
+	}
+	if c.count == 0 || num.CompareNumber(kind, c.max) > 0 {
+		c.max = num
+	}
+	c.count++
+
+	switch {
+	case value > 0:
+		c.positive.add(c.index(value), 1, c.maxBins)
+	case value < 0:
+		c.negative.add(c.index(-value), 1, c.maxBins)
+	default:
+		c.zeroCount++
+	}
+}
+
+// Merge combines two data sets into one.  Both sketches must have the
+// same relative accuracy.
+func (c *Aggregator) Merge(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentAggregatorError(c, oa)
+	}
+	if o.gamma != c.gamma {
+		return fmt.Errorf("%w: DDSketches of different relative accuracies", aggregation.ErrInconsistentType)
+	}
+	kind := desc.NumberKind()
+
+	c.sum.AddNumber(kind, o.sum)
+	if o.count == 0 {
+		return nil
+	}
+	if c.count == 0 || o.min.CompareNumber(kind, c.min) < 0 {
+		c.min = o.min
+	}
+	if c.count == 0 || o.max.CompareNumber(kind, c.max) > 0 {
+		c.max = o.max
+	}
+	c.count += o.count
+	c.zeroCount += o.zeroCount
+	c.positive.merge(&o.positive, c.maxBins)
+	c.negative.merge(&o.negative, c.maxBins)
+	return nil
+}
+
+// clear empties the store, keeping the allocated counts.
+func (s *store) clear() {
+	s.offset = 0
+	s.counts = s.counts[:0]
+}
+
+// add adds n to the count of the bucket with the given index.  When the
+// buckets would span more than maxBins indexes, the lowest buckets are
+// collapsed into the lowest remaining one.
+func (s *store) add(index int32, n uint64, maxBins int32) {
+	if len(s.counts) == 0 {
+		s.offset = index
+		s.counts = append(s.counts[:0], n)
+		return
+	}
+	low, high := s.offset, s.offset+int32(len(s.counts))-1
+	if index < low {
+		low = index
+	}
+	if index > high {
+		high = index
+	}
+	if high-low >= maxBins {
+		low = high - maxBins + 1
+	}
+	if index < low {
+		index = low
+	}
+	s.resize(low, high)
+	s.counts[index-s.offset] += n
+}
+
+// resize sets the range of bucket indexes to [low, high], which includes
+// the highest index of the store.  The counts of the buckets below low
+// are added to the bucket of low.
+func (s *store) resize(low, high int32) {
+	if low == s.offset && int(high-low)+1 == len(s.counts) {
+		return
+	}
+	counts := make([]uint64, high-low+1)
+	for i, n := range s.counts {
+		index := s.offset + int32(i)
+		if index < low {
+			index = low
+		}
+		counts[index-low] += n
+	}
+	s.offset = low
+	s.counts = counts
+}
+
+// merge adds the counts of o to the store.
+func (s *store) merge(o *store, maxBins int32) {
+	for i, n := range o.counts {
+		if n != 0 {
+			s.add(o.offset+int32(i), n, maxBins)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddsketch_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggregatortest"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+const count = 10000
+
+var testQuantiles = []float64{0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.95, 0.99, 0.999}
+
+// checkQuantiles tests that the quantiles of agg are within the relative
+// accuracy of the values of the same rank in values.
+func checkQuantiles(t *testing.T, values []float64, accuracy float64, agg *ddsketch.Aggregator) {
+	c, err := agg.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(values)), c)
+
+	sort.Float64s(values)
+	for _, q := range testQuantiles {
+		est, err := agg.Quantile(q)
+		require.NoError(t, err)
+		expect := values[int(q*float64(len(values)-1))]
+		require.InDelta(t, expect, est, accuracy*math.Abs(expect)+1e-12, "quantile %v", q)
+	}
+
+	est, err := agg.Quantile(0)
+	require.NoError(t, err)
+	require.Equal(t, values[0], est)
+	est, err = agg.Quantile(1)
+	require.NoError(t, err)
+	require.Equal(t, values[len(values)-1], est)
+}
+
+func checkZero(t *testing.T, agg *ddsketch.Aggregator) {
+	c, err := agg.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), c)
+
+	s, err := agg.Sum()
+	require.NoError(t, err)
+	require.Equal(t, number.Number(0), s)
+
+	_, err = agg.Max()
+	require.ErrorIs(t, err, aggregation.ErrNoData)
+	_, err = agg.Quantile(0.5)
+	require.ErrorIs(t, err, aggregation.ErrNoData)
+}
+
+// wideRange returns a value whose magnitude is spread over twelve orders
+// of magnitude, negative one time in three, and zero now and then.
+func wideRange(rnd *rand.Rand, i int) float64 {
+	if i%50 == 0 {
+		return 0
+	}
+	v := math.Pow(10, -6+12*rnd.Float64())
+	if i%3 == 0 {
+		return -v
+	}
+	return v
+}
+
+func TestDDSketchUpdate(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, accuracy := range []float64{0.001, ddsketch.DefaultRelativeAccuracy, 0.05} {
+		// The finest accuracy needs more than the default number of
+		// buckets for twelve orders of magnitude.
+		aggs := ddsketch.New(2, desc, ddsketch.WithRelativeAccuracy(accuracy), ddsketch.WithMaxBins(16384))
+		agg, ckpt := &aggs[0], &aggs[1]
+		require.Equal(t, aggregation.DDSketchKind, agg.Kind())
+		checkZero(t, agg)
+
+		for repeat := 0; repeat < 2; repeat++ {
+			var values []float64
+			var total float64
+			for i := 0; i < count; i++ {
+				v := wideRange(rnd, i)
+				values = append(values, v)
+				total += v
+				aggregatortest.CheckedUpdate(t, agg, number.NewFloat64Number(v), desc)
+			}
+
+			require.NoError(t, agg.SynchronizedMove(ckpt, desc))
+			checkZero(t, agg)
+			checkQuantiles(t, values, accuracy, ckpt)
+
+			s, err := ckpt.Sum()
+			require.NoError(t, err)
+			require.InEpsilon(t, total, s.AsFloat64(), 1e-9)
+		}
+	}
+}
+
+func TestDDSketchUpdateSlice(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+		agg := &ddsketch.New(1, desc)[0]
+
+		var values []float64
+		var nums []number.Number
+		for i := 0; i < count; i++ {
+			x := profile.Random(+1)
+			if i%2 == 0 {
+				x = profile.Random(-1)
+			}
+			values = append(values, x.CoerceToFloat64(profile.NumberKind))
+			nums = append(nums, x)
+		}
+		require.NoError(t, agg.UpdateSlice(context.Background(), nums, desc))
+		require.NoError(t, agg.UpdateSlice(context.Background(), nil, desc))
+		checkQuantiles(t, values, ddsketch.DefaultRelativeAccuracy, agg)
+	})
+}
+
+func TestDDSketchMerge(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	rnd := rand.New(rand.NewSource(2))
+	aggs := ddsketch.New(4, desc)
+	agg1, agg2, ckpt1, ckpt2 := &aggs[0], &aggs[1], &aggs[2], &aggs[3]
+
+	var values []float64
+	for i := 0; i < count; i++ {
+		v1, v2 := wideRange(rnd, i), wideRange(rnd, i+1)
+		values = append(values, v1, v2)
+		aggregatortest.CheckedUpdate(t, agg1, number.NewFloat64Number(v1), desc)
+		aggregatortest.CheckedUpdate(t, agg2, number.NewFloat64Number(v2), desc)
+	}
+	require.NoError(t, agg1.SynchronizedMove(ckpt1, desc))
+	require.NoError(t, agg2.SynchronizedMove(ckpt2, desc))
+
+	// Merging an empty checkpoint leaves the sketch unchanged.
+	aggregatortest.CheckedMerge(t, ckpt1, agg1, desc)
+	aggregatortest.CheckedMerge(t, ckpt1, ckpt2, desc)
+	checkQuantiles(t, values, ddsketch.DefaultRelativeAccuracy, ckpt1)
+
+	// Merging into an empty aggregator copies the sketch.
+	aggregatortest.CheckedMerge(t, agg1, ckpt1, desc)
+	checkQuantiles(t, values, ddsketch.DefaultRelativeAccuracy, agg1)
+
+	require.Error(t, agg1.Merge(&sum.New(1)[0], desc))
+	coarse := &ddsketch.New(1, desc, ddsketch.WithRelativeAccuracy(0.1))[0]
+	require.ErrorIs(t, agg1.Merge(coarse, desc), aggregation.ErrInconsistentType)
+}
+
+func TestDDSketchCollapse(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	agg := &ddsketch.New(1, desc, ddsketch.WithMaxBins(ddsketch.MinBins))[0]
+
+	// At the default accuracy, 16 buckets cover about a third of an
+	// order of magnitude, the smaller values are collapsed into the
+	// lowest bucket.
+	var values []float64
+	for i := 1; i <= 1000; i++ {
+		v := float64(i)
+		values = append(values, v)
+		aggregatortest.CheckedUpdate(t, agg, number.NewFloat64Number(v), desc)
+	}
+
+	for _, q := range []float64{0.9, 0.99} {
+		est, err := agg.Quantile(q)
+		require.NoError(t, err)
+		expect := values[int(q*float64(len(values)-1))]
+		require.InDelta(t, expect, est, ddsketch.DefaultRelativeAccuracy*expect)
+	}
+	median, err := agg.Quantile(0.5)
+	require.NoError(t, err)
+	require.Less(t, median, 900.0)
+	require.Greater(t, median, 500.0)
+
+	// The minimum is still exact.
+	minimum, err := agg.Quantile(0)
+	require.NoError(t, err)
+	require.Equal(t, 1.0, minimum)
+}
+
+func TestDDSketchOptions(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Int64Kind)
+
+	qs, err := ddsketch.New(1, desc)[0].Quantiles()
+	require.NoError(t, err)
+	require.Equal(t, ddsketch.DefaultQuantiles, qs)
+
+	agg := &ddsketch.New(1, desc, ddsketch.WithQuantiles(0.99, -1, 0.5), ddsketch.WithRelativeAccuracy(2))[0]
+	qs, err = agg.Quantiles()
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.5, 0.99}, qs)
+
+	// The accuracy is clamped to MaxRelativeAccuracy.
+	for i := int64(1); i <= 100; i++ {
+		aggregatortest.CheckedUpdate(t, agg, number.NewInt64Number(i), desc)
+	}
+	median, err := agg.Quantile(0.5)
+	require.NoError(t, err)
+	require.InDelta(t, 50, median, ddsketch.MaxRelativeAccuracy*50)
+
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		_, err := agg.Quantile(q)
+		require.ErrorIs(t, err, aggregation.ErrInvalidQuantile)
+	}
+
+	// Infinite values are only added to the sum.
+	fdesc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	inf := &ddsketch.New(1, fdesc)[0]
+	aggregatortest.CheckedUpdate(t, inf, number.NewFloat64Number(math.Inf(-1)), fdesc)
+	c, err := inf.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), c)
+	s, err := inf.Sum()
+	require.NoError(t, err)
+	require.True(t, math.IsInf(s.AsFloat64(), -1))
+}
+
+func TestDDSketchSynchronizedMoveReset(t *testing.T) {
+	aggregatortest.SynchronizedMoveResetTest(
+		t,
+		sdkapi.HistogramInstrumentKind,
+		func(desc *sdkapi.Descriptor) aggregator.Aggregator {
+			return &ddsketch.New(1, desc)[0]
+		},
+	)
+}
@@ -0,0 +1,463 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exponential // import "go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential/mapping"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential/mapping/exponent"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential/mapping/logarithm"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// Aggregator observes events and counts them in exponentially
+	// sized buckets.  It starts at the maximum scale and lowers the
+	// scale, halving the resolution, whenever the values no longer
+	// fit in the maximum number of buckets.  It also calculates the
+	// sum and count of all events.
+	Aggregator struct {
+		lock    sync.Mutex
+		maxSize int32
+		state   *state
+	}
+
+	// config describes how the histogram is aggregated.
+	config struct {
+		maxSize  int32
+		maxScale int32
+	}
+
+	// Option configures an exponential histogram config.
+	Option interface {
+		// apply sets one or more config fields.
+		apply(*config)
+	}
+
+	// state represents the state of an exponential histogram.  The
+	// positive and negative buckets share the scale of mapping.
+	state struct {
+		mapping   mapping.Mapping
+		maxScale  int32
+		positive  buckets
+		negative  buckets
+		zeroCount uint64
+		sum       number.Number
+		count     uint64
+	}
+
+	// buckets are the counts of consecutive bucket indexes starting
+	// at offset.
+	buckets struct {
+		offset int32
+		counts []uint64
+	}
+)
+
+const (
+	// DefaultMaxSize is the default maximum number of buckets for
+	// each of the positive and negative ranges.
+	DefaultMaxSize int32 = 160
+
+	// MinSize is the smallest maximum number of buckets, which
+	// holds every float64 value at the minimum scale.
+	MinSize int32 = 4
+
+	// DefaultMaxScale is the default scale of an empty histogram,
+	// its finest resolution.
+	DefaultMaxScale = logarithm.MaxScale
+
+	// MinScale is the coarsest resolution, at which every float64
+	// value maps into one of a few buckets.
+	MinScale = exponent.MinScale
+)
+
+// WithMaxSize sets the maximum number of buckets for each of the
+// positive and negative ranges.  Sizes below MinSize are raised to
+// MinSize.
+//
+// The default is DefaultMaxSize.
+func WithMaxSize(size int32) Option {
+	return maxSizeOption(size)
+}
+
+type maxSizeOption int32
+
+func (o maxSizeOption) apply(config *config) {
+	config.maxSize = int32(o)
+}
+
+// WithMaxScale sets the scale of an empty histogram, which the
+// histogram lowers as needed to fit its values in the maximum number of
+// buckets.  Scales outside [MinScale, DefaultMaxScale] are clamped to
+// that range.
+//
+// The default is DefaultMaxScale.
+func WithMaxScale(scale int32) Option {
+	return maxScaleOption(scale)
+}
+
+type maxScaleOption int32
+
+func (o maxScaleOption) apply(config *config) {
+	config.maxScale = int32(o)
+}
+
+var _ aggregator.Aggregator = &Aggregator{}
+var _ aggregation.Sum = &Aggregator{}
+var _ aggregation.Count = &Aggregator{}
+var _ aggregation.ExponentialHistogram = &Aggregator{}
+var _ aggregator.SliceUpdater = &Aggregator{}
+var _ aggregation.ExponentialBuckets = &buckets{}
+
+// New returns a new aggregator for computing base-2 exponential
+// histograms.
+func New(cnt int, desc *sdkapi.Descriptor, opts ...Option) []Aggregator {
+	cfg := config{
+		maxSize:  DefaultMaxSize,
+		maxScale: DefaultMaxScale,
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if cfg.maxSize < MinSize {
+		cfg.maxSize = MinSize
+	}
+	if cfg.maxScale > DefaultMaxScale {
+		cfg.maxScale = DefaultMaxScale
+	}
+	if cfg.maxScale < MinScale {
+		cfg.maxScale = MinScale
+	}
+
+	aggs := make([]Aggregator, cnt)
+	for i := range aggs {
+		aggs[i] = Aggregator{
+			maxSize: cfg.maxSize,
+			state: &state{
+				mapping:  newMapping(cfg.maxScale),
+				maxScale: cfg.maxScale,
+			},
+		}
+	}
+	return aggs
+}
+
+// newMapping returns the mapping function of a scale within
+// [MinScale, DefaultMaxScale].
+func newMapping(scale int32) mapping.Mapping {
+	var m mapping.Mapping
+	if scale > exponent.MaxScale {
+		m, _ = logarithm.NewMapping(scale)
+	} else {
+		m, _ = exponent.NewMapping(scale)
+	}
+	return m
+}
+
+// Aggregation returns an interface for reading the state of this aggregator.
+func (c *Aggregator) Aggregation() aggregation.Aggregation {
+	return c
+}
+
+// Kind returns aggregation.ExponentialHistogramKind.
+func (c *Aggregator) Kind() aggregation.Kind {
+	return aggregation.ExponentialHistogramKind
+}
+
+// Sum returns the sum of all values in the checkpoint.
+func (c *Aggregator) Sum() (number.Number, error) {
+	return c.state.sum, nil
+}
+
+// Count returns the number of values in the checkpoint, including
+// zeros.
+func (c *Aggregator) Count() (uint64, error) {
+	return c.state.count, nil
+}
+
+// Scale returns the scale of the buckets in the checkpoint.
+func (c *Aggregator) Scale() (int32, error) {
+	return c.state.mapping.Scale(), nil
+}
+
+// ZeroCount returns the number of zero values in the checkpoint.
+func (c *Aggregator) ZeroCount() (uint64, error) {
+	return c.state.zeroCount, nil
+}
+
+// Positive returns the buckets of the values above zero in the
+// checkpoint.
+func (c *Aggregator) Positive() (aggregation.ExponentialBuckets, error) {
+	return &c.state.positive, nil
+}
+
+// Negative returns the buckets of the values below zero in the
+// checkpoint, by the magnitude of the values.
+func (c *Aggregator) Negative() (aggregation.ExponentialBuckets, error) {
+	return &c.state.negative, nil
+}
+
+// Offset returns the index of the first bucket.
+func (b *buckets) Offset() int32 {
+	return b.offset
+}
+
+// Len returns the number of buckets.
+func (b *buckets) Len() uint32 {
+	return uint32(len(b.counts))
+}
+
+// At returns the count of the bucket at position i.
+func (b *buckets) At(i uint32) uint64 {
+	return b.counts[i]
+}
+
+// SynchronizedMove saves the current state into oa and resets the
+// current state to the empty set, at the maximum scale.
+func (c *Aggregator) SynchronizedMove(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+
+	if oa != nil && o == nil {
+		return aggregator.NewInconsistentAggregatorError(c, oa)
+	}
+
+	if o != nil {
+		// Swap case: reset the target state before swapping
+		// it under the lock below.
+		o.state.clear()
+	}
+
+	c.lock.Lock()
+	if o != nil {
+		c.state, o.state = o.state, c.state
+	} else {
+		// No swap case: the asynchronous instrument case.
+		c.state.clear()
+	}
+	c.lock.Unlock()
+
+	return nil
+}
+
+// clear empties the state and restores its maximum scale.  The bucket
+// counts keep their capacity.
+func (s *state) clear() {
+	if s.mapping.Scale() != s.maxScale {
+		s.mapping = newMapping(s.maxScale)
+	}
+	s.positive.clear()
+	s.negative.clear()
+	s.zeroCount = 0
+	s.sum = 0
+	s.count = 0
+}
+
+func (b *buckets) clear() {
+	b.offset = 0
+	b.counts = b.counts[:0]
+}
+
+// Update adds the recorded measurement to the current data set.
+// Infinite and NaN values fit no bucket, and are dropped so that the
+// count remains the total of the buckets and the sum remains finite.
+func (c *Aggregator) Update(_ context.Context, num number.Number, desc *sdkapi.Descriptor) error {
+	kind := desc.NumberKind()
+	value := num.CoerceToFloat64(kind)
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.state.count++
+	c.state.sum.AddNumber(kind, num)
+	c.update(value, 1)
+
+	return nil
+}
+
+// UpdateSlice adds the recorded measurements to the current data set,
+// acquiring the lock once for all of them.  Infinite and NaN values are
+// dropped, as by Update.
+func (c *Aggregator) UpdateSlice(_ context.Context, nums []number.Number, desc *sdkapi.Descriptor) error {
+	kind := desc.NumberKind()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, num := range nums {
+		value := num.CoerceToFloat64(kind)
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			continue
+		}
+		c.state.count++
+		c.state.sum.AddNumber(kind, num)
+		c.update(value, 1)
+	}
+
+	return nil
+}
+
+// update adds n to the bucket of value, lowering the scale when the
+// bucket does not fit.  The caller holds the lock.
+func (c *Aggregator) update(value float64, n uint64) {
+	if value == 0 {
+		c.state.zeroCount += n
+		return
+	}
+	b := &c.state.positive
+	if value < 0 {
+		b = &c.state.negative
+		value = -value
+	}
+	index := c.state.mapping.MapToIndex(value)
+	if change := c.scaleChange(b, index, index); change > 0 {
+		index >>= c.downscale(change)
+	}
+	b.increment(index, n)
+}
+
+// scaleChange returns by how much the scale has to be lowered for b to
+// hold the indexes from low to high.
+func (c *Aggregator) scaleChange(b *buckets, low, high int32) int32 {
+	if len(b.counts) != 0 {
+		if b.offset < low {
+			low = b.offset
+		}
+		if end := b.offset + int32(len(b.counts)) - 1; end > high {
+			high = end
+		}
+	}
+	var change int32
+	for high-low >= c.maxSize {
+		low >>= 1
+		high >>= 1
+		change++
+	}
+	return change
+}
+
+// downscale lowers the scale of the state by change, merging the
+// buckets pairwise change times, and returns the change, which is
+// smaller when MinScale is reached.
+func (c *Aggregator) downscale(change int32) int32 {
+	scale := c.state.mapping.Scale() - change
+	if scale < MinScale {
+		scale = MinScale
+		change = c.state.mapping.Scale() - MinScale
+	}
+	c.state.mapping = newMapping(scale)
+	c.state.positive.downscale(change)
+	c.state.negative.downscale(change)
+	return change
+}
+
+// downscale merges the buckets pairwise change times.
+func (b *buckets) downscale(change int32) {
+	if change == 0 || len(b.counts) == 0 {
+		return
+	}
+	offset := b.offset >> change
+	last := int32(-1)
+	for i, n := range b.counts {
+		pos := (b.offset+int32(i))>>change - offset
+		if pos != last {
+			b.counts[pos] = n
+			last = pos
+			continue
+		}
+		b.counts[pos] += n
+	}
+	b.offset = offset
+	b.counts = b.counts[:last+1]
+}
+
+// increment adds n to the count of the bucket with index i, growing
+// the range of buckets.  The range including i is expected to fit the
+// maximum size.
+func (b *buckets) increment(i int32, n uint64) {
+	switch {
+	case len(b.counts) == 0:
+		b.offset = i
+		b.counts = append(b.counts[:0], n)
+		return
+	case i < b.offset:
+		grow := int(b.offset - i)
+		b.counts = append(b.counts, make([]uint64, grow)...)
+		copy(b.counts[grow:], b.counts)
+		for j := 0; j < grow; j++ {
+			b.counts[j] = 0
+		}
+		b.offset = i
+	case i >= b.offset+int32(len(b.counts)):
+		b.counts = append(b.counts, make([]uint64, int(i-b.offset)+1-len(b.counts))...)
+	}
+	b.counts[i-b.offset] += n
+}
+
+// Merge combines two exponential histograms into a single one, at the
+// lower of their scales, lowered further as needed to fit the maximum
+// number of buckets.
+func (c *Aggregator) Merge(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentAggregatorError(c, oa)
+	}
+
+	c.state.sum.AddNumber(desc.NumberKind(), o.state.sum)
+	c.state.count += o.state.count
+	c.state.zeroCount += o.state.zeroCount
+
+	if shift := c.state.mapping.Scale() - o.state.mapping.Scale(); shift > 0 {
+		c.downscale(shift)
+	}
+	shift := o.state.mapping.Scale() - c.state.mapping.Scale()
+	change := c.mergeChange(&c.state.positive, &o.state.positive, shift)
+	if neg := c.mergeChange(&c.state.negative, &o.state.negative, shift); neg > change {
+		change = neg
+	}
+	if change > 0 {
+		shift += c.downscale(change)
+	}
+	c.state.positive.merge(&o.state.positive, shift)
+	c.state.negative.merge(&o.state.negative, shift)
+	return nil
+}
+
+// mergeChange returns by how much the scale has to be lowered for b to
+// hold the buckets of o, whose indexes are shifted by shift.
+func (c *Aggregator) mergeChange(b, o *buckets, shift int32) int32 {
+	if len(o.counts) == 0 {
+		return 0
+	}
+	return c.scaleChange(b, o.offset>>shift, (o.offset+int32(len(o.counts))-1)>>shift)
+}
+
+// merge adds the buckets of o, whose indexes are shifted by shift, to b.
+func (b *buckets) merge(o *buckets, shift int32) {
+	for i, n := range o.counts {
+		if n != 0 {
+			b.increment((o.offset+int32(i))>>shift, n)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exponential_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggregatortest"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// expected describes the state of an exponential histogram.
+type expected struct {
+	scale     int32
+	zeroCount uint64
+	count     uint64
+	positive  bucketsValue
+	negative  bucketsValue
+}
+
+type bucketsValue struct {
+	offset int32
+	counts []uint64
+}
+
+func bucketsOf(b aggregation.ExponentialBuckets) bucketsValue {
+	v := bucketsValue{offset: b.Offset()}
+	for i := uint32(0); i < b.Len(); i++ {
+		v.counts = append(v.counts, b.At(i))
+	}
+	return v
+}
+
+func stateOf(t *testing.T, agg *exponential.Aggregator) expected {
+	scale, err := agg.Scale()
+	require.NoError(t, err)
+	zeros, err := agg.ZeroCount()
+	require.NoError(t, err)
+	count, err := agg.Count()
+	require.NoError(t, err)
+	positive, err := agg.Positive()
+	require.NoError(t, err)
+	negative, err := agg.Negative()
+	require.NoError(t, err)
+	return expected{
+		scale:     scale,
+		zeroCount: zeros,
+		count:     count,
+		positive:  bucketsOf(positive),
+		negative:  bucketsOf(negative),
+	}
+}
+
+func update(t *testing.T, agg *exponential.Aggregator, desc *sdkapi.Descriptor, values ...float64) {
+	for _, v := range values {
+		aggregatortest.CheckedUpdate(t, agg, number.NewFloat64Number(v), desc)
+	}
+}
+
+func TestExponentialUpdate(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+
+	for _, tc := range []struct {
+		name   string
+		opts   []exponential.Option
+		values []float64
+		want   expected
+	}{
+		{
+			// Buckets are inclusive of their lower boundary,
+			// so that 1 is counted in [1, 2).
+			name:   "powers of two",
+			opts:   []exponential.Option{exponential.WithMaxScale(0)},
+			values: []float64{1, 2, 4},
+			want: expected{
+				count:    3,
+				positive: bucketsValue{offset: 0, counts: []uint64{1, 1, 1}},
+			},
+		},
+		{
+			name:   "downscale",
+			opts:   []exponential.Option{exponential.WithMaxScale(0), exponential.WithMaxSize(4)},
+			values: []float64{1, 2, 4, 8, 16},
+			want: expected{
+				scale:    -1,
+				count:    5,
+				positive: bucketsValue{offset: 0, counts: []uint64{2, 2, 1}},
+			},
+		},
+		{
+			name:   "downscale below",
+			opts:   []exponential.Option{exponential.WithMaxScale(0), exponential.WithMaxSize(4)},
+			values: []float64{16, 8, 4, 2, 1},
+			want: expected{
+				scale:    -1,
+				count:    5,
+				positive: bucketsValue{offset: 0, counts: []uint64{2, 2, 1}},
+			},
+		},
+		{
+			name:   "zero and negative",
+			opts:   []exponential.Option{exponential.WithMaxScale(0)},
+			values: []float64{0, -1, -3, 2, 0},
+			want: expected{
+				zeroCount: 2,
+				count:     5,
+				positive:  bucketsValue{offset: 1, counts: []uint64{1}},
+				negative:  bucketsValue{offset: 0, counts: []uint64{1, 1}},
+			},
+		},
+		{
+			name:   "infinity is dropped",
+			opts:   []exponential.Option{exponential.WithMaxScale(0)},
+			values: []float64{math.Inf(+1), 2},
+			want: expected{
+				count:    1,
+				positive: bucketsValue{offset: 1, counts: []uint64{1}},
+			},
+		},
+		{
+			name:   "clamped options",
+			opts:   []exponential.Option{exponential.WithMaxScale(100), exponential.WithMaxSize(1)},
+			values: []float64{math.SmallestNonzeroFloat64, 1, math.MaxFloat64},
+			want: expected{
+				scale:    -9,
+				count:    3,
+				positive: bucketsValue{offset: -2, counts: []uint64{1, 0, 1, 1}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			agg := &exponential.New(1, desc, tc.opts...)[0]
+			update(t, agg, desc, tc.values...)
+			require.Equal(t, tc.want, stateOf(t, agg))
+		})
+	}
+}
+
+func TestExponentialDropsNonFinite(t *testing.T) {
+	ctx := context.Background()
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	agg := &exponential.New(1, desc)[0]
+
+	update(t, agg, desc, 0, 1, math.Inf(+1), -2, math.Inf(-1))
+	require.NoError(t, agg.UpdateSlice(ctx, []number.Number{
+		number.NewFloat64Number(math.NaN()),
+		number.NewFloat64Number(3),
+		number.NewFloat64Number(math.Inf(+1)),
+	}, desc))
+
+	got := stateOf(t, agg)
+	total := got.zeroCount
+	for _, b := range []bucketsValue{got.positive, got.negative} {
+		for _, c := range b.counts {
+			total += c
+		}
+	}
+	require.Equal(t, uint64(4), got.count)
+	require.Equal(t, got.count, total)
+	s, err := agg.Sum()
+	require.NoError(t, err)
+	require.Equal(t, 2.0, s.AsFloat64())
+}
+
+func TestExponentialDefaults(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	agg := &exponential.New(1, desc)[0]
+	require.Equal(t, aggregation.ExponentialHistogramKind, agg.Kind())
+
+	// Values spanning a factor of 1e6 fit in 160 buckets at scale 3.
+	for v := 1.0; v <= 1e6; v *= 1.01 {
+		update(t, agg, desc, v)
+	}
+	got := stateOf(t, agg)
+	require.Equal(t, int32(3), got.scale)
+	require.LessOrEqual(t, len(got.positive.counts), int(exponential.DefaultMaxSize))
+}
+
+func TestExponentialIntegers(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Int64Kind)
+	agg := &exponential.New(1, desc, exponential.WithMaxScale(0))[0]
+	for _, v := range []int64{2, 3, 0} {
+		aggregatortest.CheckedUpdate(t, agg, number.NewInt64Number(v), desc)
+	}
+	s, err := agg.Sum()
+	require.NoError(t, err)
+	require.Equal(t, number.NewInt64Number(5), s)
+	require.Equal(t, expected{
+		zeroCount: 1,
+		count:     3,
+		positive:  bucketsValue{offset: 1, counts: []uint64{2}},
+	}, stateOf(t, agg))
+}
+
+func TestExponentialMerge(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+
+	// After merging, the histogram has the scale and buckets of a
+	// single histogram of all the values.
+	for trial := 0; trial < 100; trial++ {
+		aggs := exponential.New(3, desc, exponential.WithMaxSize(20))
+		for i := 0; i < 50; i++ {
+			v := math.Ldexp(rand.Float64(), rand.Intn(20)-10)
+			if rand.Intn(4) == 0 {
+				v = -v
+			}
+			update(t, &aggs[i%2], desc, v)
+			update(t, &aggs[2], desc, v)
+		}
+		aggregatortest.CheckedMerge(t, &aggs[0], &aggs[1], desc)
+		require.Equal(t, stateOf(t, &aggs[2]), stateOf(t, &aggs[0]))
+	}
+
+	agg := &exponential.New(1, desc)[0]
+	require.Error(t, agg.Merge(&sum.New(1)[0], desc))
+}
+
+func TestExponentialSynchronizedMove(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	aggs := exponential.New(2, desc, exponential.WithMaxScale(0), exponential.WithMaxSize(4))
+	agg, ckpt := &aggs[0], &aggs[1]
+
+	update(t, agg, desc, 1, 16)
+	require.NoError(t, agg.SynchronizedMove(ckpt, desc))
+	require.Equal(t, expected{
+		scale:    -1,
+		count:    2,
+		positive: bucketsValue{offset: 0, counts: []uint64{1, 0, 1}},
+	}, stateOf(t, ckpt))
+
+	// The scale is restored for the next interval.
+	require.Equal(t, expected{}, stateOf(t, agg))
+	update(t, agg, desc, 2)
+	require.Equal(t, expected{
+		count:    1,
+		positive: bucketsValue{offset: 1, counts: []uint64{1}},
+	}, stateOf(t, agg))
+
+	require.Error(t, agg.SynchronizedMove(&sum.New(1)[0], desc))
+}
+
+func TestExponentialSynchronizedMoveReset(t *testing.T) {
+	aggregatortest.SynchronizedMoveResetTest(
+		t,
+		sdkapi.HistogramInstrumentKind,
+		func(desc *sdkapi.Descriptor) aggregator.Aggregator {
+			return &exponential.New(1, desc)[0]
+		},
+	)
+}
+
+func TestExponentialUpdateSlice(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	aggs := exponential.New(2, desc, exponential.WithMaxSize(8))
+	var nums []number.Number
+	for i := 0; i < 100; i++ {
+		v := rand.Float64() * 1000
+		nums = append(nums, number.NewFloat64Number(v))
+		update(t, &aggs[0], desc, v)
+	}
+	require.NoError(t, aggs[1].UpdateSlice(context.Background(), nums, desc))
+	require.Equal(t, stateOf(t, &aggs[0]), stateOf(t, &aggs[1]))
+}
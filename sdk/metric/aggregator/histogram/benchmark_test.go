@@ -128,3 +128,34 @@ func BenchmarkHistogramSearchInt64_512(b *testing.B) {
 func BenchmarkHistogramSearchInt64_1024(b *testing.B) {
 	benchmarkHistogramSearchInt64(b, 1024)
 }
+
+func benchmarkHistogramMerge(b *testing.B, size int) {
+	boundaries := make([]float64, size)
+	for i := range boundaries {
+		boundaries[i] = float64(i)
+	}
+
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	aggs := histogram.New(2, desc, histogram.WithExplicitBoundaries(boundaries))
+	ctx := context.Background()
+	for i := 0; i <= size; i++ {
+		_ = aggs[1].Update(ctx, number.NewFloat64Number(float64(i)), desc)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = aggs[0].Merge(&aggs[1], desc)
+	}
+}
+
+func BenchmarkHistogramMerge_10(b *testing.B) {
+	benchmarkHistogramMerge(b, 10)
+}
+func BenchmarkHistogramMerge_60(b *testing.B) {
+	benchmarkHistogramMerge(b, 60)
+}
+func BenchmarkHistogramMerge_160(b *testing.B) {
+	benchmarkHistogramMerge(b, 160)
+}
@@ -16,6 +16,7 @@ package histogram // import "go.opentelemetry.io/otel/sdk/metric/aggregator/hist
 
 import (
 	"context"
+	"encoding/binary"
 	"sort"
 	"sync"
 
@@ -37,6 +38,7 @@ type (
 		lock       sync.Mutex
 		boundaries []float64
 		kind       number.Kind
+		sparse     bool
 		state      *state
 	}
 
@@ -45,6 +47,11 @@ type (
 		// explicitBoundaries support arbitrary bucketing schemes.  This
 		// is the general case.
 		explicitBoundaries []float64
+
+		// sparseThreshold is the number of buckets above which
+		// bucket counts are stored sparsely.  Zero disables sparse
+		// storage.
+		sparseThreshold int
 	}
 
 	// Option configures a histogram config.
@@ -56,10 +63,19 @@ type (
 	// state represents the state of a histogram, consisting of
 	// the sum and counts for all observed values and
 	// the less than equal bucket count for the pre-determined boundaries.
+	//
+	// Bucket counts are stored either densely in bucketCounts, or
+	// sparsely as pairs of sparseIndexes and sparseCounts ordered by
+	// bucket index, in which case bucketCounts is nil.  A compressed
+	// state stores them only in compressed, see Compress.
 	state struct {
-		bucketCounts []uint64
-		sum          number.Number
-		count        uint64
+		bucketCounts  []uint64
+		sparseIndexes []int
+		sparseCounts  []uint64
+		compressed    []byte
+		numBuckets    int
+		sum           number.Number
+		count         uint64
 	}
 )
 
@@ -76,6 +92,28 @@ func (o explicitBoundariesOption) apply(config *config) {
 	config.explicitBoundaries = o.boundaries
 }
 
+// WithSparseThreshold sets the number of buckets above which the
+// histogram stores its bucket counts as index/count pairs instead of
+// one count per bucket.  This saves memory for histograms with many
+// boundaries and traffic concentrated in a few buckets, at the cost of
+// slower updates and of allocating the bucket counts when they are
+// read.  A threshold of zero disables sparse storage.
+//
+// The default is DefaultSparseThreshold.
+func WithSparseThreshold(buckets int) Option {
+	return sparseThresholdOption(buckets)
+}
+
+type sparseThresholdOption int
+
+func (o sparseThresholdOption) apply(config *config) {
+	config.sparseThreshold = int(o)
+}
+
+// DefaultSparseThreshold is the default number of buckets above which
+// bucket counts are stored sparsely.
+const DefaultSparseThreshold = 64
+
 // defaultExplicitBoundaries have been copied from prometheus.DefBuckets.
 //
 // Note we anticipate the use of a high-precision histogram sketch as
@@ -100,6 +138,9 @@ var _ aggregator.Aggregator = &Aggregator{}
 var _ aggregation.Sum = &Aggregator{}
 var _ aggregation.Count = &Aggregator{}
 var _ aggregation.Histogram = &Aggregator{}
+var _ aggregation.Quantile = &Aggregator{}
+var _ aggregator.SliceUpdater = &Aggregator{}
+var _ aggregator.Compressor = &Aggregator{}
 
 // New returns a new aggregator for computing Histograms.
 //
@@ -110,7 +151,9 @@ var _ aggregation.Histogram = &Aggregator{}
 // atomic operations, which introduces the possibility that
 // checkpoints are inconsistent.
 func New(cnt int, desc *sdkapi.Descriptor, opts ...Option) []Aggregator {
-	var cfg config
+	cfg := config{
+		sparseThreshold: DefaultSparseThreshold,
+	}
 
 	if desc.NumberKind() == number.Int64Kind {
 		cfg.explicitBoundaries = defaultInt64ExplicitBoundaries
@@ -135,6 +178,7 @@ func New(cnt int, desc *sdkapi.Descriptor, opts ...Option) []Aggregator {
 		aggs[i] = Aggregator{
 			kind:       desc.NumberKind(),
 			boundaries: sortedBoundaries,
+			sparse:     cfg.sparseThreshold > 0 && len(sortedBoundaries)+1 > cfg.sparseThreshold,
 		}
 		aggs[i].state = aggs[i].newState()
 	}
@@ -162,13 +206,26 @@ func (c *Aggregator) Count() (uint64, error) {
 }
 
 // Histogram returns the count of events in pre-determined buckets.
+// When the bucket counts are stored sparsely or compressed, this
+// allocates them.
 func (c *Aggregator) Histogram() (aggregation.Buckets, error) {
 	return aggregation.Buckets{
 		Boundaries: c.boundaries,
-		Counts:     c.state.bucketCounts,
+		Counts:     c.state.denseCounts(),
 	}, nil
 }
 
+// Quantile returns an estimate of the q-quantile of the values in the
+// checkpoint, interpolated within the buckets, see
+// aggregation.Buckets.Quantile.
+func (c *Aggregator) Quantile(q float64) (float64, error) {
+	buckets, err := c.Histogram()
+	if err != nil {
+		return 0, err
+	}
+	return buckets.Quantile(q)
+}
+
 // SynchronizedMove saves the current state into oa and resets the current state to
 // the empty set.  Since no locks are taken, there is a chance that
 // the independent Sum, Count and Bucket Count are not consistent with each
@@ -205,51 +262,208 @@ func (c *Aggregator) SynchronizedMove(oa aggregator.Aggregator, desc *sdkapi.Des
 }
 
 func (c *Aggregator) newState() *state {
+	if c.sparse {
+		return &state{
+			numBuckets: len(c.boundaries) + 1,
+		}
+	}
 	return &state{
 		bucketCounts: make([]uint64, len(c.boundaries)+1),
+		numBuckets:   len(c.boundaries) + 1,
 	}
 }
 
 func (c *Aggregator) clearState() {
+	if c.state.compressed != nil {
+		c.state.compressed = nil
+		if !c.sparse {
+			c.state.bucketCounts = make([]uint64, c.state.numBuckets)
+		}
+	}
 	for i := range c.state.bucketCounts {
 		c.state.bucketCounts[i] = 0
 	}
+	// Sparse storage keeps its capacity, which is bounded by the
+	// number of buckets that received values in a prior interval.
+	c.state.sparseIndexes = c.state.sparseIndexes[:0]
+	c.state.sparseCounts = c.state.sparseCounts[:0]
 	c.state.sum = 0
 	c.state.count = 0
 }
 
+// increment adds n to the count of bucket i.
+func (s *state) increment(i int, n uint64) {
+	if s.bucketCounts != nil {
+		s.bucketCounts[i] += n
+		return
+	}
+	pos := sort.SearchInts(s.sparseIndexes, i)
+	if pos < len(s.sparseIndexes) && s.sparseIndexes[pos] == i {
+		s.sparseCounts[pos] += n
+		return
+	}
+	s.sparseIndexes = append(s.sparseIndexes, 0)
+	s.sparseCounts = append(s.sparseCounts, 0)
+	copy(s.sparseIndexes[pos+1:], s.sparseIndexes[pos:])
+	copy(s.sparseCounts[pos+1:], s.sparseCounts[pos:])
+	s.sparseIndexes[pos] = i
+	s.sparseCounts[pos] = n
+}
+
+// denseCounts returns one count per bucket.
+func (s *state) denseCounts() []uint64 {
+	if s.bucketCounts != nil {
+		return s.bucketCounts
+	}
+	counts := make([]uint64, s.numBuckets)
+	if s.compressed != nil {
+		s.forEachCompressed(func(i int, n uint64) {
+			counts[i] = n
+		})
+		return counts
+	}
+	for j, i := range s.sparseIndexes {
+		counts[i] = s.sparseCounts[j]
+	}
+	return counts
+}
+
+// Compress encodes the bucket counts as varint pairs of the distance
+// from the previous non-empty bucket and the count, releasing the dense
+// or sparse counts.  The sum and count are not compressed.
+func (c *Aggregator) Compress() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	s := c.state
+	if s.compressed != nil {
+		return
+	}
+	buf := make([]byte, 0, 2*binary.MaxVarintLen64)
+	last := -1
+	add := func(i int, n uint64) {
+		if n == 0 {
+			return
+		}
+		buf = appendUvarint(buf, uint64(i-last))
+		buf = appendUvarint(buf, n)
+		last = i
+	}
+	if s.bucketCounts != nil {
+		for i, n := range s.bucketCounts {
+			add(i, n)
+		}
+	} else {
+		for j, i := range s.sparseIndexes {
+			add(i, s.sparseCounts[j])
+		}
+	}
+	// An empty encoding is distinguished from an uncompressed
+	// state by being non-nil.
+	s.compressed = append([]byte{}, buf...)
+	s.bucketCounts = nil
+	s.sparseIndexes = nil
+	s.sparseCounts = nil
+}
+
+// decompress restores the dense or sparse bucket counts of a
+// compressed state.
+func (c *Aggregator) decompress() {
+	s := c.state
+	if s.compressed == nil {
+		return
+	}
+	if c.sparse {
+		s.forEachCompressed(func(i int, n uint64) {
+			s.sparseIndexes = append(s.sparseIndexes, i)
+			s.sparseCounts = append(s.sparseCounts, n)
+		})
+	} else {
+		s.bucketCounts = s.denseCounts()
+	}
+	s.compressed = nil
+}
+
+// forEachCompressed calls f with the index and count of each non-empty
+// bucket of a compressed state, in index order.
+func (s *state) forEachCompressed(f func(i int, n uint64)) {
+	buf := s.compressed
+	i := -1
+	for len(buf) > 0 {
+		delta, k := binary.Uvarint(buf)
+		n, l := binary.Uvarint(buf[k:])
+		buf = buf[k+l:]
+		i += int(delta)
+		f(i, n)
+	}
+}
+
+// appendUvarint appends the varint encoding of x to buf.
+func appendUvarint(buf []byte, x uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutUvarint(tmp[:], x)]...)
+}
+
 // Update adds the recorded measurement to the current data set.
 func (c *Aggregator) Update(_ context.Context, number number.Number, desc *sdkapi.Descriptor) error {
 	kind := desc.NumberKind()
 	asFloat := number.CoerceToFloat64(kind)
 
-	bucketID := len(c.boundaries)
-	for i, boundary := range c.boundaries {
-		if asFloat < boundary {
-			bucketID = i
-			break
-		}
+	bucketID := c.bucketFor(asFloat)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.decompress()
+	c.state.count++
+	c.state.sum.AddNumber(kind, number)
+	c.state.increment(bucketID, 1)
+
+	return nil
+}
+
+// UpdateSlice adds the recorded measurements to the current data set,
+// acquiring the lock once for all of them.
+func (c *Aggregator) UpdateSlice(_ context.Context, nums []number.Number, desc *sdkapi.Descriptor) error {
+	kind := desc.NumberKind()
+
+	var sum number.Number
+	for _, num := range nums {
+		sum.AddNumber(kind, num)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.decompress()
+	c.state.count += uint64(len(nums))
+	c.state.sum.AddNumber(kind, sum)
+	for _, num := range nums {
+		c.state.increment(c.bucketFor(num.CoerceToFloat64(kind)), 1)
 	}
+
+	return nil
+}
+
+// bucketFor returns the index of the bucket that value belongs to.
+func (c *Aggregator) bucketFor(value float64) int {
 	// Note: Binary-search was compared using the benchmarks. The following
-	// code is equivalent to the linear search above:
+	// code is equivalent to the linear search below:
 	//
-	//     bucketID := sort.Search(len(c.boundaries), func(i int) bool {
-	//         return asFloat < c.boundaries[i]
+	//     return sort.Search(len(c.boundaries), func(i int) bool {
+	//         return value < c.boundaries[i]
 	//     })
 	//
 	// The binary search wins for very large boundary sets, but
 	// the linear search performs better up through arrays between
 	// 256 and 512 elements, which is a relatively large histogram, so we
 	// continue to prefer linear search.
-
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	c.state.count++
-	c.state.sum.AddNumber(kind, number)
-	c.state.bucketCounts[bucketID]++
-
-	return nil
+	for i, boundary := range c.boundaries {
+		if value < boundary {
+			return i
+		}
+	}
+	return len(c.boundaries)
 }
 
 // Merge combines two histograms that have the same buckets into a single one.
@@ -259,11 +473,51 @@ func (c *Aggregator) Merge(oa aggregator.Aggregator, desc *sdkapi.Descriptor) er
 		return aggregator.NewInconsistentAggregatorError(c, oa)
 	}
 
+	c.decompress()
 	c.state.sum.AddNumber(desc.NumberKind(), o.state.sum)
 	c.state.count += o.state.count
 
-	for i := 0; i < len(c.state.bucketCounts); i++ {
-		c.state.bucketCounts[i] += o.state.bucketCounts[i]
+	switch {
+	case o.state.compressed != nil:
+		o.state.forEachCompressed(c.state.increment)
+	case c.state.bucketCounts != nil && o.state.bucketCounts != nil:
+		mergeCounts(c.state.bucketCounts, o.state.bucketCounts)
+	case o.state.bucketCounts != nil:
+		for i, n := range o.state.bucketCounts {
+			if n != 0 {
+				c.state.increment(i, n)
+			}
+		}
+	default:
+		for j, i := range o.state.sparseIndexes {
+			c.state.increment(i, o.state.sparseCounts[j])
+		}
 	}
 	return nil
 }
+
+// mergeCounts adds src into dst bucket-wise.  Both slices are expected
+// to have the same length.
+//
+// Cumulative exporters merge every series on every collection, so this
+// loop is hot.  Working on local slices lets the compiler keep them in
+// registers, and the fixed-size chunks let it prove that indexes are in
+// range, so the bounds checks are hoisted out of the loop body.
+func mergeCounts(dst, src []uint64) {
+	src = src[:len(dst)]
+	for len(dst) >= 8 {
+		d, s := dst[:8:8], src[:8:8]
+		d[0] += s[0]
+		d[1] += s[1]
+		d[2] += s[2]
+		d[3] += s[3]
+		d[4] += s[4]
+		d[5] += s[5]
+		d[6] += s[6]
+		d[7] += s[7]
+		dst, src = dst[8:], src[8:]
+	}
+	for i := range dst {
+		dst[i] += src[i]
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/metric/aggregator"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggregatortest"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 	"go.opentelemetry.io/otel/sdk/metric/number"
 	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
 )
@@ -176,6 +177,73 @@ func TestHistogramMerge(t *testing.T) {
 	})
 }
 
+func TestHistogramMergeWideBoundaries(t *testing.T) {
+	// 21 buckets exercise both the chunked and the remainder loop.
+	var boundaries []float64
+	for b := 50.0; b < aggregatortest.Magnitude; b += 50 {
+		boundaries = append(boundaries, b)
+	}
+
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		descriptor := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+
+		agg1, agg2, ckpt, all := new4(descriptor, histogram.WithExplicitBoundaries(boundaries))
+
+		for i := 0; i < count; i++ {
+			x := profile.Random(+1)
+			aggregatortest.CheckedUpdate(t, agg1, x, descriptor)
+			aggregatortest.CheckedUpdate(t, all, x, descriptor)
+
+			y := profile.Random(+1)
+			aggregatortest.CheckedUpdate(t, agg2, y, descriptor)
+			aggregatortest.CheckedUpdate(t, all, y, descriptor)
+		}
+
+		require.NoError(t, agg1.SynchronizedMove(ckpt, descriptor))
+		aggregatortest.CheckedMerge(t, ckpt, agg2, descriptor)
+
+		got, err := ckpt.Histogram()
+		require.NoError(t, err)
+		want, err := all.Histogram()
+		require.NoError(t, err)
+		require.Equal(t, want.Counts, got.Counts)
+	})
+}
+
+func TestHistogramUpdateSlice(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		descriptor := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+
+		agg, ckpt, single, singleCkpt := new4(descriptor, histogram.WithExplicitBoundaries(testBoundaries))
+
+		nums := make([]number.Number, count)
+		for i := range nums {
+			nums[i] = profile.Random(+1)
+			aggregatortest.CheckedUpdate(t, single, nums[i], descriptor)
+		}
+		require.NoError(t, agg.UpdateSlice(context.Background(), nums, descriptor))
+
+		require.NoError(t, agg.SynchronizedMove(ckpt, descriptor))
+		require.NoError(t, single.SynchronizedMove(singleCkpt, descriptor))
+
+		sum, err := ckpt.Sum()
+		require.NoError(t, err)
+		wantSum, err := singleCkpt.Sum()
+		require.NoError(t, err)
+		require.Equal(t, wantSum, sum)
+
+		cnt, err := ckpt.Count()
+		require.NoError(t, err)
+		require.Equal(t, uint64(count), cnt)
+
+		got, err := ckpt.Histogram()
+		require.NoError(t, err)
+		want, err := singleCkpt.Histogram()
+		require.NoError(t, err)
+		require.Equal(t, want.Counts, got.Counts)
+	})
+}
+
 func TestHistogramNotSet(t *testing.T) {
 	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
 		descriptor := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
@@ -294,3 +362,158 @@ func TestHistogramDefaultBoundaries(t *testing.T) {
 		require.EqualValues(t, expect, bucks.Counts)
 	})
 }
+
+func TestHistogramQuantile(t *testing.T) {
+	ctx := context.Background()
+	descriptor := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	agg, ckpt := new2(descriptor, histogram.WithExplicitBoundaries([]float64{100, 200, 400}))
+
+	for _, v := range []float64{50, 150, 160, 170, 180, 500} {
+		require.NoError(t, agg.Update(ctx, number.NewFloat64Number(v), descriptor))
+	}
+	require.NoError(t, agg.SynchronizedMove(ckpt, descriptor))
+
+	p50, err := ckpt.Quantile(0.5)
+	require.NoError(t, err)
+	require.InDelta(t, 150, p50, 1e-9)
+	p99, err := ckpt.Quantile(0.99)
+	require.NoError(t, err)
+	require.Equal(t, 400.0, p99)
+
+	_, err = agg.Quantile(0.5)
+	require.ErrorIs(t, err, aggregation.ErrNoData)
+}
+
+func TestHistogramSparse(t *testing.T) {
+	// 20 buckets, stored sparsely with a threshold of 4.
+	var boundaries []float64
+	for b := 50.0; b < aggregatortest.Magnitude; b += 50 {
+		boundaries = append(boundaries, b)
+	}
+
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		ctx := context.Background()
+		descriptor := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+
+		sparse, sparseCkpt := new2(descriptor, histogram.WithExplicitBoundaries(boundaries), histogram.WithSparseThreshold(4))
+		dense, denseCkpt := new2(descriptor, histogram.WithExplicitBoundaries(boundaries), histogram.WithSparseThreshold(0))
+
+		for repeat := 0; repeat < 3; repeat++ {
+			nums := make([]number.Number, count)
+			for i := range nums {
+				nums[i] = profile.Random(+1)
+				aggregatortest.CheckedUpdate(t, sparse, nums[i], descriptor)
+				aggregatortest.CheckedUpdate(t, dense, nums[i], descriptor)
+			}
+			require.NoError(t, sparse.UpdateSlice(ctx, nums, descriptor))
+			require.NoError(t, dense.UpdateSlice(ctx, nums, descriptor))
+
+			require.NoError(t, sparse.SynchronizedMove(sparseCkpt, descriptor))
+			require.NoError(t, dense.SynchronizedMove(denseCkpt, descriptor))
+
+			got, err := sparseCkpt.Histogram()
+			require.NoError(t, err)
+			want, err := denseCkpt.Histogram()
+			require.NoError(t, err)
+			require.Equal(t, want.Counts, got.Counts)
+
+			got, err = sparse.Histogram()
+			require.NoError(t, err)
+			require.Equal(t, make([]uint64, len(boundaries)+1), got.Counts)
+		}
+
+		// Merge between sparse and dense storage in both directions.
+		aggregatortest.CheckedMerge(t, sparseCkpt, denseCkpt, descriptor)
+		aggregatortest.CheckedMerge(t, denseCkpt, denseCkpt, descriptor)
+		got, err := sparseCkpt.Histogram()
+		require.NoError(t, err)
+		want, err := denseCkpt.Histogram()
+		require.NoError(t, err)
+		require.Equal(t, want.Counts, got.Counts)
+
+		aggregatortest.CheckedMerge(t, dense, sparseCkpt, descriptor)
+		got, err = dense.Histogram()
+		require.NoError(t, err)
+		require.Equal(t, want.Counts, got.Counts)
+	})
+}
+
+func TestHistogramCompress(t *testing.T) {
+	var boundaries []float64
+	for b := 50.0; b < aggregatortest.Magnitude; b += 50 {
+		boundaries = append(boundaries, b)
+	}
+
+	for _, threshold := range []int{0, 4} {
+		aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+			descriptor := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+			opts := []histogram.Option{histogram.WithExplicitBoundaries(boundaries), histogram.WithSparseThreshold(threshold)}
+
+			agg, ckpt := new2(descriptor, opts...)
+			ref, merged := new2(descriptor, opts...)
+			update := func() {
+				for i := 0; i < count; i++ {
+					num := profile.Random(+1)
+					aggregatortest.CheckedUpdate(t, agg, num, descriptor)
+					aggregatortest.CheckedUpdate(t, ref, num, descriptor)
+				}
+			}
+			requireEqual := func(want, got *histogram.Aggregator) {
+				wb, err := want.Histogram()
+				require.NoError(t, err)
+				gb, err := got.Histogram()
+				require.NoError(t, err)
+				require.Equal(t, wb.Counts, gb.Counts)
+				ws, err := want.Sum()
+				require.NoError(t, err)
+				gs, err := got.Sum()
+				require.NoError(t, err)
+				require.Equal(t, ws, gs)
+			}
+
+			// Compressed state is read without being decoded.
+			update()
+			agg.Compress()
+			agg.Compress()
+			requireEqual(ref, agg)
+
+			// Updates decode it.
+			update()
+			requireEqual(ref, agg)
+
+			// Merges from and into compressed states.
+			agg.Compress()
+			aggregatortest.CheckedMerge(t, merged, agg, descriptor)
+			requireEqual(ref, merged)
+			merged.Compress()
+			aggregatortest.CheckedMerge(t, merged, agg, descriptor)
+			aggregatortest.CheckedMerge(t, ref, ref, descriptor)
+			requireEqual(ref, merged)
+
+			// Moving a compressed state resets it.
+			require.NoError(t, agg.SynchronizedMove(ckpt, descriptor))
+			buckets, err := agg.Histogram()
+			require.NoError(t, err)
+			require.Equal(t, make([]uint64, len(boundaries)+1), buckets.Counts)
+			aggregatortest.CheckedUpdate(t, agg, profile.Random(+1), descriptor)
+			cnt, err := agg.Count()
+			require.NoError(t, err)
+			require.Equal(t, uint64(1), cnt)
+		})
+	}
+}
+
+func TestPresetBoundaries(t *testing.T) {
+	for _, name := range []string{histogram.PresetHTTPLatencyMs, histogram.PresetPayloadSizeBytes, histogram.PresetQueueDepth} {
+		boundaries, ok := histogram.PresetBoundaries(name)
+		require.True(t, ok, name)
+		require.True(t, sort.Float64sAreSorted(boundaries), name)
+
+		// The presets are copied.
+		boundaries[0] = -1
+		again, _ := histogram.PresetBoundaries(name)
+		require.NotEqual(t, boundaries[0], again[0])
+	}
+	_, ok := histogram.PresetBoundaries("unknown")
+	require.False(t, ok)
+}
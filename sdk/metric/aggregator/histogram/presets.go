@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package histogram // import "go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+
+// The names of the boundary presets of PresetBoundaries.
+const (
+	// PresetHTTPLatencyMs suits the durations of HTTP requests in
+	// milliseconds, from 5ms to 10s.
+	PresetHTTPLatencyMs = "http-latency-ms"
+	// PresetPayloadSizeBytes suits the sizes of request and response
+	// bodies in bytes, in powers of four from 64B to 16MiB.
+	PresetPayloadSizeBytes = "payload-size-bytes"
+	// PresetQueueDepth suits the number of items waiting in a queue,
+	// from 1 to 1000.
+	PresetQueueDepth = "queue-depth"
+)
+
+var presets = map[string][]float64{
+	PresetHTTPLatencyMs:    {5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000},
+	PresetPayloadSizeBytes: {64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216},
+	PresetQueueDepth:       {1, 2, 5, 10, 20, 50, 100, 200, 500, 1000},
+}
+
+// PresetBoundaries returns a copy of the boundaries of the named preset,
+// for WithExplicitBoundaries, and true, or false for an unknown name.
+func PresetBoundaries(name string) ([]float64, bool) {
+	boundaries, ok := presets[name]
+	if !ok {
+		return nil, false
+	}
+	return append([]float64(nil), boundaries...), true
+}
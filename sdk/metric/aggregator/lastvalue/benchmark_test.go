@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lastvalue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggregatortest"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	controllerTime "go.opentelemetry.io/otel/sdk/metric/controller/time"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+func benchmarkLastValueUpdate(b *testing.B, opts ...lastvalue.Option) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.GaugeObserverInstrumentKind, number.Int64Kind)
+	agg := &lastvalue.New(1, opts...)[0]
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = agg.Update(ctx, number.NewInt64Number(int64(i)), desc)
+	}
+}
+
+func BenchmarkLastValueUpdateTimeNow(b *testing.B) {
+	benchmarkLastValueUpdate(b, lastvalue.WithTimeSource(time.Now))
+}
+
+func BenchmarkLastValueUpdateCoarseClock(b *testing.B) {
+	clock := controllerTime.NewCoarseClock(0)
+	defer clock.Stop()
+	benchmarkLastValueUpdate(b, lastvalue.WithTimeSource(clock.Now))
+}
@@ -32,6 +32,31 @@ type (
 	Aggregator struct {
 		// value is an atomic pointer to *lastValueData.  It is never nil.
 		value unsafe.Pointer
+
+		// sequence points to the counter that orders updates when
+		// SequenceOrdering is configured, otherwise it is nil.  The
+		// counters are allocated in a separate slice so that they
+		// are aligned for 64-bit atomic operations.
+		sequence *uint64
+
+		ordering Ordering
+		now      func() time.Time
+	}
+
+	// Ordering determines which of several concurrent updates of an
+	// Aggregator is retained as the last value.
+	Ordering int
+
+	// config describes how the lastValue is aggregated.
+	config struct {
+		ordering Ordering
+		now      func() time.Time
+	}
+
+	// Option configures a lastValue config.
+	Option interface {
+		// apply sets one or more config fields.
+		apply(*config)
 	}
 
 	// lastValueData stores the current value of a lastValue along with
@@ -46,22 +71,91 @@ type (
 		// used to pick a winner when multiple records contain lastValue data
 		// for the same attributes due to races.
 		timestamp time.Time
+
+		// sequence orders the updates of one Aggregator when
+		// SequenceOrdering is configured.
+		sequence uint64
 	}
 )
 
+const (
+	// TimestampOrdering retains the update that read the clock last.
+	// An update that completes after a later-stamped update does not
+	// overwrite it.  This is the default.
+	TimestampOrdering Ordering = iota
+
+	// SequenceOrdering retains the update that started last, as
+	// determined by a counter incremented on entry to Update.  This
+	// is not subject to the resolution of the clock, at the cost of
+	// one additional atomic operation per update.
+	SequenceOrdering
+
+	// StoreOrdering retains the update that stored its value last.
+	// An update that started earlier but was delayed overwrites the
+	// value of an update that started later.
+	StoreOrdering
+)
+
+// WithOrdering sets the Ordering used to resolve concurrent updates.
+func WithOrdering(ordering Ordering) Option {
+	return orderingOption(ordering)
+}
+
+type orderingOption Ordering
+
+func (o orderingOption) apply(cfg *config) {
+	cfg.ordering = Ordering(o)
+}
+
+// WithTimeSource sets the function that timestamps updates, time.Now by
+// default.  A coarse source such as the Now method of a
+// controller/time.CoarseClock avoids reading the clock on every update.
+// Updates stamped with the same time are resolved in favor of the one
+// stored last under TimestampOrdering; SequenceOrdering is not affected
+// by the resolution of the source.
+func WithTimeSource(now func() time.Time) Option {
+	return timeSourceOption(now)
+}
+
+type timeSourceOption func() time.Time
+
+func (o timeSourceOption) apply(cfg *config) {
+	cfg.now = o
+}
+
 var _ aggregator.Aggregator = &Aggregator{}
 var _ aggregation.LastValue = &Aggregator{}
+var _ aggregator.SliceUpdater = &Aggregator{}
 
 // An unset lastValue has zero timestamp and zero value.
 var unsetLastValue = &lastValueData{}
 
 // New returns a new lastValue aggregator.  This aggregator retains the
-// last value and timestamp that were recorded.
-func New(cnt int) []Aggregator {
+// last value and timestamp that were recorded.  Concurrent updates are
+// resolved according to the configured Ordering, TimestampOrdering by
+// default.
+func New(cnt int, opts ...Option) []Aggregator {
+	cfg := config{
+		now: time.Now,
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+
+	var sequences []uint64
+	if cfg.ordering == SequenceOrdering {
+		sequences = make([]uint64, cnt)
+	}
+
 	aggs := make([]Aggregator, cnt)
 	for i := range aggs {
 		aggs[i] = Aggregator{
-			value: unsafe.Pointer(unsetLastValue),
+			value:    unsafe.Pointer(unsetLastValue),
+			ordering: cfg.ordering,
+			now:      cfg.now,
+		}
+		if sequences != nil {
+			aggs[i].sequence = &sequences[i]
 		}
 	}
 	return aggs
@@ -77,6 +171,11 @@ func (g *Aggregator) Kind() aggregation.Kind {
 	return aggregation.LastValueKind
 }
 
+// Ordering returns the Ordering used to resolve concurrent updates.
+func (g *Aggregator) Ordering() Ordering {
+	return g.ordering
+}
+
 // LastValue returns the last-recorded lastValue value and the
 // corresponding timestamp.  The error value aggregation.ErrNoData
 // will be returned if (due to a race condition) the checkpoint was
@@ -103,18 +202,63 @@ func (g *Aggregator) SynchronizedMove(oa aggregator.Aggregator, _ *sdkapi.Descri
 	return nil
 }
 
-// Update atomically sets the current "last" value.
-func (g *Aggregator) Update(_ context.Context, number number.Number, desc *sdkapi.Descriptor) error {
+// Update atomically sets the current "last" value, unless a concurrent
+// update that takes precedence according to the configured Ordering has
+// already been stored.  The value is stamped with the observation time
+// carried by ctx (see aggregator.ContextWithObservationTime), if any,
+// otherwise with the time source.
+func (g *Aggregator) Update(ctx context.Context, number number.Number, desc *sdkapi.Descriptor) error {
+	ts, ok := aggregator.ObservationTime(ctx)
+	if !ok {
+		ts = g.now()
+	}
 	ngd := &lastValueData{
 		value:     number,
-		timestamp: time.Now(),
+		timestamp: ts,
 	}
-	atomic.StorePointer(&g.value, unsafe.Pointer(ngd))
-	return nil
+	switch g.ordering {
+	case StoreOrdering:
+		atomic.StorePointer(&g.value, unsafe.Pointer(ngd))
+		return nil
+	case SequenceOrdering:
+		ngd.sequence = atomic.AddUint64(g.sequence, 1)
+	}
+
+	for {
+		old := atomic.LoadPointer(&g.value)
+		if g.superseded(ngd, (*lastValueData)(old)) {
+			return nil
+		}
+		if atomic.CompareAndSwapPointer(&g.value, old, unsafe.Pointer(ngd)) {
+			return nil
+		}
+	}
+}
+
+// superseded returns true if ngd takes no precedence over the already stored
+// ogd.  The unset value is superseded by any update.
+func (g *Aggregator) superseded(ngd, ogd *lastValueData) bool {
+	if ogd == unsetLastValue {
+		return false
+	}
+	if g.ordering == SequenceOrdering {
+		return ogd.sequence > ngd.sequence
+	}
+	return ogd.timestamp.After(ngd.timestamp)
+}
+
+// UpdateSlice atomically sets the current "last" value to the final
+// value of the slice.
+func (g *Aggregator) UpdateSlice(ctx context.Context, nums []number.Number, desc *sdkapi.Descriptor) error {
+	if len(nums) == 0 {
+		return nil
+	}
+	return g.Update(ctx, nums[len(nums)-1], desc)
 }
 
 // Merge combines state from two aggregators.  The most-recently set
-// value is chosen.
+// value is chosen, by timestamp regardless of the configured Ordering,
+// since the sequences of different aggregators are not comparable.
 func (g *Aggregator) Merge(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
 	o, _ := oa.(*Aggregator)
 	if o == nil {
@@ -15,9 +15,11 @@
 package lastvalue
 
 import (
+	"context"
 	"errors"
 	"math/rand"
 	"os"
+	"sync"
 	"testing"
 	"time"
 	"unsafe"
@@ -90,6 +92,26 @@ func TestLastValueUpdate(t *testing.T) {
 	})
 }
 
+func TestLastValueUpdateSlice(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		agg, ckpt := new2()
+
+		record := aggregatortest.NewAggregatorTest(sdkapi.GaugeObserverInstrumentKind, profile.NumberKind)
+
+		require.NoError(t, agg.UpdateSlice(context.Background(), nil, record))
+		checkZero(t, agg)
+
+		nums := []number.Number{profile.Random(+1), profile.Random(-1), profile.Random(+1)}
+		require.NoError(t, agg.UpdateSlice(context.Background(), nums, record))
+
+		require.NoError(t, agg.SynchronizedMove(ckpt, record))
+
+		lv, _, err := ckpt.LastValue()
+		require.Equal(t, nums[2], lv, "Last value of the slice")
+		require.Nil(t, err)
+	})
+}
+
 func TestLastValueMerge(t *testing.T) {
 	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
 		agg1, agg2, ckpt1, ckpt2 := new4()
@@ -144,3 +166,93 @@ func TestSynchronizedMoveReset(t *testing.T) {
 		},
 	)
 }
+
+func TestLastValueOrdering(t *testing.T) {
+	ctx := context.Background()
+	desc := aggregatortest.NewAggregatorTest(sdkapi.GaugeObserverInstrumentKind, number.Int64Kind)
+
+	for _, tc := range []struct {
+		name     string
+		ordering Ordering
+		// stored is the value of a concurrent update that has
+		// already been stored when Update(1) stores its value.
+		stored   lastValueData
+		expected int64
+	}{
+		{"timestamp/later", TimestampOrdering, lastValueData{value: 5, timestamp: time.Now().Add(time.Hour)}, 5},
+		{"timestamp/earlier", TimestampOrdering, lastValueData{value: 5, timestamp: time.Now().Add(-time.Hour)}, 1},
+		{"sequence/later", SequenceOrdering, lastValueData{value: 5, sequence: 10}, 5},
+		{"sequence/earlier", SequenceOrdering, lastValueData{value: 5, sequence: 0}, 1},
+		{"store", StoreOrdering, lastValueData{value: 5, timestamp: time.Now().Add(time.Hour), sequence: 10}, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			agg := &New(1, WithOrdering(tc.ordering))[0]
+			stored := tc.stored
+			agg.value = unsafe.Pointer(&stored)
+
+			require.NoError(t, agg.Update(ctx, number.NewInt64Number(1), desc))
+			lv, _, err := agg.LastValue()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, lv.AsInt64())
+		})
+	}
+}
+
+func TestLastValueSequenceOrderingConcurrent(t *testing.T) {
+	const goroutines, updates = 8, 1000
+	ctx := context.Background()
+	desc := aggregatortest.NewAggregatorTest(sdkapi.GaugeObserverInstrumentKind, number.Int64Kind)
+	agg := &New(1, WithOrdering(SequenceOrdering))[0]
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < updates; j++ {
+				require.NoError(t, agg.Update(ctx, number.NewInt64Number(int64(j)), desc))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The retained update is the one that started last.
+	require.Equal(t, uint64(goroutines*updates), (*lastValueData)(agg.value).sequence)
+}
+
+func TestLastValueTimeSource(t *testing.T) {
+	ctx := context.Background()
+	desc := aggregatortest.NewAggregatorTest(sdkapi.GaugeObserverInstrumentKind, number.Int64Kind)
+	stamp := time.Unix(1000, 0)
+	agg := &New(1, WithTimeSource(func() time.Time { return stamp }))[0]
+
+	require.NoError(t, agg.Update(ctx, number.NewInt64Number(1), desc))
+	// An update with the same coarse timestamp replaces the value.
+	require.NoError(t, agg.Update(ctx, number.NewInt64Number(2), desc))
+
+	lv, ts, err := agg.LastValue()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), lv.AsInt64())
+	require.Equal(t, stamp, ts)
+}
+
+func TestLastValueObservationTime(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.GaugeObserverInstrumentKind, number.Int64Kind)
+	measured := time.Unix(1000, 0)
+	agg := &New(1)[0]
+
+	ctx := aggregator.ContextWithObservationTime(context.Background(), measured)
+	require.NoError(t, agg.Update(ctx, number.NewInt64Number(1), desc))
+	lv, ts, err := agg.LastValue()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), lv.AsInt64())
+	require.Equal(t, measured, ts)
+
+	// An update taken earlier than the stored one does not replace it.
+	ctx = aggregator.ContextWithObservationTime(context.Background(), measured.Add(-time.Second))
+	require.NoError(t, agg.Update(ctx, number.NewInt64Number(2), desc))
+	lv, ts, err = agg.LastValue()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), lv.AsInt64())
+	require.Equal(t, measured, ts)
+}
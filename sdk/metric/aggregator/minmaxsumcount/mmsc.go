@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minmaxsumcount // import "go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// Aggregator aggregates events that form a distribution,
+	// keeping only the min, max, sum, and count.
+	Aggregator struct {
+		lock sync.Mutex
+		state
+	}
+
+	// state is the min, max, sum and count of the aggregated
+	// values.  min and max are only set when count is not zero.
+	state struct {
+		count uint64
+		sum   number.Number
+		min   number.Number
+		max   number.Number
+	}
+)
+
+var _ aggregator.Aggregator = &Aggregator{}
+var _ aggregation.MinMaxSumCount = &Aggregator{}
+var _ aggregator.SliceUpdater = &Aggregator{}
+
+// New returns a new aggregator for computing the min, max, sum, and
+// count.  This aggregator keeps much less information about the
+// distribution than a histogram, and is cheaper to update, to merge and
+// to export.
+func New(cnt int) []Aggregator {
+	return make([]Aggregator, cnt)
+}
+
+// Aggregation returns an interface for reading the state of this aggregator.
+func (c *Aggregator) Aggregation() aggregation.Aggregation {
+	return c
+}
+
+// Kind returns aggregation.MinMaxSumCountKind.
+func (c *Aggregator) Kind() aggregation.Kind {
+	return aggregation.MinMaxSumCountKind
+}
+
+// Sum returns the sum of values in the checkpoint.
+func (c *Aggregator) Sum() (number.Number, error) {
+	return c.sum, nil
+}
+
+// Count returns the number of values in the checkpoint.
+func (c *Aggregator) Count() (uint64, error) {
+	return c.count, nil
+}
+
+// Min returns the minimum value in the checkpoint.  The error value
+// aggregation.ErrNoData will be returned if there were no measurements
+// recorded during the checkpoint.
+func (c *Aggregator) Min() (number.Number, error) {
+	if c.count == 0 {
+		return 0, aggregation.ErrNoData
+	}
+	return c.min, nil
+}
+
+// Max returns the maximum value in the checkpoint.  The error value
+// aggregation.ErrNoData will be returned if there were no measurements
+// recorded during the checkpoint.
+func (c *Aggregator) Max() (number.Number, error) {
+	if c.count == 0 {
+		return 0, aggregation.ErrNoData
+	}
+	return c.max, nil
+}
+
+// SynchronizedMove saves the current state into oa and resets the
+// current state to the empty set.
+func (c *Aggregator) SynchronizedMove(oa aggregator.Aggregator, _ *sdkapi.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+
+	if oa != nil && o == nil {
+		return aggregator.NewInconsistentAggregatorError(c, oa)
+	}
+
+	c.lock.Lock()
+	if o != nil {
+		o.state = c.state
+	}
+	c.state = state{}
+	c.lock.Unlock()
+
+	return nil
+}
+
+// Update adds the recorded measurement to the current data set.
+func (c *Aggregator) Update(_ context.Context, num number.Number, desc *sdkapi.Descriptor) error {
+	kind := desc.NumberKind()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.add(kind, state{count: 1, sum: num, min: num, max: num})
+	return nil
+}
+
+// UpdateSlice adds the recorded measurements to the current data set,
+// acquiring the lock once for all of them.
+func (c *Aggregator) UpdateSlice(_ context.Context, nums []number.Number, desc *sdkapi.Descriptor) error {
+	if len(nums) == 0 {
+		return nil
+	}
+	kind := desc.NumberKind()
+
+	s := state{count: uint64(len(nums)), min: nums[0], max: nums[0]}
+	for _, num := range nums {
+		s.sum.AddNumber(kind, num)
+		if num.CompareNumber(kind, s.min) < 0 {
+			s.min = num
+		}
+		if num.CompareNumber(kind, s.max) > 0 {
+			s.max = num
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.add(kind, s)
+	return nil
+}
+
+// Merge combines two data sets into one.  The min and max of an empty
+// data set do not contribute, so that merging the checkpoints of many
+// collectors yields the min and max of all their values.
+func (c *Aggregator) Merge(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentAggregatorError(c, oa)
+	}
+	c.add(desc.NumberKind(), o.state)
+	return nil
+}
+
+// add combines s into the state of c.
+func (c *Aggregator) add(kind number.Kind, s state) {
+	if s.count == 0 {
+		return
+	}
+	if c.count == 0 {
+		c.state = s
+		return
+	}
+	c.count += s.count
+	c.sum.AddNumber(kind, s.sum)
+	if s.min.CompareNumber(kind, c.min) < 0 {
+		c.min = s.min
+	}
+	if s.max.CompareNumber(kind, c.max) > 0 {
+		c.max = s.max
+	}
+}
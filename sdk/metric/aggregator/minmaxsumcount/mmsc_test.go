@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package minmaxsumcount_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggregatortest"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+const count = 100
+
+func checkMinMaxSumCount(t *testing.T, kind number.Kind, all aggregatortest.Numbers, agg *minmaxsumcount.Aggregator) {
+	c, err := agg.Count()
+	require.NoError(t, err)
+	require.Equal(t, all.Count(), c)
+
+	s, err := agg.Sum()
+	require.NoError(t, err)
+	sum := all.Sum()
+	require.InDelta(t, sum.CoerceToFloat64(kind), s.CoerceToFloat64(kind), 1e-6)
+
+	all.Sort()
+	minimum, err := agg.Min()
+	require.NoError(t, err)
+	require.Equal(t, all.Min(), minimum)
+
+	maximum, err := agg.Max()
+	require.NoError(t, err)
+	require.Equal(t, all.Max(), maximum)
+}
+
+func checkZero(t *testing.T, agg *minmaxsumcount.Aggregator) {
+	c, err := agg.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), c)
+
+	s, err := agg.Sum()
+	require.NoError(t, err)
+	require.Equal(t, number.Number(0), s)
+
+	_, err = agg.Min()
+	require.ErrorIs(t, err, aggregation.ErrNoData)
+	_, err = agg.Max()
+	require.ErrorIs(t, err, aggregation.ErrNoData)
+}
+
+func TestMinMaxSumCountUpdate(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+		aggs := minmaxsumcount.New(2)
+		agg, ckpt := &aggs[0], &aggs[1]
+		require.Equal(t, aggregation.MinMaxSumCountKind, agg.Kind())
+		checkZero(t, agg)
+
+		for repeat := 0; repeat < 3; repeat++ {
+			all := aggregatortest.NewNumbers(profile.NumberKind)
+			for i := 0; i < count; i++ {
+				sign := 1
+				if i%3 == 0 {
+					sign = -1
+				}
+				x := profile.Random(sign)
+				all.Append(x)
+				aggregatortest.CheckedUpdate(t, agg, x, desc)
+			}
+
+			require.NoError(t, agg.SynchronizedMove(ckpt, desc))
+			checkZero(t, agg)
+			checkMinMaxSumCount(t, profile.NumberKind, all, ckpt)
+		}
+	})
+}
+
+func TestMinMaxSumCountUpdateSlice(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+		agg := &minmaxsumcount.New(1)[0]
+
+		all := aggregatortest.NewNumbers(profile.NumberKind)
+		var nums []number.Number
+		for i := 0; i < count; i++ {
+			x := profile.Random(+1)
+			all.Append(x)
+			nums = append(nums, x)
+		}
+		require.NoError(t, agg.UpdateSlice(context.Background(), nums, desc))
+		require.NoError(t, agg.UpdateSlice(context.Background(), nil, desc))
+		checkMinMaxSumCount(t, profile.NumberKind, all, agg)
+	})
+}
+
+func TestMinMaxSumCountMerge(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+		aggs := minmaxsumcount.New(4)
+		agg1, agg2, ckpt1, ckpt2 := &aggs[0], &aggs[1], &aggs[2], &aggs[3]
+
+		all := aggregatortest.NewNumbers(profile.NumberKind)
+		for i := 0; i < count; i++ {
+			x1 := profile.Random(+1)
+			all.Append(x1)
+			aggregatortest.CheckedUpdate(t, agg1, x1, desc)
+
+			x2 := profile.Random(-1)
+			all.Append(x2)
+			aggregatortest.CheckedUpdate(t, agg2, x2, desc)
+		}
+		require.NoError(t, agg1.SynchronizedMove(ckpt1, desc))
+		require.NoError(t, agg2.SynchronizedMove(ckpt2, desc))
+
+		// Merging an empty checkpoint, as from a collector that saw no
+		// values, leaves the min and max unchanged.
+		aggregatortest.CheckedMerge(t, ckpt1, agg1, desc)
+		aggregatortest.CheckedMerge(t, ckpt1, ckpt2, desc)
+		checkMinMaxSumCount(t, profile.NumberKind, all, ckpt1)
+
+		// Merging into an empty aggregator copies the state.
+		aggregatortest.CheckedMerge(t, agg1, ckpt1, desc)
+		checkMinMaxSumCount(t, profile.NumberKind, all, agg1)
+
+		require.Error(t, agg1.Merge(&sum.New(1)[0], desc))
+	})
+}
+
+func TestMinMaxSumCountSynchronizedMoveReset(t *testing.T) {
+	aggregatortest.SynchronizedMoveResetTest(
+		t,
+		sdkapi.HistogramInstrumentKind,
+		func(desc *sdkapi.Descriptor) aggregator.Aggregator {
+			return &minmaxsumcount.New(1)[0]
+		},
+	)
+}
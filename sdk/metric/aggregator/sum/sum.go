@@ -32,6 +32,7 @@ type Aggregator struct {
 
 var _ aggregator.Aggregator = &Aggregator{}
 var _ aggregation.Sum = &Aggregator{}
+var _ aggregator.SliceUpdater = &Aggregator{}
 
 // New returns a new counter aggregator implemented by atomic
 // operations.  This aggregator implements the aggregation.Sum
@@ -77,6 +78,18 @@ func (c *Aggregator) Update(_ context.Context, num number.Number, desc *sdkapi.D
 	return nil
 }
 
+// UpdateSlice reduces the values to their sum first, then atomically
+// adds the result to the current value.
+func (c *Aggregator) UpdateSlice(_ context.Context, nums []number.Number, desc *sdkapi.Descriptor) error {
+	kind := desc.NumberKind()
+	var total number.Number
+	for _, num := range nums {
+		total.AddNumber(kind, num)
+	}
+	c.value.AddNumberAtomic(kind, total)
+	return nil
+}
+
 // Merge combines two counters by adding their sums.
 func (c *Aggregator) Merge(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
 	o, _ := oa.(*Aggregator)
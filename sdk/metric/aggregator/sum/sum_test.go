@@ -15,6 +15,7 @@
 package sum
 
 import (
+	"context"
 	"os"
 	"testing"
 	"unsafe"
@@ -87,6 +88,29 @@ func TestCounterSum(t *testing.T) {
 	})
 }
 
+func TestCounterUpdateSlice(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		agg, ckpt := new2()
+
+		descriptor := aggregatortest.NewAggregatorTest(sdkapi.CounterInstrumentKind, profile.NumberKind)
+
+		sum := number.Number(0)
+		nums := make([]number.Number, count)
+		for i := range nums {
+			nums[i] = profile.Random(+1)
+			sum.AddNumber(profile.NumberKind, nums[i])
+		}
+		require.NoError(t, agg.UpdateSlice(context.Background(), nums, descriptor))
+
+		require.NoError(t, agg.SynchronizedMove(ckpt, descriptor))
+		checkZero(t, agg, descriptor)
+
+		asum, err := ckpt.Sum()
+		require.Equal(t, sum, asum, "Same sum - monotonic")
+		require.Nil(t, err)
+	})
+}
+
 func TestHistogramSum(t *testing.T) {
 	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
 		agg, ckpt := new2()
@@ -0,0 +1,414 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tdigest // import "go.opentelemetry.io/otel/sdk/metric/aggregator/tdigest"
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// Aggregator observes events and estimates quantiles of their
+	// distribution with a merging t-digest, which summarizes the
+	// values in a bounded number of centroids.  Centroids near the
+	// median are large and centroids near the extremes are small,
+	// so that the tail quantiles are estimated most accurately.  It
+	// also calculates the sum, count, minimum and maximum of all
+	// events.
+	Aggregator struct {
+		lock        sync.Mutex
+		kind        number.Kind
+		compression float64
+		quantiles   []float64
+		state
+	}
+
+	// config describes how the t-digest is aggregated.
+	config struct {
+		compression float64
+		quantiles   []float64
+	}
+
+	// Option configures a t-digest config.
+	Option interface {
+		// apply sets one or more config fields.
+		apply(*config)
+	}
+
+	// state is the digest of the aggregated values.  centroids are
+	// sorted by mean and compressed, pending are added to them by
+	// the next compression.  min and max are only set when count is
+	// not zero.
+	state struct {
+		centroids []centroid
+		pending   []centroid
+		count     uint64
+		sum       number.Number
+		min       number.Number
+		max       number.Number
+	}
+
+	// centroid is the mean of count values.
+	centroid struct {
+		mean  float64
+		count float64
+	}
+)
+
+const (
+	// DefaultCompression is the default compression of the digest.
+	// The digest keeps on the order of compression centroids.
+	DefaultCompression = 100
+
+	// MinCompression is the smallest compression.
+	MinCompression = 10
+)
+
+// DefaultQuantiles are the quantiles reported by default, the median,
+// 95th and 99th percentiles.
+var DefaultQuantiles = []float64{0.5, 0.95, 0.99}
+
+// WithCompression sets the compression of the digest, which bounds its
+// size: larger compressions estimate quantiles more accurately and use
+// proportionally more memory.  Compressions below MinCompression are
+// raised to MinCompression.
+//
+// The default is DefaultCompression.
+func WithCompression(compression float64) Option {
+	return compressionOption(compression)
+}
+
+type compressionOption float64
+
+func (o compressionOption) apply(config *config) {
+	config.compression = float64(o)
+}
+
+// WithQuantiles sets the quantiles reported by Quantiles, which the
+// exporters export.  Quantiles outside [0, 1] are ignored.
+//
+// The default is DefaultQuantiles.
+func WithQuantiles(quantiles ...float64) Option {
+	return quantilesOption(quantiles)
+}
+
+type quantilesOption []float64
+
+func (o quantilesOption) apply(config *config) {
+	config.quantiles = nil
+	for _, q := range o {
+		if q >= 0 && q <= 1 {
+			config.quantiles = append(config.quantiles, q)
+		}
+	}
+	sort.Float64s(config.quantiles)
+}
+
+var _ aggregator.Aggregator = &Aggregator{}
+var _ aggregation.Summary = &Aggregator{}
+var _ aggregation.Quantile = &Aggregator{}
+var _ aggregator.SliceUpdater = &Aggregator{}
+
+// New returns a new aggregator for estimating quantiles with a
+// t-digest.  Unlike a histogram, it needs no boundaries chosen in
+// advance, and its quantiles can be exported to backends that do not
+// support histograms.  Unlike histograms, the quantiles of several
+// checkpoints cannot be combined accurately after they are exported.
+func New(cnt int, desc *sdkapi.Descriptor, opts ...Option) []Aggregator {
+	cfg := config{
+		compression: DefaultCompression,
+		quantiles:   DefaultQuantiles,
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if !(cfg.compression >= MinCompression) {
+		cfg.compression = MinCompression
+	}
+
+	aggs := make([]Aggregator, cnt)
+	for i := range aggs {
+		aggs[i] = Aggregator{
+			kind:        desc.NumberKind(),
+			compression: cfg.compression,
+			quantiles:   cfg.quantiles,
+		}
+	}
+	return aggs
+}
+
+// Aggregation returns an interface for reading the state of this aggregator.
+func (c *Aggregator) Aggregation() aggregation.Aggregation {
+	return c
+}
+
+// Kind returns aggregation.TDigestKind.
+func (c *Aggregator) Kind() aggregation.Kind {
+	return aggregation.TDigestKind
+}
+
+// Sum returns the sum of values in the checkpoint.
+func (c *Aggregator) Sum() (number.Number, error) {
+	return c.sum, nil
+}
+
+// Count returns the number of values in the checkpoint.
+func (c *Aggregator) Count() (uint64, error) {
+	return c.count, nil
+}
+
+// Min returns the minimum value in the checkpoint.  The error value
+// aggregation.ErrNoData will be returned if there were no measurements
+// recorded during the checkpoint.
+func (c *Aggregator) Min() (number.Number, error) {
+	if c.count == 0 {
+		return 0, aggregation.ErrNoData
+	}
+	return c.min, nil
+}
+
+// Max returns the maximum value in the checkpoint.  The error value
+// aggregation.ErrNoData will be returned if there were no measurements
+// recorded during the checkpoint.
+func (c *Aggregator) Max() (number.Number, error) {
+	if c.count == 0 {
+		return 0, aggregation.ErrNoData
+	}
+	return c.max, nil
+}
+
+// Quantiles returns the quantiles configured to be reported, in
+// increasing order.
+func (c *Aggregator) Quantiles() ([]float64, error) {
+	return c.quantiles, nil
+}
+
+// Quantile returns an estimate of the q-quantile of the values in the
+// checkpoint, interpolated between the means of the centroids, for q in
+// [0, 1].  The 0 and 1 quantiles are the minimum and maximum.  It
+// returns ErrInvalidQuantile for q outside [0, 1], and ErrNoData when no
+// value was recorded.
+func (c *Aggregator) Quantile(q float64) (float64, error) {
+	if !(q >= 0 && q <= 1) {
+		return 0, aggregation.ErrInvalidQuantile
+	}
+	if c.count == 0 {
+		return 0, aggregation.ErrNoData
+	}
+	c.compress()
+	return c.quantile(q), nil
+}
+
+// SynchronizedMove saves the current state into oa and resets the
+// current state to the empty set, reusing the memory of the previous
+// checkpoint.  The checkpoint is compressed after the lock is released.
+func (c *Aggregator) SynchronizedMove(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+
+	if oa != nil && o == nil {
+		return aggregator.NewInconsistentAggregatorError(c, oa)
+	}
+
+	c.lock.Lock()
+	if o != nil {
+		c.state, o.state = o.state, c.state
+	}
+	c.clear()
+	c.lock.Unlock()
+
+	if o != nil {
+		o.compress()
+	}
+	return nil
+}
+
+// clear resets the state, keeping the allocated centroids.
+func (s *state) clear() {
+	s.centroids = s.centroids[:0]
+	s.pending = s.pending[:0]
+	s.count = 0
+	s.sum = 0
+	s.min = 0
+	s.max = 0
+}
+
+// Update adds the recorded measurement to the current data set.
+// Infinite values are only added to the sum.
+func (c *Aggregator) Update(_ context.Context, num number.Number, desc *sdkapi.Descriptor) error {
+	kind := desc.NumberKind()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.update(kind, num)
+	return nil
+}
+
+// UpdateSlice adds the recorded measurements to the current data set,
+// acquiring the lock once for all of them.
+func (c *Aggregator) UpdateSlice(_ context.Context, nums []number.Number, desc *sdkapi.Descriptor) error {
+	kind := desc.NumberKind()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, num := range nums {
+		c.update(kind, num)
+	}
+	return nil
+}
+
+// update adds num to the state, compressing the pending centroids
+// when there are too many of them.  The lock must be held.
+func (c *Aggregator) update(kind number.Kind, num number.Number) {
+	c.sum.AddNumber(kind, num)
+
+	value := num.CoerceToFloat64(kind)
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return
+	}
+	if c.count == 0 || num.CompareNumber(kind, c.min) < 0 {
+		c.min = num
+	}
+	if c.count == 0 || num.CompareNumber(kind, c.max) > 0 {
+		c.max = num
+	}
+	c.count++
+
+	c.pending = append(c.pending, centroid{mean: value, count: 1})
+	if len(c.pending) >= c.pendingLimit() {
+		c.compress()
+	}
+}
+
+// pendingLimit is the number of pending centroids that triggers a
+// compression.
+func (c *Aggregator) pendingLimit() int {
+	return int(5 * c.compression)
+}
+
+// Merge combines two data sets into one.
+func (c *Aggregator) Merge(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	o, _ := oa.(*Aggregator)
+	if o == nil {
+		return aggregator.NewInconsistentAggregatorError(c, oa)
+	}
+	kind := desc.NumberKind()
+
+	c.sum.AddNumber(kind, o.sum)
+	if o.count == 0 {
+		return nil
+	}
+	if c.count == 0 || o.min.CompareNumber(kind, c.min) < 0 {
+		c.min = o.min
+	}
+	if c.count == 0 || o.max.CompareNumber(kind, c.max) > 0 {
+		c.max = o.max
+	}
+	c.count += o.count
+
+	c.pending = append(c.pending, o.centroids...)
+	c.pending = append(c.pending, o.pending...)
+	c.compress()
+	return nil
+}
+
+// compress merges the pending centroids into the centroids, combining
+// neighbors whose weight fits within the limit of the scale function.
+func (c *Aggregator) compress() {
+	if len(c.pending) == 0 {
+		return
+	}
+	all := append(c.centroids, c.pending...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].mean < all[j].mean
+	})
+
+	total := float64(c.count)
+	below := 0.0
+	limit := c.quantileLimit(0)
+	merged := 0
+	current := all[0]
+	for _, next := range all[1:] {
+		if (below+current.count+next.count)/total <= limit {
+			current.mean += (next.mean - current.mean) * next.count / (current.count + next.count)
+			current.count += next.count
+			continue
+		}
+		below += current.count
+		all[merged] = current
+		merged++
+		limit = c.quantileLimit(below / total)
+		current = next
+	}
+	all[merged] = current
+	merged++
+
+	c.centroids = all[:merged]
+	c.pending = c.pending[:0]
+}
+
+// quantileLimit returns the largest quantile that a centroid starting
+// at quantile q may extend to, one unit of the k1 scale function
+//
+//	k(q) = compression / 2π * asin(2q - 1)
+//
+// above q.  The scale function limits the size of the centroids near the
+// minimum and maximum.
+func (c *Aggregator) quantileLimit(q float64) float64 {
+	k := c.compression/(2*math.Pi)*math.Asin(2*q-1) + 1
+	if k >= c.compression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/c.compression) + 1) / 2
+}
+
+// quantile interpolates the q-quantile of a state that is not empty and
+// compressed.  The centroids are
+// placed at the middle of the values they count, between the minimum at
+// rank 0 and the maximum at the count.
+func (c *Aggregator) quantile(q float64) float64 {
+	minimum := c.min.CoerceToFloat64(c.kind)
+	maximum := c.max.CoerceToFloat64(c.kind)
+
+	rank := q * float64(c.count)
+	prevRank, prevValue := 0.0, minimum
+	below := 0.0
+	for _, cent := range c.centroids {
+		centerRank := below + cent.count/2
+		if rank < centerRank {
+			return interpolate(rank, prevRank, prevValue, centerRank, cent.mean)
+		}
+		prevRank, prevValue = centerRank, cent.mean
+		below += cent.count
+	}
+	return interpolate(rank, prevRank, prevValue, below, maximum)
+}
+
+// interpolate returns the value at rank on the line from (r0, v0) to
+// (r1, v1).
+func interpolate(rank, r0, v0, r1, v1 float64) float64 {
+	if r1 <= r0 {
+		return v1
+	}
+	return v0 + (v1-v0)*(rank-r0)/(r1-r0)
+}
@@ -0,0 +1,238 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tdigest_test
+
+import (
+	"context"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggregatortest"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/tdigest"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+const count = 10000
+
+// checkQuantiles tests that the quantiles of agg are close to those of
+// all in rank, the fraction of the values below the estimate being
+// within a percent of the quantile.
+func checkQuantiles(t *testing.T, kind number.Kind, all aggregatortest.Numbers, agg *tdigest.Aggregator) {
+	c, err := agg.Count()
+	require.NoError(t, err)
+	require.Equal(t, all.Count(), c)
+
+	s, err := agg.Sum()
+	require.NoError(t, err)
+	sum := all.Sum()
+	require.InDelta(t, sum.CoerceToFloat64(kind), s.CoerceToFloat64(kind), 1e-6*math.Abs(sum.CoerceToFloat64(kind))+1e-6)
+
+	all.Sort()
+	minimum, err := agg.Min()
+	require.NoError(t, err)
+	require.Equal(t, all.Min(), minimum)
+	maximum, err := agg.Max()
+	require.NoError(t, err)
+	require.Equal(t, all.Max(), maximum)
+
+	values := make([]float64, all.Len())
+	for i, p := range all.Points() {
+		values[i] = p.CoerceToFloat64(kind)
+	}
+	for _, q := range []float64{0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.95, 0.99, 0.999} {
+		est, err := agg.Quantile(q)
+		require.NoError(t, err)
+		below := float64(sort.SearchFloat64s(values, est)) / float64(len(values))
+		require.InDelta(t, q, below, 0.01, "quantile %v estimated %v", q, est)
+	}
+
+	est, err := agg.Quantile(0)
+	require.NoError(t, err)
+	require.Equal(t, values[0], est)
+	est, err = agg.Quantile(1)
+	require.NoError(t, err)
+	require.Equal(t, values[len(values)-1], est)
+}
+
+func checkZero(t *testing.T, agg *tdigest.Aggregator) {
+	c, err := agg.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), c)
+
+	s, err := agg.Sum()
+	require.NoError(t, err)
+	require.Equal(t, number.Number(0), s)
+
+	_, err = agg.Min()
+	require.ErrorIs(t, err, aggregation.ErrNoData)
+	_, err = agg.Quantile(0.5)
+	require.ErrorIs(t, err, aggregation.ErrNoData)
+}
+
+func TestTDigestUpdate(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+		aggs := tdigest.New(2, desc)
+		agg, ckpt := &aggs[0], &aggs[1]
+		require.Equal(t, aggregation.TDigestKind, agg.Kind())
+		checkZero(t, agg)
+
+		for repeat := 0; repeat < 3; repeat++ {
+			all := aggregatortest.NewNumbers(profile.NumberKind)
+			for i := 0; i < count; i++ {
+				sign := 1
+				if i%3 == 0 {
+					sign = -1
+				}
+				x := profile.Random(sign)
+				all.Append(x)
+				aggregatortest.CheckedUpdate(t, agg, x, desc)
+			}
+
+			require.NoError(t, agg.SynchronizedMove(ckpt, desc))
+			checkZero(t, agg)
+			checkQuantiles(t, profile.NumberKind, all, ckpt)
+		}
+	})
+}
+
+func TestTDigestUpdateSlice(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+		aggs := tdigest.New(2, desc)
+		agg, ckpt := &aggs[0], &aggs[1]
+
+		all := aggregatortest.NewNumbers(profile.NumberKind)
+		var nums []number.Number
+		for i := 0; i < count; i++ {
+			x := profile.Random(+1)
+			all.Append(x)
+			nums = append(nums, x)
+		}
+		require.NoError(t, agg.UpdateSlice(context.Background(), nums, desc))
+		require.NoError(t, agg.UpdateSlice(context.Background(), nil, desc))
+		require.NoError(t, agg.SynchronizedMove(ckpt, desc))
+		checkQuantiles(t, profile.NumberKind, all, ckpt)
+	})
+}
+
+func TestTDigestMerge(t *testing.T) {
+	aggregatortest.RunProfiles(t, func(t *testing.T, profile aggregatortest.Profile) {
+		desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, profile.NumberKind)
+		aggs := tdigest.New(4, desc)
+		agg1, agg2, ckpt1, ckpt2 := &aggs[0], &aggs[1], &aggs[2], &aggs[3]
+
+		all := aggregatortest.NewNumbers(profile.NumberKind)
+		for i := 0; i < count; i++ {
+			x1 := profile.Random(+1)
+			all.Append(x1)
+			aggregatortest.CheckedUpdate(t, agg1, x1, desc)
+
+			x2 := profile.Random(-1)
+			all.Append(x2)
+			aggregatortest.CheckedUpdate(t, agg2, x2, desc)
+		}
+		require.NoError(t, agg1.SynchronizedMove(ckpt1, desc))
+		require.NoError(t, agg2.SynchronizedMove(ckpt2, desc))
+
+		// Merging an empty checkpoint leaves the digest unchanged.
+		aggregatortest.CheckedMerge(t, ckpt1, agg1, desc)
+		aggregatortest.CheckedMerge(t, ckpt1, ckpt2, desc)
+		checkQuantiles(t, profile.NumberKind, all, ckpt1)
+
+		// Merging into an empty aggregator copies the digest.
+		aggregatortest.CheckedMerge(t, agg1, ckpt1, desc)
+		checkQuantiles(t, profile.NumberKind, all, agg1)
+
+		require.Error(t, agg1.Merge(&sum.New(1)[0], desc))
+	})
+}
+
+func TestTDigestSmall(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	aggs := tdigest.New(2, desc)
+	agg, ckpt := &aggs[0], &aggs[1]
+
+	for _, v := range []float64{4, 1, 3, 2, math.Inf(+1)} {
+		aggregatortest.CheckedUpdate(t, agg, number.NewFloat64Number(v), desc)
+	}
+	require.NoError(t, agg.SynchronizedMove(ckpt, desc))
+
+	// Infinite values are only added to the sum.
+	c, err := ckpt.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), c)
+	s, err := ckpt.Sum()
+	require.NoError(t, err)
+	require.True(t, math.IsInf(s.AsFloat64(), +1))
+
+	// With one value per centroid, the quantiles interpolate between
+	// the values.
+	for q, expect := range map[float64]float64{
+		0:     1,
+		0.125: 1,
+		0.25:  1.5,
+		0.5:   2.5,
+		0.75:  3.5,
+		1:     4,
+	} {
+		got, err := ckpt.Quantile(q)
+		require.NoError(t, err)
+		require.InDelta(t, expect, got, 1e-9, "quantile %v", q)
+	}
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		_, err := ckpt.Quantile(q)
+		require.ErrorIs(t, err, aggregation.ErrInvalidQuantile)
+	}
+}
+
+func TestTDigestOptions(t *testing.T) {
+	desc := aggregatortest.NewAggregatorTest(sdkapi.HistogramInstrumentKind, number.Int64Kind)
+
+	qs, err := tdigest.New(1, desc)[0].Quantiles()
+	require.NoError(t, err)
+	require.Equal(t, tdigest.DefaultQuantiles, qs)
+
+	agg := &tdigest.New(1, desc, tdigest.WithQuantiles(0.99, 2, 0.5, -1), tdigest.WithCompression(1))[0]
+	qs, err = agg.Quantiles()
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.5, 0.99}, qs)
+
+	// The clamped compression still estimates the median of a uniform
+	// distribution.
+	for i := int64(1); i <= 1000; i++ {
+		aggregatortest.CheckedUpdate(t, agg, number.NewInt64Number(i), desc)
+	}
+	median, err := agg.Quantile(0.5)
+	require.NoError(t, err)
+	require.InDelta(t, 500, median, 50)
+}
+
+func TestTDigestSynchronizedMoveReset(t *testing.T) {
+	aggregatortest.SynchronizedMoveResetTest(
+		t,
+		sdkapi.HistogramInstrumentKind,
+		func(desc *sdkapi.Descriptor) aggregator.Aggregator {
+			return &tdigest.New(1, desc)[0]
+		},
+	)
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregator // import "go.opentelemetry.io/otel/sdk/metric/aggregator"
+
+import (
+	"context"
+	"time"
+)
+
+type observationTimeKey struct{}
+
+// ContextWithObservationTime returns a copy of parent carrying the time
+// at which the observations made with it were taken.  Asynchronous
+// instrument callbacks that relay data measured elsewhere, for example
+// by a device, use it to report the time of the measurement instead of
+// the time of the collection:
+//
+//	gauge.Observe(aggregator.ContextWithObservationTime(ctx, reading.Time), reading.Value)
+//
+// Aggregators that retain a timestamp, such as the lastvalue
+// Aggregator, use the observation time in place of the current time.
+// Other Aggregators ignore it.  The Accumulator rejects observations
+// whose time is further in the future than its configured tolerance.
+func ContextWithObservationTime(parent context.Context, t time.Time) context.Context {
+	return context.WithValue(parent, observationTimeKey{}, t)
+}
+
+// ObservationTime returns the observation time carried by ctx, and false
+// if ctx carries none.
+func ObservationTime(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(observationTimeKey{}).(time.Time)
+	return t, ok
+}
@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apitest provides a conformance test suite for implementations
+// of the metric.Meter API, such as wrappers built on this SDK.
+//
+// This package is currently in a pre-GA phase. Backwards incompatible changes
+// may be introduced in subsequent minor version releases as we work to track the
+// evolving OpenTelemetry specification and user feedback.
+package apitest // import "go.opentelemetry.io/otel/sdk/metric/apitest"
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/nonrecording"
+	"go.opentelemetry.io/otel/sdk/metric/registry"
+)
+
+// Collector collects the Meter under test and returns the value of
+// every exported point keyed by "<instrument name>/<encoded
+// attributes>", using attribute.DefaultEncoder.  The value is the
+// Sum of Counter, UpDownCounter and Histogram points and the last
+// value of Gauge points.  Values must be cumulative.
+type Collector func(ctx context.Context) (map[string]float64, error)
+
+// Harness constructs a new, empty instance of the implementation under
+// test.
+type Harness func(t *testing.T) (metric.Meter, Collector)
+
+// Concurrency is the number of goroutines used by the concurrency tests.
+const Concurrency = 10
+
+// RunConformance runs every conformance test against the implementation
+// constructed by the harness.
+func RunConformance(t *testing.T, h Harness) {
+	t.Run("DuplicateRegistration", func(t *testing.T) {
+		DuplicateRegistrationTest(t, h)
+	})
+	t.Run("ConflictingRegistration", func(t *testing.T) {
+		ConflictingRegistrationTest(t, h)
+	})
+	t.Run("InvalidName", func(t *testing.T) {
+		InvalidNameTest(t, h)
+	})
+	t.Run("Callback", func(t *testing.T) {
+		CallbackTest(t, h)
+	})
+	t.Run("ForeignInstrument", func(t *testing.T) {
+		ForeignInstrumentTest(t, h)
+	})
+	t.Run("Concurrency", func(t *testing.T) {
+		ConcurrencyTest(t, h)
+	})
+}
+
+func encode(name string, attrs ...attribute.KeyValue) string {
+	set := attribute.NewSet(attrs...)
+	return name + "/" + set.Encoded(attribute.DefaultEncoder())
+}
+
+func collect(t *testing.T, c Collector) map[string]float64 {
+	values, err := c(context.Background())
+	require.NoError(t, err)
+	return values
+}
+
+// DuplicateRegistrationTest verifies that registering an identical
+// instrument twice succeeds and that both refer to the same stream.
+func DuplicateRegistrationTest(t *testing.T, h Harness) {
+	ctx := context.Background()
+	meter, c := h(t)
+
+	c1, err := meter.SyncInt64().Counter("dup.counter")
+	require.NoError(t, err)
+	c2, err := meter.SyncInt64().Counter("dup.counter")
+	require.NoError(t, err)
+
+	h1, err := meter.SyncFloat64().Histogram("dup.histogram")
+	require.NoError(t, err)
+	h2, err := meter.SyncFloat64().Histogram("dup.histogram")
+	require.NoError(t, err)
+
+	attr := attribute.String("K", "V")
+	c1.Add(ctx, 1, attr)
+	c2.Add(ctx, 2, attr)
+	h1.Record(ctx, 1.5, attr)
+	h2.Record(ctx, 2.5, attr)
+
+	values := collect(t, c)
+	require.Equal(t, 3.0, values[encode("dup.counter", attr)])
+	require.Equal(t, 4.0, values[encode("dup.histogram", attr)])
+}
+
+// ConflictingRegistrationTest verifies that registering an instrument
+// with the name of an existing instrument of a different kind or number
+// type fails.
+func ConflictingRegistrationTest(t *testing.T, h Harness) {
+	meter, _ := h(t)
+
+	_, err := meter.SyncInt64().Counter("conflict")
+	require.NoError(t, err)
+
+	_, err = meter.SyncFloat64().Counter("conflict")
+	require.Error(t, err)
+	_, err = meter.SyncInt64().UpDownCounter("conflict")
+	require.Error(t, err)
+	_, err = meter.SyncInt64().Histogram("conflict")
+	require.Error(t, err)
+	_, err = meter.AsyncInt64().Counter("conflict")
+	require.Error(t, err)
+	_, err = meter.AsyncInt64().Gauge("conflict")
+	require.Error(t, err)
+}
+
+// InvalidNameTest verifies that creating an instrument of any kind with
+// a name that does not follow the instrument name syntax fails with an
+// error wrapping registry.ErrInvalidInstrumentName.
+func InvalidNameTest(t *testing.T, h Harness) {
+	meter, _ := h(t)
+
+	for _, name := range []string{"", "1invalid", "invalid name", "invalid/name", "invalid:name"} {
+		_, err := meter.SyncInt64().Counter(name)
+		require.ErrorIs(t, err, registry.ErrInvalidInstrumentName, "name %q", name)
+		_, err = meter.SyncFloat64().UpDownCounter(name)
+		require.ErrorIs(t, err, registry.ErrInvalidInstrumentName, "name %q", name)
+		_, err = meter.SyncFloat64().Histogram(name)
+		require.ErrorIs(t, err, registry.ErrInvalidInstrumentName, "name %q", name)
+		_, err = meter.AsyncInt64().Counter(name)
+		require.ErrorIs(t, err, registry.ErrInvalidInstrumentName, "name %q", name)
+		_, err = meter.AsyncFloat64().Gauge(name)
+		require.ErrorIs(t, err, registry.ErrInvalidInstrumentName, "name %q", name)
+	}
+}
+
+// CallbackTest verifies that a registered callback is run exactly once
+// per collection and that its observations are exported.
+func CallbackTest(t *testing.T, h Harness) {
+	meter, c := h(t)
+
+	gauge, err := meter.AsyncFloat64().Gauge("callback.gauge")
+	require.NoError(t, err)
+	counter, err := meter.AsyncInt64().Counter("callback.counter")
+	require.NoError(t, err)
+
+	calls := 0
+	require.NoError(t, meter.RegisterCallback(
+		[]instrument.Asynchronous{gauge, counter},
+		func(ctx context.Context) {
+			calls++
+			gauge.Observe(ctx, float64(calls))
+			counter.Observe(ctx, int64(10*calls))
+		},
+	))
+
+	for i := 1; i <= 3; i++ {
+		values := collect(t, c)
+		require.Equal(t, i, calls)
+		require.Equal(t, float64(i), values[encode("callback.gauge")])
+		require.Equal(t, float64(10*i), values[encode("callback.counter")])
+	}
+}
+
+// ForeignInstrumentTest verifies that registering a callback for an
+// instrument created by another implementation fails.
+func ForeignInstrumentTest(t *testing.T, h Harness) {
+	meter, _ := h(t)
+
+	foreign, err := nonrecording.NewNoopMeterProvider().Meter("foreign").AsyncInt64().Gauge("foreign.gauge")
+	require.NoError(t, err)
+
+	require.Error(t, meter.RegisterCallback(
+		[]instrument.Asynchronous{foreign},
+		func(context.Context) {},
+	))
+}
+
+// ConcurrencyTest verifies that instruments may be created and used
+// concurrently without losing measurements.
+func ConcurrencyTest(t *testing.T, h Harness) {
+	const updates = 100
+	ctx := context.Background()
+	meter, c := h(t)
+
+	var wg sync.WaitGroup
+	wg.Add(Concurrency)
+	for i := 0; i < Concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			counter, err := meter.SyncInt64().Counter("concurrent.counter")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for j := 0; j < updates; j++ {
+				counter.Add(ctx, 1, attribute.Int("J", j%2))
+			}
+		}()
+	}
+	wg.Wait()
+
+	values := collect(t, c)
+	require.Equal(t, float64(Concurrency*updates/2), values[encode("concurrent.counter", attribute.Int("J", 0))])
+	require.Equal(t, float64(Concurrency*updates/2), values[encode("concurrent.counter", attribute.Int("J", 1))])
+}
@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apitest_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric/apitest"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func sdkHarness(t *testing.T) (metric.Meter, apitest.Collector) {
+	provider, exp := metrictest.NewTestMeterProvider()
+	return provider.Meter("apitest"), func(ctx context.Context) (map[string]float64, error) {
+		if err := exp.Collect(ctx); err != nil {
+			return nil, err
+		}
+		values := map[string]float64{}
+		for _, rec := range exp.GetRecords() {
+			set := attribute.NewSet(rec.Attributes...)
+			key := rec.InstrumentName + "/" + set.Encoded(attribute.DefaultEncoder())
+			num := rec.Sum
+			if rec.AggregationKind == aggregation.LastValueKind {
+				num = rec.LastValue
+			}
+			values[key] = num.CoerceToFloat64(rec.NumberKind)
+		}
+		return values, nil
+	}
+}
+
+func TestSDKConformance(t *testing.T) {
+	apitest.RunConformance(t, sdkHarness)
+}
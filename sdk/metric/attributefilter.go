@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// AttributeFilterSelector selects the attribute filter of an instrument,
+// for example the selectors of the
+// go.opentelemetry.io/otel/sdk/metric/processor/reducer package.
+type AttributeFilterSelector interface {
+	// AttributeFilterFor returns the filter of the attributes of
+	// the instrument described by desc, or nil to keep all of
+	// them.  It is called once per instrument.
+	AttributeFilterFor(desc *sdkapi.Descriptor) attribute.Filter
+}
+
+// WithAttributeFilter filters the attributes of the measurements of every
+// instrument with the filter afs selects for it, before they are
+// aggregated, so that the measurements of attribute sets that differ
+// only by filtered attributes share one record.  The verbose views of
+// synchronous instruments keep all attributes.
+func WithAttributeFilter(afs AttributeFilterSelector) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.attributeFilter = afs
+		return cfg
+	})
+}
+
+// attributeFilterFor returns the attribute filter of the instrument
+// described by desc, nil when all attributes are kept.
+func (m *Accumulator) attributeFilterFor(desc *sdkapi.Descriptor) attribute.Filter {
+	if m.attributeFilter == nil {
+		return nil
+	}
+	return m.attributeFilter.AttributeFilterFor(desc)
+}
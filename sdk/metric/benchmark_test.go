@@ -138,6 +138,22 @@ func BenchmarkInt64CounterAddWithAttrs_16(b *testing.B) {
 	benchmarkAttrs(b, 16)
 }
 
+func BenchmarkInt64CounterAddWithAttributeBuffer(b *testing.B) {
+	ctx := context.Background()
+	fix := newFixture(b)
+	labs := makeAttrs(4)
+	cnt := fix.iCounter("int64.sum")
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf := sdkapi.NewAttributeBuffer()
+		buf.Append(labs...)
+		cnt.Add(ctx, 1, buf.Attributes()...)
+		buf.Release()
+	}
+}
+
 // Note: performance does not depend on attribute set size for the benchmarks
 // below--all are benchmarked for a single attribute.
 
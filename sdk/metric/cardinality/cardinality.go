@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cardinality estimates the number of series an export pipeline
+// would produce under a different attribute reduction, from the
+// attribute sets that were actually observed.
+//
+// Platform teams can sample a running process, or replay recorded data,
+// into a Distribution and compare the forecasts of candidate
+// reducer.AttributeFilterSelectors before rolling them out.
+//
+// This package is currently in a pre-GA phase. Backwards incompatible changes
+// may be introduced in subsequent minor version releases as we work to track the
+// evolving OpenTelemetry specification and user feedback.
+package cardinality // import "go.opentelemetry.io/otel/sdk/metric/cardinality"
+
+import (
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/processor/reducer"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// Distribution records the distinct attribute sets observed for each
+// instrument.  The memory it uses is proportional to the number of
+// distinct series observed, so it is intended for analysis rather than
+// to be left running in production.
+type Distribution struct {
+	lock        sync.Mutex
+	instruments map[string]*instrumentDistribution
+}
+
+type instrumentDistribution struct {
+	descriptor *sdkapi.Descriptor
+	sets       map[attribute.Distinct]attribute.Set
+}
+
+// Estimate is the number of series of one instrument.
+type Estimate struct {
+	// Instrument is the name of the instrument.
+	Instrument string
+	// Observed is the number of distinct attribute sets observed.
+	Observed int
+	// Forecast is the number of distinct attribute sets that remain
+	// after the reduction, given the observed attribute sets.
+	Forecast int
+	// UpperBound is the product of the number of distinct values of
+	// every attribute that remains after the reduction.  It bounds the
+	// number of series should the observed values occur in every
+	// combination.  It saturates at the largest int.
+	UpperBound int
+}
+
+// NewDistribution returns an empty Distribution.
+func NewDistribution() *Distribution {
+	return &Distribution{
+		instruments: map[string]*instrumentDistribution{},
+	}
+}
+
+// Add records one observed attribute set of the described instrument.
+func (d *Distribution) Add(desc *sdkapi.Descriptor, attrs *attribute.Set) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	inst, ok := d.instruments[desc.Name()]
+	if !ok {
+		inst = &instrumentDistribution{
+			descriptor: desc,
+			sets:       map[attribute.Distinct]attribute.Set{},
+		}
+		d.instruments[desc.Name()] = inst
+	}
+	if _, ok := inst.sets[attrs.Equivalent()]; !ok {
+		inst.sets[attrs.Equivalent()] = *attrs
+	}
+}
+
+// AddReader records the attribute sets of every record of a collection,
+// for example one replayed from a recording.
+func (d *Distribution) AddReader(reader export.InstrumentationLibraryReader) error {
+	return reader.ForEach(func(_ instrumentation.Library, r export.Reader) error {
+		return r.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+			d.Add(rec.Descriptor(), rec.Attributes())
+			return nil
+		})
+	})
+}
+
+// Forecast estimates the number of series of every observed instrument
+// when its attributes are reduced by selector, ordered by instrument
+// name.  A nil selector keeps all attributes.
+func (d *Distribution) Forecast(selector reducer.AttributeFilterSelector) []Estimate {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	estimates := make([]Estimate, 0, len(d.instruments))
+	for name, inst := range d.instruments {
+		var filter attribute.Filter
+		if selector != nil {
+			filter = selector.AttributeFilterFor(inst.descriptor)
+		}
+
+		reduced := map[attribute.Distinct]struct{}{}
+		values := map[attribute.Key]map[attribute.Value]struct{}{}
+		for _, set := range inst.sets {
+			if filter != nil {
+				set, _ = set.Filter(filter)
+			}
+			reduced[set.Equivalent()] = struct{}{}
+			for iter := set.Iter(); iter.Next(); {
+				kv := iter.Attribute()
+				if values[kv.Key] == nil {
+					values[kv.Key] = map[attribute.Value]struct{}{}
+				}
+				values[kv.Key][kv.Value] = struct{}{}
+			}
+		}
+
+		estimates = append(estimates, Estimate{
+			Instrument: name,
+			Observed:   len(inst.sets),
+			Forecast:   len(reduced),
+			UpperBound: upperBound(values),
+		})
+	}
+	sort.Slice(estimates, func(i, j int) bool {
+		return estimates[i].Instrument < estimates[j].Instrument
+	})
+	return estimates
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// upperBound returns the number of combinations of the values of every
+// key.
+func upperBound(values map[attribute.Key]map[attribute.Value]struct{}) int {
+	bound := 1
+	for _, vs := range values {
+		n := len(vs)
+		if bound > maxInt/n {
+			return maxInt
+		}
+		bound *= n
+	}
+	return bound
+}
+
+// Sampler is a Processor that records the attribute sets of the
+// accumulations it processes into a Distribution before passing them
+// to the next stage in an export pipeline.
+type Sampler struct {
+	export.Checkpointer
+	distribution *Distribution
+}
+
+var _ export.Processor = &Sampler{}
+var _ export.Checkpointer = &Sampler{}
+
+// NewSampler returns a Sampler recording into d that passes data to
+// ckpter.
+func NewSampler(d *Distribution, ckpter export.Checkpointer) *Sampler {
+	return &Sampler{
+		Checkpointer: ckpter,
+		distribution: d,
+	}
+}
+
+// Process implements export.Processor.
+func (s *Sampler) Process(accum export.Accumulation) error {
+	s.distribution.Add(accum.Descriptor(), accum.Attributes())
+	return s.Checkpointer.Process(accum)
+}
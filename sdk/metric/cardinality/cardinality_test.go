@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinality_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/cardinality"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/processor/reducer"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+var (
+	requests = metrictest.NewDescriptor("requests.sum", sdkapi.CounterInstrumentKind, number.Int64Kind)
+	latency  = metrictest.NewDescriptor("latency.sum", sdkapi.CounterInstrumentKind, number.Int64Kind)
+)
+
+type dropKeys []attribute.Key
+
+func (d dropKeys) AttributeFilterFor(*sdkapi.Descriptor) attribute.Filter {
+	return func(kv attribute.KeyValue) bool {
+		for _, k := range d {
+			if kv.Key == k {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func newDistribution() *cardinality.Distribution {
+	d := cardinality.NewDistribution()
+	for route := 0; route < 4; route++ {
+		for user := 0; user < 25; user++ {
+			// Each user only visits the routes of their parity.
+			if user%2 != route%2 {
+				continue
+			}
+			attrs := attribute.NewSet(
+				attribute.String("route", fmt.Sprint("/r", route)),
+				attribute.Int("user", user),
+			)
+			d.Add(&requests, &attrs)
+			d.Add(&requests, &attrs)
+		}
+	}
+	attrs := attribute.NewSet(attribute.String("route", "/r0"))
+	d.Add(&latency, &attrs)
+	return d
+}
+
+func TestForecast(t *testing.T) {
+	d := newDistribution()
+
+	// 13 even users on 2 routes and 12 odd users on 2 routes.
+	require.Equal(t, []cardinality.Estimate{
+		{Instrument: "latency.sum", Observed: 1, Forecast: 1, UpperBound: 1},
+		{Instrument: "requests.sum", Observed: 50, Forecast: 50, UpperBound: 100},
+	}, d.Forecast(nil))
+
+	require.Equal(t, []cardinality.Estimate{
+		{Instrument: "latency.sum", Observed: 1, Forecast: 1, UpperBound: 1},
+		{Instrument: "requests.sum", Observed: 50, Forecast: 4, UpperBound: 4},
+	}, d.Forecast(dropKeys{"user"}))
+
+	require.Equal(t, []cardinality.Estimate{
+		{Instrument: "latency.sum", Observed: 1, Forecast: 1, UpperBound: 1},
+		{Instrument: "requests.sum", Observed: 50, Forecast: 1, UpperBound: 1},
+	}, d.Forecast(dropKeys{"user", "route"}))
+}
+
+func TestAddReader(t *testing.T) {
+	ctx := context.Background()
+	s := &sum.New(1)[0]
+	require.NoError(t, s.Update(ctx, number.NewInt64Number(1), &requests))
+	a1 := attribute.NewSet(attribute.Int("user", 1))
+	a2 := attribute.NewSet(attribute.Int("user", 2))
+
+	d := cardinality.NewDistribution()
+	require.NoError(t, d.AddReader(processorTest.MultiInstrumentationLibraryReader(
+		map[instrumentation.Library][]export.Record{
+			{Name: "test"}: {
+				export.NewRecord(&requests, &a1, s.Aggregation(), time.Now(), time.Now()),
+				export.NewRecord(&requests, &a2, s.Aggregation(), time.Now(), time.Now()),
+			},
+		},
+	)))
+	require.Equal(t, []cardinality.Estimate{
+		{Instrument: "requests.sum", Observed: 2, Forecast: 1, UpperBound: 1},
+	}, d.Forecast(dropKeys{"user"}))
+}
+
+func TestSampler(t *testing.T) {
+	ctx := context.Background()
+	d := cardinality.NewDistribution()
+	testProc := processorTest.NewProcessor(
+		processorTest.AggregatorSelector(),
+		attribute.DefaultEncoder(),
+	)
+	accum := metricsdk.NewAccumulator(
+		cardinality.NewSampler(d, processorTest.NewCheckpointer(testProc)),
+	)
+
+	counter, err := sdkapi.WrapMeterImpl(accum).SyncInt64().Counter("requests.sum", instrument.WithAttributeKeys("route"))
+	require.NoError(t, err)
+	counter.Add(ctx, 1, attribute.Int("user", 1), attribute.String("route", "/a"))
+	counter.Add(ctx, 1, attribute.Int("user", 2), attribute.String("route", "/a"))
+	accum.Collect(ctx)
+
+	require.Equal(t, map[string]float64{
+		"requests.sum/route=/a,user=1/": 1,
+		"requests.sum/route=/a,user=2/": 1,
+	}, testProc.Values())
+	require.Equal(t, []cardinality.Estimate{
+		{Instrument: "requests.sum", Observed: 2, Forecast: 1, UpperBound: 1},
+	}, d.Forecast(reducer.NewAdviceFilterSelector()))
+}
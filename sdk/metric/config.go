@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// config contains the configuration of an Accumulator.
+type config struct {
+	verboseKey           string
+	observationTolerance time.Duration
+	usageSampling        int
+	instrumentChecker    InstrumentChecker
+	contextAttributes    ContextAttributes
+	attributeFilter      AttributeFilterSelector
+}
+
+// Option configures an Accumulator.
+type Option interface {
+	apply(config) config
+}
+
+type optionFunc func(config) config
+
+func (fn optionFunc) apply(cfg config) config {
+	return fn(cfg)
+}
+
+// DefaultObservationTimeTolerance is the default of
+// WithObservationTimeTolerance.
+const DefaultObservationTimeTolerance = 10 * time.Second
+
+// ErrFutureObservation is reported to the global error handler for an
+// asynchronous observation whose observation time is further in the
+// future than the configured tolerance.  The observation is dropped.
+var ErrFutureObservation = fmt.Errorf("observation time is in the future")
+
+// WithObservationTimeTolerance sets how far in the future the observation
+// time of an asynchronous observation, set with
+// aggregator.ContextWithObservationTime, may be to allow for the clock
+// skew of the source of the observation.  Observations beyond the
+// tolerance are dropped and ErrFutureObservation is reported to the
+// global error handler.  A negative tolerance disables the validation.
+//
+// The default is DefaultObservationTimeTolerance.
+func WithObservationTimeTolerance(d time.Duration) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.observationTolerance = d
+		return cfg
+	})
+}
+
+// validObservation returns false, after reporting an error, if the
+// observation time carried by ctx is too far in the future.
+func (m *Accumulator) validObservation(ctx context.Context, desc *sdkapi.Descriptor) bool {
+	if m.observationTolerance < 0 {
+		return true
+	}
+	ts, ok := aggregator.ObservationTime(ctx)
+	if !ok {
+		return true
+	}
+	if skew := time.Until(ts); skew > m.observationTolerance {
+		otel.Handle(fmt.Errorf("%w: %s observed %v ahead", ErrFutureObservation, desc.Name(), skew))
+		return false
+	}
+	return true
+}
+
+// InstrumentChecker inspects the instruments created by an Accumulator,
+// for example against semantic conventions.
+type InstrumentChecker interface {
+	// CheckInstrument returns the descriptor to create the
+	// instrument described by desc with, desc itself or a copy
+	// with inferred properties, and an error describing problems
+	// with desc.
+	CheckInstrument(desc sdkapi.Descriptor) (sdkapi.Descriptor, error)
+}
+
+// WithInstrumentChecker checks every instrument with ic when it is
+// created.  The errors of ic are reported to the global error handler;
+// the instrument is created regardless.
+func WithInstrumentChecker(ic InstrumentChecker) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.instrumentChecker = ic
+		return cfg
+	})
+}
+
+// checkInstrument returns the descriptor of a new instrument, after
+// reporting the errors of the InstrumentChecker.
+func (m *Accumulator) checkInstrument(desc sdkapi.Descriptor) sdkapi.Descriptor {
+	if m.instrumentChecker == nil {
+		return desc
+	}
+	checked, err := m.instrumentChecker.CheckInstrument(desc)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return checked
+}
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// ContextAttributes derives attributes of synchronous measurements from
+// the context they are made in, for example the pprof labels of the
+// recording goroutine.
+type ContextAttributes interface {
+	// ContextAttributes returns the attributes of ctx to add to a
+	// measurement of the instrument described by desc, or nil.  It
+	// is called for every measurement and should be fast.
+	ContextAttributes(ctx context.Context, desc *sdkapi.Descriptor) []attribute.KeyValue
+}
+
+// WithContextAttributes adds the attributes returned by ca to the
+// measurements of synchronous instruments.  The attributes of a
+// measurement take precedence over those of ca sharing the same key.
+func WithContextAttributes(ca ContextAttributes) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.contextAttributes = ca
+		return cfg
+	})
+}
+
+// withContextAttributes returns kvs joined with the attributes derived
+// from ctx for the instrument described by desc, kvs itself when there
+// are none.
+func (m *Accumulator) withContextAttributes(ctx context.Context, desc *sdkapi.Descriptor, kvs []attribute.KeyValue) []attribute.KeyValue {
+	if m.contextAttributes == nil {
+		return kvs
+	}
+	extra := m.contextAttributes.ContextAttributes(ctx, desc)
+	if len(extra) == 0 {
+		return kvs
+	}
+	// NewSet keeps the last of duplicate keys, so the attributes of
+	// the measurement follow those of the context.
+	attrs := make([]attribute.KeyValue, 0, len(extra)+len(kvs))
+	attrs = append(attrs, extra...)
+	return append(attrs, kvs...)
+}
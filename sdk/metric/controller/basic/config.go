@@ -18,7 +18,11 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdk "go.opentelemetry.io/otel/sdk/metric"
+	controllerTime "go.opentelemetry.io/otel/sdk/metric/controller/time"
 	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/processor/reducer"
 	"go.opentelemetry.io/otel/sdk/resource"
 )
 
@@ -57,6 +61,112 @@ type config struct {
 	//
 	// Default value is 10s.  If zero, no Export timeout is applied.
 	PushTimeout time.Duration
+
+	// HealthStaleness is the longest time allowed since the last
+	// successful collection before Healthy() reports ErrStale.
+	//
+	// Default value is 0, which disables the staleness check.
+	HealthStaleness time.Duration
+
+	// MaxDataPointsPerExport is the largest number of data points
+	// presented to the Exporter by one export.  Exports with more data
+	// points are truncated and ErrDataPointsTruncated is reported to
+	// the global error handler.  Pull exporters calling ForEach are
+	// not limited.
+	//
+	// Default value is 0, which does not limit exports.
+	MaxDataPointsPerExport int
+
+	// DataPointPriority orders data points when an export is
+	// truncated to MaxDataPointsPerExport.
+	//
+	// Default value is nil, which gives every data point the same
+	// priority.
+	DataPointPriority DataPointPriority
+
+	// SortedExport orders the data presented to the Exporter and to
+	// pull exporters calling ForEach: instrumentation libraries by
+	// name, version and schema URL, and the records of each library
+	// by instrument name and encoded attributes, so that exports can
+	// be compared with golden files or diffed byte for byte.
+	//
+	// Default value is false, which presents data in an unspecified
+	// order.
+	SortedExport bool
+
+	// VerboseBaggageKey is the baggage member that requests the verbose
+	// views of synchronous instruments, see the WithVerboseBaggage
+	// option of the go.opentelemetry.io/otel/sdk/metric package.
+	//
+	// Default value is "", which disables the verbose views.
+	VerboseBaggageKey string
+
+	// ObservationTimeTolerance is how far in the future the explicit
+	// observation time of an asynchronous observation may be, see
+	// the WithObservationTimeTolerance option of the
+	// go.opentelemetry.io/otel/sdk/metric package.
+	//
+	// Default value is 10s.  If negative, observation times are not
+	// validated.
+	ObservationTimeTolerance time.Duration
+
+	// UsageSampling is the interval of the call sites sampled for the
+	// usage statistics of synchronous instruments, see the
+	// WithUsageSampling option of the go.opentelemetry.io/otel/sdk/metric
+	// package.
+	//
+	// Default value is 0, which disables the statistics.
+	UsageSampling int
+
+	// InstrumentChecker checks the instruments of every Meter when
+	// they are created, see the WithInstrumentChecker option of the
+	// go.opentelemetry.io/otel/sdk/metric package.
+	//
+	// Default value is nil, which disables the checks.
+	InstrumentChecker sdk.InstrumentChecker
+
+	// SharedInstrumentNames checks the names of the instruments of
+	// every instrumentation library against each other, so that two
+	// libraries cannot create instruments of different kinds or
+	// number types with the same name, as needed by exporters such
+	// as Prometheus that do not tell libraries apart.  The
+	// instruments of each library are distinct all the same.
+	//
+	// Default value is false, which checks the names of the
+	// instruments of each library separately, so that libraries
+	// may each create instruments with any name.
+	SharedInstrumentNames bool
+
+	// ContextAttributes derives attributes of synchronous
+	// measurements from their context, see the WithContextAttributes
+	// option of the go.opentelemetry.io/otel/sdk/metric package.
+	//
+	// Default value is nil, which adds no attributes.
+	ContextAttributes sdk.ContextAttributes
+
+	// AttributeAdvice keeps only the attribute keys recommended by
+	// instruments that recommend some with
+	// instrument.WithAttributeKeys, see the NewAdviceFilterSelector
+	// function of the go.opentelemetry.io/otel/sdk/metric/processor/reducer
+	// package.
+	//
+	// Default value is true.  If false, all attributes are kept.
+	AttributeAdvice bool
+
+	// AttributeAdviceOptions configures the filtering of
+	// AttributeAdvice, for example to keep all attributes of some
+	// instruments with reducer.WithFullCardinality.
+	//
+	// Default value is nil.
+	AttributeAdviceOptions []reducer.AdviceOption
+
+	// CoarseClock is refreshed at the start of every collection and
+	// stopped by Stop, for aggregators that timestamp updates with its
+	// Now method, see the NewWithTimeSource function of the
+	// go.opentelemetry.io/otel/sdk/metric/selector/simple package.
+	//
+	// Default value is nil.
+	CoarseClock *controllerTime.CoarseClock
 }
 
 // Option is the interface that applies the value to a configuration option.
@@ -129,3 +239,175 @@ func (o pushTimeoutOption) apply(cfg config) config {
 	cfg.PushTimeout = time.Duration(o)
 	return cfg
 }
+
+// WithHealthStaleness sets the HealthStaleness configuration option of a Config.
+func WithHealthStaleness(staleness time.Duration) Option {
+	return healthStalenessOption(staleness)
+}
+
+type healthStalenessOption time.Duration
+
+func (o healthStalenessOption) apply(cfg config) config {
+	cfg.HealthStaleness = time.Duration(o)
+	return cfg
+}
+
+// DataPointPriority returns the priority of a data point.  When an
+// export is truncated, the data points with the highest priority are
+// kept.  Data points of equal priority are kept in order of instrument
+// name, instrumentation library name and encoded attributes.
+type DataPointPriority func(lib instrumentation.Library, rec export.Record) int
+
+// WithMaxDataPointsPerExport sets the MaxDataPointsPerExport
+// configuration option of a Config.
+func WithMaxDataPointsPerExport(n int) Option {
+	return maxDataPointsOption(n)
+}
+
+type maxDataPointsOption int
+
+func (o maxDataPointsOption) apply(cfg config) config {
+	cfg.MaxDataPointsPerExport = int(o)
+	return cfg
+}
+
+// WithDataPointPriority sets the DataPointPriority configuration option
+// of a Config.
+func WithDataPointPriority(priority DataPointPriority) Option {
+	return dataPointPriorityOption(priority)
+}
+
+type dataPointPriorityOption DataPointPriority
+
+func (o dataPointPriorityOption) apply(cfg config) config {
+	cfg.DataPointPriority = DataPointPriority(o)
+	return cfg
+}
+
+// WithSortedExport sets the SortedExport configuration option of a Config.
+func WithSortedExport(sorted bool) Option {
+	return sortedExportOption(sorted)
+}
+
+type sortedExportOption bool
+
+func (o sortedExportOption) apply(cfg config) config {
+	cfg.SortedExport = bool(o)
+	return cfg
+}
+
+// WithVerboseBaggage sets the VerboseBaggageKey configuration option of a
+// Config.
+func WithVerboseBaggage(key string) Option {
+	return verboseBaggageOption(key)
+}
+
+type verboseBaggageOption string
+
+func (o verboseBaggageOption) apply(cfg config) config {
+	cfg.VerboseBaggageKey = string(o)
+	return cfg
+}
+
+// WithObservationTimeTolerance sets the ObservationTimeTolerance
+// configuration option of a Config.
+func WithObservationTimeTolerance(d time.Duration) Option {
+	return observationTimeToleranceOption(d)
+}
+
+type observationTimeToleranceOption time.Duration
+
+func (o observationTimeToleranceOption) apply(cfg config) config {
+	cfg.ObservationTimeTolerance = time.Duration(o)
+	return cfg
+}
+
+// WithUsageSampling sets the UsageSampling configuration option of a
+// Config.
+func WithUsageSampling(every int) Option {
+	return usageSamplingOption(every)
+}
+
+type usageSamplingOption int
+
+func (o usageSamplingOption) apply(cfg config) config {
+	cfg.UsageSampling = int(o)
+	return cfg
+}
+
+// WithInstrumentChecker sets the InstrumentChecker configuration option
+// of a Config.
+func WithInstrumentChecker(ic sdk.InstrumentChecker) Option {
+	return instrumentCheckerOption{ic}
+}
+
+type instrumentCheckerOption struct{ sdk.InstrumentChecker }
+
+func (o instrumentCheckerOption) apply(cfg config) config {
+	cfg.InstrumentChecker = o.InstrumentChecker
+	return cfg
+}
+
+// WithSharedInstrumentNames sets the SharedInstrumentNames configuration
+// option of a Config.
+func WithSharedInstrumentNames(shared bool) Option {
+	return sharedInstrumentNamesOption(shared)
+}
+
+type sharedInstrumentNamesOption bool
+
+func (o sharedInstrumentNamesOption) apply(cfg config) config {
+	cfg.SharedInstrumentNames = bool(o)
+	return cfg
+}
+
+// WithContextAttributes sets the ContextAttributes configuration option
+// of a Config.
+func WithContextAttributes(ca sdk.ContextAttributes) Option {
+	return contextAttributesOption{ca}
+}
+
+type contextAttributesOption struct{ sdk.ContextAttributes }
+
+func (o contextAttributesOption) apply(cfg config) config {
+	cfg.ContextAttributes = o.ContextAttributes
+	return cfg
+}
+
+// WithAttributeAdvice sets the AttributeAdvice configuration option of a
+// Config.
+func WithAttributeAdvice(enabled bool) Option {
+	return attributeAdviceOption(enabled)
+}
+
+type attributeAdviceOption bool
+
+func (o attributeAdviceOption) apply(cfg config) config {
+	cfg.AttributeAdvice = bool(o)
+	return cfg
+}
+
+// WithAttributeAdviceOptions adds to the AttributeAdviceOptions
+// configuration option of a Config.
+func WithAttributeAdviceOptions(opts ...reducer.AdviceOption) Option {
+	return attributeAdviceOptionsOption(opts)
+}
+
+type attributeAdviceOptionsOption []reducer.AdviceOption
+
+func (o attributeAdviceOptionsOption) apply(cfg config) config {
+	cfg.AttributeAdviceOptions = append(cfg.AttributeAdviceOptions, o...)
+	return cfg
+}
+
+// WithCoarseClock sets the CoarseClock configuration option of a Config.
+func WithCoarseClock(clock *controllerTime.CoarseClock) Option {
+	return coarseClockOption{clock}
+}
+
+type coarseClockOption struct{ *controllerTime.CoarseClock }
+
+func (o coarseClockOption) apply(cfg config) config {
+	cfg.CoarseClock = o.CoarseClock
+	return cfg
+}
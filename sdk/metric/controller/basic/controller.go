@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -26,6 +27,7 @@ import (
 	sdk "go.opentelemetry.io/otel/sdk/metric"
 	controllerTime "go.opentelemetry.io/otel/sdk/metric/controller/time"
 	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/processor/reducer"
 	"go.opentelemetry.io/otel/sdk/metric/registry"
 	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -57,28 +59,66 @@ var ErrControllerStarted = fmt.Errorf("controller already started")
 // using the export.Reader RWLock interface.  Collection will
 // be blocked by a pull request in the basic controller.
 type Controller struct {
+	// sequence is the number of the last collection.  It is
+	// accessed atomically and comes first for 64-bit alignment.
+	sequence uint64
+
 	// lock synchronizes Start() and Stop().
 	lock                sync.Mutex
 	libraries           sync.Map
 	checkpointerFactory export.CheckpointerFactory
 
+	// collectLock serializes collections, so that each checkpoint
+	// is exported before the next one is computed.
+	collectLock sync.Mutex
+
+	// exporterLock protects exporter, which SetExporter replaces
+	// while Healthy and Config may read it.
+	exporterLock sync.RWMutex
+	exporter     export.Exporter
+
 	resource *resource.Resource
-	exporter export.Exporter
 	wg       sync.WaitGroup
 	stopCh   chan struct{}
 	clock    controllerTime.Clock
 	ticker   controllerTime.Ticker
+	// coarseClock is refreshed by every collection, nil when no
+	// CoarseClock is configured.
+	coarseClock *controllerTime.CoarseClock
 
 	collectPeriod  time.Duration
 	collectTimeout time.Duration
 	pushTimeout    time.Duration
 
+	maxDataPoints     int
+	dataPointPriority DataPointPriority
+	sortedExport      bool
+
+	verboseKey           string
+	observationTolerance time.Duration
+	usageSampling        int
+	instrumentChecker    sdk.InstrumentChecker
+	contextAttributes    sdk.ContextAttributes
+	attributeFilter      sdk.AttributeFilterSelector
+	// names is shared by the Meters of every library, nil when
+	// instrument names are checked per library.
+	names *registry.Names
+
 	// collectedTime is used only in configurations with no
 	// exporter, when ticker != nil.
 	collectedTime time.Time
+
+	// healthLock protects the health state below, which is
+	// updated by every collection.
+	healthLock      sync.Mutex
+	healthStaleness time.Duration
+	startedTime     time.Time
+	lastSuccess     time.Time
+	lastErr         error
 }
 
 var _ export.InstrumentationLibraryReader = &Controller{}
+var _ export.SequencedReader = &Controller{}
 var _ metric.MeterProvider = &Controller{}
 
 func (c *Controller) Meter(instrumentationName string, opts ...metric.MeterOption) metric.Meter {
@@ -91,14 +131,22 @@ func (c *Controller) Meter(instrumentationName string, opts ...metric.MeterOptio
 
 	m, ok := c.libraries.Load(library)
 	if !ok {
-		checkpointer := c.checkpointerFactory.NewCheckpointer()
+		checkpointer := export.NewLibraryCheckpointer(c.checkpointerFactory, library)
 		m, _ = c.libraries.LoadOrStore(
 			library,
-			registry.NewUniqueInstrumentMeterImpl(&accumulatorCheckpointer{
-				Accumulator:  sdk.NewAccumulator(checkpointer),
+			registry.NewUniqueInstrumentMeterImplWithNames(&accumulatorCheckpointer{
+				Accumulator: sdk.NewAccumulator(
+					checkpointer,
+					sdk.WithVerboseBaggage(c.verboseKey),
+					sdk.WithObservationTimeTolerance(c.observationTolerance),
+					sdk.WithUsageSampling(c.usageSampling),
+					sdk.WithInstrumentChecker(c.instrumentChecker),
+					sdk.WithContextAttributes(c.contextAttributes),
+					sdk.WithAttributeFilter(c.attributeFilter),
+				),
 				checkpointer: checkpointer,
 				library:      library,
-			}))
+			}, c.names))
 	}
 	return sdkapi.WrapMeterImpl(m.(*registry.UniqueInstrumentMeterImpl))
 }
@@ -119,6 +167,9 @@ func New(checkpointerFactory export.CheckpointerFactory, opts ...Option) *Contro
 		CollectPeriod:  DefaultPeriod,
 		CollectTimeout: DefaultPeriod,
 		PushTimeout:    DefaultPeriod,
+
+		ObservationTimeTolerance: sdk.DefaultObservationTimeTolerance,
+		AttributeAdvice:          true,
 	}
 	for _, opt := range opts {
 		c = opt.apply(c)
@@ -132,16 +183,39 @@ func New(checkpointerFactory export.CheckpointerFactory, opts ...Option) *Contro
 			otel.Handle(err)
 		}
 	}
+	var names *registry.Names
+	if c.SharedInstrumentNames {
+		names = registry.NewNames()
+	}
+	var attributeFilter sdk.AttributeFilterSelector
+	if c.AttributeAdvice {
+		attributeFilter = reducer.NewAdviceFilterSelector(c.AttributeAdviceOptions...)
+	}
 	return &Controller{
 		checkpointerFactory: checkpointerFactory,
 		exporter:            c.Exporter,
 		resource:            c.Resource,
 		stopCh:              nil,
 		clock:               controllerTime.RealClock{},
+		coarseClock:         c.CoarseClock,
 
 		collectPeriod:  c.CollectPeriod,
 		collectTimeout: c.CollectTimeout,
 		pushTimeout:    c.PushTimeout,
+
+		maxDataPoints:     c.MaxDataPointsPerExport,
+		dataPointPriority: c.DataPointPriority,
+		sortedExport:      c.SortedExport,
+
+		verboseKey:           c.VerboseBaggageKey,
+		observationTolerance: c.ObservationTimeTolerance,
+		usageSampling:        c.UsageSampling,
+		instrumentChecker:    c.InstrumentChecker,
+		contextAttributes:    c.ContextAttributes,
+		attributeFilter:      attributeFilter,
+		names:                names,
+
+		healthStaleness: c.HealthStaleness,
 	}
 }
 
@@ -153,6 +227,37 @@ func (c *Controller) SetClock(clock controllerTime.Clock) {
 	c.clock = clock
 }
 
+// SetExporter replaces the exporter of the Controller and returns the
+// previous one, for example to rotate the endpoint or the credentials of
+// an exporter by replacing it with a new exporter.  The state of the
+// checkpointers is kept, so that the next collection exports the same
+// cumulative values to the new exporter, without a gap.  A collection or
+// ForceFlush in progress completes with the previous exporter before
+// SetExporter returns, and the previous exporter can then be shut down.
+//
+// The new exporter must select the same temporality as the one the
+// checkpointers were configured with, since their state depends on it.
+// A nil exporter stops exporting, as when the Controller is configured
+// without WithExporter.
+func (c *Controller) SetExporter(exporter export.Exporter) export.Exporter {
+	c.collectLock.Lock()
+	defer c.collectLock.Unlock()
+	c.exporterLock.Lock()
+	defer c.exporterLock.Unlock()
+
+	prev := c.exporter
+	c.exporter = exporter
+	return prev
+}
+
+// currentExporter returns the exporter of the Controller, nil when
+// metrics are only pulled.
+func (c *Controller) currentExporter() export.Exporter {
+	c.exporterLock.RLock()
+	defer c.exporterLock.RUnlock()
+	return c.exporter
+}
+
 // Resource returns the *resource.Resource associated with this
 // controller.
 func (c *Controller) Resource() *resource.Resource {
@@ -182,6 +287,9 @@ func (c *Controller) Start(ctx context.Context) error {
 	c.wg.Add(1)
 	c.stopCh = make(chan struct{})
 	c.ticker = c.clock.Ticker(c.collectPeriod)
+	c.healthLock.Lock()
+	c.startedTime = c.clock.Now()
+	c.healthLock.Unlock()
 	go c.runTicker(ctx, c.stopCh)
 	return nil
 }
@@ -192,7 +300,13 @@ func (c *Controller) Start(ctx context.Context) error {
 // final asynchronous instruments.
 //
 // Note that Stop() will not cancel an ongoing collection or export.
+//
+// A CoarseClock configured with WithCoarseClock is stopped, after
+// being refreshed by the final collection.
 func (c *Controller) Stop(ctx context.Context) error {
+	if c.coarseClock != nil {
+		defer c.coarseClock.Stop()
+	}
 	if lastCollection := func() bool {
 		c.lock.Lock()
 		defer c.lock.Unlock()
@@ -228,18 +342,36 @@ func (c *Controller) runTicker(ctx context.Context, stopCh chan struct{}) {
 	}
 }
 
+// ForceFlush collects and exports the metric data of the Controller
+// immediately, whether or not it was started.  A ForceFlush concurrent
+// with a periodic collection happens entirely before or after it, so
+// the data of each collection interval is exported exactly once.
+func (c *Controller) ForceFlush(ctx context.Context) error {
+	return c.collect(ctx)
+}
+
 // collect computes a checkpoint and optionally exports it.
 func (c *Controller) collect(ctx context.Context) error {
+	c.collectLock.Lock()
+	defer c.collectLock.Unlock()
+
+	err := c.collectAndExport(ctx)
+	c.recordHealth(err)
+	return err
+}
+
+func (c *Controller) collectAndExport(ctx context.Context) error {
 	if err := c.checkpoint(ctx); err != nil {
 		return err
 	}
-	if c.exporter == nil {
+	exporter := c.currentExporter()
+	if exporter == nil {
 		return nil
 	}
 
 	// Note: this is not subject to collectTimeout.  This blocks the next
 	// collection despite collectTimeout because it holds a lock.
-	return c.export(ctx)
+	return c.export(ctx, exporter)
 }
 
 // accumulatorList returns a snapshot of current accumulators
@@ -261,6 +393,10 @@ func (c *Controller) accumulatorList() []*accumulatorCheckpointer {
 // timeout.  Note that this does not try to cancel a Collect or Export
 // when Stop() is called.
 func (c *Controller) checkpoint(ctx context.Context) error {
+	atomic.AddUint64(&c.sequence, 1)
+	if c.coarseClock != nil {
+		c.coarseClock.Refresh()
+	}
 	for _, impl := range c.accumulatorList() {
 		if err := c.checkpointSingleAccumulator(ctx, impl); err != nil {
 			return err
@@ -310,20 +446,37 @@ func (c *Controller) checkpointSingleAccumulator(ctx context.Context, ac *accumu
 
 // export calls the exporter with a read lock on the Reader,
 // applying the configured export timeout.
-func (c *Controller) export(ctx context.Context) error {
+func (c *Controller) export(ctx context.Context, exporter export.Exporter) error {
 	if c.pushTimeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, c.pushTimeout)
 		defer cancel()
 	}
 
-	return c.exporter.Export(ctx, c.resource, c)
+	if c.maxDataPoints > 0 {
+		return exporter.Export(ctx, c.resource, &truncatingReader{Controller: c, exporter: exporter})
+	}
+	return exporter.Export(ctx, c.resource, c)
+}
+
+// Sequence implements export.SequencedReader.  It returns the number of
+// the last collection, counting those that failed, so that a gap in the
+// numbers of the exported checkpoints reveals a lost interval.
+func (c *Controller) Sequence() (uint64, bool) {
+	return atomic.LoadUint64(&c.sequence), true
 }
 
 // ForEach implements export.InstrumentationLibraryReader.
 func (c *Controller) ForEach(readerFunc func(l instrumentation.Library, r export.Reader) error) error {
-	for _, acPair := range c.accumulatorList() {
-		reader := acPair.checkpointer.Reader()
+	list := c.accumulatorList()
+	if c.sortedExport {
+		sortLibraries(list)
+	}
+	for _, acPair := range list {
+		var reader export.Reader = acPair.checkpointer.Reader()
+		if c.sortedExport {
+			reader = sortedReader{Reader: reader}
+		}
 		// TODO: We should not fail fast; instead accumulate errors.
 		if err := func() error {
 			reader.RLock()
@@ -336,6 +489,20 @@ func (c *Controller) ForEach(readerFunc func(l instrumentation.Library, r export
 	return nil
 }
 
+// Usage returns the usage statistics of the synchronous instruments of
+// each instrumentation library, or nil unless WithUsageSampling is
+// configured.
+func (c *Controller) Usage() map[instrumentation.Library][]sdk.InstrumentUsage {
+	if c.usageSampling <= 0 {
+		return nil
+	}
+	usage := map[instrumentation.Library][]sdk.InstrumentUsage{}
+	for _, ac := range c.accumulatorList() {
+		usage[ac.library] = ac.Usage()
+	}
+	return usage
+}
+
 // IsRunning returns true if the controller was started via Start(),
 // indicating that the current export.Reader is being kept
 // up-to-date.
@@ -347,8 +514,15 @@ func (c *Controller) IsRunning() bool {
 
 // Collect requests a collection.  The collection will be skipped if
 // the last collection is aged less than the configured collection
-// period.
+// period.  It returns ErrReentrantCollect of the
+// go.opentelemetry.io/otel/sdk/metric package when called with the
+// context of a callback, since the collection in progress holds the
+// Reader locks; callbacks must not call ForEach either.
 func (c *Controller) Collect(ctx context.Context) error {
+	if sdk.InCallback(ctx) {
+		// The collection in progress holds collectLock.
+		return sdk.ErrReentrantCollect
+	}
 	if c.IsRunning() {
 		// When there's a non-nil ticker, there's a goroutine
 		// computing checkpoints with the collection period.
@@ -358,7 +532,12 @@ func (c *Controller) Collect(ctx context.Context) error {
 		return nil
 	}
 
-	return c.checkpoint(ctx)
+	c.collectLock.Lock()
+	defer c.collectLock.Unlock()
+
+	err := c.checkpoint(ctx)
+	c.recordHealth(err)
+	return err
 }
 
 // shouldCollect returns true if the collector should collect now,
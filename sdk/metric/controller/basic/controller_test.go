@@ -24,16 +24,23 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	ottest "go.opentelemetry.io/otel/internal/internaltest"
 	"go.opentelemetry.io/otel/metric/instrument"
 	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
 	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
 	"go.opentelemetry.io/otel/sdk/metric/controller/controllertest"
+	controllerTime "go.opentelemetry.io/otel/sdk/metric/controller/time"
 	"go.opentelemetry.io/otel/sdk/metric/export"
 	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
 	"go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/processor/reducer"
+	"go.opentelemetry.io/otel/sdk/metric/registry"
 	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
 	"go.opentelemetry.io/otel/sdk/resource"
 )
 
@@ -281,6 +288,47 @@ func TestObserverContext(t *testing.T) {
 	require.EqualValues(t, expect, getMap(t, cont))
 }
 
+func TestObserverReentrant(t *testing.T) {
+	cont := controller.New(
+		processor.NewFactory(
+			processortest.AggregatorSelector(),
+			aggregation.CumulativeTemporalitySelector(),
+		),
+		controller.WithCollectPeriod(0),
+		controller.WithResource(resource.Empty()),
+	)
+	meter := cont.Meter("go.opentelemetry.io/otel/sdk/metric/controller/basic_test#ObserverReentrant")
+
+	counterObserver, err := meter.AsyncInt64().Counter("reentrant.lastvalue")
+	require.NoError(t, err)
+
+	var collectErr error
+	calls := 0
+	err = meter.RegisterCallback([]instrument.Asynchronous{counterObserver}, func(ctx context.Context) {
+		counterObserver.Observe(ctx, 1)
+		calls++
+		if calls > 1 {
+			return
+		}
+		collectErr = cont.Collect(ctx)
+
+		// A Meter of a new library is collected from the next
+		// collection.
+		counter, err := cont.Meter("late").SyncInt64().Counter("late.sum")
+		require.NoError(t, err)
+		counter.Add(ctx, 2)
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, cont.Collect(context.Background()))
+	require.ErrorIs(t, collectErr, sdk.ErrReentrantCollect)
+	require.NoError(t, cont.Collect(context.Background()))
+	require.EqualValues(t, map[string]float64{
+		"reentrant.lastvalue//": 1,
+		"late.sum//":            2,
+	}, getMap(t, cont))
+}
+
 type blockingExporter struct {
 	calls    int
 	exporter *processortest.Exporter
@@ -491,3 +539,283 @@ func TestRegistryFunction(t *testing.T) {
 		"counter.sum//": 20,
 	}, exp.Values())
 }
+
+func TestVerboseBaggage(t *testing.T) {
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithResource(resource.Empty()),
+		controller.WithCollectPeriod(0),
+		controller.WithVerboseBaggage("debug"),
+	)
+	require.Equal(t, "debug", cont.Config().VerboseBaggageKey)
+
+	counter, err := cont.Meter("test").SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+
+	member, err := baggage.NewMember("debug", "1")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	counter.Add(ctx, 1)
+	counter.Add(baggage.ContextWithBaggage(ctx, bag), 2)
+
+	require.NoError(t, cont.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"counter.sum//":         3,
+		"verbose.counter.sum//": 2,
+	}, getMap(t, cont))
+}
+
+func TestObservationTimeTolerance(t *testing.T) {
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithResource(resource.Empty()),
+		controller.WithCollectPeriod(0),
+		controller.WithObservationTimeTolerance(time.Minute),
+	)
+	require.Equal(t, time.Minute, cont.Config().ObservationTimeTolerance)
+
+	meter := cont.Meter("test")
+	gauge, err := meter.AsyncInt64().Gauge("gauge.lastvalue")
+	require.NoError(t, err)
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{gauge}, func(ctx context.Context) {
+		now := time.Now()
+		gauge.Observe(aggregator.ContextWithObservationTime(ctx, now.Add(-time.Hour)), 1, attribute.String("at", "past"))
+		gauge.Observe(aggregator.ContextWithObservationTime(ctx, now.Add(time.Hour)), 2, attribute.String("at", "future"))
+	}))
+
+	require.NoError(t, cont.Collect(context.Background()))
+	require.EqualValues(t, map[string]float64{
+		"gauge.lastvalue/at=past/": 1,
+	}, getMap(t, cont))
+	require.ErrorIs(t, testHandler.Flush(), sdk.ErrFutureObservation)
+}
+
+func TestUsageSampling(t *testing.T) {
+	ctx := context.Background()
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithCollectPeriod(0),
+		controller.WithUsageSampling(1),
+	)
+	require.Equal(t, 1, cont.Config().UsageSampling)
+
+	counter, err := cont.Meter("used").SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+	_, err = cont.Meter("unused").SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+
+	usage := cont.Usage()
+	require.Len(t, usage, 2)
+	used := usage[instrumentation.Library{Name: "used"}]
+	require.Len(t, used, 1)
+	require.Equal(t, uint64(1), used[0].Updates)
+	require.Len(t, used[0].CallSites, 1)
+	require.Zero(t, usage[instrumentation.Library{Name: "unused"}][0].Updates)
+
+	require.Nil(t, controller.New(newCheckpointerFactory()).Usage())
+}
+
+// libraryValues returns the exported values of each instrumentation
+// library by name.
+func libraryValues(t *testing.T, cont *controller.Controller) map[string]map[string]float64 {
+	values := map[string]map[string]float64{}
+	require.NoError(t, cont.ForEach(
+		func(library instrumentation.Library, reader export.Reader) error {
+			out := processortest.NewOutput(attribute.DefaultEncoder())
+			if err := reader.ForEach(
+				aggregation.CumulativeTemporalitySelector(),
+				func(record export.Record) error {
+					return out.AddRecord(record)
+				},
+			); err != nil {
+				return err
+			}
+			values[library.Name] = out.Map()
+			return nil
+		}))
+	return values
+}
+
+func TestMeterIsolation(t *testing.T) {
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithResource(resource.Empty()),
+		controller.WithCollectPeriod(0),
+	)
+	require.False(t, cont.Config().SharedInstrumentNames)
+	ctx := context.Background()
+
+	// Instruments with the same name in different libraries are
+	// distinct, whatever their kinds.
+	c1, err := cont.Meter("lib1").SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	c2, err := cont.Meter("lib2").SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	_, err = cont.Meter("lib3").SyncFloat64().Histogram("requests.sum")
+	require.NoError(t, err)
+
+	// Within a library, the name identifies the instrument.
+	again, err := cont.Meter("lib1").SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	require.Equal(t, c1, again)
+	_, err = cont.Meter("lib1").SyncFloat64().Counter("requests.sum")
+	require.ErrorIs(t, err, registry.ErrMetricKindMismatch)
+
+	c1.Add(ctx, 1)
+	again.Add(ctx, 2)
+	c2.Add(ctx, 10)
+
+	require.NoError(t, cont.Collect(ctx))
+	require.EqualValues(t, map[string]map[string]float64{
+		"lib1": {"requests.sum//": 3},
+		"lib2": {"requests.sum//": 10},
+		"lib3": {},
+	}, libraryValues(t, cont))
+}
+
+func TestSharedInstrumentNames(t *testing.T) {
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithResource(resource.Empty()),
+		controller.WithCollectPeriod(0),
+		controller.WithSharedInstrumentNames(true),
+	)
+	require.True(t, cont.Config().SharedInstrumentNames)
+	ctx := context.Background()
+
+	c1, err := cont.Meter("lib1").SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	c2, err := cont.Meter("lib2").SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+
+	// Another kind or number type may not take a name used by any
+	// library.
+	_, err = cont.Meter("lib3").SyncFloat64().Histogram("requests.sum")
+	require.ErrorIs(t, err, registry.ErrMetricKindMismatch)
+	_, err = cont.Meter("lib2").SyncFloat64().Counter("requests.sum")
+	require.ErrorIs(t, err, registry.ErrMetricKindMismatch)
+
+	// Compatible instruments of different libraries remain distinct.
+	c1.Add(ctx, 1)
+	c2.Add(ctx, 10)
+
+	require.NoError(t, cont.Collect(ctx))
+	require.EqualValues(t, map[string]map[string]float64{
+		"lib1": {"requests.sum//": 1},
+		"lib2": {"requests.sum//": 10},
+		"lib3": {},
+	}, libraryValues(t, cont))
+}
+
+func TestAttributeAdvice(t *testing.T) {
+	ctx := context.Background()
+	attrs := []attribute.KeyValue{attribute.String("A", "a"), attribute.String("B", "b")}
+	for _, tc := range []struct {
+		name string
+		opts []controller.Option
+		want map[string]float64
+	}{
+		{
+			name: "default",
+			want: map[string]float64{
+				"advised.sum/A=a/": 1,
+				"full.sum/A=a/":    2,
+			},
+		},
+		{
+			name: "disabled",
+			opts: []controller.Option{controller.WithAttributeAdvice(false)},
+			want: map[string]float64{
+				"advised.sum/A=a,B=b/": 1,
+				"full.sum/A=a,B=b/":    2,
+			},
+		},
+		{
+			name: "full cardinality",
+			opts: []controller.Option{controller.WithAttributeAdviceOptions(reducer.WithFullCardinality("full.sum"))},
+			want: map[string]float64{
+				"advised.sum/A=a/":  1,
+				"full.sum/A=a,B=b/": 2,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append([]controller.Option{
+				controller.WithResource(resource.Empty()),
+				controller.WithCollectPeriod(0),
+			}, tc.opts...)
+			cont := controller.New(newCheckpointerFactory(), opts...)
+			meter := cont.Meter("test")
+
+			advised, err := meter.SyncInt64().Counter("advised.sum", instrument.WithAttributeKeys("A"))
+			require.NoError(t, err)
+			full, err := meter.SyncInt64().Counter("full.sum", instrument.WithAttributeKeys("A"))
+			require.NoError(t, err)
+			advised.Add(ctx, 1, attrs...)
+			full.Add(ctx, 2, attrs...)
+
+			require.NoError(t, cont.Collect(ctx))
+			require.EqualValues(t, tc.want, getMap(t, cont))
+		})
+	}
+	require.True(t, controller.New(newCheckpointerFactory()).Config().AttributeAdvice)
+	require.False(t, controller.New(newCheckpointerFactory(), controller.WithAttributeAdvice(false)).Config().AttributeAdvice)
+}
+
+func TestCoarseClock(t *testing.T) {
+	ctx := context.Background()
+	clock := controllerTime.NewCoarseClock(0)
+	created := clock.Now()
+	cont := controller.New(
+		processortest.NewCheckpointerFactory(
+			simple.NewWithTimeSource(processortest.AggregatorSelector(), clock.Now),
+			attribute.DefaultEncoder(),
+		),
+		controller.WithResource(resource.Empty()),
+		controller.WithCollectPeriod(0),
+		controller.WithCoarseClock(clock),
+	)
+	require.True(t, cont.Config().CoarseClock)
+	meter := cont.Meter("test")
+
+	gauge, err := meter.AsyncInt64().Gauge("gauge.lastvalue")
+	require.NoError(t, err)
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{gauge}, func(ctx context.Context) {
+		gauge.Observe(ctx, 1)
+	}))
+
+	time.Sleep(time.Millisecond)
+	require.NoError(t, cont.Collect(ctx))
+	refreshed := clock.Now()
+	require.True(t, refreshed.After(created))
+
+	var stamps []time.Time
+	require.NoError(t, cont.ForEach(func(_ instrumentation.Library, reader export.Reader) error {
+		return reader.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+			_, stamp, err := rec.Aggregation().(aggregation.LastValue).LastValue()
+			stamps = append(stamps, stamp)
+			return err
+		})
+	}))
+	require.Equal(t, []time.Time{refreshed}, stamps)
+}
+
+func TestCoarseClockStopped(t *testing.T) {
+	clock := controllerTime.NewCoarseClock(time.Millisecond)
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithResource(resource.Empty()),
+		controller.WithCoarseClock(clock),
+	)
+	require.NoError(t, cont.Start(context.Background()))
+	require.NoError(t, cont.Stop(context.Background()))
+
+	time.Sleep(5 * time.Millisecond)
+	stopped := clock.Now()
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(t, stopped, clock.Now())
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic // import "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+
+import (
+	"fmt"
+)
+
+// ErrStale indicates that no collection has succeeded within the
+// configured health staleness period.
+var ErrStale = fmt.Errorf("no successful collection within the health staleness period")
+
+// HealthChecker is implemented by metric export pipeline components
+// that can report whether they are working.  Exporters may implement
+// it to contribute to the health of the Controller they are
+// configured with.
+type HealthChecker interface {
+	// Healthy returns nil when the component is working, and an
+	// error describing the problem otherwise.
+	Healthy() error
+}
+
+var _ HealthChecker = &Controller{}
+
+// recordHealth updates the health state with the result of a
+// collection.
+func (c *Controller) recordHealth(err error) {
+	now := c.clock.Now()
+
+	c.healthLock.Lock()
+	defer c.healthLock.Unlock()
+
+	c.lastErr = err
+	if err == nil {
+		c.lastSuccess = now
+	}
+}
+
+// Healthy returns nil when the last collection, including its export
+// when an exporter is configured, succeeded.  It returns ErrStale when
+// a health staleness period is configured (see WithHealthStaleness) and
+// no collection has succeeded within that period since the controller
+// was started.  When the configured exporter implements HealthChecker,
+// its health is reported as well.
+//
+// A controller that has not collected yet is healthy.
+func (c *Controller) Healthy() error {
+	c.healthLock.Lock()
+	lastErr, lastSuccess, started := c.lastErr, c.lastSuccess, c.startedTime
+	c.healthLock.Unlock()
+
+	if lastErr != nil {
+		return fmt.Errorf("last collection failed: %w", lastErr)
+	}
+	if c.healthStaleness > 0 {
+		ref := lastSuccess
+		if ref.IsZero() {
+			ref = started
+		}
+		if !ref.IsZero() && c.clock.Now().Sub(ref) > c.healthStaleness {
+			return ErrStale
+		}
+	}
+	if hc, ok := c.currentExporter().(HealthChecker); ok {
+		return hc.Healthy()
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	"go.opentelemetry.io/otel/sdk/metric/controller/controllertest"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+)
+
+type healthFunc func() error
+
+func (f healthFunc) Healthy() error { return f() }
+
+func TestHealthyExportFailure(t *testing.T) {
+	ctx := context.Background()
+	errExport := fmt.Errorf("export failed")
+	exporter := newExporter()
+	exporter.InjectErr = func(export.Record) error { return errExport }
+
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithExporter(exporter),
+		controller.WithResource(testResource),
+	)
+	require.NoError(t, cont.Healthy())
+
+	counter, err := cont.Meter("health").SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+
+	require.NoError(t, cont.Start(ctx))
+	require.ErrorIs(t, cont.Stop(ctx), errExport)
+	require.ErrorIs(t, cont.Healthy(), errExport)
+
+	exporter.InjectErr = nil
+	require.NoError(t, cont.Start(ctx))
+	require.NoError(t, cont.Stop(ctx))
+	require.NoError(t, cont.Healthy())
+}
+
+func TestHealthyStaleness(t *testing.T) {
+	ctx := context.Background()
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithCollectPeriod(0),
+		controller.WithHealthStaleness(5*time.Second),
+	)
+	mock := controllertest.NewMockClock()
+	cont.SetClock(mock)
+
+	require.NoError(t, cont.Collect(ctx))
+	mock.Add(5 * time.Second)
+	require.NoError(t, cont.Healthy())
+
+	mock.Add(time.Second)
+	require.True(t, errors.Is(cont.Healthy(), controller.ErrStale))
+
+	require.NoError(t, cont.Collect(ctx))
+	require.NoError(t, cont.Healthy())
+}
+
+func TestHealthyExporterHealthChecker(t *testing.T) {
+	errExporter := fmt.Errorf("exporter unhealthy")
+	exporter := struct {
+		export.Exporter
+		healthFunc
+	}{newExporter(), func() error { return errExporter }}
+
+	cont := controller.New(newCheckpointerFactory(), controller.WithExporter(exporter))
+	require.ErrorIs(t, cont.Healthy(), errExporter)
+}
+
+func TestHealthHandler(t *testing.T) {
+	var state error
+	checker := healthFunc(func() error { return state })
+	handler := controller.NewHealthHandler(checker, healthFunc(func() error { return nil }))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "ok\n", rec.Body.String())
+
+	state = fmt.Errorf("broken pipeline")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, "broken pipeline\n", rec.Body.String())
+}
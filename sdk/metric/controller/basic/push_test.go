@@ -23,16 +23,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
 	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
 	"go.opentelemetry.io/otel/sdk/metric/controller/controllertest"
 	"go.opentelemetry.io/otel/sdk/metric/export"
 	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/export/mutate"
 	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
 	"go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
 	"go.opentelemetry.io/otel/sdk/resource"
 )
 
@@ -150,6 +154,122 @@ func TestPushTicker(t *testing.T) {
 	require.NoError(t, p.Stop(ctx))
 }
 
+func TestPushSetExporter(t *testing.T) {
+	first := newExporter()
+	p := controller.New(
+		newCheckpointerFactory(),
+		controller.WithExporter(first),
+		controller.WithCollectPeriod(time.Second),
+		controller.WithResource(testResource),
+	)
+	meter := p.Meter("name")
+
+	mock := controllertest.NewMockClock()
+	p.SetClock(mock)
+
+	ctx := context.Background()
+
+	counter, err := meter.SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(ctx))
+
+	counter.Add(ctx, 3)
+	mock.Add(time.Second)
+	runtime.Gosched()
+
+	require.EqualValues(t, map[string]float64{
+		"counter.sum//R=V": 3,
+	}, first.Values())
+
+	second := newExporter()
+	require.Equal(t, first, p.SetExporter(second))
+	first.Reset()
+
+	// The cumulative state is exported to the new exporter.
+	counter.Add(ctx, 7)
+	mock.Add(time.Second)
+	runtime.Gosched()
+
+	require.EqualValues(t, map[string]float64{}, first.Values())
+	require.Equal(t, 0, first.ExportCount())
+	require.EqualValues(t, map[string]float64{
+		"counter.sum//R=V": 10,
+	}, second.Values())
+	require.Equal(t, 1, second.ExportCount())
+
+	require.Equal(t, second, p.SetExporter(nil))
+	require.NoError(t, p.Stop(ctx))
+	require.Equal(t, 1, second.ExportCount())
+}
+
+func TestPushSetExporterConcurrent(t *testing.T) {
+	p := controller.New(
+		newCheckpointerFactory(),
+		controller.WithExporter(newExporter()),
+		controller.WithCollectPeriod(time.Second),
+		controller.WithResource(testResource),
+	)
+	ctx := context.Background()
+	counter, err := p.Meter("name").SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for _, f := range []func(){
+		func() { p.SetExporter(newExporter()) },
+		func() { _ = p.Healthy() },
+		func() { _ = p.Config() },
+		func() {
+			counter.Add(ctx, 1)
+			_ = p.ForceFlush(ctx)
+		},
+	} {
+		wg.Add(1)
+		go func(f func()) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				f()
+			}
+		}(f)
+	}
+	wg.Wait()
+}
+
+// sequenceExporter records the collection sequence numbers of the
+// checkpoints it exports.
+type sequenceExporter struct {
+	aggregation.TemporalitySelector
+	sequences []uint64
+}
+
+func (e *sequenceExporter) Export(_ context.Context, _ *resource.Resource, reader export.InstrumentationLibraryReader) error {
+	seq, ok := export.Sequence(reader)
+	if !ok {
+		return fmt.Errorf("unnumbered checkpoint")
+	}
+	e.sequences = append(e.sequences, seq)
+	return nil
+}
+
+func TestPushSequence(t *testing.T) {
+	ctx := context.Background()
+	exporter := &sequenceExporter{TemporalitySelector: aggregation.CumulativeTemporalitySelector()}
+	p := controller.New(newCheckpointerFactory(), controller.WithExporter(exporter))
+	seq, ok := p.Sequence()
+	require.True(t, ok)
+	require.Equal(t, uint64(0), seq)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, p.ForceFlush(ctx))
+	}
+	require.Equal(t, []uint64{1, 2, 3}, exporter.sequences)
+
+	// Readers wrapping the Controller forward its sequence numbers.
+	p.SetExporter(mutate.NewExporter(exporter, mutate.DropAttributes("A")))
+	require.NoError(t, p.ForceFlush(ctx))
+	require.Equal(t, []uint64{1, 2, 3, 4}, exporter.sequences)
+}
+
 func TestPushExportError(t *testing.T) {
 	injector := func(name string, e error) func(r export.Record) error {
 		return func(r export.Record) error {
@@ -228,3 +348,172 @@ func TestPushExportError(t *testing.T) {
 		})
 	}
 }
+
+// deltaSumExporter adds up the delta sums of every export.
+type deltaSumExporter struct {
+	lock sync.Mutex
+	sum  int64
+}
+
+func (e *deltaSumExporter) TemporalityFor(*sdkapi.Descriptor, aggregation.Kind) aggregation.Temporality {
+	return aggregation.DeltaTemporality
+}
+
+func (e *deltaSumExporter) Export(_ context.Context, _ *resource.Resource, reader export.InstrumentationLibraryReader) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return reader.ForEach(func(_ instrumentation.Library, r export.Reader) error {
+		return r.ForEach(e, func(rec export.Record) error {
+			sum, err := rec.Aggregation().(aggregation.Sum).Sum()
+			if err != nil {
+				return err
+			}
+			e.sum += sum.AsInt64()
+			return nil
+		})
+	})
+}
+
+func (e *deltaSumExporter) Sum() int64 {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.sum
+}
+
+func TestForceFlushConcurrentWithTicker(t *testing.T) {
+	exporter := &deltaSumExporter{}
+	p := controller.New(
+		processor.NewFactory(processortest.AggregatorSelector(), exporter),
+		controller.WithExporter(exporter),
+		controller.WithCollectPeriod(time.Second),
+	)
+	mock := controllertest.NewMockClock()
+	p.SetClock(mock)
+
+	ctx := context.Background()
+	counter, err := p.Meter("name").SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	require.NoError(t, p.Start(ctx))
+
+	const adds = 1000
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < adds; i++ {
+			counter.Add(ctx, 1)
+			runtime.Gosched()
+		}
+	}()
+	for f := 0; f < 4; f++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				assert.NoError(t, p.ForceFlush(ctx))
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			mock.Add(time.Second)
+			runtime.Gosched()
+		}
+	}()
+	wg.Wait()
+
+	require.NoError(t, p.Stop(ctx))
+	require.Equal(t, int64(adds), exporter.Sum())
+	require.NoError(t, testHandler.Flush())
+}
+
+func TestForceFlushNotStarted(t *testing.T) {
+	exporter := &deltaSumExporter{}
+	p := controller.New(
+		processor.NewFactory(processortest.AggregatorSelector(), exporter),
+		controller.WithExporter(exporter),
+	)
+
+	ctx := context.Background()
+	counter, err := p.Meter("name").SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+
+	counter.Add(ctx, 2)
+	require.NoError(t, p.ForceFlush(ctx))
+	require.Equal(t, int64(2), exporter.Sum())
+
+	// The flushed interval is not exported again.
+	require.NoError(t, p.ForceFlush(ctx))
+	require.Equal(t, int64(2), exporter.Sum())
+}
+
+// pausingReader signals and then pauses the first export, once the
+// checkpoint it reads has been computed.
+type pausingReader struct {
+	export.Reader
+	once    sync.Once
+	reached chan struct{}
+}
+
+func (r *pausingReader) RLock() {
+	r.once.Do(func() {
+		close(r.reached)
+		time.Sleep(50 * time.Millisecond)
+	})
+	r.Reader.RLock()
+}
+
+type pausingCheckpointer struct {
+	export.Checkpointer
+	reader *pausingReader
+}
+
+func (c *pausingCheckpointer) Reader() export.Reader {
+	return c.reader
+}
+
+type pausingFactory struct {
+	export.CheckpointerFactory
+	reached chan struct{}
+}
+
+func (f *pausingFactory) NewCheckpointer() export.Checkpointer {
+	ckpt := f.CheckpointerFactory.NewCheckpointer()
+	return &pausingCheckpointer{
+		Checkpointer: ckpt,
+		reader: &pausingReader{
+			Reader:  ckpt.Reader(),
+			reached: f.reached,
+		},
+	}
+}
+
+func TestForceFlushWaitsForExport(t *testing.T) {
+	exporter := &deltaSumExporter{}
+	reached := make(chan struct{})
+	p := controller.New(
+		&pausingFactory{
+			CheckpointerFactory: processor.NewFactory(processortest.AggregatorSelector(), exporter),
+			reached:             reached,
+		},
+		controller.WithExporter(exporter),
+	)
+
+	ctx := context.Background()
+	counter, err := p.Meter("name").SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+
+	counter.Add(ctx, 1)
+	first := make(chan error)
+	go func() { first <- p.ForceFlush(ctx) }()
+
+	// The second flush starts while the first one is about to
+	// export, its checkpoint must not replace the first one.
+	<-reached
+	counter.Add(ctx, 2)
+	require.NoError(t, p.ForceFlush(ctx))
+	require.NoError(t, <-first)
+	require.Equal(t, int64(3), exporter.Sum())
+}
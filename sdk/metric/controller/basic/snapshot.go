@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic // import "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ConfigSnapshot describes the effective configuration of a Controller,
+// including defaults.  It is intended to be logged or otherwise
+// reported when a service starts, and can be serialized as JSON.
+type ConfigSnapshot struct {
+	// Resource contains the attributes of the Controller's resource,
+	// including those detected from the environment.
+	Resource map[string]string `json:"resource"`
+	// SchemaURL is the schema URL of the Controller's resource.
+	SchemaURL string `json:"schemaURL,omitempty"`
+
+	// CheckpointerFactory is the type of the configured
+	// export.CheckpointerFactory.
+	CheckpointerFactory string `json:"checkpointerFactory"`
+	// Exporter is the type of the configured export.Exporter, or
+	// empty when metrics are only pulled.
+	Exporter string `json:"exporter,omitempty"`
+
+	CollectPeriod   time.Duration `json:"collectPeriod"`
+	CollectTimeout  time.Duration `json:"collectTimeout"`
+	PushTimeout     time.Duration `json:"pushTimeout"`
+	HealthStaleness time.Duration `json:"healthStaleness"`
+
+	// MaxDataPointsPerExport is zero when exports are not limited.
+	MaxDataPointsPerExport int `json:"maxDataPointsPerExport"`
+	// DataPointPriority is true when a DataPointPriority is
+	// configured.
+	DataPointPriority bool `json:"dataPointPriority"`
+	SortedExport      bool `json:"sortedExport"`
+	// VerboseBaggageKey is empty when the verbose views are disabled.
+	VerboseBaggageKey string `json:"verboseBaggageKey,omitempty"`
+	// ObservationTimeTolerance is negative when observation times
+	// are not validated.
+	ObservationTimeTolerance time.Duration `json:"observationTimeTolerance"`
+	// UsageSampling is zero when usage statistics are disabled.
+	UsageSampling int `json:"usageSampling"`
+	// SharedInstrumentNames is true when instrument names are
+	// checked across instrumentation libraries.
+	SharedInstrumentNames bool `json:"sharedInstrumentNames"`
+	// AttributeAdvice is true when the attributes of instruments are
+	// filtered by their attribute key advice.
+	AttributeAdvice bool `json:"attributeAdvice"`
+	// CoarseClock is true when a CoarseClock is refreshed by every
+	// collection.
+	CoarseClock bool `json:"coarseClock"`
+
+	// Libraries contains the names of the instrumentation libraries
+	// that have created a Meter, in sorted order.
+	Libraries []string `json:"libraries"`
+	// Running is true when the Controller has been started.
+	Running bool `json:"running"`
+}
+
+// Config returns the effective configuration of the Controller.
+func (c *Controller) Config() ConfigSnapshot {
+	snap := ConfigSnapshot{
+		Resource:            map[string]string{},
+		SchemaURL:           c.resource.SchemaURL(),
+		CheckpointerFactory: fmt.Sprintf("%T", c.checkpointerFactory),
+
+		CollectPeriod:   c.collectPeriod,
+		CollectTimeout:  c.collectTimeout,
+		PushTimeout:     c.pushTimeout,
+		HealthStaleness: c.healthStaleness,
+
+		MaxDataPointsPerExport: c.maxDataPoints,
+		DataPointPriority:      c.dataPointPriority != nil,
+		SortedExport:           c.sortedExport,
+		VerboseBaggageKey:      c.verboseKey,
+
+		ObservationTimeTolerance: c.observationTolerance,
+		UsageSampling:            c.usageSampling,
+		SharedInstrumentNames:    c.names != nil,
+		AttributeAdvice:          c.attributeFilter != nil,
+		CoarseClock:              c.coarseClock != nil,
+
+		Libraries: []string{},
+		Running:   c.IsRunning(),
+	}
+	if exporter := c.currentExporter(); exporter != nil {
+		snap.Exporter = fmt.Sprintf("%T", exporter)
+	}
+	for iter := c.resource.Iter(); iter.Next(); {
+		kv := iter.Attribute()
+		snap.Resource[string(kv.Key)] = kv.Value.Emit()
+	}
+	for _, ac := range c.accumulatorList() {
+		snap.Libraries = append(snap.Libraries, ac.library.Name)
+	}
+	sort.Strings(snap.Libraries)
+	return snap
+}
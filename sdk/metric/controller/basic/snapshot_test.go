@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+)
+
+func TestConfigSnapshot(t *testing.T) {
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithResource(testResource),
+		controller.WithCollectPeriod(time.Minute),
+		controller.WithMaxDataPointsPerExport(1000),
+	)
+	_ = cont.Meter("b")
+	_ = cont.Meter("a")
+
+	snap := cont.Config()
+	require.Equal(t, "V", snap.Resource["R"])
+	require.Equal(t, time.Minute, snap.CollectPeriod)
+	require.Equal(t, controller.DefaultPeriod, snap.CollectTimeout)
+	require.Equal(t, controller.DefaultPeriod, snap.PushTimeout)
+	require.Equal(t, 1000, snap.MaxDataPointsPerExport)
+	require.Equal(t, 10*time.Second, snap.ObservationTimeTolerance)
+	require.False(t, snap.DataPointPriority)
+	require.Equal(t, "", snap.Exporter)
+	require.Equal(t, []string{"a", "b"}, snap.Libraries)
+	require.False(t, snap.Running)
+
+	_, err := json.Marshal(snap)
+	require.NoError(t, err)
+}
+
+func TestConfigSnapshotExporter(t *testing.T) {
+	ctx := context.Background()
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithExporter(newExporter()),
+	)
+	require.NoError(t, cont.Start(ctx))
+	defer func() { require.NoError(t, cont.Stop(ctx)) }()
+
+	snap := cont.Config()
+	require.Equal(t, "*processortest.Exporter", snap.Exporter)
+	require.Equal(t, "processortest.testFactory", snap.CheckpointerFactory)
+	require.True(t, snap.Running)
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic // import "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+
+import (
+	"errors"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+)
+
+// sortedReader presents the records of a Reader sorted by instrument
+// name, instrument kind and encoded attributes.  The records are
+// gathered under the read lock held by the Controller, which keeps
+// them valid until they are presented.
+type sortedReader struct {
+	export.Reader
+}
+
+// sortedRecord is a record with its encoded attributes.
+type sortedRecord struct {
+	record  export.Record
+	encoded string
+}
+
+var _ export.Reader = sortedReader{}
+
+// ForEach implements export.Reader.
+func (r sortedReader) ForEach(tempSelector aggregation.TemporalitySelector, recordFunc func(export.Record) error) error {
+	var records []sortedRecord
+	if err := r.Reader.ForEach(tempSelector, func(rec export.Record) error {
+		records = append(records, sortedRecord{
+			record:  rec,
+			encoded: rec.Attributes().Encoded(attribute.DefaultEncoder()),
+		})
+		return nil
+	}); err != nil {
+		return err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		di, dj := records[i].record.Descriptor(), records[j].record.Descriptor()
+		if di.Name() != dj.Name() {
+			return di.Name() < dj.Name()
+		}
+		if di.InstrumentKind() != dj.InstrumentKind() {
+			return di.InstrumentKind() < dj.InstrumentKind()
+		}
+		return records[i].encoded < records[j].encoded
+	})
+	for _, sr := range records {
+		if err := recordFunc(sr.record); err != nil && !errors.Is(err, aggregation.ErrNoData) {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortLibraries sorts the accumulators of the Controller by the name,
+// version and schema URL of their instrumentation libraries.
+func sortLibraries(list []*accumulatorCheckpointer) {
+	sort.Slice(list, func(i, j int) bool {
+		li, lj := list[i].library, list[j].library
+		if li.Name != lj.Name {
+			return li.Name < lj.Name
+		}
+		if li.Version != lj.Version {
+			return li.Version < lj.Version
+		}
+		return li.SchemaURL < lj.SchemaURL
+	})
+}
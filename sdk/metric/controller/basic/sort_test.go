@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	"go.opentelemetry.io/otel/sdk/metric/controller/controllertest"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestSortedExport(t *testing.T) {
+	ctx := context.Background()
+	puller := controller.New(
+		processor.NewFactory(
+			processortest.AggregatorSelector(),
+			aggregation.CumulativeTemporalitySelector(),
+		),
+		controller.WithCollectPeriod(0),
+		controller.WithResource(resource.Empty()),
+		controller.WithSortedExport(true),
+	)
+	require.True(t, puller.Config().SortedExport)
+
+	for _, lib := range []string{"lib3", "lib1", "lib2"} {
+		meter := puller.Meter(lib)
+		for _, name := range []string{"c.sum", "a.sum", "b.sum"} {
+			counter, err := meter.SyncInt64().Counter(name)
+			require.NoError(t, err)
+			for _, v := range []string{"z", "x", "y"} {
+				counter.Add(ctx, 1, attribute.String("K", v), attribute.String("A", v))
+			}
+		}
+	}
+	require.NoError(t, puller.Collect(ctx))
+
+	var got []string
+	require.NoError(t, controllertest.ReadAll(puller, aggregation.CumulativeTemporalitySelector(), func(lib instrumentation.Library, rec export.Record) error {
+		got = append(got, lib.Name+"/"+rec.Descriptor().Name()+"/"+rec.Attributes().Encoded(attribute.DefaultEncoder()))
+		return nil
+	}))
+
+	var expect []string
+	for _, lib := range []string{"lib1", "lib2", "lib3"} {
+		for _, name := range []string{"a.sum", "b.sum", "c.sum"} {
+			for _, v := range []string{"x", "y", "z"} {
+				expect = append(expect, lib+"/"+name+"/A="+v+",K="+v)
+			}
+		}
+	}
+	require.Equal(t, expect, got)
+}
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic // import "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+
+import (
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// ErrDataPointsTruncated indicates that an export exceeded the
+// configured maximum number of data points and some were dropped.
+var ErrDataPointsTruncated = fmt.Errorf("export truncated to the maximum number of data points")
+
+// truncatingReader presents at most maxDataPoints of the Controller's
+// data points to the Exporter, preferring those with the highest
+// priority.
+//
+// The checkpoint does not change during an export, so the data points
+// are selected during a first pass over the Controller and filtered
+// during a second one.  This avoids retaining records beyond the
+// reader lock that protects them.
+type truncatingReader struct {
+	*Controller
+	// exporter is the exporter of the export, whose temporality
+	// selects the records.
+	exporter export.Exporter
+}
+
+// dataPointKey identifies a data point within one export.
+type dataPointKey struct {
+	library    instrumentation.Library
+	descriptor *sdkapi.Descriptor
+	attributes attribute.Distinct
+}
+
+var _ export.InstrumentationLibraryReader = &truncatingReader{}
+
+// ForEach implements export.InstrumentationLibraryReader.
+func (t *truncatingReader) ForEach(readerFunc func(l instrumentation.Library, r export.Reader) error) error {
+	keep, total, err := t.selectDataPoints()
+	if err != nil {
+		return err
+	}
+	if keep == nil {
+		return t.Controller.ForEach(readerFunc)
+	}
+	otel.Handle(fmt.Errorf("%w: dropped %d of %d data points", ErrDataPointsTruncated, total-len(keep), total))
+
+	libraries := map[instrumentation.Library]struct{}{}
+	for key := range keep {
+		libraries[key.library] = struct{}{}
+	}
+	return t.Controller.ForEach(func(lib instrumentation.Library, r export.Reader) error {
+		if _, ok := libraries[lib]; !ok {
+			return nil
+		}
+		return readerFunc(lib, &truncatedReader{
+			Reader:  r,
+			library: lib,
+			keep:    keep,
+		})
+	})
+}
+
+// selectDataPoints returns the data points to keep and the total
+// number of data points.  The returned set is nil when the export does
+// not need to be truncated.
+func (t *truncatingReader) selectDataPoints() (map[dataPointKey]struct{}, int, error) {
+	type candidate struct {
+		key      dataPointKey
+		priority int
+		encoded  string
+	}
+	var candidates []candidate
+
+	err := t.Controller.ForEach(func(lib instrumentation.Library, r export.Reader) error {
+		return r.ForEach(t.exporter, func(rec export.Record) error {
+			var priority int
+			if t.dataPointPriority != nil {
+				priority = t.dataPointPriority(lib, rec)
+			}
+			candidates = append(candidates, candidate{
+				key:      newDataPointKey(lib, rec),
+				priority: priority,
+				encoded:  rec.Attributes().Encoded(attribute.DefaultEncoder()),
+			})
+			return nil
+		})
+	})
+	if err != nil || len(candidates) <= t.maxDataPoints {
+		return nil, len(candidates), err
+	}
+
+	// The checkpoint is visited in an unspecified order; sorting by
+	// name and attributes makes the selection among equal priorities stable
+	// from one export to the next.
+	sort.Slice(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if ci.priority != cj.priority {
+			return ci.priority > cj.priority
+		}
+		if ci.key.descriptor.Name() != cj.key.descriptor.Name() {
+			return ci.key.descriptor.Name() < cj.key.descriptor.Name()
+		}
+		if ci.key.library.Name != cj.key.library.Name {
+			return ci.key.library.Name < cj.key.library.Name
+		}
+		return ci.encoded < cj.encoded
+	})
+	keep := make(map[dataPointKey]struct{}, t.maxDataPoints)
+	for _, c := range candidates[:t.maxDataPoints] {
+		keep[c.key] = struct{}{}
+	}
+	return keep, len(candidates), nil
+}
+
+func newDataPointKey(lib instrumentation.Library, rec export.Record) dataPointKey {
+	return dataPointKey{
+		library:    lib,
+		descriptor: rec.Descriptor(),
+		attributes: rec.Attributes().Equivalent(),
+	}
+}
+
+// truncatedReader presents only the kept records of one library.
+type truncatedReader struct {
+	export.Reader
+	library instrumentation.Library
+	keep    map[dataPointKey]struct{}
+}
+
+// ForEach implements export.Reader.
+func (r *truncatedReader) ForEach(tempSelector aggregation.TemporalitySelector, recordFunc func(export.Record) error) error {
+	return r.Reader.ForEach(tempSelector, func(rec export.Record) error {
+		if _, ok := r.keep[newDataPointKey(r.library, rec)]; !ok {
+			return nil
+		}
+		return recordFunc(rec)
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basic_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+)
+
+func TestMaxDataPointsPerExport(t *testing.T) {
+	ctx := context.Background()
+	exporter := newExporter()
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithExporter(exporter),
+		controller.WithResource(testResource),
+		controller.WithMaxDataPointsPerExport(3),
+		controller.WithDataPointPriority(func(_ instrumentation.Library, rec export.Record) int {
+			if strings.HasPrefix(rec.Descriptor().Name(), "critical.") {
+				return 1
+			}
+			return 0
+		}),
+	)
+
+	critical, err := cont.Meter("lib1").SyncInt64().Counter("critical.sum")
+	require.NoError(t, err)
+	noisy, err := cont.Meter("lib2").SyncInt64().Counter("noisy.sum")
+	require.NoError(t, err)
+
+	critical.Add(ctx, 1, attribute.Int("A", 1))
+	critical.Add(ctx, 2, attribute.Int("A", 2))
+	for i := 0; i < 10; i++ {
+		noisy.Add(ctx, 1, attribute.Int("B", i))
+	}
+
+	require.NoError(t, testHandler.Flush())
+	require.NoError(t, cont.Start(ctx))
+	require.NoError(t, cont.Stop(ctx))
+	require.ErrorIs(t, testHandler.Flush(), controller.ErrDataPointsTruncated)
+
+	values := exporter.Values()
+	require.Len(t, values, 3)
+	require.Equal(t, 1.0, values["critical.sum/A=1/R=V"])
+	require.Equal(t, 2.0, values["critical.sum/A=2/R=V"])
+	require.Equal(t, 1.0, values["noisy.sum/B=0/R=V"])
+}
+
+func TestMaxDataPointsPerExportUnderLimit(t *testing.T) {
+	ctx := context.Background()
+	exporter := newExporter()
+	cont := controller.New(
+		newCheckpointerFactory(),
+		controller.WithExporter(exporter),
+		controller.WithResource(testResource),
+		controller.WithMaxDataPointsPerExport(2),
+	)
+
+	counter, err := cont.Meter("lib").SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	counter.Add(ctx, 1, attribute.Int("A", 1))
+	counter.Add(ctx, 1, attribute.Int("A", 2))
+
+	require.NoError(t, testHandler.Flush())
+	require.NoError(t, cont.Start(ctx))
+	require.NoError(t, cont.Stop(ctx))
+	require.NoError(t, testHandler.Flush())
+	require.Len(t, exporter.Values(), 2)
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time // import "go.opentelemetry.io/otel/sdk/metric/controller/time"
+
+import (
+	"sync"
+	"sync/atomic"
+	lib "time"
+)
+
+// CoarseClock is a Clock whose Now returns a cached time that is
+// refreshed periodically, or on demand by calling Refresh.  Reading the
+// cached time is cheaper than calling time.Now, which matters for
+// instruments updated at a very high rate whose timestamps need not be
+// precise, at the cost of timestamps that lag by up to the resolution.
+//
+// The cached time carries no monotonic clock reading.
+type CoarseClock struct {
+	now atomic.Value
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+var _ Clock = &CoarseClock{}
+
+// NewCoarseClock returns a CoarseClock that refreshes its time every
+// resolution.  When resolution is not positive the time is only
+// refreshed by calls to Refresh, for example once per collection by a
+// controller/basic Controller configured with WithCoarseClock.
+// Stop must be called to release the CoarseClock.
+func NewCoarseClock(resolution lib.Duration) *CoarseClock {
+	c := &CoarseClock{
+		stopCh: make(chan struct{}),
+	}
+	c.Refresh()
+	if resolution > 0 {
+		ticker := lib.NewTicker(resolution)
+		go func() {
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					c.Refresh()
+				case <-c.stopCh:
+					return
+				}
+			}
+		}()
+	}
+	return c
+}
+
+// Now returns the time of the last refresh.
+func (c *CoarseClock) Now() lib.Time {
+	return c.now.Load().(lib.Time)
+}
+
+// Refresh sets the time returned by Now to the current time.
+func (c *CoarseClock) Refresh() {
+	c.now.Store(lib.Now().Round(0))
+}
+
+// Ticker returns a Ticker of the real clock.
+func (c *CoarseClock) Ticker(period lib.Duration) Ticker {
+	return RealClock{}.Ticker(period)
+}
+
+// Stop stops the periodic refresh.  Now continues to return the time of
+// the last refresh.
+func (c *CoarseClock) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	controllerTime "go.opentelemetry.io/otel/sdk/metric/controller/time"
+)
+
+func TestCoarseClockRefresh(t *testing.T) {
+	c := controllerTime.NewCoarseClock(0)
+	defer c.Stop()
+
+	first := c.Now()
+	time.Sleep(2 * time.Millisecond)
+	require.Equal(t, first, c.Now())
+
+	c.Refresh()
+	require.True(t, c.Now().After(first))
+}
+
+func TestCoarseClockResolution(t *testing.T) {
+	c := controllerTime.NewCoarseClock(time.Millisecond)
+	first := c.Now()
+	require.Eventually(t, func() bool {
+		return c.Now().After(first)
+	}, time.Second, time.Millisecond)
+
+	c.Stop()
+	c.Stop()
+}
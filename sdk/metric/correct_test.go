@@ -20,11 +20,13 @@ import (
 	"math"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/instrument"
 	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
@@ -34,6 +36,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/metric/export"
 	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 	"go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/processor/reducer"
 	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
 )
 
@@ -162,6 +165,123 @@ func TestInputRangeHistogram(t *testing.T) {
 	require.Nil(t, testHandler.Flush())
 }
 
+func TestRecordSlice(t *testing.T) {
+	ctx := context.Background()
+	meter, sdk, _, processor := newSDK(t)
+
+	counter, err := meter.SyncInt64().Counter("name.sum")
+	require.NoError(t, err)
+	histogram, err := meter.SyncFloat64().Histogram("name.histogram")
+	require.NoError(t, err)
+
+	attr := attribute.String("A", "B")
+	sdkapi.RecordInt64s(ctx, counter, []int64{1, -1, 2, 3}, attr)
+	require.Equal(t, aggregation.ErrNegativeInput, testHandler.Flush())
+	sdkapi.RecordFloat64s(ctx, histogram, []float64{0.5, math.NaN(), 1.5}, attr)
+	require.Equal(t, aggregation.ErrNaNInput, testHandler.Flush())
+
+	checkpointed := sdk.Collect(ctx)
+	require.Equal(t, map[string]float64{
+		"name.sum/A=B/":       6,
+		"name.histogram/A=B/": 2,
+	}, processor.Values())
+	require.Equal(t, 2, checkpointed)
+
+	// Empty and fully-invalid slices do not produce an update.
+	processor.Reset()
+	sdkapi.RecordInt64s(ctx, counter, nil, attr)
+	sdkapi.RecordInt64s(ctx, counter, []int64{-1}, attr)
+	require.Equal(t, aggregation.ErrNegativeInput, testHandler.Flush())
+	require.Equal(t, 0, sdk.Collect(ctx))
+}
+
+func TestRecordSliceNumberKindMismatch(t *testing.T) {
+	ctx := context.Background()
+	meter, sdk, _, processor := newSDK(t)
+
+	counter, err := meter.SyncInt64().Counter("int.sum")
+	require.NoError(t, err)
+	histogram, err := meter.SyncFloat64().Histogram("float.histogram")
+	require.NoError(t, err)
+
+	// Values recorded with the other number type are converted to
+	// the number type of the instrument.
+	sdkapi.RecordFloat64s(ctx, counter, []float64{1.5, 2.25})
+	sdkapi.RecordInt64s(ctx, histogram, []int64{2, 3})
+	sdkapi.RecordBatch(ctx, meter, nil,
+		sdkapi.Float64Measurement(counter, 4),
+		sdkapi.Int64Measurement(histogram, 5),
+	)
+	require.NoError(t, testHandler.Flush())
+
+	sdk.Collect(ctx)
+	require.Equal(t, map[string]float64{
+		"int.sum//":         7,
+		"float.histogram//": 10,
+	}, processor.Values())
+}
+
+func TestAttributeBufferReuse(t *testing.T) {
+	ctx := context.Background()
+	meter, sdk, _, processor := newSDK(t)
+
+	counter, err := meter.SyncInt64().Counter("name.sum")
+	require.NoError(t, err)
+
+	buf := sdkapi.NewAttributeBuffer()
+	defer buf.Release()
+
+	// The SDK copies the attributes it stores, so refilling the
+	// buffer after each measurement does not alter earlier ones.
+	for i, v := range []string{"B", "C", "B"} {
+		buf.Reset()
+		buf.Append(attribute.String("A", v), attribute.Int("I", i%2))
+		counter.Add(ctx, 1, buf.Attributes()...)
+	}
+	buf.Reset()
+	buf.Append(attribute.String("A", "D"))
+	counter.Add(ctx, 1, buf.Attributes()...)
+
+	require.Equal(t, 3, sdk.Collect(ctx))
+	require.Equal(t, map[string]float64{
+		"name.sum/A=B,I=0/": 2,
+		"name.sum/A=C,I=1/": 1,
+		"name.sum/A=D/":     1,
+	}, processor.Values())
+}
+
+func TestRecordBatch(t *testing.T) {
+	ctx := context.Background()
+	meter, sdk, _, processor := newSDK(t)
+
+	bytesIn, err := meter.SyncInt64().Counter("bytes_in.sum")
+	require.NoError(t, err)
+	bytesOut, err := meter.SyncInt64().Counter("bytes_out.sum")
+	require.NoError(t, err)
+	latency, err := meter.SyncFloat64().Histogram("latency.histogram")
+	require.NoError(t, err)
+
+	attrs := []attribute.KeyValue{attribute.String("A", "B")}
+	sdkapi.RecordBatch(ctx, meter, attrs,
+		sdkapi.Int64Measurement(bytesIn, 10),
+		sdkapi.Int64Measurement(bytesOut, 20),
+		sdkapi.Float64Measurement(latency, 0.5),
+	)
+	sdkapi.RecordBatch(ctx, meter, attrs,
+		sdkapi.Int64Measurement(bytesIn, 1),
+		sdkapi.Int64Measurement(bytesOut, -1),
+	)
+	require.Equal(t, aggregation.ErrNegativeInput, testHandler.Flush())
+
+	checkpointed := sdk.Collect(ctx)
+	require.Equal(t, map[string]float64{
+		"bytes_in.sum/A=B/":      11,
+		"bytes_out.sum/A=B/":     20,
+		"latency.histogram/A=B/": 0.5,
+	}, processor.Values())
+	require.Equal(t, 3, checkpointed)
+}
+
 func TestDisabledInstrument(t *testing.T) {
 	ctx := context.Background()
 	meter, sdk, _, processor := newSDK(t)
@@ -573,3 +693,393 @@ func TestSyncInAsync(t *testing.T) {
 		"observer.lastvalue//": 10,
 	}, processor.Values())
 }
+
+// TestConcurrentFirstUpdate ensures that goroutines racing to create the
+// record of one attribute set all resolve to the same record, for each of
+// several instruments, both initially and after the record is unmapped by
+// an idle collection.
+func TestReentrantCallbacks(t *testing.T) {
+	ctx := context.Background()
+	meter, sdk, _, processor := newSDK(t)
+
+	counter, err := meter.SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	gauge, err := meter.AsyncInt64().Gauge("gauge.lastvalue")
+	require.NoError(t, err)
+
+	registered := false
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{gauge}, func(ctx context.Context) {
+		require.True(t, metricsdk.InCallback(ctx))
+		gauge.Observe(ctx, 1)
+		counter.Add(ctx, 1)
+		sdkapi.RecordBatch(ctx, meter, nil, sdkapi.Int64Measurement(counter, 1))
+
+		// A collection from a callback returns at once.
+		require.Equal(t, 0, sdk.Collect(ctx))
+		require.ErrorIs(t, testHandler.Flush(), metricsdk.ErrReentrantCollect)
+
+		if registered {
+			return
+		}
+		registered = true
+		late, err := meter.AsyncInt64().Gauge("late.lastvalue")
+		require.NoError(t, err)
+		require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{late}, func(ctx context.Context) {
+			late.Observe(ctx, 2)
+		}))
+	}))
+	require.False(t, metricsdk.InCallback(ctx))
+
+	require.Equal(t, 2, sdk.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"counter.sum//":     2,
+		"gauge.lastvalue//": 1,
+	}, processor.Values())
+
+	// The callback registered by a callback runs from the next
+	// collection.
+	processor.Reset()
+	require.Equal(t, 3, sdk.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"counter.sum//":     2,
+		"gauge.lastvalue//": 1,
+		"late.lastvalue//":  2,
+	}, processor.Values())
+}
+
+func TestConcurrentFirstUpdate(t *testing.T) {
+	const goroutines, rounds = 16, 10
+	ctx := context.Background()
+	processor := processortest.NewProcessor(
+		processortest.AggregatorSelector(),
+		attribute.DefaultEncoder(),
+	)
+	sdk := metricsdk.NewAccumulator(processor)
+	meter := sdkapi.WrapMeterImpl(sdk)
+
+	counter, err := meter.SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	updown, err := meter.SyncInt64().UpDownCounter("updown.sum")
+	require.NoError(t, err)
+	histo, err := meter.SyncFloat64().Histogram("histo.histogram")
+	require.NoError(t, err)
+
+	for r := 0; r < rounds; r++ {
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(goroutines)
+		for g := 0; g < goroutines; g++ {
+			go func() {
+				defer wg.Done()
+				// The SDK may sort the attributes in place, so each
+				// goroutine passes its own slice, in reverse order.
+				attrs := []attribute.KeyValue{attribute.String("C", "D"), attribute.String("A", "B")}
+				<-start
+				counter.Add(ctx, 1, attrs...)
+				updown.Add(ctx, 2, attrs...)
+				histo.Record(ctx, 3, attrs...)
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		processor.Reset()
+		require.Equal(t, 3, sdk.Collect(ctx), "one record per instrument")
+		require.EqualValues(t, map[string]float64{
+			"counter.sum/A=B,C=D/":     goroutines,
+			"updown.sum/A=B,C=D/":      2 * goroutines,
+			"histo.histogram/A=B,C=D/": 3 * goroutines,
+		}, processor.Values())
+
+		// An idle collection unmaps the records, so that the next
+		// round races to create them again.
+		processor.Reset()
+		require.Equal(t, 0, sdk.Collect(ctx))
+	}
+	require.NoError(t, testHandler.Flush())
+}
+
+func TestVerboseBaggage(t *testing.T) {
+	ctx := context.Background()
+	processor := processortest.NewProcessor(processortest.AggregatorSelector(), attribute.DefaultEncoder())
+	sdk := metricsdk.NewAccumulator(processor, metricsdk.WithVerboseBaggage(metricsdk.DefaultVerboseBaggageKey))
+	meter := sdkapi.WrapMeterImpl(sdk)
+
+	counter, err := meter.SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	histo, err := meter.SyncFloat64().Histogram("histo.histogram")
+	require.NoError(t, err)
+
+	member, err := baggage.NewMember(metricsdk.DefaultVerboseBaggageKey, "1")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	verboseCtx := baggage.ContextWithBaggage(ctx, bag)
+
+	attrs := attribute.String("A", "B")
+	counter.Add(ctx, 1, attrs)
+	counter.Add(verboseCtx, 2, attrs)
+	histo.Record(ctx, 3)
+	histo.Record(verboseCtx, 4)
+
+	require.Equal(t, 4, sdk.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"counter.sum/A=B/":          3,
+		"verbose.counter.sum/A=B/":  2,
+		"histo.histogram//":         7,
+		"verbose.histo.histogram//": 4,
+	}, processor.Values())
+	require.NoError(t, testHandler.Flush())
+}
+
+type tenantAttributes struct{}
+
+type tenantKey struct{}
+
+func (tenantAttributes) ContextAttributes(ctx context.Context, desc *sdkapi.Descriptor) []attribute.KeyValue {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok || desc.Name() == "untouched.sum" {
+		return nil
+	}
+	return []attribute.KeyValue{attribute.String("tenant", tenant), attribute.String("A", "ctx")}
+}
+
+func TestContextAttributes(t *testing.T) {
+	ctx := context.Background()
+	processor := processortest.NewProcessor(processortest.AggregatorSelector(), attribute.DefaultEncoder())
+	sdk := metricsdk.NewAccumulator(processor, metricsdk.WithContextAttributes(tenantAttributes{}))
+	meter := sdkapi.WrapMeterImpl(sdk)
+
+	counter, err := meter.SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	untouched, err := meter.SyncInt64().Counter("untouched.sum")
+	require.NoError(t, err)
+
+	tenantCtx := context.WithValue(ctx, tenantKey{}, "t1")
+	counter.Add(ctx, 1)
+	counter.Add(tenantCtx, 2)
+	counter.Add(tenantCtx, 3, attribute.String("A", "B"))
+	sdkapi.RecordInt64s(tenantCtx, counter, []int64{4, 5})
+	sdkapi.RecordBatch(tenantCtx, meter, nil,
+		sdkapi.Int64Measurement(counter, 6),
+		sdkapi.Int64Measurement(untouched, 7),
+	)
+
+	require.Equal(t, 4, sdk.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"counter.sum//":                1,
+		"counter.sum/A=ctx,tenant=t1/": 17,
+		"counter.sum/A=B,tenant=t1/":   3,
+		"untouched.sum//":              7,
+	}, processor.Values())
+}
+
+func TestAttributeFilter(t *testing.T) {
+	ctx := context.Background()
+	processor := processortest.NewProcessor(processortest.AggregatorSelector(), attribute.DefaultEncoder())
+	sdk := metricsdk.NewAccumulator(processor, metricsdk.WithAttributeFilter(reducer.NewAdviceFilterSelector()))
+	meter := sdkapi.WrapMeterImpl(sdk)
+
+	counter, err := meter.SyncInt64().Counter("counter.sum", instrument.WithAttributeKeys("A"))
+	require.NoError(t, err)
+	untouched, err := meter.SyncInt64().Counter("untouched.sum")
+	require.NoError(t, err)
+	gauge, err := meter.AsyncInt64().Gauge("gauge.lastvalue", instrument.WithAttributeKeys("A"))
+	require.NoError(t, err)
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{gauge}, func(ctx context.Context) {
+		gauge.Observe(ctx, 8, attribute.String("A", "a"), attribute.String("B", "b"))
+	}))
+
+	counter.Add(ctx, 1, attribute.String("A", "a"), attribute.String("B", "1"))
+	counter.Add(ctx, 2, attribute.String("A", "a"), attribute.String("B", "2"))
+	sdkapi.RecordBatch(ctx, meter, []attribute.KeyValue{attribute.String("A", "a"), attribute.String("B", "3")},
+		sdkapi.Int64Measurement(counter, 3),
+		sdkapi.Int64Measurement(untouched, 4),
+	)
+
+	require.Equal(t, 3, sdk.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"counter.sum/A=a/":       6,
+		"untouched.sum/A=a,B=3/": 4,
+		"gauge.lastvalue/A=a/":   8,
+	}, processor.Values())
+}
+
+func TestVerboseBaggageDisabled(t *testing.T) {
+	ctx := context.Background()
+	meter, sdk, _, processor := newSDK(t)
+
+	counter, err := meter.SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+
+	member, err := baggage.NewMember(metricsdk.DefaultVerboseBaggageKey, "true")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	counter.Add(baggage.ContextWithBaggage(ctx, bag), 1)
+
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"counter.sum//": 1,
+	}, processor.Values())
+}
+
+type timestampProcessor struct {
+	export.AggregatorSelector
+	timestamps map[string]time.Time
+}
+
+func (p *timestampProcessor) Process(accum export.Accumulation) error {
+	_, ts, err := accum.Aggregator().Aggregation().(aggregation.LastValue).LastValue()
+	if err != nil {
+		return err
+	}
+	p.timestamps[accum.Descriptor().Name()] = ts
+	return nil
+}
+
+func TestAsyncObservationTime(t *testing.T) {
+	ctx := context.Background()
+	testHandler.Reset()
+	processor := &timestampProcessor{
+		AggregatorSelector: processortest.AggregatorSelector(),
+		timestamps:         map[string]time.Time{},
+	}
+	sdk := metricsdk.NewAccumulator(processor, metricsdk.WithObservationTimeTolerance(time.Minute))
+	meter := sdkapi.WrapMeterImpl(sdk)
+
+	measured := time.Now().Add(-time.Hour)
+	relayed, err := meter.AsyncInt64().Gauge("relayed.lastvalue")
+	require.NoError(t, err)
+	skewed, err := meter.AsyncInt64().Gauge("skewed.lastvalue")
+	require.NoError(t, err)
+	future, err := meter.AsyncInt64().Gauge("future.lastvalue")
+	require.NoError(t, err)
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{relayed, skewed, future}, func(ctx context.Context) {
+		relayed.Observe(aggregator.ContextWithObservationTime(ctx, measured), 1)
+		skewed.Observe(aggregator.ContextWithObservationTime(ctx, time.Now().Add(time.Second)), 1)
+		future.Observe(aggregator.ContextWithObservationTime(ctx, time.Now().Add(time.Hour)), 1)
+	}))
+
+	require.Equal(t, 2, sdk.Collect(ctx))
+	require.Equal(t, measured, processor.timestamps["relayed.lastvalue"])
+	require.Contains(t, processor.timestamps, "skewed.lastvalue")
+	require.NotContains(t, processor.timestamps, "future.lastvalue")
+	require.ErrorIs(t, testHandler.Flush(), metricsdk.ErrFutureObservation)
+}
+
+func TestAsyncObservationTimeUnvalidated(t *testing.T) {
+	ctx := context.Background()
+	testHandler.Reset()
+	processor := processortest.NewProcessor(processortest.AggregatorSelector(), attribute.DefaultEncoder())
+	sdk := metricsdk.NewAccumulator(processor, metricsdk.WithObservationTimeTolerance(-1))
+	meter := sdkapi.WrapMeterImpl(sdk)
+
+	gauge, err := meter.AsyncInt64().Gauge("future.lastvalue")
+	require.NoError(t, err)
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{gauge}, func(ctx context.Context) {
+		gauge.Observe(aggregator.ContextWithObservationTime(ctx, time.Now().Add(time.Hour)), 1)
+	}))
+
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"future.lastvalue//": 1,
+	}, processor.Values())
+	require.NoError(t, testHandler.Flush())
+}
+
+func TestScope(t *testing.T) {
+	ctx := context.Background()
+	meter, sdk, _, processor := newSDK(t)
+
+	counter, err := meter.SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	histo, err := meter.SyncFloat64().Histogram("histo.histogram")
+	require.NoError(t, err)
+
+	scope := metricsdk.NewScope()
+	scopeCtx := metricsdk.ContextWithScope(ctx, scope)
+
+	attrs := attribute.String("A", "B")
+	counter.Add(ctx, 1, attrs)
+	counter.Add(scopeCtx, 2, attrs)
+	counter.Add(scopeCtx, -1, attrs)
+	require.Equal(t, aggregation.ErrNegativeInput, testHandler.Flush())
+	sdkapi.RecordFloat64s(scopeCtx, histo, []float64{0.5, 1.5})
+
+	points := scope.Points()
+	require.Len(t, points, 2)
+	require.Equal(t, "counter.sum", points[0].Descriptor.Name())
+	require.Equal(t, attribute.NewSet(attrs), points[0].Attributes)
+	require.Equal(t, 2.0, points[0].Sum())
+	require.Equal(t, "histo.histogram", points[1].Descriptor.Name())
+	require.Len(t, points[1].Values, 2)
+	require.Equal(t, 2.0, points[1].Sum())
+
+	// Measurements held by the scope are not collected.
+	require.Equal(t, 1, sdk.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"counter.sum/A=B/": 1,
+	}, processor.Values())
+
+	scope.Merge(scopeCtx)
+	require.Empty(t, scope.Points())
+
+	processor.Reset()
+	require.Equal(t, 2, sdk.Collect(ctx))
+	require.EqualValues(t, map[string]float64{
+		"counter.sum/A=B/":  2,
+		"histo.histogram//": 2,
+	}, processor.Values())
+	require.NoError(t, testHandler.Flush())
+}
+
+func TestScopeDiscard(t *testing.T) {
+	ctx := context.Background()
+	meter, sdk, _, _ := newSDK(t)
+
+	counter, err := meter.SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+
+	scope := metricsdk.NewScope()
+	counter.Add(metricsdk.ContextWithScope(ctx, scope), 1)
+	require.Len(t, scope.Points(), 1)
+
+	scope.Discard()
+	require.Empty(t, scope.Points())
+	scope.Merge(ctx)
+	require.Equal(t, 0, sdk.Collect(ctx))
+}
+
+func TestUsageSampling(t *testing.T) {
+	ctx := context.Background()
+	processor := processortest.NewProcessor(processortest.AggregatorSelector(), attribute.DefaultEncoder())
+	sdk := metricsdk.NewAccumulator(processor, metricsdk.WithUsageSampling(2))
+	meter := sdkapi.WrapMeterImpl(sdk)
+
+	counter, err := meter.SyncInt64().Counter("counter.sum")
+	require.NoError(t, err)
+	_, err = meter.SyncFloat64().Histogram("unused.histogram")
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		counter.Add(ctx, 1)
+	}
+	sdkapi.RecordInt64s(ctx, counter, []int64{1, 2, 3})
+
+	usage := sdk.Usage()
+	require.Len(t, usage, 2)
+	require.Equal(t, "counter.sum", usage[0].Descriptor.Name())
+	require.Equal(t, uint64(8), usage[0].Updates)
+	require.Len(t, usage[0].CallSites, 2)
+	require.Equal(t, uint64(2), usage[0].CallSites[0].Samples)
+	require.Equal(t, uint64(1), usage[0].CallSites[1].Samples)
+	for _, site := range usage[0].CallSites {
+		require.Equal(t, "go.opentelemetry.io/otel/sdk/metric_test.TestUsageSampling", site.Function)
+	}
+	require.Equal(t, "unused.histogram", usage[1].Descriptor.Name())
+	require.Zero(t, usage[1].Updates)
+	require.Empty(t, usage[1].CallSites)
+
+	require.Nil(t, metricsdk.NewAccumulator(processor).Usage())
+}
@@ -54,6 +54,13 @@ sweeps through all records in the SDK, checkpointing their state.  When a
 record is discovered that has no references and has not been updated since
 the prior collection pass, it is removed from the Map.
 
+Callbacks run during Collect, without holding the locks of the
+Accumulator.  They may record measurements of synchronous instruments,
+which the same collection includes, and create instruments and register
+callbacks, which the next collection includes.  A Collect called from a
+callback of the collection in progress returns without collecting and
+reports ErrReentrantCollect.
+
 Both synchronous and asynchronous instruments have an associated
 aggregator, which maintains the current state resulting from all metric
 events since its last checkpoint.  Aggregators may be lock-free or they may
@@ -127,5 +134,16 @@ Collect() method, then read the checkpoint, then invoke the exporter.
 Controllers are expected to implement the public metric.MeterProvider
 API, meaning they can be installed as the global Meter provider.
 
+Module layout
+
+This module, with the aggregators, processors, views and controllers it
+contains, depends only on the API and SDK modules of OpenTelemetry-Go
+and has no gRPC or protobuf dependencies.  Exporters live in modules of
+their own, such as go.opentelemetry.io/otel/exporters/prometheus or
+go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc, so
+that a program links only the exporters it imports.  The
+core-deps-check make target verifies that the core modules stay free of
+these dependencies.
+
 */
 package metric // import "go.opentelemetry.io/otel/sdk/metric"
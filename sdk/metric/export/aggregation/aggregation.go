@@ -16,6 +16,8 @@ package aggregation // import "go.opentelemetry.io/otel/sdk/metric/export/aggreg
 
 import (
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel/sdk/metric/number"
@@ -46,6 +48,27 @@ type (
 		Count() (uint64, error)
 	}
 
+	// Min returns the minimum value over the set of values that were aggregated.
+	Min interface {
+		Aggregation
+		Min() (number.Number, error)
+	}
+
+	// Max returns the maximum value over the set of values that were aggregated.
+	Max interface {
+		Aggregation
+		Max() (number.Number, error)
+	}
+
+	// MinMaxSumCount supports the Min, Max, Sum, and Count interfaces.
+	MinMaxSumCount interface {
+		Aggregation
+		Min() (number.Number, error)
+		Max() (number.Number, error)
+		Sum() (number.Number, error)
+		Count() (uint64, error)
+	}
+
 	// LastValue returns the latest value that was aggregated.
 	LastValue interface {
 		Aggregation
@@ -72,6 +95,57 @@ type (
 		Sum() (number.Number, error)
 		Histogram() (Buckets, error)
 	}
+
+	// ExponentialBuckets is a range of consecutive buckets of an
+	// exponential histogram, for values either above or below zero.
+	// The bucket with index i, relative to Offset, counts the values
+	// whose magnitude is in [base**(Offset+i), base**(Offset+i+1)),
+	// where base is 2**(2**-scale).
+	ExponentialBuckets interface {
+		// Offset returns the index of the first bucket.
+		Offset() int32
+
+		// Len returns the number of buckets.
+		Len() uint32
+
+		// At returns the count of the bucket at position i, for i
+		// less than Len.
+		At(i uint32) uint64
+	}
+
+	// ExponentialHistogram returns the count of events in
+	// exponentially sized buckets, whose resolution is set by Scale.
+	ExponentialHistogram interface {
+		Aggregation
+		Count() (uint64, error)
+		Sum() (number.Number, error)
+		Scale() (int32, error)
+		ZeroCount() (uint64, error)
+		Positive() (ExponentialBuckets, error)
+		Negative() (ExponentialBuckets, error)
+	}
+
+	// Summary returns estimates of a set of quantiles of the values
+	// that were aggregated, in increasing order of Quantiles, along
+	// with their count, sum, minimum and maximum.
+	Summary interface {
+		Aggregation
+		Count() (uint64, error)
+		Sum() (number.Number, error)
+		Min() (number.Number, error)
+		Max() (number.Number, error)
+		Quantiles() ([]float64, error)
+		Quantile(q float64) (float64, error)
+	}
+
+	// Quantile returns an estimate of a quantile of the values that
+	// were aggregated, so that programs can act on their own
+	// distributions, for example an adaptive concurrency limit on
+	// the 99th percentile of latencies.
+	Quantile interface {
+		Aggregation
+		Quantile(q float64) (float64, error)
+	}
 )
 
 type (
@@ -92,9 +166,13 @@ type (
 
 // Kind description constants.
 const (
-	SumKind       Kind = "Sum"
-	HistogramKind Kind = "Histogram"
-	LastValueKind Kind = "Lastvalue"
+	SumKind                  Kind = "Sum"
+	MinMaxSumCountKind       Kind = "MinMaxSumCount"
+	HistogramKind            Kind = "Histogram"
+	ExponentialHistogramKind Kind = "ExponentialHistogram"
+	TDigestKind              Kind = "TDigest"
+	DDSketchKind             Kind = "DDSketch"
+	LastValueKind            Kind = "Lastvalue"
 )
 
 // Sentinel errors for Aggregation interface.
@@ -102,6 +180,7 @@ var (
 	ErrNegativeInput    = fmt.Errorf("negative value is out of range for this instrument")
 	ErrNaNInput         = fmt.Errorf("NaN value is an invalid input")
 	ErrInconsistentType = fmt.Errorf("inconsistent aggregator types")
+	ErrInvalidQuantile  = fmt.Errorf("the requested quantile is out of range")
 
 	// ErrNoCumulativeToDelta is returned when requesting delta
 	// export kind for a precomputed sum instrument.
@@ -113,7 +192,78 @@ var (
 	ErrNoData = fmt.Errorf("no data collected by this aggregator")
 )
 
+// Quantile returns an estimate of the q-quantile of the values counted by
+// the buckets, for q in [0, 1], interpolating linearly within the bucket
+// holding it as Prometheus does.  The values of the first bucket are
+// assumed to be above zero when its upper boundary is positive, and the
+// quantiles of the first bucket otherwise, and of the last bucket, are
+// estimated by the boundary of the bucket.  It returns NaN for buckets
+// without boundaries, ErrInvalidQuantile for q outside [0, 1], and ErrNoData
+// when no value was counted.
+func (b Buckets) Quantile(q float64) (float64, error) {
+	if !(q >= 0 && q <= 1) {
+		return 0, ErrInvalidQuantile
+	}
+	var count uint64
+	for _, c := range b.Counts {
+		count += c
+	}
+	if count == 0 {
+		return 0, ErrNoData
+	}
+	if len(b.Boundaries) == 0 || len(b.Counts) != len(b.Boundaries)+1 {
+		return math.NaN(), nil
+	}
+
+	rank := q * float64(count)
+	var below uint64
+	i := 0
+	for ; i < len(b.Counts)-1; i++ {
+		if float64(below+b.Counts[i]) >= rank && b.Counts[i] > 0 {
+			break
+		}
+		below += b.Counts[i]
+	}
+	if i == len(b.Boundaries) {
+		return b.Boundaries[i-1], nil
+	}
+	upper := b.Boundaries[i]
+	var lower float64
+	switch {
+	case i > 0:
+		lower = b.Boundaries[i-1]
+	case upper > 0:
+		lower = 0
+	default:
+		return upper, nil
+	}
+	return lower + (upper-lower)*(rank-float64(below))/float64(b.Counts[i]), nil
+}
+
 // String returns the string value of Kind.
 func (k Kind) String() string {
 	return string(k)
 }
+
+// MarshalText implements encoding.TextMarshaler.
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.  The names of the
+// Kind constants are recognized ignoring case, so that "sum" decodes as
+// SumKind.  Any other non-empty text is decoded as is, since Kinds of
+// user-defined Aggregators are allowed.
+func (k *Kind) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return fmt.Errorf("empty aggregation kind")
+	}
+	for _, kind := range []Kind{SumKind, MinMaxSumCountKind, HistogramKind, ExponentialHistogramKind, TDigestKind, DDSketchKind, LastValueKind} {
+		if strings.EqualFold(string(text), string(kind)) {
+			*k = kind
+			return nil
+		}
+	}
+	*k = Kind(text)
+	return nil
+}
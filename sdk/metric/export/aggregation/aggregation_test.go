@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketsQuantile(t *testing.T) {
+	buckets := Buckets{
+		Boundaries: []float64{10, 20, 40},
+		Counts:     []uint64{2, 4, 0, 2},
+	}
+	for _, tc := range []struct {
+		q      float64
+		expect float64
+	}{
+		{0, 0},
+		{0.125, 5},
+		{0.25, 10},
+		{0.5, 15},
+		{0.75, 20},
+		{0.8, 40},
+		{1, 40},
+	} {
+		got, err := buckets.Quantile(tc.q)
+		require.NoError(t, err)
+		require.InDelta(t, tc.expect, got, 1e-9, "q=%v", tc.q)
+	}
+
+	negative := Buckets{Boundaries: []float64{-10, 0}, Counts: []uint64{1, 1, 0}}
+	got, err := negative.Quantile(0.25)
+	require.NoError(t, err)
+	require.Equal(t, -10.0, got)
+	got, err = negative.Quantile(0.75)
+	require.NoError(t, err)
+	require.Equal(t, -5.0, got)
+
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		_, err := buckets.Quantile(q)
+		require.ErrorIs(t, err, ErrInvalidQuantile)
+	}
+	_, err = Buckets{Boundaries: []float64{1}, Counts: []uint64{0, 0}}.Quantile(0.5)
+	require.ErrorIs(t, err, ErrNoData)
+
+	got, err = Buckets{Counts: []uint64{3}}.Quantile(0.5)
+	require.NoError(t, err)
+	require.True(t, math.IsNaN(got))
+}
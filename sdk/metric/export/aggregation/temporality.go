@@ -17,6 +17,9 @@
 package aggregation // import "go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 
 import (
+	"fmt"
+	"strings"
+
 	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
 )
 
@@ -34,6 +37,32 @@ const (
 	DeltaTemporality Temporality = 2
 )
 
+// MarshalText implements encoding.TextMarshaler, encoding the
+// temporality as "cumulative" or "delta".
+func (t Temporality) MarshalText() ([]byte, error) {
+	switch t {
+	case CumulativeTemporality:
+		return []byte("cumulative"), nil
+	case DeltaTemporality:
+		return []byte("delta"), nil
+	}
+	return nil, fmt.Errorf("invalid temporality: %d", t)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.  It accepts the
+// encoding of MarshalText and the String of the temporality, ignoring
+// case.
+func (t *Temporality) UnmarshalText(text []byte) error {
+	for _, temp := range []Temporality{CumulativeTemporality, DeltaTemporality} {
+		name, _ := temp.MarshalText()
+		if strings.EqualFold(string(text), string(name)) || strings.EqualFold(string(text), temp.String()) {
+			*t = temp
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown temporality: %q", text)
+}
+
 // Includes returns if t includes support for other temporality.
 func (t Temporality) Includes(other Temporality) bool {
 	return t&other != 0
@@ -106,6 +135,22 @@ func (s statelessTemporalitySelector) TemporalityFor(desc *sdkapi.Descriptor, ki
 	return DeltaTemporality
 }
 
+// ResolveTemporality returns the Temporality in which the Processor
+// exports data of an instrument to an exporter choosing temporalities
+// with selector.  This is the selected Temporality, except that
+// UpDownCounterObserver instruments are exported with
+// CumulativeTemporality when DeltaTemporality is selected: they observe
+// the current value of a non-monotonic sum, which the specification
+// requires to be exported as a cumulative sum.  Exporters use this to
+// report the temporality of the data they are given.
+func ResolveTemporality(selector TemporalitySelector, desc *sdkapi.Descriptor, kind Kind) Temporality {
+	t := selector.TemporalityFor(desc, kind)
+	if t == DeltaTemporality && desc.InstrumentKind() == sdkapi.UpDownCounterObserverInstrumentKind {
+		return CumulativeTemporality
+	}
+	return t
+}
+
 // TemporalitySelector is a sub-interface of Exporter used to indicate
 // whether the Processor should compute Delta or Cumulative
 // Aggregations.
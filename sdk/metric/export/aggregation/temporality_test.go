@@ -15,6 +15,7 @@
 package aggregation
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -71,3 +72,62 @@ func TestTemporalitySelectors(t *testing.T) {
 		require.False(t, sAggTemp.TemporalityFor(&desc, akind).MemoryRequired(ikind))
 	}
 }
+
+func TestResolveTemporality(t *testing.T) {
+	for _, ikind := range append(deltaMemoryTemporalties, cumulativeMemoryTemporalties...) {
+		desc := sdkapi.NewDescriptor("instrument", ikind, number.Int64Kind, "", "")
+
+		want := DeltaTemporality
+		if ikind == sdkapi.UpDownCounterObserverInstrumentKind {
+			// Exporting the observed sums requires no memory.
+			want = CumulativeTemporality
+			require.False(t, want.MemoryRequired(ikind))
+		}
+		require.Equal(t, want, ResolveTemporality(DeltaTemporalitySelector(), &desc, SumKind), ikind)
+		require.Equal(t, CumulativeTemporality, ResolveTemporality(CumulativeTemporalitySelector(), &desc, SumKind), ikind)
+	}
+}
+
+func TestTemporalityText(t *testing.T) {
+	for temp, text := range map[Temporality]string{
+		CumulativeTemporality: "cumulative",
+		DeltaTemporality:      "delta",
+	} {
+		got, err := temp.MarshalText()
+		require.NoError(t, err)
+		require.Equal(t, text, string(got))
+
+		var decoded Temporality
+		require.NoError(t, decoded.UnmarshalText([]byte(text)))
+		require.Equal(t, temp, decoded)
+
+		decoded = 0
+		require.NoError(t, decoded.UnmarshalText([]byte(temp.String())))
+		require.Equal(t, temp, decoded)
+	}
+
+	_, err := (CumulativeTemporality | DeltaTemporality).MarshalText()
+	require.Error(t, err)
+
+	var decoded Temporality
+	require.Error(t, decoded.UnmarshalText([]byte("stateless")))
+}
+
+func TestKindText(t *testing.T) {
+	type config struct {
+		Aggregation Kind        `json:"aggregation"`
+		Temporality Temporality `json:"temporality"`
+	}
+	var cfg config
+	require.NoError(t, json.Unmarshal([]byte(`{"aggregation": "lastvalue", "temporality": "DELTA"}`), &cfg))
+	require.Equal(t, config{LastValueKind, DeltaTemporality}, cfg)
+
+	data, err := json.Marshal(config{HistogramKind, CumulativeTemporality})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"aggregation": "Histogram", "temporality": "cumulative"}`, string(data))
+
+	var kind Kind
+	require.NoError(t, kind.UnmarshalText([]byte("Sketch")))
+	require.Equal(t, Kind("Sketch"), kind)
+	require.Error(t, kind.UnmarshalText(nil))
+}
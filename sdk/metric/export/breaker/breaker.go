@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breaker // import "go.opentelemetry.io/otel/sdk/metric/export/breaker"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// ErrOpen is returned by Export while the circuit is open.
+var ErrOpen = fmt.Errorf("exporter circuit breaker is open")
+
+// ErrUnsupportedAggregation is reported to the global error handler for
+// the records that the Spool policy cannot copy, which are not exported.
+var ErrUnsupportedAggregation = fmt.Errorf("aggregation cannot be spooled")
+
+// Policy determines what happens to the data presented to the Exporter
+// while it cannot be exported.
+type Policy int
+
+const (
+	// Drop discards the data.  This is the default.
+	Drop Policy = iota
+	// Spool retains the data in memory and exports it once the
+	// circuit closes.
+	Spool
+)
+
+// Default configuration values.
+const (
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = 30 * time.Second
+	DefaultSpoolSize        = 10
+)
+
+// config contains the configuration of an Exporter.
+type config struct {
+	failureThreshold int
+	cooldown         time.Duration
+	policy           Policy
+	spoolSize        int
+}
+
+// Option configures an Exporter.
+type Option interface {
+	apply(config) config
+}
+
+type optionFunc func(config) config
+
+func (fn optionFunc) apply(cfg config) config {
+	return fn(cfg)
+}
+
+// WithFailureThreshold sets the number of consecutive failed exports
+// after which the circuit opens.  The default is
+// DefaultFailureThreshold.
+func WithFailureThreshold(n int) Option {
+	return optionFunc(func(cfg config) config {
+		if n > 0 {
+			cfg.failureThreshold = n
+		}
+		return cfg
+	})
+}
+
+// WithCooldown sets how long the circuit stays open before an export is
+// attempted again.  The default is DefaultCooldown.
+func WithCooldown(d time.Duration) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.cooldown = d
+		return cfg
+	})
+}
+
+// WithPolicy sets what happens to data that cannot be exported.  The
+// default is Drop.
+func WithPolicy(p Policy) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.policy = p
+		return cfg
+	})
+}
+
+// WithSpoolSize sets the largest number of exports retained by the
+// Spool policy.  The default is DefaultSpoolSize.
+func WithSpoolSize(n int) Option {
+	return optionFunc(func(cfg config) config {
+		if n > 0 {
+			cfg.spoolSize = n
+		}
+		return cfg
+	})
+}
+
+// Exporter is an export.Exporter that stops calling the wrapped
+// Exporter after repeated failures.
+type Exporter struct {
+	export.Exporter
+
+	config config
+	now    func() time.Time
+
+	lock      sync.Mutex
+	failures  int
+	openUntil time.Time
+	spool     []*spooled
+}
+
+var _ export.Exporter = &Exporter{}
+
+// New returns an Exporter that wraps exp in a circuit breaker.
+func New(exp export.Exporter, opts ...Option) *Exporter {
+	cfg := config{
+		failureThreshold: DefaultFailureThreshold,
+		cooldown:         DefaultCooldown,
+		spoolSize:        DefaultSpoolSize,
+	}
+	for _, opt := range opts {
+		cfg = opt.apply(cfg)
+	}
+	return &Exporter{
+		Exporter: exp,
+		config:   cfg,
+		now:      time.Now,
+	}
+}
+
+// Export calls the wrapped Exporter unless the circuit is open, in
+// which case it returns ErrOpen.  With the Spool policy, spooled data
+// is exported first.
+func (e *Exporter) Export(ctx context.Context, res *resource.Resource, reader export.InstrumentationLibraryReader) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.now().Before(e.openUntil) {
+		e.spoolExport(res, reader)
+		return ErrOpen
+	}
+
+	for len(e.spool) > 0 {
+		s := e.spool[0]
+		if err := e.Exporter.Export(ctx, s.resource, s); err != nil {
+			e.spoolExport(res, reader)
+			return e.fail(err)
+		}
+		e.spool[0] = nil
+		e.spool = e.spool[1:]
+	}
+
+	if err := e.Exporter.Export(ctx, res, reader); err != nil {
+		e.spoolExport(res, reader)
+		return e.fail(err)
+	}
+	e.failures = 0
+	return nil
+}
+
+// fail records a failed export, opening the circuit when the failure
+// threshold is reached.
+func (e *Exporter) fail(err error) error {
+	e.failures++
+	if e.failures >= e.config.failureThreshold {
+		e.openUntil = e.now().Add(e.config.cooldown)
+	}
+	return err
+}
+
+// spoolExport retains a copy of the data under the Spool policy.
+func (e *Exporter) spoolExport(res *resource.Resource, reader export.InstrumentationLibraryReader) {
+	if e.config.policy != Spool {
+		return
+	}
+	s, err := newSpooled(res, reader, e.Exporter)
+	if err != nil || len(s.libraries) == 0 {
+		return
+	}
+	if len(e.spool) >= e.config.spoolSize {
+		e.spool[0] = nil
+		e.spool = e.spool[1:]
+	}
+	e.spool = append(e.spool, s)
+}
+
+// Healthy returns ErrOpen while the circuit is open, and nil otherwise.
+// This contributes to the health of a basic Controller configured with
+// the Exporter.
+func (e *Exporter) Healthy() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.now().Before(e.openUntil) {
+		return ErrOpen
+	}
+	return nil
+}
+
+// Spooled returns the number of exports retained by the Spool policy.
+func (e *Exporter) Spooled() int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return len(e.spool)
+}
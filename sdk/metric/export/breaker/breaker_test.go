@@ -0,0 +1,538 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breaker
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/tdigest"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+var (
+	library  = instrumentation.Library{Name: "test"}
+	counter  = metrictest.NewDescriptor("counter.sum", sdkapi.CounterInstrumentKind, number.Int64Kind)
+	duration = metrictest.NewDescriptor("duration.histogram", sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	errDown  = fmt.Errorf("backend unavailable")
+)
+
+// testExporter sums the Sum of every exported record by instrument name.
+type testExporter struct {
+	aggregation.TemporalitySelector
+	err     error
+	calls   int
+	totals  map[string]float64
+	buckets []uint64
+}
+
+func newTestExporter() *testExporter {
+	return &testExporter{
+		TemporalitySelector: aggregation.DeltaTemporalitySelector(),
+		totals:              map[string]float64{},
+	}
+}
+
+func (e *testExporter) Export(_ context.Context, _ *resource.Resource, reader export.InstrumentationLibraryReader) error {
+	e.calls++
+	if e.err != nil {
+		return e.err
+	}
+	return reader.ForEach(func(_ instrumentation.Library, r export.Reader) error {
+		return r.ForEach(e, func(rec export.Record) error {
+			s, err := rec.Aggregation().(aggregation.Sum).Sum()
+			if err != nil {
+				return err
+			}
+			e.totals[rec.Descriptor().Name()] += s.CoerceToFloat64(rec.Descriptor().NumberKind())
+			if h, ok := rec.Aggregation().(aggregation.Histogram); ok {
+				b, err := h.Histogram()
+				if err != nil {
+					return err
+				}
+				e.buckets = b.Counts
+			}
+			return nil
+		})
+	})
+}
+
+func collection(t *testing.T, value int64) export.InstrumentationLibraryReader {
+	ctx := context.Background()
+	now := time.Now()
+
+	s := &sum.New(1)[0]
+	require.NoError(t, s.Update(ctx, number.NewInt64Number(value), &counter))
+	attrs := attribute.NewSet(attribute.String("A", "B"))
+
+	h := &histogram.New(1, &duration)[0]
+	require.NoError(t, h.Update(ctx, number.NewFloat64Number(5), &duration))
+
+	return processorTest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		library: {
+			export.NewRecord(&counter, &attrs, s.Aggregation(), now, now),
+			export.NewRecord(&duration, attribute.EmptySet(), h.Aggregation(), now, now),
+		},
+	})
+}
+
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func newTestBreaker(inner export.Exporter, opts ...Option) (*Exporter, *testClock) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	e := New(inner, opts...)
+	e.now = clock.Now
+	return e, clock
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	ctx := context.Background()
+	inner := newTestExporter()
+	inner.err = errDown
+	e, clock := newTestBreaker(inner, WithFailureThreshold(2), WithCooldown(time.Minute))
+
+	require.ErrorIs(t, e.Export(ctx, resource.Empty(), collection(t, 1)), errDown)
+	require.NoError(t, e.Healthy())
+	require.ErrorIs(t, e.Export(ctx, resource.Empty(), collection(t, 1)), errDown)
+	require.ErrorIs(t, e.Healthy(), ErrOpen)
+
+	// The open circuit does not call the exporter.
+	require.ErrorIs(t, e.Export(ctx, resource.Empty(), collection(t, 1)), ErrOpen)
+	require.Equal(t, 2, inner.calls)
+
+	// After the cool-down a failed attempt opens the circuit again.
+	clock.now = clock.now.Add(time.Minute)
+	require.ErrorIs(t, e.Export(ctx, resource.Empty(), collection(t, 1)), errDown)
+	require.Equal(t, 3, inner.calls)
+	require.ErrorIs(t, e.Export(ctx, resource.Empty(), collection(t, 1)), ErrOpen)
+	require.Equal(t, 3, inner.calls)
+
+	// A successful attempt closes it.
+	clock.now = clock.now.Add(time.Minute)
+	inner.err = nil
+	require.NoError(t, e.Export(ctx, resource.Empty(), collection(t, 7)))
+	require.NoError(t, e.Healthy())
+	require.Equal(t, 7.0, inner.totals["counter.sum"])
+	require.Equal(t, 0, e.Spooled())
+
+	// The failure count was reset.
+	inner.err = errDown
+	require.ErrorIs(t, e.Export(ctx, resource.Empty(), collection(t, 1)), errDown)
+	require.NoError(t, e.Healthy())
+}
+
+func TestBreakerSpool(t *testing.T) {
+	ctx := context.Background()
+	inner := newTestExporter()
+	inner.err = errDown
+	e, clock := newTestBreaker(inner,
+		WithFailureThreshold(1),
+		WithCooldown(time.Minute),
+		WithPolicy(Spool),
+		WithSpoolSize(3),
+	)
+
+	// One failed export and three while open; the oldest is dropped.
+	for i := int64(1); i <= 4; i++ {
+		require.Error(t, e.Export(ctx, resource.Empty(), collection(t, i)))
+	}
+	require.Equal(t, 3, e.Spooled())
+	require.Equal(t, 1, inner.calls)
+
+	clock.now = clock.now.Add(time.Minute)
+	inner.err = nil
+	require.NoError(t, e.Export(ctx, resource.Empty(), collection(t, 10)))
+	require.Equal(t, 0, e.Spooled())
+	require.Equal(t, 5, inner.calls)
+	require.Equal(t, float64(2+3+4+10), inner.totals["counter.sum"])
+	require.Equal(t, float64(4*5), inner.totals["duration.histogram"])
+	require.Len(t, inner.buckets, 12)
+	require.Equal(t, uint64(1), inner.buckets[10])
+}
+
+func TestBreakerDropsByDefault(t *testing.T) {
+	ctx := context.Background()
+	inner := newTestExporter()
+	inner.err = errDown
+	e, clock := newTestBreaker(inner, WithFailureThreshold(1))
+
+	require.ErrorIs(t, e.Export(ctx, resource.Empty(), collection(t, 1)), errDown)
+	require.ErrorIs(t, e.Export(ctx, resource.Empty(), collection(t, 2)), ErrOpen)
+	require.Equal(t, 0, e.Spooled())
+
+	clock.now = clock.now.Add(DefaultCooldown)
+	inner.err = nil
+	require.NoError(t, e.Export(ctx, resource.Empty(), collection(t, 3)))
+	require.Equal(t, 3.0, inner.totals["counter.sum"])
+}
+
+// unsupportedAggregation is an Aggregation that the spool cannot copy.
+type unsupportedAggregation struct{}
+
+func (unsupportedAggregation) Kind() aggregation.Kind { return "Unsupported" }
+
+func TestSpoolReportsUnsupportedAggregations(t *testing.T) {
+	var handled []error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { handled = append(handled, err) }))
+	defer otel.SetErrorHandler(otel.ErrorHandlerFunc(func(error) {}))
+
+	now := time.Now()
+	s := &sum.New(1)[0]
+	require.NoError(t, s.Update(context.Background(), number.NewInt64Number(3), &counter))
+	reader := processorTest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		library: {
+			export.NewRecord(&duration, attribute.EmptySet(), unsupportedAggregation{}, now, now),
+			export.NewRecord(&counter, attribute.EmptySet(), s.Aggregation(), now, now),
+		},
+	})
+
+	spooled, err := newSpooled(resource.Empty(), reader, aggregation.DeltaTemporalitySelector())
+	require.NoError(t, err)
+	require.Len(t, spooled.libraries, 1)
+	require.Len(t, spooled.libraries[0].records, 1)
+	require.Equal(t, "counter.sum", spooled.libraries[0].records[0].Descriptor().Name())
+	require.Len(t, handled, 1)
+	require.ErrorIs(t, handled[0], ErrUnsupportedAggregation)
+}
+
+// spoolRoundTrip returns the aggregation of a record of agg after it is
+// spooled, once agg has been reset.
+func spoolRoundTrip(t *testing.T, agg aggregator.Aggregator, desc *sdkapi.Descriptor) aggregation.Aggregation {
+	now := time.Now()
+	reader := processorTest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		library: {export.NewRecord(desc, attribute.EmptySet(), agg.Aggregation(), now, now)},
+	})
+	spooled, err := newSpooled(resource.Empty(), reader, aggregation.DeltaTemporalitySelector())
+	require.NoError(t, err)
+	require.NoError(t, agg.SynchronizedMove(nil, desc))
+
+	require.Len(t, spooled.libraries, 1)
+	require.Len(t, spooled.libraries[0].records, 1)
+	return spooled.libraries[0].records[0].Aggregation()
+}
+
+func TestSpoolExponentialHistogram(t *testing.T) {
+	ctx := context.Background()
+	agg := &exponential.New(1, &duration, exponential.WithMaxScale(0))[0]
+	for _, v := range []float64{0, 1, 3, -2} {
+		require.NoError(t, agg.Update(ctx, number.NewFloat64Number(v), &duration))
+	}
+
+	copied := spoolRoundTrip(t, agg, &duration).(aggregation.ExponentialHistogram)
+	require.Equal(t, aggregation.ExponentialHistogramKind, copied.Kind())
+	count, err := copied.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), count)
+	s, err := copied.Sum()
+	require.NoError(t, err)
+	require.Equal(t, 2.0, s.AsFloat64())
+	scale, err := copied.Scale()
+	require.NoError(t, err)
+	require.Equal(t, int32(0), scale)
+	zeros, err := copied.ZeroCount()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), zeros)
+
+	positive, err := copied.Positive()
+	require.NoError(t, err)
+	require.Equal(t, int32(0), positive.Offset())
+	require.Equal(t, uint32(2), positive.Len())
+	require.Equal(t, []uint64{1, 1}, []uint64{positive.At(0), positive.At(1)})
+	negative, err := copied.Negative()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), negative.Offset())
+	require.Equal(t, uint32(1), negative.Len())
+	require.Equal(t, uint64(1), negative.At(0))
+}
+
+func TestSpoolMinMaxSumCount(t *testing.T) {
+	ctx := context.Background()
+	agg := &minmaxsumcount.New(1)[0]
+	for _, v := range []float64{2, -1, 4} {
+		require.NoError(t, agg.Update(ctx, number.NewFloat64Number(v), &duration))
+	}
+
+	copied := spoolRoundTrip(t, agg, &duration).(aggregation.MinMaxSumCount)
+	require.Equal(t, aggregation.MinMaxSumCountKind, copied.Kind())
+	count, err := copied.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), count)
+	s, err := copied.Sum()
+	require.NoError(t, err)
+	require.Equal(t, 5.0, s.AsFloat64())
+	low, err := copied.Min()
+	require.NoError(t, err)
+	require.Equal(t, -1.0, low.AsFloat64())
+	high, err := copied.Max()
+	require.NoError(t, err)
+	require.Equal(t, 4.0, high.AsFloat64())
+}
+
+func TestSpoolTDigest(t *testing.T) {
+	ctx := context.Background()
+	agg := &tdigest.New(1, &duration, tdigest.WithQuantiles(0.5))[0]
+	for _, v := range []float64{4, 1, 3, 2} {
+		require.NoError(t, agg.Update(ctx, number.NewFloat64Number(v), &duration))
+	}
+	median, err := agg.Quantile(0.5)
+	require.NoError(t, err)
+
+	copied := spoolRoundTrip(t, agg, &duration).(aggregation.Summary)
+	require.Equal(t, aggregation.TDigestKind, copied.Kind())
+	count, err := copied.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), count)
+	s, err := copied.Sum()
+	require.NoError(t, err)
+	require.Equal(t, 10.0, s.AsFloat64())
+	low, err := copied.Min()
+	require.NoError(t, err)
+	require.Equal(t, 1.0, low.AsFloat64())
+	high, err := copied.Max()
+	require.NoError(t, err)
+	require.Equal(t, 4.0, high.AsFloat64())
+
+	quantiles, err := copied.Quantiles()
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.5}, quantiles)
+	for _, tc := range []struct{ q, want float64 }{
+		{0, 1},
+		{0.5, median},
+		{0.75, (median + 4) / 2},
+		{1, 4},
+	} {
+		got, err := copied.Quantile(tc.q)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, got, "quantile %v", tc.q)
+	}
+	_, err = copied.Quantile(2)
+	require.ErrorIs(t, err, aggregation.ErrInvalidQuantile)
+}
+
+func TestSpoolDDSketch(t *testing.T) {
+	ctx := context.Background()
+	agg := &ddsketch.New(1, &duration, ddsketch.WithQuantiles(0.5, 0.9))[0]
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		require.NoError(t, agg.Update(ctx, number.NewFloat64Number(v), &duration))
+	}
+	median, err := agg.Quantile(0.5)
+	require.NoError(t, err)
+	p90, err := agg.Quantile(0.9)
+	require.NoError(t, err)
+
+	copied := spoolRoundTrip(t, agg, &duration).(aggregation.Summary)
+	require.Equal(t, aggregation.DDSketchKind, copied.Kind())
+	count, err := copied.Count()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), count)
+	s, err := copied.Sum()
+	require.NoError(t, err)
+	require.Equal(t, 15.0, s.AsFloat64())
+
+	quantiles, err := copied.Quantiles()
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.5, 0.9}, quantiles)
+	for _, tc := range []struct{ q, want float64 }{
+		{0, 1},
+		{0.5, median},
+		{0.9, p90},
+		{1, 5},
+	} {
+		got, err := copied.Quantile(tc.q)
+		require.NoError(t, err)
+		require.Equal(t, tc.want, got, "quantile %v", tc.q)
+	}
+}
+
+// aggregationInterfaces lists every interface of the aggregation package
+// that an aggregator may implement and a spooled copy has to preserve.
+var aggregationInterfaces = map[string]reflect.Type{
+	"Sum":                  reflect.TypeOf((*aggregation.Sum)(nil)).Elem(),
+	"Count":                reflect.TypeOf((*aggregation.Count)(nil)).Elem(),
+	"Min":                  reflect.TypeOf((*aggregation.Min)(nil)).Elem(),
+	"Max":                  reflect.TypeOf((*aggregation.Max)(nil)).Elem(),
+	"MinMaxSumCount":       reflect.TypeOf((*aggregation.MinMaxSumCount)(nil)).Elem(),
+	"LastValue":            reflect.TypeOf((*aggregation.LastValue)(nil)).Elem(),
+	"Histogram":            reflect.TypeOf((*aggregation.Histogram)(nil)).Elem(),
+	"ExponentialHistogram": reflect.TypeOf((*aggregation.ExponentialHistogram)(nil)).Elem(),
+	"Summary":              reflect.TypeOf((*aggregation.Summary)(nil)).Elem(),
+	"Quantile":             reflect.TypeOf((*aggregation.Quantile)(nil)).Elem(),
+}
+
+// TestSpoolAggregationInterfacesListed fails when the aggregation package
+// gains an interface that aggregationInterfaces does not cover.
+func TestSpoolAggregationInterfacesListed(t *testing.T) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), "../aggregation", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	notAggregations := map[string]bool{
+		"Aggregation":         true,
+		"ExponentialBuckets":  true,
+		"TemporalitySelector": true,
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				spec, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+				if _, ok := spec.Type.(*ast.InterfaceType); ok && spec.Name.IsExported() && !notAggregations[spec.Name.Name] {
+					require.Contains(t, aggregationInterfaces, spec.Name.Name)
+				}
+				return false
+			})
+		}
+	}
+}
+
+// TestSpoolRoundTripsEveryAggregator checks that the copy of every
+// aggregator implements the same aggregation interfaces as the aggregator
+// and returns the same values from them.
+func TestSpoolRoundTripsEveryAggregator(t *testing.T) {
+	aggregators := map[string]func() aggregator.Aggregator{
+		"ddsketch": func() aggregator.Aggregator {
+			return &ddsketch.New(1, &duration, ddsketch.WithQuantiles(0.5, 0.9))[0]
+		},
+		"exponential": func() aggregator.Aggregator {
+			return &exponential.New(1, &duration, exponential.WithMaxScale(0))[0]
+		},
+		"histogram": func() aggregator.Aggregator {
+			return &histogram.New(1, &duration, histogram.WithExplicitBoundaries([]float64{1, 2, 4}))[0]
+		},
+		"lastvalue":      func() aggregator.Aggregator { return &lastvalue.New(1)[0] },
+		"minmaxsumcount": func() aggregator.Aggregator { return &minmaxsumcount.New(1)[0] },
+		"sum":            func() aggregator.Aggregator { return &sum.New(1)[0] },
+		"tdigest": func() aggregator.Aggregator {
+			return &tdigest.New(1, &duration, tdigest.WithQuantiles(0.5, 0.9))[0]
+		},
+	}
+
+	entries, err := os.ReadDir("../../aggregator")
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != "aggregatortest" {
+			require.Contains(t, aggregators, entry.Name(), "aggregator package is not round-tripped")
+		}
+	}
+
+	ctx := context.Background()
+	for name, newAggregator := range aggregators {
+		t.Run(name, func(t *testing.T) {
+			agg := newAggregator()
+			for _, v := range []float64{1, 3, 0.5, 2, 5} {
+				require.NoError(t, agg.Update(ctx, number.NewFloat64Number(v), &duration))
+			}
+			want := aggregationValues(t, agg.Aggregation())
+			copied := spoolRoundTrip(t, agg, &duration)
+
+			require.Equal(t, agg.Aggregation().Kind(), copied.Kind())
+			require.Equal(t, want, aggregationValues(t, copied))
+		})
+	}
+}
+
+// aggregationValues returns the results of every method agg implements
+// from aggregationInterfaces, keyed by interface and method.
+func aggregationValues(t *testing.T, agg aggregation.Aggregation) map[string][]interface{} {
+	values := map[string][]interface{}{}
+	v := reflect.ValueOf(agg)
+	for name, iface := range aggregationInterfaces {
+		if !v.Type().Implements(iface) {
+			continue
+		}
+		values[name] = nil
+		for i := 0; i < iface.NumMethod(); i++ {
+			method := iface.Method(i)
+			call := v.MethodByName(method.Name)
+			switch {
+			case method.Type.NumIn() == 0:
+				values[name+"."+method.Name] = resultValues(call.Call(nil))
+			case method.Name == "Quantile":
+				for _, q := range quantilesOf(t, agg) {
+					key := fmt.Sprintf("%s.%s(%v)", name, method.Name, q)
+					values[key] = resultValues(call.Call([]reflect.Value{reflect.ValueOf(q)}))
+				}
+			default:
+				t.Fatalf("no arguments known for %s.%s", name, method.Name)
+			}
+		}
+	}
+	return values
+}
+
+// quantilesOf returns the quantiles to compare for agg. Summaries are only
+// compared at the quantiles they report, since their copies interpolate
+// between them.
+func quantilesOf(t *testing.T, agg aggregation.Aggregation) []float64 {
+	if s, ok := agg.(aggregation.Summary); ok {
+		quantiles, err := s.Quantiles()
+		require.NoError(t, err)
+		return quantiles
+	}
+	return []float64{0, 0.25, 0.5, 0.9, 1}
+}
+
+// resultValues converts the results of a method call into comparable
+// values, copying buckets that the aggregator reuses once it is reset.
+func resultValues(results []reflect.Value) []interface{} {
+	var values []interface{}
+	for _, r := range results {
+		switch b := r.Interface().(type) {
+		case aggregation.ExponentialBuckets:
+			counts := make([]uint64, b.Len())
+			for i := range counts {
+				counts[i] = b.At(uint32(i))
+			}
+			values = append(values, b.Offset(), counts)
+		case aggregation.Buckets:
+			values = append(values, append([]float64(nil), b.Boundaries...), append([]uint64(nil), b.Counts...))
+		default:
+			values = append(values, b)
+		}
+	}
+	return values
+}
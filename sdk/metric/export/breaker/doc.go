@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package breaker implements an Exporter wrapper that stops calling a
+failing Exporter for a while.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+After a configured number of consecutive failed exports the circuit
+opens: exports are not attempted for a cool-down period and ErrOpen is
+returned instead, so that an unreachable backend does not consume CPU
+and network on every collection.  The first export after the cool-down
+is attempted normally; the circuit closes when it succeeds and opens
+again when it fails.
+
+	exp := breaker.New(otlpExporter,
+	        breaker.WithFailureThreshold(3),
+	        breaker.WithCooldown(time.Minute),
+	)
+	cont := controller.New(
+	        processor.NewFactory(selector, exp),
+	        controller.WithExporter(exp),
+	)
+
+By default the data presented while the circuit is open is dropped,
+which loses nothing for cumulative exporters since the next successful
+export includes it.  Exporters of delta temporality may use the Spool
+policy instead, which copies the data presented while the circuit is
+open, and after failed exports, into memory and exports it ahead of new
+data once the circuit closes.  At most WithSpoolSize exports are
+retained, the oldest ones being dropped first.
+*/
+package breaker // import "go.opentelemetry.io/otel/sdk/metric/export/breaker"
@@ -0,0 +1,353 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package breaker // import "go.opentelemetry.io/otel/sdk/metric/export/breaker"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// spooled is a copy of the data presented to one export, which
+// remains valid after the checkpoint it was copied from is reused.
+type spooled struct {
+	resource  *resource.Resource
+	libraries []*spooledLibrary
+	// sequence and sequenced are the collection sequence number of
+	// the checkpoint, see export.SequencedReader.
+	sequence  uint64
+	sequenced bool
+}
+
+type spooledLibrary struct {
+	sync.RWMutex
+	library instrumentation.Library
+	records []export.Record
+}
+
+var _ export.SequencedReader = &spooled{}
+var _ export.Reader = &spooledLibrary{}
+
+// newSpooled copies the records of reader, computed with the
+// temporality chosen by tempSelector.  Records whose aggregation cannot
+// be copied are reported to the global error handler and not spooled.
+func newSpooled(res *resource.Resource, reader export.InstrumentationLibraryReader, tempSelector aggregation.TemporalitySelector) (*spooled, error) {
+	s := &spooled{resource: res}
+	s.sequence, s.sequenced = export.Sequence(reader)
+	err := reader.ForEach(func(lib instrumentation.Library, r export.Reader) error {
+		sl := &spooledLibrary{library: lib}
+		if err := r.ForEach(tempSelector, func(rec export.Record) error {
+			agg, err := copyAggregation(rec.Aggregation())
+			if errors.Is(err, ErrUnsupportedAggregation) {
+				otel.Handle(fmt.Errorf("%w of %s", err, rec.Descriptor().Name()))
+				return nil
+			}
+			if err != nil || agg == nil {
+				return err
+			}
+			attrs := *rec.Attributes()
+			newRecord := export.NewRecord
+			if rec.Stale() {
+				newRecord = export.NewStaleRecord
+			}
+			sl.records = append(sl.records, newRecord(
+				rec.Descriptor(),
+				&attrs,
+				agg,
+				rec.StartTime(),
+				rec.EndTime(),
+			))
+			return nil
+		}); err != nil {
+			return err
+		}
+		if len(sl.records) != 0 {
+			s.libraries = append(s.libraries, sl)
+		}
+		return nil
+	})
+	return s, err
+}
+
+// Sequence implements export.SequencedReader.
+func (s *spooled) Sequence() (uint64, bool) {
+	return s.sequence, s.sequenced
+}
+
+// ForEach implements export.InstrumentationLibraryReader.
+func (s *spooled) ForEach(readerFunc func(instrumentation.Library, export.Reader) error) error {
+	for _, sl := range s.libraries {
+		if err := readerFunc(sl.library, sl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEach implements export.Reader.  The records were computed with the
+// temporality chosen when they were spooled.
+func (sl *spooledLibrary) ForEach(_ aggregation.TemporalitySelector, recordFunc func(export.Record) error) error {
+	for _, rec := range sl.records {
+		if err := recordFunc(rec); err != nil && !errors.Is(err, aggregation.ErrNoData) {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyAggregation returns a copy of agg, or nil when agg holds no data.
+// It returns an error wrapping ErrUnsupportedAggregation when agg cannot
+// be copied.
+func copyAggregation(agg aggregation.Aggregation) (aggregation.Aggregation, error) {
+	var err error
+	switch a := agg.(type) {
+	case aggregation.ExponentialHistogram:
+		return copyExponentialHistogram(a)
+	case aggregation.Summary:
+		return copySummary(a)
+	case aggregation.MinMaxSumCount:
+		m := &minMaxSumCountCopy{kind: a.Kind()}
+		if m.sum, err = a.Sum(); err == nil {
+			if m.count, err = a.Count(); err == nil {
+				if m.min, err = a.Min(); err == nil {
+					m.max, err = a.Max()
+				}
+			}
+		}
+		return noData(m, err)
+	case aggregation.Histogram:
+		h := &histogramCopy{kind: a.Kind()}
+		if h.sum, err = a.Sum(); err == nil {
+			if h.count, err = a.Count(); err == nil {
+				h.buckets, err = a.Histogram()
+			}
+		}
+		h.buckets = aggregation.Buckets{
+			Boundaries: append([]float64(nil), h.buckets.Boundaries...),
+			Counts:     append([]uint64(nil), h.buckets.Counts...),
+		}
+		return noData(h, err)
+	case aggregation.LastValue:
+		lv := &lastValueCopy{kind: a.Kind()}
+		lv.value, lv.timestamp, err = a.LastValue()
+		return noData(lv, err)
+	case aggregation.Sum:
+		s := &sumCopy{kind: a.Kind()}
+		s.sum, err = a.Sum()
+		return noData(s, err)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedAggregation, agg.Kind())
+}
+
+func copyExponentialHistogram(a aggregation.ExponentialHistogram) (aggregation.Aggregation, error) {
+	e := &exponentialCopy{kind: a.Kind()}
+	var err error
+	if e.sum, err = a.Sum(); err != nil {
+		return noData(e, err)
+	}
+	if e.count, err = a.Count(); err != nil {
+		return noData(e, err)
+	}
+	if e.scale, err = a.Scale(); err != nil {
+		return noData(e, err)
+	}
+	if e.zeroCount, err = a.ZeroCount(); err != nil {
+		return noData(e, err)
+	}
+	var positive, negative aggregation.ExponentialBuckets
+	if positive, err = a.Positive(); err != nil {
+		return noData(e, err)
+	}
+	if negative, err = a.Negative(); err != nil {
+		return noData(e, err)
+	}
+	e.positive, e.negative = copyBuckets(positive), copyBuckets(negative)
+	return e, nil
+}
+
+// copySummary copies the count, sum, minimum and maximum of a, and its
+// estimates of the 0, 1 and configured quantiles.
+func copySummary(a aggregation.Summary) (aggregation.Aggregation, error) {
+	s := &summaryCopy{kind: a.Kind()}
+	var err error
+	if s.sum, err = a.Sum(); err != nil {
+		return noData(s, err)
+	}
+	if s.count, err = a.Count(); err != nil {
+		return noData(s, err)
+	}
+	if s.min, err = a.Min(); err != nil {
+		return noData(s, err)
+	}
+	if s.max, err = a.Max(); err != nil {
+		return noData(s, err)
+	}
+	var quantiles []float64
+	if quantiles, err = a.Quantiles(); err != nil {
+		return noData(s, err)
+	}
+	s.quantiles = append([]float64(nil), quantiles...)
+	s.points = make([]float64, 0, len(quantiles)+2)
+	for _, q := range append(append([]float64{0}, quantiles...), 1) {
+		v, err := a.Quantile(q)
+		if err != nil {
+			return noData(s, err)
+		}
+		s.points = append(s.points, v)
+	}
+	return s, nil
+}
+
+func copyBuckets(b aggregation.ExponentialBuckets) *bucketsCopy {
+	c := &bucketsCopy{offset: b.Offset(), counts: make([]uint64, b.Len())}
+	for i := range c.counts {
+		c.counts[i] = b.At(uint32(i))
+	}
+	return c
+}
+
+func noData(agg aggregation.Aggregation, err error) (aggregation.Aggregation, error) {
+	if errors.Is(err, aggregation.ErrNoData) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return agg, nil
+}
+
+type sumCopy struct {
+	kind aggregation.Kind
+	sum  number.Number
+}
+
+func (s *sumCopy) Kind() aggregation.Kind      { return s.kind }
+func (s *sumCopy) Sum() (number.Number, error) { return s.sum, nil }
+
+type lastValueCopy struct {
+	kind      aggregation.Kind
+	value     number.Number
+	timestamp time.Time
+}
+
+func (lv *lastValueCopy) Kind() aggregation.Kind { return lv.kind }
+func (lv *lastValueCopy) LastValue() (number.Number, time.Time, error) {
+	return lv.value, lv.timestamp, nil
+}
+
+type histogramCopy struct {
+	kind    aggregation.Kind
+	sum     number.Number
+	count   uint64
+	buckets aggregation.Buckets
+}
+
+func (h *histogramCopy) Kind() aggregation.Kind                  { return h.kind }
+func (h *histogramCopy) Sum() (number.Number, error)             { return h.sum, nil }
+func (h *histogramCopy) Count() (uint64, error)                  { return h.count, nil }
+func (h *histogramCopy) Histogram() (aggregation.Buckets, error) { return h.buckets, nil }
+func (h *histogramCopy) Quantile(q float64) (float64, error)     { return h.buckets.Quantile(q) }
+
+type minMaxSumCountCopy struct {
+	kind     aggregation.Kind
+	min, max number.Number
+	sum      number.Number
+	count    uint64
+}
+
+func (m *minMaxSumCountCopy) Kind() aggregation.Kind      { return m.kind }
+func (m *minMaxSumCountCopy) Min() (number.Number, error) { return m.min, nil }
+func (m *minMaxSumCountCopy) Max() (number.Number, error) { return m.max, nil }
+func (m *minMaxSumCountCopy) Sum() (number.Number, error) { return m.sum, nil }
+func (m *minMaxSumCountCopy) Count() (uint64, error)      { return m.count, nil }
+
+// summaryCopy answers the quantiles that were copied, and interpolates
+// linearly between them for the others.
+type summaryCopy struct {
+	kind      aggregation.Kind
+	sum       number.Number
+	count     uint64
+	min, max  number.Number
+	quantiles []float64
+	// points holds the estimates of the 0 quantile, those of
+	// quantiles, and the estimate of the 1 quantile.
+	points []float64
+}
+
+func (s *summaryCopy) Kind() aggregation.Kind        { return s.kind }
+func (s *summaryCopy) Sum() (number.Number, error)   { return s.sum, nil }
+func (s *summaryCopy) Count() (uint64, error)        { return s.count, nil }
+func (s *summaryCopy) Min() (number.Number, error)   { return s.min, nil }
+func (s *summaryCopy) Max() (number.Number, error)   { return s.max, nil }
+func (s *summaryCopy) Quantiles() ([]float64, error) { return s.quantiles, nil }
+func (s *summaryCopy) Quantile(q float64) (float64, error) {
+	if !(q >= 0 && q <= 1) {
+		return 0, aggregation.ErrInvalidQuantile
+	}
+	lowQ, low := 0.0, s.points[0]
+	for i := 1; i < len(s.points); i++ {
+		highQ, high := 1.0, s.points[i]
+		if i <= len(s.quantiles) {
+			highQ = s.quantiles[i-1]
+		}
+		if q <= highQ {
+			if highQ == lowQ {
+				return high, nil
+			}
+			return low + (high-low)*(q-lowQ)/(highQ-lowQ), nil
+		}
+		lowQ, low = highQ, high
+	}
+	return s.points[len(s.points)-1], nil
+}
+
+type exponentialCopy struct {
+	kind      aggregation.Kind
+	sum       number.Number
+	count     uint64
+	scale     int32
+	zeroCount uint64
+	positive  *bucketsCopy
+	negative  *bucketsCopy
+}
+
+func (e *exponentialCopy) Kind() aggregation.Kind      { return e.kind }
+func (e *exponentialCopy) Sum() (number.Number, error) { return e.sum, nil }
+func (e *exponentialCopy) Count() (uint64, error)      { return e.count, nil }
+func (e *exponentialCopy) Scale() (int32, error)       { return e.scale, nil }
+func (e *exponentialCopy) ZeroCount() (uint64, error)  { return e.zeroCount, nil }
+func (e *exponentialCopy) Positive() (aggregation.ExponentialBuckets, error) {
+	return e.positive, nil
+}
+func (e *exponentialCopy) Negative() (aggregation.ExponentialBuckets, error) {
+	return e.negative, nil
+}
+
+type bucketsCopy struct {
+	offset int32
+	counts []uint64
+}
+
+func (b *bucketsCopy) Offset() int32      { return b.offset }
+func (b *bucketsCopy) Len() uint32        { return uint32(len(b.counts)) }
+func (b *bucketsCopy) At(i uint32) uint64 { return b.counts[i] }
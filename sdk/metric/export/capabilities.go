@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export // import "go.opentelemetry.io/otel/sdk/metric/export"
+
+import (
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// Capabilities describes the data that an exporter is able to export,
+// so that an export pipeline can be configured to produce only such data
+// instead of failing at export time.
+type Capabilities struct {
+	// Aggregations lists the supported kinds of aggregation.  Nil
+	// means that every kind is supported.
+	Aggregations []aggregation.Kind
+
+	// Temporalities lists the supported temporalities.  Nil means
+	// that every temporality is supported.
+	Temporalities []aggregation.Temporality
+}
+
+// CapabilityReporter is an optional interface implemented by exporters
+// that do not support every kind of data.
+type CapabilityReporter interface {
+	// Capabilities returns the data supported by the exporter.
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the Capabilities reported by exporter, which
+// supports every kind of data when it does not implement
+// CapabilityReporter.
+func CapabilitiesOf(exporter interface{}) Capabilities {
+	if r, ok := exporter.(CapabilityReporter); ok {
+		return r.Capabilities()
+	}
+	return Capabilities{}
+}
+
+// SupportsAggregation returns true if the aggregation kind is supported.
+func (c Capabilities) SupportsAggregation(kind aggregation.Kind) bool {
+	if c.Aggregations == nil {
+		return true
+	}
+	for _, k := range c.Aggregations {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsTemporality returns true if the temporality is supported.
+func (c Capabilities) SupportsTemporality(t aggregation.Temporality) bool {
+	if c.Temporalities == nil {
+		return true
+	}
+	for _, s := range c.Temporalities {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+// TemporalitySelector returns a TemporalitySelector that returns the
+// Temporality chosen by preferred when it is supported, and otherwise
+// the first supported Temporality.
+func (c Capabilities) TemporalitySelector(preferred aggregation.TemporalitySelector) aggregation.TemporalitySelector {
+	return capabilityTemporalitySelector{
+		preferred:    preferred,
+		capabilities: c,
+	}
+}
+
+type capabilityTemporalitySelector struct {
+	preferred    aggregation.TemporalitySelector
+	capabilities Capabilities
+}
+
+// TemporalityFor implements aggregation.TemporalitySelector.
+func (s capabilityTemporalitySelector) TemporalityFor(desc *sdkapi.Descriptor, kind aggregation.Kind) aggregation.Temporality {
+	t := s.preferred.TemporalityFor(desc, kind)
+	if s.capabilities.SupportsTemporality(t) || len(s.capabilities.Temporalities) == 0 {
+		return t
+	}
+	return s.capabilities.Temporalities[0]
+}
@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type cumulativeOnly struct{}
+
+func (cumulativeOnly) Capabilities() Capabilities {
+	return Capabilities{
+		Temporalities: []aggregation.Temporality{aggregation.CumulativeTemporality},
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	all := CapabilitiesOf(struct{}{})
+	require.True(t, all.SupportsAggregation(aggregation.HistogramKind))
+	require.True(t, all.SupportsTemporality(aggregation.DeltaTemporality))
+
+	caps := CapabilitiesOf(cumulativeOnly{})
+	require.True(t, caps.SupportsAggregation(aggregation.HistogramKind))
+	require.False(t, caps.SupportsTemporality(aggregation.DeltaTemporality))
+	require.True(t, caps.SupportsTemporality(aggregation.CumulativeTemporality))
+
+	desc := sdkapi.NewDescriptor("counter", sdkapi.CounterInstrumentKind, number.Int64Kind, "", "")
+	require.Equal(t, aggregation.CumulativeTemporality,
+		caps.TemporalitySelector(aggregation.DeltaTemporalitySelector()).TemporalityFor(&desc, aggregation.SumKind))
+	require.Equal(t, aggregation.DeltaTemporality,
+		all.TemporalitySelector(aggregation.DeltaTemporalitySelector()).TemporalityFor(&desc, aggregation.SumKind))
+}
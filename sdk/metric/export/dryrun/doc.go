@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package dryrun implements an Exporter wrapper that encodes and summarizes
+collections instead of exporting them.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+A dry run validates configuration changes, such as new views or
+attribute filters, against production traffic without sending anything
+to the backend.  The wrapped Exporter is never called; it only selects
+the temporality of the collection, so that the summary describes exactly
+what it would have sent:
+
+	enc, err := encoding.Lookup("otlp-proto")
+	if err != nil {
+	        return err
+	}
+	exp := dryrun.New(otlpExporter, dryrun.WithEncoder(enc))
+	cont := controller.New(
+	        processor.NewFactory(selector, exp),
+	        controller.WithExporter(exp),
+	)
+
+Every export logs a Summary with the number of metrics and points, the
+size of the encoded payload and the metrics with the most points, which
+are the first to inspect when the cardinality of a change is too high.
+*/
+package dryrun // import "go.opentelemetry.io/otel/sdk/metric/export/dryrun"
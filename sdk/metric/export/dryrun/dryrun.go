@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dryrun // import "go.opentelemetry.io/otel/sdk/metric/export/dryrun"
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/encoding"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// DefaultTopMetrics is the default number of metrics listed in a
+// Summary.
+const DefaultTopMetrics = 10
+
+// config contains the configuration of an Exporter.
+type config struct {
+	encoder    encoding.Encoder
+	logger     *log.Logger
+	topMetrics int
+}
+
+// Option configures an Exporter.
+type Option interface {
+	apply(config) config
+}
+
+type optionFunc func(config) config
+
+func (fn optionFunc) apply(cfg config) config {
+	return fn(cfg)
+}
+
+// WithEncoder sets the Encoder of the payloads whose size is reported.
+// The default is the InfluxDB line protocol encoder of the encoding
+// package.
+func WithEncoder(enc encoding.Encoder) Option {
+	return optionFunc(func(cfg config) config {
+		if enc != nil {
+			cfg.encoder = enc
+		}
+		return cfg
+	})
+}
+
+// WithLogger sets the Logger that summaries are written to.  The default
+// is the standard logger of the log package.
+func WithLogger(logger *log.Logger) Option {
+	return optionFunc(func(cfg config) config {
+		if logger != nil {
+			cfg.logger = logger
+		}
+		return cfg
+	})
+}
+
+// WithTopMetrics sets the largest number of metrics listed in a Summary.
+// The default is DefaultTopMetrics.
+func WithTopMetrics(n int) Option {
+	return optionFunc(func(cfg config) config {
+		if n >= 0 {
+			cfg.topMetrics = n
+		}
+		return cfg
+	})
+}
+
+// Summary describes a collection presented to an Exporter.
+type Summary struct {
+	// Metrics is the number of distinct metrics.
+	Metrics int
+	// Points is the number of data points, one per attribute set
+	// of every metric.
+	Points int
+	// Bytes is the size of the encoded collection.
+	Bytes int
+	// ContentType is the media type of the encoding.
+	ContentType string
+	// TopMetrics are the metrics with the most points, the largest
+	// first.
+	TopMetrics []MetricPoints
+}
+
+// MetricPoints is the number of points of one metric.
+type MetricPoints struct {
+	Library string
+	Name    string
+	Points  int
+}
+
+// String returns a one-line description of the Summary.
+func (s Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "dry run: %d metrics, %d points, %d bytes of %s", s.Metrics, s.Points, s.Bytes, s.ContentType)
+	for i, m := range s.TopMetrics {
+		sep := ", "
+		if i == 0 {
+			sep = "; top: "
+		}
+		fmt.Fprintf(&b, "%s%s/%s=%d", sep, m.Library, m.Name, m.Points)
+	}
+	return b.String()
+}
+
+// Exporter is an export.Exporter that logs a Summary of every collection
+// instead of exporting it.
+type Exporter struct {
+	export.Exporter
+
+	config config
+
+	lock sync.Mutex
+	last Summary
+}
+
+var _ export.Exporter = &Exporter{}
+
+// New returns an Exporter that summarizes the collections selected with
+// the temporalities of exp, without calling its Export method.
+func New(exp export.Exporter, opts ...Option) *Exporter {
+	cfg := config{
+		encoder:    encoding.NewInfluxEncoder(),
+		logger:     log.Default(),
+		topMetrics: DefaultTopMetrics,
+	}
+	for _, opt := range opts {
+		cfg = opt.apply(cfg)
+	}
+	return &Exporter{
+		Exporter: exp,
+		config:   cfg,
+	}
+}
+
+// Export encodes and summarizes the collection, and logs the Summary.
+// It returns the error of the Encoder, if any.
+func (e *Exporter) Export(ctx context.Context, res *resource.Resource, reader export.InstrumentationLibraryReader) error {
+	payload, err := e.config.encoder.Encode(ctx, res, reader, e.Exporter)
+	if err != nil {
+		return err
+	}
+	summary, err := e.summarize(reader)
+	if err != nil {
+		return err
+	}
+	summary.Bytes = len(payload)
+	summary.ContentType = e.config.encoder.ContentType()
+
+	e.lock.Lock()
+	e.last = summary
+	e.lock.Unlock()
+
+	e.config.logger.Print(summary)
+	return nil
+}
+
+// Last returns the Summary of the most recent export.
+func (e *Exporter) Last() Summary {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.last
+}
+
+func (e *Exporter) summarize(reader export.InstrumentationLibraryReader) (Summary, error) {
+	var (
+		summary Summary
+		metrics []MetricPoints
+		index   = map[[2]string]int{}
+	)
+	err := export.ForEachMetric(reader, e.Exporter, func(m export.Metric) error {
+		summary.Points++
+		key := [2]string{m.Library.Name, m.Descriptor().Name()}
+		i, ok := index[key]
+		if !ok {
+			i = len(metrics)
+			index[key] = i
+			metrics = append(metrics, MetricPoints{Library: key[0], Name: key[1]})
+		}
+		metrics[i].Points++
+		return nil
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+	summary.Metrics = len(metrics)
+
+	sort.SliceStable(metrics, func(i, j int) bool {
+		if metrics[i].Points != metrics[j].Points {
+			return metrics[i].Points > metrics[j].Points
+		}
+		if metrics[i].Library != metrics[j].Library {
+			return metrics[i].Library < metrics[j].Library
+		}
+		return metrics[i].Name < metrics[j].Name
+	})
+	if len(metrics) > e.config.topMetrics {
+		metrics = metrics[:e.config.topMetrics]
+	}
+	summary.TopMetrics = metrics
+	return summary, nil
+}
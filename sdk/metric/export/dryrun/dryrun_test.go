@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// testExporter fails the test when it is called.
+type testExporter struct {
+	aggregation.TemporalitySelector
+	t *testing.T
+}
+
+func (e testExporter) Export(context.Context, *resource.Resource, export.InstrumentationLibraryReader) error {
+	e.t.Fatal("the wrapped exporter was called")
+	return nil
+}
+
+func collection(t *testing.T) export.InstrumentationLibraryReader {
+	ctx := context.Background()
+	now := time.Now()
+	requests := metrictest.NewDescriptor("requests", sdkapi.CounterInstrumentKind, number.Int64Kind)
+	errors := metrictest.NewDescriptor("errors", sdkapi.CounterInstrumentKind, number.Int64Kind)
+
+	record := func(desc *sdkapi.Descriptor, kvs ...attribute.KeyValue) export.Record {
+		s := &sum.New(1)[0]
+		require.NoError(t, s.Update(ctx, number.NewInt64Number(1), desc))
+		attrs := attribute.NewSet(kvs...)
+		return export.NewRecord(desc, &attrs, s.Aggregation(), now, now)
+	}
+	return processorTest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		{Name: "http"}: {
+			record(&requests, attribute.String("route", "/a")),
+			record(&requests, attribute.String("route", "/b")),
+			record(&requests, attribute.String("route", "/c")),
+			record(&errors),
+		},
+		{Name: "grpc"}: {
+			record(&requests, attribute.String("method", "Get")),
+			record(&requests, attribute.String("method", "Put")),
+		},
+	})
+}
+
+func TestExport(t *testing.T) {
+	var buf bytes.Buffer
+	e := New(
+		testExporter{TemporalitySelector: aggregation.CumulativeTemporalitySelector(), t: t},
+		WithLogger(log.New(&buf, "", 0)),
+		WithTopMetrics(2),
+	)
+	require.NoError(t, e.Export(context.Background(), resource.Empty(), collection(t)))
+
+	summary := e.Last()
+	require.Equal(t, 3, summary.Metrics)
+	require.Equal(t, 6, summary.Points)
+	require.Greater(t, summary.Bytes, 0)
+	require.Equal(t, "text/plain; charset=utf-8", summary.ContentType)
+	require.Equal(t, []MetricPoints{
+		{Library: "http", Name: "requests", Points: 3},
+		{Library: "grpc", Name: "requests", Points: 2},
+	}, summary.TopMetrics)
+	require.Equal(t, summary.String()+"\n", buf.String())
+	require.Contains(t, buf.String(), "dry run: 3 metrics, 6 points, ")
+	require.Contains(t, buf.String(), "; top: http/requests=3, grpc/requests=2")
+}
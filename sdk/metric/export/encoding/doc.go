@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package encoding provides a registry of metric encoders that translate
+a collection into a payload of one wire format, independent of the
+transport that carries it.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+Encoders register themselves by name, usually from the init function
+of the package that implements them, in the manner of database/sql
+drivers.  This package registers the "influx" line protocol encoder;
+importing go.opentelemetry.io/otel/exporters/otlp/otlpmetric registers
+"otlp-proto" and "otlp-json", and importing
+go.opentelemetry.io/otel/exporters/prometheus registers "prometheus".
+
+NewExporter composes encoders with a Sender into an Exporter.  Every
+Target is encoded from the same collection, so a single controller can
+publish, for example, OTLP-JSON to a message bus and line protocol to a
+file:
+
+	enc, err := encoding.Lookup("otlp-json")
+	if err != nil {
+	        return err
+	}
+	exp := encoding.NewExporter(
+	        aggregation.CumulativeTemporalitySelector(),
+	        encoding.Target{Encoder: enc, Sender: func(ctx context.Context, contentType string, payload []byte) error {
+	                return bus.Publish(ctx, "metrics", contentType, payload)
+	        }},
+	)
+*/
+package encoding // import "go.opentelemetry.io/otel/sdk/metric/export/encoding"
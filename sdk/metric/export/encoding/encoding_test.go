@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/export/encoding"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+var (
+	library  = instrumentation.Library{Name: "test"}
+	counter  = metrictest.NewDescriptor("requests count", sdkapi.CounterInstrumentKind, number.Int64Kind)
+	gauge    = metrictest.NewDescriptor("temperature", sdkapi.GaugeObserverInstrumentKind, number.Float64Kind)
+	duration = metrictest.NewDescriptor("duration", sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	end      = time.Unix(10, 0)
+)
+
+func collection(t *testing.T) export.InstrumentationLibraryReader {
+	ctx := context.Background()
+
+	s := &sum.New(1)[0]
+	require.NoError(t, s.Update(ctx, number.NewInt64Number(3), &counter))
+	attrs := attribute.NewSet(attribute.String("host", "a,b"), attribute.String("R", "record"))
+
+	lv := &lastvalue.New(1)[0]
+	require.NoError(t, lv.Update(aggregator.ContextWithObservationTime(ctx, time.Unix(5, 0)), number.NewFloat64Number(21.5), &gauge))
+
+	h := &histogram.New(1, &duration, histogram.WithExplicitBoundaries([]float64{1, 10}))[0]
+	for _, v := range []float64{0.5, 5, 50} {
+		require.NoError(t, h.Update(ctx, number.NewFloat64Number(v), &duration))
+	}
+
+	return processorTest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		library: {
+			export.NewRecord(&counter, &attrs, s.Aggregation(), end, end),
+			export.NewRecord(&gauge, attribute.EmptySet(), lv.Aggregation(), end, end),
+			export.NewRecord(&duration, attribute.EmptySet(), h.Aggregation(), end, end),
+		},
+	})
+}
+
+func TestInfluxEncoder(t *testing.T) {
+	enc, err := encoding.Lookup("influx")
+	require.NoError(t, err)
+	require.Equal(t, "text/plain; charset=utf-8", enc.ContentType())
+
+	res := resource.NewSchemaless(attribute.String("R", "resource"), attribute.String("service", "svc"))
+	payload, err := enc.Encode(context.Background(), res, collection(t), aggregation.CumulativeTemporalitySelector())
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{
+		`requests\ count,R=record,host=a\,b,service=svc value=3i 10000000000`,
+		`temperature,R=resource,service=svc value=21.5 5000000000`,
+		`duration,R=resource,service=svc count=3i,sum=55.5,1=1i,10=2i,+Inf=3i 10000000000`,
+	}, strings.Split(strings.TrimSuffix(string(payload), "\n"), "\n"))
+}
+
+type stubEncoder struct {
+	contentType string
+	err         error
+}
+
+func (s stubEncoder) ContentType() string { return s.contentType }
+
+func (s stubEncoder) Encode(_ context.Context, _ *resource.Resource, reader export.InstrumentationLibraryReader, sel aggregation.TemporalitySelector) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var names []string
+	err := reader.ForEach(func(_ instrumentation.Library, r export.Reader) error {
+		return r.ForEach(sel, func(rec export.Record) error {
+			names = append(names, rec.Descriptor().Name())
+			return nil
+		})
+	})
+	return []byte(strings.Join(names, ",")), err
+}
+
+func TestRegistry(t *testing.T) {
+	encoding.Register("test-stub", stubEncoder{contentType: "text/stub"})
+	t.Cleanup(func() { encoding.Unregister("test-stub") })
+	require.Panics(t, func() { encoding.Register("test-stub", stubEncoder{}) })
+	require.Panics(t, func() { encoding.Register("test-nil", nil) })
+
+	enc, err := encoding.Lookup("test-stub")
+	require.NoError(t, err)
+	require.Equal(t, "text/stub", enc.ContentType())
+	require.Contains(t, encoding.Names(), "influx")
+	require.Contains(t, encoding.Names(), "test-stub")
+
+	_, err = encoding.Lookup("unknown")
+	require.ErrorIs(t, err, encoding.ErrUnknownEncoder)
+}
+
+func TestExporterSingleCollection(t *testing.T) {
+	errEncode := fmt.Errorf("encode failed")
+	errSend := fmt.Errorf("send failed")
+	sent := map[string]string{}
+	send := func(_ context.Context, contentType string, payload []byte) error {
+		sent[contentType] = string(payload)
+		return nil
+	}
+
+	exp := encoding.NewExporter(
+		aggregation.DeltaTemporalitySelector(),
+		encoding.Target{Encoder: stubEncoder{contentType: "a"}, Sender: send},
+		encoding.Target{Encoder: stubEncoder{contentType: "b", err: errEncode}, Sender: send},
+		encoding.Target{Encoder: encoding.NewInfluxEncoder(), Sender: send},
+		encoding.Target{Encoder: stubEncoder{contentType: "c"}, Sender: func(context.Context, string, []byte) error { return errSend }},
+	)
+	require.Equal(t, aggregation.DeltaTemporality, exp.TemporalityFor(&counter, aggregation.SumKind))
+
+	err := exp.Export(context.Background(), resource.Empty(), collection(t))
+	require.True(t, errors.Is(err, errEncode))
+	require.True(t, errors.Is(err, errSend))
+
+	require.Len(t, sent, 2)
+	require.Equal(t, "requests count,temperature,duration", sent["a"])
+	require.Len(t, strings.Split(strings.TrimSpace(sent["text/plain; charset=utf-8"]), "\n"), 3)
+}
@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding // import "go.opentelemetry.io/otel/sdk/metric/export/encoding"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func init() {
+	Register("influx", NewInfluxEncoder())
+}
+
+// NewInfluxEncoder returns an Encoder of the InfluxDB line protocol.
+//
+// Each record is written as one line whose measurement is the
+// instrument name and whose tags are the resource attributes followed
+// by the record attributes, which take precedence.  Sums and last
+// values are written as the field "value".  Histograms are written as
+// the fields "count" and "sum" and one field per bucket boundary
+// holding the cumulative count of the bucket, with "+Inf" for the
+// last, following the conventions of Telegraf's Prometheus parser.
+// The timestamp is the time of the last value, or the record end time
+// for other aggregations, in nanoseconds.
+func NewInfluxEncoder() Encoder {
+	return influxEncoder{}
+}
+
+type influxEncoder struct{}
+
+// ContentType returns the media type of the InfluxDB line protocol.
+func (influxEncoder) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+// Encode implements Encoder.
+func (influxEncoder) Encode(_ context.Context, res *resource.Resource, reader export.InstrumentationLibraryReader, sel aggregation.TemporalitySelector) ([]byte, error) {
+	var buf bytes.Buffer
+	err := reader.ForEach(func(_ instrumentation.Library, r export.Reader) error {
+		return r.ForEach(sel, func(rec export.Record) error {
+			return writeInfluxLine(&buf, res, rec)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeInfluxLine(buf *bytes.Buffer, res *resource.Resource, rec export.Record) error {
+	desc := rec.Descriptor()
+	kind := desc.NumberKind()
+	fields := make([]string, 0, 2)
+	ts := rec.EndTime()
+
+	switch agg := rec.Aggregation().(type) {
+	case aggregation.Histogram:
+		count, err := agg.Count()
+		if err != nil {
+			return err
+		}
+		sum, err := agg.Sum()
+		if err != nil {
+			return err
+		}
+		buckets, err := agg.Histogram()
+		if err != nil {
+			return err
+		}
+		fields = append(fields, "count="+strconv.FormatUint(count, 10)+"i", "sum="+formatInfluxNumber(kind, sum))
+		var cumulative uint64
+		for i, c := range buckets.Counts {
+			cumulative += c
+			bound := "+Inf"
+			if i < len(buckets.Boundaries) {
+				bound = strconv.FormatFloat(buckets.Boundaries[i], 'g', -1, 64)
+			}
+			fields = append(fields, escapeInfluxKey(bound)+"="+strconv.FormatUint(cumulative, 10)+"i")
+		}
+	case aggregation.Sum:
+		sum, err := agg.Sum()
+		if err != nil {
+			return err
+		}
+		fields = append(fields, "value="+formatInfluxNumber(kind, sum))
+	case aggregation.LastValue:
+		lv, observed, err := agg.LastValue()
+		if errors.Is(err, aggregation.ErrNoData) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fields = append(fields, "value="+formatInfluxNumber(kind, lv))
+		ts = observed
+	default:
+		return fmt.Errorf("influx: unsupported aggregation: %s", rec.Aggregation().Kind())
+	}
+
+	buf.WriteString(escapeInfluxMeasurement(desc.Name()))
+	tags := mergeAttributes(res.Set(), rec.Attributes())
+	for iter := tags.Iter(); iter.Next(); {
+		kv := iter.Attribute()
+		value := kv.Value.Emit()
+		if value == "" {
+			// Empty tag values are not valid line protocol.
+			continue
+		}
+		buf.WriteByte(',')
+		buf.WriteString(escapeInfluxKey(string(kv.Key)))
+		buf.WriteByte('=')
+		buf.WriteString(escapeInfluxKey(value))
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(strings.Join(fields, ","))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatInt(ts.UnixNano(), 10))
+	buf.WriteByte('\n')
+	return nil
+}
+
+// mergeAttributes returns the union of the resource and record
+// attributes, preferring the record for duplicate keys.
+func mergeAttributes(res, rec *attribute.Set) attribute.Set {
+	if res.Len() == 0 {
+		return *rec
+	}
+	kvs := append(res.ToSlice(), rec.ToSlice()...)
+	// NewSet keeps the last value of duplicate keys.
+	return attribute.NewSet(kvs...)
+}
+
+func formatInfluxNumber(kind number.Kind, n number.Number) string {
+	if kind == number.Int64Kind {
+		return strconv.FormatInt(n.AsInt64(), 10) + "i"
+	}
+	return strconv.FormatFloat(n.AsFloat64(), 'g', -1, 64)
+}
+
+var (
+	influxMeasurementReplacer = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	influxKeyReplacer         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+)
+
+func escapeInfluxMeasurement(s string) string {
+	return influxMeasurementReplacer.Replace(s)
+}
+
+func escapeInfluxKey(s string) string {
+	return influxKeyReplacer.Replace(s)
+}
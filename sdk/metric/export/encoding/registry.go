@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding // import "go.opentelemetry.io/otel/sdk/metric/export/encoding"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// ErrUnknownEncoder is returned by Lookup for a name that no Encoder
+// has been registered with.
+var ErrUnknownEncoder = fmt.Errorf("unknown metric encoder")
+
+// Encoder translates a collection into a payload of one format.
+type Encoder interface {
+	// ContentType returns the media type of the payloads returned
+	// by Encode.
+	ContentType() string
+
+	// Encode returns the encoding of the collection read from
+	// reader, which reports temporalities according to the
+	// passed selector.
+	Encode(ctx context.Context, res *resource.Resource, reader export.InstrumentationLibraryReader, sel aggregation.TemporalitySelector) ([]byte, error)
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]Encoder{}
+)
+
+// Register makes an Encoder available by the provided name.  Register
+// panics if it is called twice with the same name or if enc is nil.
+func Register(name string, enc Encoder) {
+	if enc == nil {
+		panic("encoding: Register encoder is nil")
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, dup := registry[name]; dup {
+		panic("encoding: Register called twice for encoder " + name)
+	}
+	registry[name] = enc
+}
+
+// unregister removes the Encoder registered with the provided name, so
+// that tests can register theirs again.
+func unregister(name string) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	delete(registry, name)
+}
+
+// Lookup returns the Encoder registered with the provided name.
+func Lookup(name string) (Encoder, error) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	enc, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncoder, name)
+	}
+	return enc, nil
+}
+
+// Names returns the sorted names of the registered Encoders.
+func Names() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Sender transmits an encoded payload over a transport of the user's
+// choice.
+type Sender func(ctx context.Context, contentType string, payload []byte) error
+
+// Target pairs an Encoder with the Sender its payloads are passed to.
+type Target struct {
+	Encoder Encoder
+	Sender  Sender
+}
+
+type exporter struct {
+	aggregation.TemporalitySelector
+	targets []Target
+}
+
+var _ export.Exporter = &exporter{}
+
+// NewExporter returns an Exporter that encodes every collection once
+// for each of the targets and passes the payloads to their Senders.
+// The selector determines the temporality requested from the
+// processor, which is shared by all the targets.
+//
+// A failure of one target does not prevent the export to the others;
+// the errors are joined in the returned error.
+func NewExporter(sel aggregation.TemporalitySelector, targets ...Target) export.Exporter {
+	return &exporter{
+		TemporalitySelector: sel,
+		targets:             append([]Target(nil), targets...),
+	}
+}
+
+// Export implements export.Exporter.
+func (e *exporter) Export(ctx context.Context, res *resource.Resource, reader export.InstrumentationLibraryReader) error {
+	var errs []error
+	for _, t := range e.targets {
+		payload, err := t.Encoder.Encode(ctx, res, reader, e.TemporalitySelector)
+		if err == nil {
+			err = t.Sender(ctx, t.Encoder.ContentType(), payload)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", t.Encoder.ContentType(), err))
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return multiError(errs)
+}
+
+// multiError reports the errors of several targets.
+type multiError []error
+
+func (m multiError) Error() string {
+	msg := m[0].Error()
+	for _, err := range m[1:] {
+		msg += "; " + err.Error()
+	}
+	return msg
+}
+
+// Is reports whether any of the errors matches target.
+func (m multiError) Is(target error) bool {
+	for _, err := range m {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package flightrecorder implements an Exporter wrapper that retains the
+most recent collections in memory for inspection.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+The Recorder copies every collection presented to the wrapped Exporter
+into a fixed-size ring buffer before exporting it, together with the
+result of the export.  When the exporter is failing, for example because
+the backend is unreachable during an incident, the last few collections
+remain available through Snapshots, or as JSON by serving the Recorder
+as an http.Handler:
+
+	rec := flightrecorder.New(otlpExporter, 30)
+	cont := controller.New(
+	        processor.NewFactory(selector, rec),
+	        controller.WithExporter(rec),
+	)
+	http.Handle("/debug/metrics/recent", rec)
+
+NewInMemory returns a Recorder that wraps no Exporter, for environments
+such as WebAssembly plugins where the collections are read back with
+Snapshots.  The http.Handler is not available when building with the
+tinygo build tag.
+
+The memory retained is proportional to the number of snapshots times the
+number of exported series.
+*/
+package flightrecorder // import "go.opentelemetry.io/otel/sdk/metric/export/flightrecorder"
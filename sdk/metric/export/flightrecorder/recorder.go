@@ -0,0 +1,321 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightrecorder // import "go.opentelemetry.io/otel/sdk/metric/export/flightrecorder"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+type (
+	// Recorder is an Exporter that keeps the last collections
+	// presented to the wrapped Exporter in a ring buffer.
+	Recorder struct {
+		export.Exporter
+
+		lock      sync.Mutex
+		snapshots []Snapshot
+		next      int
+		full      bool
+	}
+
+	// Snapshot is a copy of one collection.
+	Snapshot struct {
+		// Time is when the collection was exported.
+		Time time.Time `json:"time"`
+
+		// Resource holds the attributes of the exported resource.
+		Resource []attribute.KeyValue `json:"resource,omitempty"`
+
+		// Points holds one entry per exported record.
+		Points []Point `json:"points"`
+
+		// Error is the error returned by the wrapped Exporter, if
+		// any.
+		Error string `json:"error,omitempty"`
+	}
+
+	// Point is a copy of one exported record.
+	Point struct {
+		Library     instrumentation.Library `json:"library"`
+		Name        string                  `json:"name"`
+		Attributes  []attribute.KeyValue    `json:"attributes,omitempty"`
+		Aggregation aggregation.Kind        `json:"aggregation"`
+		StartTime   time.Time               `json:"start_time"`
+		EndTime     time.Time               `json:"end_time"`
+
+		// Value is the Sum or the LastValue of the record,
+		// whichever is defined.
+		Value float64 `json:"value"`
+
+		// Count is the Count of the record, when defined.
+		Count uint64 `json:"count,omitempty"`
+
+		// Min and Max are the Min and the Max of the record,
+		// when defined.
+		Min *float64 `json:"min,omitempty"`
+		Max *float64 `json:"max,omitempty"`
+
+		// Boundaries and Counts hold the buckets of histogram
+		// records.
+		Boundaries []float64 `json:"boundaries,omitempty"`
+		Counts     []uint64  `json:"counts,omitempty"`
+
+		// Quantiles and QuantileValues hold the configured
+		// quantiles of summary records and their estimates.
+		Quantiles      []float64 `json:"quantiles,omitempty"`
+		QuantileValues []float64 `json:"quantile_values,omitempty"`
+
+		// Scale, ZeroCount, Positive and Negative hold the
+		// buckets of exponential histogram records, which have
+		// both Positive and Negative set.
+		Scale     int32               `json:"scale,omitempty"`
+		ZeroCount uint64              `json:"zero_count,omitempty"`
+		Positive  *ExponentialBuckets `json:"positive,omitempty"`
+		Negative  *ExponentialBuckets `json:"negative,omitempty"`
+	}
+
+	// ExponentialBuckets is a copy of a range of consecutive buckets
+	// of an exponential histogram, see
+	// aggregation.ExponentialBuckets.
+	ExponentialBuckets struct {
+		Offset int32    `json:"offset"`
+		Counts []uint64 `json:"counts,omitempty"`
+	}
+)
+
+var _ export.Exporter = &Recorder{}
+
+// ErrUnsupportedAggregation is reported to the global error handler for
+// the records whose aggregation a Point cannot represent, which are not
+// retained.
+var ErrUnsupportedAggregation = fmt.Errorf("aggregation cannot be recorded")
+
+// New returns a Recorder that retains the last size collections
+// exported to exp.  It panics if size is not positive.
+func New(exp export.Exporter, size int) *Recorder {
+	if size <= 0 {
+		panic("flightrecorder: size must be positive")
+	}
+	return &Recorder{
+		Exporter:  exp,
+		snapshots: make([]Snapshot, size),
+	}
+}
+
+// NewInMemory returns a Recorder that retains the last size collections
+// without exporting them anywhere, computing them with the temporality
+// chosen by temporality.  This is a minimal reader for environments that
+// have no exporter, such as WebAssembly plugins that hand their metrics
+// to the host.  It panics if size is not positive.
+func NewInMemory(temporality aggregation.TemporalitySelector, size int) *Recorder {
+	return New(discardExporter{temporality}, size)
+}
+
+// discardExporter is an Exporter that exports nothing.
+type discardExporter struct {
+	aggregation.TemporalitySelector
+}
+
+func (discardExporter) Export(context.Context, *resource.Resource, export.InstrumentationLibraryReader) error {
+	return nil
+}
+
+// Export implements export.Exporter.  The collection is copied before
+// it is passed to the wrapped Exporter, so that it is retained even
+// when the export fails part way.  Records that cannot be copied are
+// reported to the global error handler and left out of the Snapshot;
+// the wrapped Exporter is called regardless.
+func (r *Recorder) Export(ctx context.Context, res *resource.Resource, ilr export.InstrumentationLibraryReader) error {
+	snap := Snapshot{
+		Time:     time.Now(),
+		Resource: res.Attributes(),
+	}
+	if err := ilr.ForEach(func(lib instrumentation.Library, reader export.Reader) error {
+		return reader.ForEach(r.Exporter, func(rec export.Record) error {
+			point, err := newPoint(lib, rec)
+			if errors.Is(err, aggregation.ErrNoData) {
+				return nil
+			} else if errors.Is(err, ErrUnsupportedAggregation) {
+				otel.Handle(fmt.Errorf("%w: %s of %s", err, rec.Aggregation().Kind(), rec.Descriptor().Name()))
+				return nil
+			} else if err != nil {
+				otel.Handle(fmt.Errorf("flightrecorder: cannot record %s: %w", rec.Descriptor().Name(), err))
+				return nil
+			}
+			snap.Points = append(snap.Points, point)
+			return nil
+		})
+	}); err != nil {
+		otel.Handle(fmt.Errorf("flightrecorder: %w", err))
+	}
+	err := r.Exporter.Export(ctx, res, ilr)
+	if err != nil {
+		snap.Error = err.Error()
+	}
+	r.add(snap)
+	return err
+}
+
+func newPoint(lib instrumentation.Library, rec export.Record) (Point, error) {
+	kind := rec.Descriptor().NumberKind()
+	agg := rec.Aggregation()
+	switch agg.(type) {
+	case aggregation.LastValue, aggregation.Sum, aggregation.Count:
+	default:
+		return Point{}, ErrUnsupportedAggregation
+	}
+	point := Point{
+		Library:     lib,
+		Name:        rec.Descriptor().Name(),
+		Attributes:  rec.Attributes().ToSlice(),
+		Aggregation: agg.Kind(),
+		StartTime:   rec.StartTime(),
+		EndTime:     rec.EndTime(),
+	}
+
+	switch a := agg.(type) {
+	case aggregation.LastValue:
+		value, _, err := a.LastValue()
+		if err != nil {
+			return Point{}, err
+		}
+		point.Value = value.CoerceToFloat64(kind)
+	case aggregation.Sum:
+		sum, err := a.Sum()
+		if err != nil {
+			return Point{}, err
+		}
+		point.Value = sum.CoerceToFloat64(kind)
+	}
+	if c, ok := agg.(aggregation.Count); ok {
+		count, err := c.Count()
+		if err != nil {
+			return Point{}, err
+		}
+		point.Count = count
+	}
+	if m, ok := agg.(aggregation.Min); ok {
+		n, err := m.Min()
+		if err != nil {
+			return Point{}, err
+		}
+		value := n.CoerceToFloat64(kind)
+		point.Min = &value
+	}
+	if m, ok := agg.(aggregation.Max); ok {
+		n, err := m.Max()
+		if err != nil {
+			return Point{}, err
+		}
+		value := n.CoerceToFloat64(kind)
+		point.Max = &value
+	}
+	if h, ok := agg.(aggregation.Histogram); ok {
+		buckets, err := h.Histogram()
+		if err != nil {
+			return Point{}, err
+		}
+		// The aggregator state is reused by the next collection.
+		point.Boundaries = append([]float64(nil), buckets.Boundaries...)
+		point.Counts = append([]uint64(nil), buckets.Counts...)
+	}
+	if s, ok := agg.(aggregation.Summary); ok {
+		quantiles, err := s.Quantiles()
+		if err != nil {
+			return Point{}, err
+		}
+		point.Quantiles = append([]float64(nil), quantiles...)
+		for _, q := range quantiles {
+			v, err := s.Quantile(q)
+			if err != nil {
+				return Point{}, err
+			}
+			point.QuantileValues = append(point.QuantileValues, v)
+		}
+	}
+	if e, ok := agg.(aggregation.ExponentialHistogram); ok {
+		if err := exponentialPoint(&point, e); err != nil {
+			return Point{}, err
+		}
+	}
+	return point, nil
+}
+
+// exponentialPoint copies the buckets of e into point.
+func exponentialPoint(point *Point, e aggregation.ExponentialHistogram) error {
+	var err error
+	if point.Scale, err = e.Scale(); err != nil {
+		return err
+	}
+	if point.ZeroCount, err = e.ZeroCount(); err != nil {
+		return err
+	}
+	positive, err := e.Positive()
+	if err != nil {
+		return err
+	}
+	negative, err := e.Negative()
+	if err != nil {
+		return err
+	}
+	point.Positive, point.Negative = copyBuckets(positive), copyBuckets(negative)
+	return nil
+}
+
+// copyBuckets returns a copy of b, whose counts are reused by the next
+// collection.
+func copyBuckets(b aggregation.ExponentialBuckets) *ExponentialBuckets {
+	c := &ExponentialBuckets{Offset: b.Offset()}
+	for i := uint32(0); i < b.Len(); i++ {
+		c.Counts = append(c.Counts, b.At(i))
+	}
+	return c
+}
+
+func (r *Recorder) add(snap Snapshot) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.snapshots[r.next] = snap
+	r.next++
+	if r.next == len(r.snapshots) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// Snapshots returns the retained collections, oldest first.
+func (r *Recorder) Snapshots() []Snapshot {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.full {
+		return append([]Snapshot(nil), r.snapshots[:r.next]...)
+	}
+	out := make([]Snapshot, 0, len(r.snapshots))
+	out = append(out, r.snapshots[r.next:]...)
+	return append(out, r.snapshots[:r.next]...)
+}
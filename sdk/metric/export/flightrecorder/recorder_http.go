@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !tinygo
+// +build !tinygo
+
+package flightrecorder // import "go.opentelemetry.io/otel/sdk/metric/export/flightrecorder"
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+var _ http.Handler = &Recorder{}
+
+// ServeHTTP implements http.Handler, responding with the retained
+// collections encoded as a JSON array, oldest first.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r.Snapshots()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
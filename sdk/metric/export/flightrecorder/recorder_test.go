@@ -0,0 +1,341 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flightrecorder_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/tdigest"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/export/flightrecorder"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+var (
+	library  = instrumentation.Library{Name: "test"}
+	counter  = metrictest.NewDescriptor("counter.sum", sdkapi.CounterInstrumentKind, number.Int64Kind)
+	duration = metrictest.NewDescriptor("duration.histogram", sdkapi.HistogramInstrumentKind, number.Float64Kind)
+)
+
+// handler collects the errors reported to the global error handler.
+type handler struct {
+	sync.Mutex
+	errs []error
+}
+
+func (h *handler) Handle(err error) {
+	h.Lock()
+	h.errs = append(h.errs, err)
+	h.Unlock()
+}
+
+func (h *handler) Flush() []error {
+	h.Lock()
+	errs := h.errs
+	h.errs = nil
+	h.Unlock()
+	return errs
+}
+
+var testHandler *handler
+
+func init() {
+	testHandler = new(handler)
+	otel.SetErrorHandler(testHandler)
+}
+
+func collection(t *testing.T, value int64) export.InstrumentationLibraryReader {
+	ctx := context.Background()
+	now := time.Now()
+
+	s := &sum.New(1)[0]
+	require.NoError(t, s.Update(ctx, number.NewInt64Number(value), &counter))
+	attrs := attribute.NewSet(attribute.String("A", "B"))
+
+	h := &histogram.New(1, &duration)[0]
+	require.NoError(t, h.Update(ctx, number.NewFloat64Number(5), &duration))
+
+	return processorTest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		library: {
+			export.NewRecord(&counter, &attrs, s.Aggregation(), now, now),
+			export.NewRecord(&duration, attribute.EmptySet(), h.Aggregation(), now, now),
+		},
+	})
+}
+
+func TestRecorderRetainsLastCollections(t *testing.T) {
+	ctx := context.Background()
+	inner := processorTest.New(aggregation.CumulativeTemporalitySelector(), attribute.DefaultEncoder())
+	rec := flightrecorder.New(inner, 2)
+	res := resource.NewSchemaless(attribute.String("R", "V"))
+
+	require.Len(t, rec.Snapshots(), 0)
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(t, rec.Export(ctx, res, collection(t, i)))
+	}
+	require.Equal(t, 3, inner.ExportCount())
+
+	snaps := rec.Snapshots()
+	require.Len(t, snaps, 2)
+	for i, snap := range snaps {
+		require.Empty(t, snap.Error)
+		require.Equal(t, res.Attributes(), snap.Resource)
+		require.Len(t, snap.Points, 2)
+
+		var sumPoint, histPoint flightrecorder.Point
+		for _, p := range snap.Points {
+			switch p.Name {
+			case "counter.sum":
+				sumPoint = p
+			case "duration.histogram":
+				histPoint = p
+			}
+		}
+		require.Equal(t, library, sumPoint.Library)
+		require.Equal(t, aggregation.SumKind, sumPoint.Aggregation)
+		require.Equal(t, float64(i+2), sumPoint.Value)
+		require.Equal(t, []attribute.KeyValue{attribute.String("A", "B")}, sumPoint.Attributes)
+
+		require.Equal(t, aggregation.HistogramKind, histPoint.Aggregation)
+		require.Equal(t, 5.0, histPoint.Value)
+		require.Equal(t, uint64(1), histPoint.Count)
+		require.Len(t, histPoint.Counts, len(histPoint.Boundaries)+1)
+		require.Equal(t, uint64(1), histPoint.Counts[10])
+	}
+}
+
+func TestRecorderRetainsFailedExports(t *testing.T) {
+	errExport := fmt.Errorf("backend unavailable")
+	inner := processorTest.New(aggregation.CumulativeTemporalitySelector(), attribute.DefaultEncoder())
+	inner.InjectErr = func(export.Record) error { return errExport }
+	rec := flightrecorder.New(inner, 4)
+
+	require.ErrorIs(t, rec.Export(context.Background(), resource.Empty(), collection(t, 7)), errExport)
+
+	snaps := rec.Snapshots()
+	require.Len(t, snaps, 1)
+	require.Equal(t, errExport.Error(), snaps[0].Error)
+	require.Len(t, snaps[0].Points, 2)
+}
+
+func TestRecorderServeHTTP(t *testing.T) {
+	inner := processorTest.New(aggregation.CumulativeTemporalitySelector(), attribute.DefaultEncoder())
+	rec := flightrecorder.New(inner, 4)
+	require.NoError(t, rec.Export(context.Background(), resource.Empty(), collection(t, 7)))
+
+	w := httptest.NewRecorder()
+	rec.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &decoded))
+	require.Len(t, decoded, 1)
+	require.Len(t, decoded[0]["points"], 2)
+}
+
+func TestRecorderPanicsOnInvalidSize(t *testing.T) {
+	require.Panics(t, func() {
+		flightrecorder.New(nil, 0)
+	})
+}
+
+func TestRecorderInMemory(t *testing.T) {
+	ctx := context.Background()
+	rec := flightrecorder.NewInMemory(aggregation.DeltaTemporalitySelector(), 1)
+	require.Equal(t, aggregation.DeltaTemporality, rec.TemporalityFor(&counter, aggregation.SumKind))
+
+	require.NoError(t, rec.Export(ctx, resource.Empty(), collection(t, 1)))
+	require.NoError(t, rec.Export(ctx, resource.Empty(), collection(t, 2)))
+
+	snaps := rec.Snapshots()
+	require.Len(t, snaps, 1)
+	require.Empty(t, snaps[0].Error)
+	require.Len(t, snaps[0].Points, 2)
+}
+
+// unsupportedAggregation is an Aggregation that a Point cannot represent.
+type unsupportedAggregation struct{}
+
+func (unsupportedAggregation) Kind() aggregation.Kind { return "Unsupported" }
+
+func TestRecorderReportsUnsupportedAggregations(t *testing.T) {
+	testHandler.Flush()
+	now := time.Now()
+	reader := processorTest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		library: {
+			export.NewRecord(&duration, attribute.EmptySet(), unsupportedAggregation{}, now, now),
+		},
+	})
+	rec := flightrecorder.NewInMemory(aggregation.DeltaTemporalitySelector(), 1)
+	require.NoError(t, rec.Export(context.Background(), resource.Empty(), reader))
+
+	require.Empty(t, rec.Snapshots()[0].Points)
+	handled := testHandler.Flush()
+	require.Len(t, handled, 1)
+	require.ErrorIs(t, handled[0], flightrecorder.ErrUnsupportedAggregation)
+}
+
+// failingSum is a Sum whose value cannot be read.
+type failingSum struct{}
+
+var errFailingSum = fmt.Errorf("sum unavailable")
+
+func (failingSum) Kind() aggregation.Kind { return aggregation.SumKind }
+
+func (failingSum) Sum() (number.Number, error) { return 0, errFailingSum }
+
+// countingExporter counts the collections exported to it.
+type countingExporter struct {
+	aggregation.TemporalitySelector
+	exports int
+}
+
+func (e *countingExporter) Export(context.Context, *resource.Resource, export.InstrumentationLibraryReader) error {
+	e.exports++
+	return nil
+}
+
+func TestRecorderExportsRecordsItCannotCopy(t *testing.T) {
+	testHandler.Flush()
+	now := time.Now()
+	reader := processorTest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		library: {
+			export.NewRecord(&counter, attribute.EmptySet(), failingSum{}, now, now),
+		},
+	})
+	inner := &countingExporter{TemporalitySelector: aggregation.DeltaTemporalitySelector()}
+	rec := flightrecorder.New(inner, 1)
+	require.NoError(t, rec.Export(context.Background(), resource.Empty(), reader))
+
+	require.Equal(t, 1, inner.exports)
+	snap := rec.Snapshots()[0]
+	require.Empty(t, snap.Points)
+	require.Empty(t, snap.Error)
+	handled := testHandler.Flush()
+	require.Len(t, handled, 1)
+	require.ErrorIs(t, handled[0], errFailingSum)
+}
+
+// recordPoint returns the Point recorded for agg, which is reset after it
+// is exported.
+func recordPoint(t *testing.T, agg aggregator.Aggregator, desc *sdkapi.Descriptor) flightrecorder.Point {
+	now := time.Now()
+	reader := processorTest.MultiInstrumentationLibraryReader(map[instrumentation.Library][]export.Record{
+		library: {export.NewRecord(desc, attribute.EmptySet(), agg.Aggregation(), now, now)},
+	})
+	rec := flightrecorder.NewInMemory(aggregation.DeltaTemporalitySelector(), 1)
+	require.NoError(t, rec.Export(context.Background(), resource.Empty(), reader))
+	require.NoError(t, agg.SynchronizedMove(nil, desc))
+
+	points := rec.Snapshots()[0].Points
+	require.Len(t, points, 1)
+	return points[0]
+}
+
+func TestRecorderExponentialHistogram(t *testing.T) {
+	agg := &exponential.New(1, &duration, exponential.WithMaxScale(0))[0]
+	for _, v := range []float64{0, 1, 3, -2} {
+		require.NoError(t, agg.Update(context.Background(), number.NewFloat64Number(v), &duration))
+	}
+
+	point := recordPoint(t, agg, &duration)
+	require.Equal(t, aggregation.ExponentialHistogramKind, point.Aggregation)
+	require.Equal(t, 2.0, point.Value)
+	require.Equal(t, uint64(4), point.Count)
+	require.Equal(t, int32(0), point.Scale)
+	require.Equal(t, uint64(1), point.ZeroCount)
+	require.Equal(t, &flightrecorder.ExponentialBuckets{Offset: 0, Counts: []uint64{1, 1}}, point.Positive)
+	require.Equal(t, &flightrecorder.ExponentialBuckets{Offset: 1, Counts: []uint64{1}}, point.Negative)
+}
+
+func TestRecorderMinMaxSumCount(t *testing.T) {
+	agg := &minmaxsumcount.New(1)[0]
+	for _, v := range []float64{2, -1, 4} {
+		require.NoError(t, agg.Update(context.Background(), number.NewFloat64Number(v), &duration))
+	}
+
+	point := recordPoint(t, agg, &duration)
+	require.Equal(t, aggregation.MinMaxSumCountKind, point.Aggregation)
+	require.Equal(t, 5.0, point.Value)
+	require.Equal(t, uint64(3), point.Count)
+	require.NotNil(t, point.Min)
+	require.Equal(t, -1.0, *point.Min)
+	require.NotNil(t, point.Max)
+	require.Equal(t, 4.0, *point.Max)
+}
+
+func TestRecorderTDigest(t *testing.T) {
+	agg := &tdigest.New(1, &duration, tdigest.WithQuantiles(0.5, 0.75))[0]
+	for _, v := range []float64{4, 1, 3, 2} {
+		require.NoError(t, agg.Update(context.Background(), number.NewFloat64Number(v), &duration))
+	}
+	median, err := agg.Quantile(0.5)
+	require.NoError(t, err)
+	p75, err := agg.Quantile(0.75)
+	require.NoError(t, err)
+
+	point := recordPoint(t, agg, &duration)
+	require.Equal(t, aggregation.TDigestKind, point.Aggregation)
+	require.Equal(t, 10.0, point.Value)
+	require.Equal(t, uint64(4), point.Count)
+	require.Equal(t, 1.0, *point.Min)
+	require.Equal(t, 4.0, *point.Max)
+	require.Equal(t, []float64{0.5, 0.75}, point.Quantiles)
+	require.Equal(t, []float64{median, p75}, point.QuantileValues)
+}
+
+func TestRecorderDDSketch(t *testing.T) {
+	agg := &ddsketch.New(1, &duration, ddsketch.WithQuantiles(0.5, 0.9))[0]
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		require.NoError(t, agg.Update(context.Background(), number.NewFloat64Number(v), &duration))
+	}
+	median, err := agg.Quantile(0.5)
+	require.NoError(t, err)
+	p90, err := agg.Quantile(0.9)
+	require.NoError(t, err)
+
+	point := recordPoint(t, agg, &duration)
+	require.Equal(t, aggregation.DDSketchKind, point.Aggregation)
+	require.Equal(t, 15.0, point.Value)
+	require.Equal(t, uint64(5), point.Count)
+	require.Equal(t, 1.0, *point.Min)
+	require.Equal(t, 5.0, *point.Max)
+	require.Equal(t, []float64{0.5, 0.9}, point.Quantiles)
+	require.Equal(t, []float64{median, p90}, point.QuantileValues)
+}
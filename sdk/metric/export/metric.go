@@ -96,6 +96,31 @@ type AggregatorSelector interface {
 	AggregatorFor(descriptor *sdkapi.Descriptor, aggregator ...*aggregator.Aggregator)
 }
 
+// AttributeAggregatorSelector is an optional interface implemented by
+// Processors that select the kind of Aggregator for each attribute set
+// of an instrument rather than once per instrument.  When the Processor
+// passed to the SDK implements it, AggregatorForAttributes is called in
+// place of AggregatorFor whenever a new record requires an Aggregator.
+type AttributeAggregatorSelector interface {
+	// AggregatorForAttributes is AggregatorFor for the record of
+	// the instrument with the given attribute set.  The same type
+	// must be returned for equal attribute sets of one instrument.
+	AggregatorForAttributes(descriptor *sdkapi.Descriptor, attrs *attribute.Set, aggregator ...*aggregator.Aggregator)
+}
+
+// VersionedAggregatorSelector is an optional interface implemented by
+// Processors whose choice of Aggregators may change at runtime.  When
+// the Processor passed to the SDK implements it, the SDK replaces the
+// records of its instruments at the first collection after the version
+// changes, once they are checkpointed, so that later measurements are
+// aggregated by newly selected Aggregators.
+type VersionedAggregatorSelector interface {
+	// SelectionVersion returns a number that changes whenever
+	// AggregatorFor may select different Aggregators than for
+	// the previous version.
+	SelectionVersion() uint64
+}
+
 // Checkpointer is the interface used by a Controller to coordinate
 // the Processor with Accumulator(s) and Exporter(s).  The
 // StartCollection() and FinishCollection() methods start and finish a
@@ -129,6 +154,27 @@ type CheckpointerFactory interface {
 	NewCheckpointer() Checkpointer
 }
 
+// LibraryCheckpointerFactory is a CheckpointerFactory whose
+// Checkpointers depend on the instrumentation library they serve, for
+// example to apply views to the instruments of some libraries only.
+type LibraryCheckpointerFactory interface {
+	CheckpointerFactory
+
+	// NewLibraryCheckpointer returns a Checkpointer for the
+	// instruments of library.
+	NewLibraryCheckpointer(library instrumentation.Library) Checkpointer
+}
+
+// NewLibraryCheckpointer returns a Checkpointer of f for the instruments
+// of library, using NewLibraryCheckpointer when f is a
+// LibraryCheckpointerFactory.
+func NewLibraryCheckpointer(f CheckpointerFactory, library instrumentation.Library) Checkpointer {
+	if lf, ok := f.(LibraryCheckpointerFactory); ok {
+		return lf.NewLibraryCheckpointer(library)
+	}
+	return f.NewCheckpointer()
+}
+
 // Exporter handles presentation of the checkpoint of aggregate
 // metrics.  This is the final stage of a metrics export pipeline,
 // where metric data are formatted for a specific system.
@@ -158,6 +204,57 @@ type InstrumentationLibraryReader interface {
 	ForEach(readerFunc func(instrumentation.Library, Reader) error) error
 }
 
+// SequencedReader is an optional interface implemented by
+// InstrumentationLibraryReaders whose checkpoints are numbered, so that
+// exporters and the systems they export to can detect the collection
+// intervals of a process that were dropped or exported twice.
+type SequencedReader interface {
+	InstrumentationLibraryReader
+
+	// Sequence returns the number of the collection that computed
+	// the checkpoint, which starts at one and increases by one with
+	// every collection of the Controller, and true; or false when
+	// the checkpoint is not numbered, as for readers wrapping
+	// readers that are not.
+	Sequence() (uint64, bool)
+}
+
+// Sequence returns the collection sequence number of the checkpoint of
+// reader and true, or false when reader does not implement
+// SequencedReader or its checkpoint is not numbered.
+func Sequence(reader InstrumentationLibraryReader) (uint64, bool) {
+	if sr, ok := reader.(SequencedReader); ok {
+		return sr.Sequence()
+	}
+	return 0, false
+}
+
+// Metric is a Record of an instrumentation library, as visited by
+// ForEachMetric.
+type Metric struct {
+	Library instrumentation.Library
+	Record
+}
+
+// ForEachMetric calls fn with every Record of every instrumentation
+// library of reader, aggregated with the temporality chosen by
+// tempSelector.  Records are visited as the Readers produce them, none is
+// retained, so that an exporter can encode and send metrics as it visits
+// them with constant memory however many series there are.  The
+// Aggregation of a Record refers to the state of its Processor and is
+// only valid during the call of fn.  An error returned by fn stops the
+// iteration and is returned.
+func ForEachMetric(reader InstrumentationLibraryReader, tempSelector aggregation.TemporalitySelector, fn func(Metric) error) error {
+	return reader.ForEach(func(library instrumentation.Library, r Reader) error {
+		return r.ForEach(tempSelector, func(rec Record) error {
+			return fn(Metric{
+				Library: library,
+				Record:  rec,
+			})
+		})
+	})
+}
+
 // Reader allows a controller to access a complete checkpoint of
 // aggregated metrics from the Processor for a single library of
 // metric data.  This is passed to the Exporter which may then use
@@ -215,6 +312,7 @@ type Record struct {
 	aggregation aggregation.Aggregation
 	start       time.Time
 	end         time.Time
+	stale       bool
 }
 
 // Descriptor describes the metric instrument being exported.
@@ -263,6 +361,16 @@ func NewRecord(descriptor *sdkapi.Descriptor, attrs *attribute.Set, aggregation
 	}
 }
 
+// NewStaleRecord constructs an export record like NewRecord, for an
+// instrument and attribute set that were not updated in the latest
+// collection, whose aggregation a Processor with memory kept from an
+// earlier collection.
+func NewStaleRecord(descriptor *sdkapi.Descriptor, attrs *attribute.Set, aggregation aggregation.Aggregation, start, end time.Time) Record {
+	r := NewRecord(descriptor, attrs, aggregation, start, end)
+	r.stale = true
+	return r
+}
+
 // Aggregation returns the aggregation, an interface to the record and
 // its aggregator, dependent on the kind of both the input and exporter.
 func (r Record) Aggregation() aggregation.Aggregation {
@@ -278,3 +386,11 @@ func (r Record) StartTime() time.Time {
 func (r Record) EndTime() time.Time {
 	return r.end
 }
+
+// Stale returns whether the instrument and attribute set of the record
+// were not updated in the latest collection.  The aggregation of a stale
+// record is kept from an earlier collection, as the last value of an
+// asynchronous gauge that was not observed.
+func (r Record) Stale() bool {
+	return r.stale
+}
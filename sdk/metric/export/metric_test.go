@@ -15,11 +15,18 @@
 package export
 
 import (
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
 )
 
 var testSlice = []attribute.KeyValue{
@@ -69,3 +76,55 @@ func TestIteratorToSlice(t *testing.T) {
 	got = iter.ToSlice()
 	require.Nil(t, got)
 }
+
+type testReader struct {
+	sync.RWMutex
+	records []Record
+}
+
+func (r *testReader) ForEach(_ aggregation.TemporalitySelector, fn func(Record) error) error {
+	for _, rec := range r.records {
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type testLibraryReader map[string]*testReader
+
+func (l testLibraryReader) ForEach(fn func(instrumentation.Library, Reader) error) error {
+	for _, name := range []string{"a", "b"} {
+		if err := fn(instrumentation.Library{Name: name}, l[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestRecord(name string) Record {
+	desc := sdkapi.NewDescriptor(name, sdkapi.CounterInstrumentKind, number.Int64Kind, "", "")
+	return NewRecord(&desc, nil, nil, time.Time{}, time.Time{})
+}
+
+func TestForEachMetric(t *testing.T) {
+	reader := testLibraryReader{
+		"a": {records: []Record{newTestRecord("a.1"), newTestRecord("a.2")}},
+		"b": {records: []Record{newTestRecord("b.1")}},
+	}
+
+	var visited []string
+	require.NoError(t, ForEachMetric(reader, aggregation.CumulativeTemporalitySelector(), func(m Metric) error {
+		visited = append(visited, m.Library.Name+":"+m.Descriptor().Name())
+		return nil
+	}))
+	require.Equal(t, []string{"a:a.1", "a:a.2", "b:b.1"}, visited)
+
+	stop := errors.New("stop")
+	visited = nil
+	require.ErrorIs(t, ForEachMetric(reader, aggregation.CumulativeTemporalitySelector(), func(m Metric) error {
+		visited = append(visited, m.Descriptor().Name())
+		return stop
+	}), stop)
+	require.Equal(t, []string{"a.1"}, visited)
+}
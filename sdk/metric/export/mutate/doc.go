@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package mutate implements an Exporter wrapper that modifies the
+collected records before the wrapped Exporter sees them.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+A chain of Mutators is applied, in order, to every record presented to
+the wrapped Exporter.  A Mutator may replace the record, for example
+to rename the instrument or to scrub attributes that must not leave
+the process, or drop it altogether.  For example, to redact user
+e-mail addresses and rename a metric before export:
+
+	exporter := mutate.NewExporter(
+	        otlpExporter,
+	        mutate.RedactAttributes("REDACTED", "user.email"),
+	        mutate.Rename(map[string]string{
+	                "http.server.duration": "http.server.request.duration",
+	        }),
+	)
+
+The Scrubber Mutator redacts sensitive data found inside string
+attribute values, such as e-mail addresses, IP addresses and payment
+card numbers, using configurable Matchers.  The number of values it
+redacted can be reported as a metric with RegisterMetrics.
+
+Mutators that also implement ResourceMutator are given the Resource of
+the export.  CopyResourceAttributes uses this to copy selected Resource
+attributes, such as "k8s.pod.name", into the attributes of the records
+of selected instruments, for backends that cannot join on Resource
+attributes.
+
+Mutators see each record independently.  Records that become identical
+after mutation, for example because the only attribute distinguishing
+them was removed, are not merged and reach the Exporter as separate
+records.  To reduce the attributes of a metric before aggregation use
+the reducer Processor instead.
+*/
+package mutate // import "go.opentelemetry.io/otel/sdk/metric/export/mutate"
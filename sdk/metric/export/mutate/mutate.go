@@ -0,0 +1,322 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutate // import "go.opentelemetry.io/otel/sdk/metric/export/mutate"
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+type (
+	// Mutator modifies the records of a collection before they are
+	// exported.
+	Mutator interface {
+		// Mutate returns the record to export in place of rec.
+		// When the second return value is false the record is
+		// dropped and later Mutators are not called for it.
+		//
+		// Mutate may be called concurrently by exports that
+		// run in parallel.
+		Mutate(lib instrumentation.Library, rec export.Record) (export.Record, bool)
+	}
+
+	// ResourceMutator is implemented by Mutators that depend on the
+	// Resource of the export.  The Exporter calls MutateWithResource
+	// in place of Mutate for them.
+	ResourceMutator interface {
+		Mutator
+
+		// MutateWithResource is Mutate for a record exported
+		// with the given Resource.
+		MutateWithResource(res *resource.Resource, lib instrumentation.Library, rec export.Record) (export.Record, bool)
+	}
+
+	// MutatorFunc is a function that implements Mutator.
+	MutatorFunc func(lib instrumentation.Library, rec export.Record) (export.Record, bool)
+
+	// Exporter applies a chain of Mutators to the records of each
+	// collection and passes the result to the wrapped Exporter.
+	Exporter struct {
+		export.Exporter
+		mutators []Mutator
+	}
+
+	libraryReader struct {
+		export.InstrumentationLibraryReader
+		resource *resource.Resource
+		mutators []Mutator
+	}
+
+	reader struct {
+		export.Reader
+		resource *resource.Resource
+		library  instrumentation.Library
+		mutators []Mutator
+	}
+
+	renamer struct {
+		names map[string]string
+
+		lock        sync.Mutex
+		descriptors map[*sdkapi.Descriptor]*sdkapi.Descriptor
+	}
+)
+
+var _ export.Exporter = &Exporter{}
+var _ export.SequencedReader = libraryReader{}
+var _ export.Reader = reader{}
+var _ Mutator = MutatorFunc(nil)
+var _ ResourceMutator = &resourceCopier{}
+
+// NewExporter returns an Exporter that applies the mutators, in order,
+// to every record before passing it to exp.  The temporality of exp is
+// used unchanged.
+func NewExporter(exp export.Exporter, mutators ...Mutator) *Exporter {
+	return &Exporter{
+		Exporter: exp,
+		mutators: mutators,
+	}
+}
+
+// Export implements export.Exporter.
+func (e *Exporter) Export(ctx context.Context, res *resource.Resource, ilr export.InstrumentationLibraryReader) error {
+	if len(e.mutators) == 0 {
+		return e.Exporter.Export(ctx, res, ilr)
+	}
+	return e.Exporter.Export(ctx, res, libraryReader{
+		InstrumentationLibraryReader: ilr,
+		resource:                     res,
+		mutators:                     e.mutators,
+	})
+}
+
+// Sequence implements export.SequencedReader.
+func (l libraryReader) Sequence() (uint64, bool) {
+	return export.Sequence(l.InstrumentationLibraryReader)
+}
+
+// ForEach implements export.InstrumentationLibraryReader.
+func (l libraryReader) ForEach(readerFunc func(instrumentation.Library, export.Reader) error) error {
+	return l.InstrumentationLibraryReader.ForEach(func(lib instrumentation.Library, r export.Reader) error {
+		return readerFunc(lib, reader{
+			Reader:   r,
+			resource: l.resource,
+			library:  lib,
+			mutators: l.mutators,
+		})
+	})
+}
+
+// ForEach implements export.Reader.
+func (r reader) ForEach(tempSelector aggregation.TemporalitySelector, recordFunc func(export.Record) error) error {
+	return r.Reader.ForEach(tempSelector, func(rec export.Record) error {
+		for _, m := range r.mutators {
+			var ok bool
+			if rm, isResource := m.(ResourceMutator); isResource {
+				rec, ok = rm.MutateWithResource(r.resource, r.library, rec)
+			} else {
+				rec, ok = m.Mutate(r.library, rec)
+			}
+			if !ok {
+				return nil
+			}
+		}
+		return recordFunc(rec)
+	})
+}
+
+// Mutate implements Mutator.
+func (f MutatorFunc) Mutate(lib instrumentation.Library, rec export.Record) (export.Record, bool) {
+	return f(lib, rec)
+}
+
+// Rename returns a Mutator that renames the instruments whose names are
+// keys of names to the corresponding value.  Other records are passed
+// unchanged.
+func Rename(names map[string]string) Mutator {
+	copied := make(map[string]string, len(names))
+	for from, to := range names {
+		copied[from] = to
+	}
+	return &renamer{
+		names:       copied,
+		descriptors: map[*sdkapi.Descriptor]*sdkapi.Descriptor{},
+	}
+}
+
+func (r *renamer) Mutate(_ instrumentation.Library, rec export.Record) (export.Record, bool) {
+	desc := r.descriptorFor(rec.Descriptor())
+	if desc == rec.Descriptor() {
+		return rec, true
+	}
+	return newRecord(rec, desc, rec.Attributes()), true
+}
+
+// descriptorFor returns the descriptor to export in place of desc.  The
+// renamed descriptors are cached so that every record of an instrument
+// refers to the same one.
+func (r *renamer) descriptorFor(desc *sdkapi.Descriptor) *sdkapi.Descriptor {
+	name, ok := r.names[desc.Name()]
+	if !ok {
+		return desc
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if renamed, ok := r.descriptors[desc]; ok {
+		return renamed
+	}
+	renamed := sdkapi.NewDescriptorWithAttributeKeys(
+		name,
+		desc.InstrumentKind(),
+		desc.NumberKind(),
+		desc.Description(),
+		desc.Unit(),
+		desc.AttributeKeys(),
+	)
+	r.descriptors[desc] = &renamed
+	return &renamed
+}
+
+// FilterAttributes returns a Mutator that removes the attributes for
+// which filter returns false from every record.
+func FilterAttributes(filter attribute.Filter) Mutator {
+	return MutatorFunc(func(_ instrumentation.Library, rec export.Record) (export.Record, bool) {
+		filtered, dropped := rec.Attributes().Filter(filter)
+		if len(dropped) == 0 {
+			return rec, true
+		}
+		return withAttributes(rec, &filtered), true
+	})
+}
+
+// DropAttributes returns a Mutator that removes the attributes with the
+// given keys from every record.
+func DropAttributes(keys ...attribute.Key) Mutator {
+	drop := keySet(keys)
+	return FilterAttributes(func(kv attribute.KeyValue) bool {
+		_, ok := drop[kv.Key]
+		return !ok
+	})
+}
+
+// RedactAttributes returns a Mutator that replaces the value of the
+// attributes with the given keys by the string replacement, keeping
+// the keys so that the presence of the attribute is still visible.
+func RedactAttributes(replacement string, keys ...attribute.Key) Mutator {
+	redact := keySet(keys)
+	return MutatorFunc(func(_ instrumentation.Library, rec export.Record) (export.Record, bool) {
+		attrs := rec.Attributes()
+		found := false
+		kvs := make([]attribute.KeyValue, 0, attrs.Len())
+		for iter := attrs.Iter(); iter.Next(); {
+			kv := iter.Attribute()
+			if _, ok := redact[kv.Key]; ok {
+				kv = kv.Key.String(replacement)
+				found = true
+			}
+			kvs = append(kvs, kv)
+		}
+		if !found {
+			return rec, true
+		}
+		set := attribute.NewSet(kvs...)
+		return withAttributes(rec, &set), true
+	})
+}
+
+// CopyResourceAttributes returns a Mutator that adds the Resource
+// attributes with the given keys to the attributes of the records of the
+// named instruments, or of all records when no instrument is named.  This
+// supports backends that cannot query the dimensions of a Resource.  An
+// attribute of the record takes precedence over a Resource attribute
+// with the same key.
+func CopyResourceAttributes(keys []attribute.Key, instruments ...string) ResourceMutator {
+	c := &resourceCopier{keys: keys}
+	if len(instruments) != 0 {
+		c.instruments = make(map[string]struct{}, len(instruments))
+		for _, name := range instruments {
+			c.instruments[name] = struct{}{}
+		}
+	}
+	return c
+}
+
+type resourceCopier struct {
+	keys        []attribute.Key
+	instruments map[string]struct{}
+}
+
+// Mutate passes the record unchanged, since no Resource is known.
+func (c *resourceCopier) Mutate(_ instrumentation.Library, rec export.Record) (export.Record, bool) {
+	return rec, true
+}
+
+func (c *resourceCopier) MutateWithResource(res *resource.Resource, _ instrumentation.Library, rec export.Record) (export.Record, bool) {
+	if c.instruments != nil {
+		if _, ok := c.instruments[rec.Descriptor().Name()]; !ok {
+			return rec, true
+		}
+	}
+	attrs := rec.Attributes()
+	var kvs []attribute.KeyValue
+	for _, key := range c.keys {
+		if attrs.HasValue(key) {
+			continue
+		}
+		v, ok := res.Set().Value(key)
+		if !ok {
+			continue
+		}
+		if kvs == nil {
+			kvs = attrs.ToSlice()
+		}
+		kvs = append(kvs, attribute.KeyValue{Key: key, Value: v})
+	}
+	if kvs == nil {
+		return rec, true
+	}
+	set := attribute.NewSet(kvs...)
+	return withAttributes(rec, &set), true
+}
+
+func withAttributes(rec export.Record, attrs *attribute.Set) export.Record {
+	return newRecord(rec, rec.Descriptor(), attrs)
+}
+
+// newRecord returns a copy of rec with the descriptor desc and the
+// attributes attrs, stale when rec is.
+func newRecord(rec export.Record, desc *sdkapi.Descriptor, attrs *attribute.Set) export.Record {
+	if rec.Stale() {
+		return export.NewStaleRecord(desc, attrs, rec.Aggregation(), rec.StartTime(), rec.EndTime())
+	}
+	return export.NewRecord(desc, attrs, rec.Aggregation(), rec.StartTime(), rec.EndTime())
+}
+
+func keySet(keys []attribute.Key) map[attribute.Key]struct{} {
+	set := make(map[attribute.Key]struct{}, len(keys))
+	for _, k := range keys {
+		set[k] = struct{}{}
+	}
+	return set
+}
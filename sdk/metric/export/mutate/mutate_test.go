@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/export/mutate"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+var (
+	library   = instrumentation.Library{Name: "test"}
+	requests  = metrictest.NewDescriptor("requests.sum", sdkapi.CounterInstrumentKind, number.Int64Kind)
+	bytesSent = metrictest.NewDescriptor("bytes.sum", sdkapi.CounterInstrumentKind, number.Int64Kind)
+)
+
+func newRecord(t *testing.T, desc *sdkapi.Descriptor, value int64, attrs ...attribute.KeyValue) export.Record {
+	agg := &sum.New(1)[0]
+	require.NoError(t, agg.Update(context.Background(), number.NewInt64Number(value), desc))
+	set := attribute.NewSet(attrs...)
+	now := time.Now()
+	return export.NewRecord(desc, &set, agg.Aggregation(), now, now)
+}
+
+func exportRecords(t *testing.T, mutators []mutate.Mutator, records ...export.Record) map[string]float64 {
+	inner := processorTest.New(aggregation.CumulativeTemporalitySelector(), attribute.DefaultEncoder())
+	exp := mutate.NewExporter(inner, mutators...)
+	require.NoError(t, exp.Export(context.Background(), resource.Empty(), processorTest.MultiInstrumentationLibraryReader(
+		map[instrumentation.Library][]export.Record{
+			library: records,
+		},
+	)))
+	return inner.Values()
+}
+
+func TestNoMutators(t *testing.T) {
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/A=1/": 3,
+	}, exportRecords(t, nil, newRecord(t, &requests, 3, attribute.Int("A", 1))))
+}
+
+func TestRename(t *testing.T) {
+	require.EqualValues(t, map[string]float64{
+		"http.requests.sum/A=1/": 3,
+		"bytes.sum//":            10,
+	}, exportRecords(t,
+		[]mutate.Mutator{mutate.Rename(map[string]string{"requests.sum": "http.requests.sum"})},
+		newRecord(t, &requests, 3, attribute.Int("A", 1)),
+		newRecord(t, &bytesSent, 10),
+	))
+}
+
+func TestRenameSharesDescriptor(t *testing.T) {
+	m := mutate.Rename(map[string]string{"requests.sum": "http.requests.sum"})
+	r1, ok := m.Mutate(library, newRecord(t, &requests, 1, attribute.Int("A", 1)))
+	require.True(t, ok)
+	r2, ok := m.Mutate(library, newRecord(t, &requests, 1, attribute.Int("A", 2)))
+	require.True(t, ok)
+	require.Same(t, r1.Descriptor(), r2.Descriptor())
+	require.Equal(t, requests.InstrumentKind(), r1.Descriptor().InstrumentKind())
+	require.Equal(t, requests.NumberKind(), r1.Descriptor().NumberKind())
+}
+
+func TestDropAttributes(t *testing.T) {
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/A=1/": 3,
+		"bytes.sum/B=2/":    10,
+	}, exportRecords(t,
+		[]mutate.Mutator{mutate.DropAttributes("user.id")},
+		newRecord(t, &requests, 3, attribute.Int("A", 1), attribute.String("user.id", "alice")),
+		newRecord(t, &bytesSent, 10, attribute.Int("B", 2)),
+	))
+}
+
+func TestRedactAttributes(t *testing.T) {
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/A=1,user.email=REDACTED/": 3,
+	}, exportRecords(t,
+		[]mutate.Mutator{mutate.RedactAttributes("REDACTED", "user.email")},
+		newRecord(t, &requests, 3, attribute.Int("A", 1), attribute.String("user.email", "alice@example.com")),
+	))
+}
+
+func TestChainOrderAndDrop(t *testing.T) {
+	calls := 0
+	dropBytes := mutate.MutatorFunc(func(lib instrumentation.Library, rec export.Record) (export.Record, bool) {
+		require.Equal(t, library, lib)
+		return rec, rec.Descriptor().Name() != "bytes.sum"
+	})
+	count := mutate.MutatorFunc(func(_ instrumentation.Library, rec export.Record) (export.Record, bool) {
+		// Renamed by the previous Mutator, bytes already dropped.
+		require.Equal(t, "http.requests.sum", rec.Descriptor().Name())
+		calls++
+		return rec, true
+	})
+
+	require.EqualValues(t, map[string]float64{
+		"http.requests.sum/A=1/": 3,
+	}, exportRecords(t,
+		[]mutate.Mutator{
+			dropBytes,
+			mutate.Rename(map[string]string{"requests.sum": "http.requests.sum"}),
+			count,
+		},
+		newRecord(t, &requests, 3, attribute.Int("A", 1)),
+		newRecord(t, &bytesSent, 10),
+	))
+	require.Equal(t, 1, calls)
+}
+
+func TestTemporalityPassthrough(t *testing.T) {
+	inner := processorTest.New(aggregation.DeltaTemporalitySelector(), attribute.DefaultEncoder())
+	exp := mutate.NewExporter(inner, mutate.DropAttributes("A"))
+	require.Equal(t,
+		aggregation.DeltaTemporality,
+		exp.TemporalityFor(&requests, aggregation.SumKind),
+	)
+}
+
+func TestCopyResourceAttributes(t *testing.T) {
+	res := resource.NewSchemaless(
+		attribute.String("k8s.pod.name", "pod-1"),
+		attribute.String("k8s.namespace.name", "default"),
+		attribute.String("host.name", "node-1"),
+	)
+	inner := processorTest.New(aggregation.CumulativeTemporalitySelector(), attribute.DefaultEncoder())
+	exp := mutate.NewExporter(inner, mutate.CopyResourceAttributes(
+		[]attribute.Key{"k8s.pod.name", "k8s.namespace.name", "missing"},
+		"requests.sum",
+	))
+	require.NoError(t, exp.Export(context.Background(), res, processorTest.MultiInstrumentationLibraryReader(
+		map[instrumentation.Library][]export.Record{
+			library: {
+				newRecord(t, &requests, 3, attribute.String("k8s.namespace.name", "override")),
+				newRecord(t, &bytesSent, 10),
+			},
+		},
+	)))
+
+	const encodedRes = "host.name=node-1,k8s.namespace.name=default,k8s.pod.name=pod-1"
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/k8s.namespace.name=override,k8s.pod.name=pod-1/" + encodedRes: 3,
+		"bytes.sum//" + encodedRes: 10,
+	}, inner.Values())
+}
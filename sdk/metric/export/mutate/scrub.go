@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutate // import "go.opentelemetry.io/otel/sdk/metric/export/mutate"
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+)
+
+// ScrubbedMetricName is the name of the counter registered by
+// Scrubber.RegisterMetrics.
+const ScrubbedMetricName = "otel.sdk.metric.scrubbed_values"
+
+// matcherKey is the attribute key identifying the Matcher in the
+// metric registered by Scrubber.RegisterMetrics.
+const matcherKey = attribute.Key("matcher")
+
+type (
+	// Matcher detects sensitive data inside an attribute value.
+	Matcher interface {
+		// Name identifies the Matcher in the scrubbing report.
+		Name() string
+
+		// Redact returns value with every sensitive part
+		// replaced by replacement.  The second return value is
+		// false when value contains no sensitive data.
+		Redact(value, replacement string) (string, bool)
+	}
+
+	regexpMatcher struct {
+		name     string
+		re       *regexp.Regexp
+		validate func(string) bool
+	}
+
+	// ipMatcher redacts the IP addresses found in the candidate
+	// tokens of a value, which may carry a port, a key or
+	// punctuation around the address.
+	ipMatcher struct{}
+
+	// Scrubber is a Mutator that redacts the parts of string
+	// attribute values detected by its Matchers.  It counts the
+	// number of values each Matcher has redacted.
+	Scrubber struct {
+		replacement string
+		matchers    []Matcher
+		counts      []uint64
+	}
+)
+
+var _ Mutator = &Scrubber{}
+
+var (
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	ipPattern         = regexp.MustCompile(`[0-9A-Fa-f:.]*[.:][0-9A-Fa-f:.]*`)
+	ipv4Pattern       = regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1[0-9][0-9]|[1-9]?[0-9])\b`)
+	creditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+)
+
+// NewRegexpMatcher returns a Matcher that redacts every match of re.
+func NewRegexpMatcher(name string, re *regexp.Regexp) Matcher {
+	return regexpMatcher{name: name, re: re}
+}
+
+// EmailMatcher returns a Matcher that redacts e-mail addresses.
+func EmailMatcher() Matcher {
+	return regexpMatcher{name: "email", re: emailPattern}
+}
+
+// IPMatcher returns a Matcher that redacts IPv4 and IPv6 addresses.
+func IPMatcher() Matcher {
+	return ipMatcher{}
+}
+
+func (ipMatcher) Name() string {
+	return "ip"
+}
+
+func (ipMatcher) Redact(value, replacement string) (string, bool) {
+	found := false
+	redacted := ipPattern.ReplaceAllStringFunc(value, func(token string) string {
+		out, ok := redactIP(token, replacement)
+		found = found || ok
+		return out
+	})
+	return redacted, found
+}
+
+// redactIP replaces the IP address in token, a run of hexadecimal
+// digits, colons and dots, by replacement.  The token is an address,
+// an address surrounded by colons and dots such as "10.1.2.3." or the
+// ":fe80::1" of "addr:fe80::1", or contains IPv4 addresses followed
+// or preceded by other fields, such as "10.0.0.1:8080".
+func redactIP(token, replacement string) (string, bool) {
+	if net.ParseIP(token) != nil {
+		return replacement, true
+	}
+	if trimmed := strings.Trim(token, ":."); trimmed != token && net.ParseIP(trimmed) != nil {
+		i := strings.Index(token, trimmed)
+		return token[:i] + replacement + token[i+len(trimmed):], true
+	}
+	found := false
+	redacted := ipv4Pattern.ReplaceAllStringFunc(token, func(string) string {
+		found = true
+		return replacement
+	})
+	return redacted, found
+}
+
+// CreditCardMatcher returns a Matcher that redacts payment card
+// numbers of 13 to 19 digits, optionally grouped by spaces or dashes.
+// Only numbers passing the Luhn checksum are redacted.
+func CreditCardMatcher() Matcher {
+	return regexpMatcher{
+		name:     "credit_card",
+		re:       creditCardPattern,
+		validate: luhn,
+	}
+}
+
+func (m regexpMatcher) Name() string {
+	return m.name
+}
+
+func (m regexpMatcher) Redact(value, replacement string) (string, bool) {
+	found := false
+	redacted := m.re.ReplaceAllStringFunc(value, func(match string) string {
+		if m.validate != nil && !m.validate(match) {
+			return match
+		}
+		found = true
+		return replacement
+	})
+	return redacted, found
+}
+
+// luhn reports whether the digits of s pass the Luhn checksum.
+// Characters other than digits are ignored.
+func luhn(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// NewScrubber returns a Scrubber that replaces the parts of string
+// attribute values detected by any of the matchers with replacement.
+// Matchers are applied in order, each to the output of the previous.
+//
+// Every string attribute of every exported record is inspected, so the
+// cost of scrubbing grows with the number of exported series.
+func NewScrubber(replacement string, matchers ...Matcher) *Scrubber {
+	return &Scrubber{
+		replacement: replacement,
+		matchers:    matchers,
+		counts:      make([]uint64, len(matchers)),
+	}
+}
+
+// Mutate implements Mutator.
+func (s *Scrubber) Mutate(_ instrumentation.Library, rec export.Record) (export.Record, bool) {
+	attrs := rec.Attributes()
+	var kvs []attribute.KeyValue
+	for iter := attrs.Iter(); iter.Next(); {
+		idx, kv := iter.IndexedAttribute()
+		if kv.Value.Type() == attribute.STRING {
+			if value, ok := s.scrub(kv.Value.AsString()); ok {
+				if kvs == nil {
+					// Copy the attributes preceding the first
+					// scrubbed one.
+					kvs = make([]attribute.KeyValue, idx, attrs.Len())
+					for j := range kvs {
+						kvs[j], _ = attrs.Get(j)
+					}
+				}
+				kv = kv.Key.String(value)
+			}
+		}
+		if kvs != nil {
+			kvs = append(kvs, kv)
+		}
+	}
+	if kvs == nil {
+		return rec, true
+	}
+	set := attribute.NewSet(kvs...)
+	return withAttributes(rec, &set), true
+}
+
+func (s *Scrubber) scrub(value string) (string, bool) {
+	scrubbed := false
+	for i, m := range s.matchers {
+		var ok bool
+		if value, ok = m.Redact(value, s.replacement); ok {
+			atomic.AddUint64(&s.counts[i], 1)
+			scrubbed = true
+		}
+	}
+	return value, scrubbed
+}
+
+// Counts returns the number of attribute values redacted by each
+// Matcher, keyed by Matcher name.  A series is counted again each time
+// it is exported.
+func (s *Scrubber) Counts() map[string]uint64 {
+	counts := make(map[string]uint64, len(s.matchers))
+	for i, m := range s.matchers {
+		counts[m.Name()] += atomic.LoadUint64(&s.counts[i])
+	}
+	return counts
+}
+
+// RegisterMetrics registers an asynchronous counter named
+// ScrubbedMetricName with meter, reporting Counts with one series per
+// Matcher.
+func (s *Scrubber) RegisterMetrics(meter metric.Meter) error {
+	counter, err := meter.AsyncInt64().Counter(
+		ScrubbedMetricName,
+		instrument.WithDescription("Number of attribute values redacted before export"),
+	)
+	if err != nil {
+		return err
+	}
+	return meter.RegisterCallback([]instrument.Asynchronous{counter}, func(ctx context.Context) {
+		for name, count := range s.Counts() {
+			counter.Observe(ctx, int64(count), matcherKey.String(name))
+		}
+	})
+}
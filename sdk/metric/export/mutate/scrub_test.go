@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutate_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/export/mutate"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func TestMatchers(t *testing.T) {
+	for _, tc := range []struct {
+		matcher  mutate.Matcher
+		input    string
+		expected string
+	}{
+		{mutate.EmailMatcher(), "alice@example.com", "X"},
+		{mutate.EmailMatcher(), "from alice.b+tag@mail.example.org today", "from X today"},
+		{mutate.EmailMatcher(), "example.com", "example.com"},
+		{mutate.IPMatcher(), "10.0.0.1", "X"},
+		{mutate.IPMatcher(), "peer 192.168.1.20 and fe80::1", "peer X and X"},
+		{mutate.IPMatcher(), "10:30:00", "10:30:00"},
+		{mutate.IPMatcher(), "v1.2", "v1.2"},
+		{mutate.IPMatcher(), "10.0.0.1:8080", "X:8080"},
+		{mutate.IPMatcher(), "[fe80::1]:8080", "[X]:8080"},
+		{mutate.IPMatcher(), "addr:10.1.2.3", "addr:X"},
+		{mutate.IPMatcher(), "addr:fe80::1", "addr:X"},
+		{mutate.IPMatcher(), "peer=::1", "peer=X"},
+		{mutate.IPMatcher(), "connect to 10.1.2.3.", "connect to X."},
+		{mutate.IPMatcher(), "from 10.1.2.3, to 10.1.2.4:443.", "from X, to X:443."},
+		{mutate.IPMatcher(), "1.2.3", "1.2.3"},
+		{mutate.IPMatcher(), "999.1.2.3", "999.1.2.3"},
+		{mutate.CreditCardMatcher(), "4111 1111 1111 1111", "X"},
+		{mutate.CreditCardMatcher(), "card=4111-1111-1111-1111", "card=X"},
+		{mutate.CreditCardMatcher(), "4111111111111112", "4111111111111112"},
+		{mutate.CreditCardMatcher(), "order 12345", "order 12345"},
+		{mutate.NewRegexpMatcher("token", regexp.MustCompile(`tok_[a-z0-9]+`)), "auth tok_abc123", "auth X"},
+	} {
+		t.Run(tc.matcher.Name()+"/"+tc.input, func(t *testing.T) {
+			out, ok := tc.matcher.Redact(tc.input, "X")
+			require.Equal(t, tc.expected, out)
+			require.Equal(t, tc.expected != tc.input, ok)
+		})
+	}
+}
+
+func TestScrubber(t *testing.T) {
+	scrubber := mutate.NewScrubber("REDACTED", mutate.EmailMatcher(), mutate.IPMatcher())
+
+	values := exportRecords(t,
+		[]mutate.Mutator{scrubber},
+		newRecord(t, &requests, 3,
+			attribute.Int("A", 1),
+			attribute.String("client", "10.1.2.3"),
+			attribute.String("user", "bob@example.com"),
+		),
+		newRecord(t, &bytesSent, 10, attribute.String("route", "/index")),
+	)
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/A=1,client=REDACTED,user=REDACTED/": 3,
+		"bytes.sum/route=/index/":                         10,
+	}, values)
+	require.Equal(t, map[string]uint64{
+		"email": 1,
+		"ip":    1,
+	}, scrubber.Counts())
+}
+
+func TestScrubberRegisterMetrics(t *testing.T) {
+	ctx := context.Background()
+	scrubber := mutate.NewScrubber("REDACTED", mutate.EmailMatcher())
+	_ = exportRecords(t,
+		[]mutate.Mutator{scrubber},
+		newRecord(t, &requests, 1, attribute.String("user", "bob@example.com")),
+		newRecord(t, &requests, 1, attribute.String("user", "eve@example.com")),
+	)
+
+	mp, exp := metrictest.NewTestMeterProvider()
+	require.NoError(t, scrubber.RegisterMetrics(mp.Meter("scrub")))
+	require.NoError(t, exp.Collect(ctx))
+
+	rec, err := exp.GetByNameAndAttributes(mutate.ScrubbedMetricName, []attribute.KeyValue{attribute.String("matcher", "email")})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), rec.Sum.AsInt64())
+}
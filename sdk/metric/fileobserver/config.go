@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileobserver // import "go.opentelemetry.io/otel/sdk/metric/fileobserver"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/metric/instrument"
+)
+
+const (
+	// DefaultMinInterval is the default minimum time between reads of
+	// a file.
+	DefaultMinInterval = time.Second
+
+	// DefaultInitialBackoff is the default time a Source waits before
+	// reading a file again after the first failure.
+	DefaultInitialBackoff = time.Second
+
+	// DefaultMaxBackoff is the default longest time a Source waits
+	// before reading a file again after repeated failures.
+	DefaultMaxBackoff = time.Minute
+)
+
+// config contains the configuration of a Source.
+type config struct {
+	minInterval    time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	instrumentOpts []instrument.Option
+}
+
+// Option configures a Source.
+type Option interface {
+	apply(config) config
+}
+
+type optionFunc func(config) config
+
+func (fn optionFunc) apply(cfg config) config {
+	return fn(cfg)
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		minInterval:    DefaultMinInterval,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		cfg = opt.apply(cfg)
+	}
+	if cfg.maxBackoff < cfg.initialBackoff {
+		cfg.maxBackoff = cfg.initialBackoff
+	}
+	return cfg
+}
+
+// WithMinInterval sets the minimum time between reads of the file.
+// Observations made within this interval of the last read reuse its
+// result.  A zero interval reads the file on every observation.
+func WithMinInterval(d time.Duration) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.minInterval = d
+		return cfg
+	})
+}
+
+// WithBackoff sets the time to wait before reading the file again after
+// a failure.  The wait starts at initial and doubles with every
+// consecutive failure up to max.
+func WithBackoff(initial, max time.Duration) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.initialBackoff = initial
+		cfg.maxBackoff = max
+		return cfg
+	})
+}
+
+// WithInstrumentOptions sets the options used to create the instrument
+// registered by Gauge or Counter.
+func WithInstrumentOptions(opts ...instrument.Option) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.instrumentOpts = append(cfg.instrumentOpts, opts...)
+		return cfg
+	})
+}
@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fileobserver provides asynchronous instruments observing values
+// read from files, such as those of the proc and cgroup file systems.
+//
+// This package is currently in a pre-GA phase. Backwards incompatible changes
+// may be introduced in subsequent minor version releases as we work to track the
+// evolving OpenTelemetry specification and user feedback.
+//
+// A Source reads and parses a file on behalf of an instrument callback.
+// It limits how often the file is read, so that several collections in
+// quick succession reuse one read, and it backs off exponentially while
+// the file cannot be read or parsed, so that a missing file does not
+// produce an error on every collection.  For example:
+//
+//	err := fileobserver.Gauge(
+//	        meter,
+//	        "container.memory.usage",
+//	        "/sys/fs/cgroup/memory.current",
+//	        fileobserver.ParseSingleValue(1),
+//	        fileobserver.WithInstrumentOptions(instrument.WithUnit(unit.Bytes)),
+//	)
+package fileobserver // import "go.opentelemetry.io/otel/sdk/metric/fileobserver"
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+)
+
+// Source reads and parses a file, caching the result.
+type Source struct {
+	path  string
+	parse ParseFunc
+	cfg   config
+	now   func() time.Time
+
+	lock    sync.Mutex
+	obs     []Observation
+	readAt  time.Time
+	err     error
+	backoff time.Duration
+	retryAt time.Time
+}
+
+// NewSource returns a Source that reads the file at path and parses it
+// with parse.
+func NewSource(path string, parse ParseFunc, opts ...Option) *Source {
+	return &Source{
+		path:  path,
+		parse: parse,
+		cfg:   newConfig(opts),
+		now:   time.Now,
+	}
+}
+
+// Observations returns the observations parsed from the file.  The
+// result of the last read is returned when it is more recent than the
+// minimum interval.  While backing off after a failure, the error of the
+// failed read is returned without reading the file.
+func (s *Source) Observations() ([]Observation, error) {
+	obs, _, err := s.observations()
+	return obs, err
+}
+
+// observations is Observations, also reporting whether the file was
+// read by this call.
+func (s *Source) observations() ([]Observation, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	if s.err != nil {
+		if now.Before(s.retryAt) {
+			return nil, false, s.err
+		}
+	} else if !s.readAt.IsZero() && now.Sub(s.readAt) < s.cfg.minInterval {
+		return s.obs, false, nil
+	}
+
+	obs, err := s.read()
+	if err != nil {
+		if s.backoff == 0 {
+			s.backoff = s.cfg.initialBackoff
+		} else if s.backoff *= 2; s.backoff > s.cfg.maxBackoff {
+			s.backoff = s.cfg.maxBackoff
+		}
+		s.err = err
+		s.retryAt = now.Add(s.backoff)
+		s.obs = nil
+		return nil, true, err
+	}
+	s.err = nil
+	s.backoff = 0
+	s.obs = obs
+	s.readAt = now
+	return obs, true, nil
+}
+
+func (s *Source) read() ([]Observation, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	obs, err := s.parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path, err)
+	}
+	return obs, nil
+}
+
+// observe calls fn with each observation of the Source.  Errors are
+// reported to the global error handler when the file was read, so that
+// a failing file is reported once per backoff period.
+func (s *Source) observe(fn func(Observation)) {
+	obs, read, err := s.observations()
+	if err != nil {
+		if read {
+			otel.Handle(err)
+		}
+		return
+	}
+	for _, o := range obs {
+		fn(o)
+	}
+}
+
+// Gauge registers an asynchronous float64 Gauge named name with meter
+// that observes the values parsed from the file at path.
+func Gauge(meter metric.Meter, name, path string, parse ParseFunc, opts ...Option) error {
+	src := NewSource(path, parse, opts...)
+	gauge, err := meter.AsyncFloat64().Gauge(name, src.cfg.instrumentOpts...)
+	if err != nil {
+		return err
+	}
+	return meter.RegisterCallback([]instrument.Asynchronous{gauge}, func(ctx context.Context) {
+		src.observe(func(o Observation) {
+			gauge.Observe(ctx, o.Value, o.Attributes...)
+		})
+	})
+}
+
+// Counter registers an asynchronous float64 Counter named name with
+// meter that observes the values parsed from the file at path.  The
+// file must hold monotonically increasing totals, such as the usage
+// fields of /sys/fs/cgroup/cpu.stat.
+func Counter(meter metric.Meter, name, path string, parse ParseFunc, opts ...Option) error {
+	src := NewSource(path, parse, opts...)
+	counter, err := meter.AsyncFloat64().Counter(name, src.cfg.instrumentOpts...)
+	if err != nil {
+		return err
+	}
+	return meter.RegisterCallback([]instrument.Asynchronous{counter}, func(ctx context.Context) {
+		src.observe(func(o Observation) {
+			counter.Observe(ctx, o.Value, o.Attributes...)
+		})
+	})
+}
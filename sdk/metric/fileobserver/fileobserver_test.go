@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileobserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestSourceCaching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "value")
+	writeFile(t, path, "1\n")
+
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	src := NewSource(path, ParseSingleValue(1), WithMinInterval(time.Second))
+	src.now = clock.now
+
+	obs, err := src.Observations()
+	require.NoError(t, err)
+	require.Equal(t, []Observation{{Value: 1}}, obs)
+
+	// Within the minimum interval the cached value is returned.
+	writeFile(t, path, "2\n")
+	clock.advance(500 * time.Millisecond)
+	obs, err = src.Observations()
+	require.NoError(t, err)
+	require.Equal(t, []Observation{{Value: 1}}, obs)
+
+	clock.advance(500 * time.Millisecond)
+	obs, err = src.Observations()
+	require.NoError(t, err)
+	require.Equal(t, []Observation{{Value: 2}}, obs)
+}
+
+func TestSourceBackoff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "value")
+
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	src := NewSource(path, ParseSingleValue(1), WithMinInterval(0), WithBackoff(time.Second, 3*time.Second))
+	src.now = clock.now
+
+	for _, wait := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		_, read, err := src.observations()
+		require.Error(t, err)
+		require.True(t, read)
+
+		// The file is present, but the Source is backing off.
+		writeFile(t, path, "7")
+		clock.advance(wait - time.Nanosecond)
+		_, read, err = src.observations()
+		require.Error(t, err)
+		require.False(t, read)
+
+		require.NoError(t, os.Remove(path))
+		clock.advance(time.Nanosecond)
+	}
+
+	writeFile(t, path, "7")
+	obs, read, err := src.observations()
+	require.NoError(t, err)
+	require.True(t, read)
+	require.Equal(t, []Observation{{Value: 7}}, obs)
+
+	// A failure after recovering restarts the backoff.
+	writeFile(t, path, "not a number")
+	_, _, err = src.observations()
+	require.Error(t, err)
+	require.Equal(t, time.Second, src.backoff)
+}
+
+func TestParseSingleValue(t *testing.T) {
+	obs, err := ParseSingleValue(1e-6)([]byte(" 2500000\n"))
+	require.NoError(t, err)
+	require.Equal(t, []Observation{{Value: 2.5}}, obs)
+
+	_, err = ParseSingleValue(1)([]byte("max\n"))
+	require.Error(t, err)
+}
+
+func TestParseFlatKeyed(t *testing.T) {
+	data := []byte("anon 4096\nfile 8192\n\nkernel 1024\n")
+
+	obs, err := ParseFlatKeyed("type", 1)(data)
+	require.NoError(t, err)
+	require.Equal(t, []Observation{
+		{Value: 4096, Attributes: []attribute.KeyValue{attribute.String("type", "anon")}},
+		{Value: 8192, Attributes: []attribute.KeyValue{attribute.String("type", "file")}},
+		{Value: 1024, Attributes: []attribute.KeyValue{attribute.String("type", "kernel")}},
+	}, obs)
+
+	obs, err = ParseFlatKeyed("type", 1, "file")(data)
+	require.NoError(t, err)
+	require.Equal(t, []Observation{
+		{Value: 8192, Attributes: []attribute.KeyValue{attribute.String("type", "file")}},
+	}, obs)
+
+	_, err = ParseFlatKeyed("type", 1)([]byte("anon 1 2\n"))
+	require.Error(t, err)
+	_, err = ParseFlatKeyed("type", 1)([]byte("anon x\n"))
+	require.Error(t, err)
+}
+
+func TestGaugeAndCounter(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	current := filepath.Join(dir, "memory.current")
+	stat := filepath.Join(dir, "cpu.stat")
+	writeFile(t, current, "4096\n")
+	writeFile(t, stat, "usage_usec 1500000\nuser_usec 1000000\n")
+
+	mp, exp := metrictest.NewTestMeterProvider()
+	meter := mp.Meter("fileobserver")
+	require.NoError(t, Gauge(meter, "memory.usage", current, ParseSingleValue(1)))
+	require.NoError(t, Counter(meter, "cpu.time", stat, ParseFlatKeyed("field", 1e-6, "usage_usec")))
+	require.NoError(t, exp.Collect(ctx))
+
+	rec, err := exp.GetByName("memory.usage")
+	require.NoError(t, err)
+	require.Equal(t, 4096.0, rec.LastValue.AsFloat64())
+
+	rec, err = exp.GetByNameAndAttributes("cpu.time", []attribute.KeyValue{attribute.String("field", "usage_usec")})
+	require.NoError(t, err)
+	require.Equal(t, 1.5, rec.Sum.AsFloat64())
+	require.Len(t, exp.GetRecords(), 2)
+}
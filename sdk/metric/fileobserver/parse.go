@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileobserver // import "go.opentelemetry.io/otel/sdk/metric/fileobserver"
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Observation is one value parsed from a file.
+type Observation struct {
+	Value      float64
+	Attributes []attribute.KeyValue
+}
+
+// ParseFunc parses the content of a file into observations.
+type ParseFunc func(data []byte) ([]Observation, error)
+
+// ParseSingleValue returns a ParseFunc for files that contain a single
+// number, such as /sys/fs/cgroup/memory.current.  The value is
+// multiplied by scale, for example to convert microseconds to seconds.
+func ParseSingleValue(scale float64) ParseFunc {
+	return func(data []byte) ([]Observation, error) {
+		v, err := strconv.ParseFloat(string(bytes.TrimSpace(data)), 64)
+		if err != nil {
+			return nil, err
+		}
+		return []Observation{{Value: v * scale}}, nil
+	}
+}
+
+// ParseFlatKeyed returns a ParseFunc for files with one "<name> <value>"
+// pair per line, such as /sys/fs/cgroup/memory.stat.  Each line becomes
+// an observation with the name as the value of the attribute key.  When
+// names is not empty only the listed names are observed.  The values
+// are multiplied by scale.
+func ParseFlatKeyed(key attribute.Key, scale float64, names ...string) ParseFunc {
+	var only map[string]struct{}
+	if len(names) != 0 {
+		only = make(map[string]struct{}, len(names))
+		for _, n := range names {
+			only[n] = struct{}{}
+		}
+	}
+	return func(data []byte) ([]Observation, error) {
+		var obs []Observation
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for line := 1; scanner.Scan(); line++ {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: expected 2 fields, found %d", line, len(fields))
+			}
+			if only != nil {
+				if _, ok := only[fields[0]]; !ok {
+					continue
+				}
+			}
+			v, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			obs = append(obs, Observation{
+				Value:      v * scale,
+				Attributes: []attribute.KeyValue{key.String(fields[0])},
+			})
+		}
+		return obs, scanner.Err()
+	}
+}
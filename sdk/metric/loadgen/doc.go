@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package loadgen generates synthetic measurements to test the capacity of
+a metrics pipeline.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+Run creates counters and histograms with a MeterProvider and updates
+them from concurrent workers with a configured number of attribute sets
+each, at a configured rate or as fast as possible, then reports the
+throughput achieved and the memory allocated and retained.  Running it
+against the MeterProvider, views and exporter intended for production
+validates that they sustain the expected load before rollout:
+
+	cont := controller.New(factory, controller.WithExporter(exporter))
+	if err := cont.Start(ctx); err != nil {
+	        return err
+	}
+	report, err := loadgen.Run(ctx, cont,
+	        loadgen.WithInstruments(20, 5),
+	        loadgen.WithCardinality(1000),
+	        loadgen.WithDuration(time.Minute),
+	)
+	if err != nil {
+	        return err
+	}
+	fmt.Println(report)
+*/
+package loadgen // import "go.opentelemetry.io/otel/sdk/metric/loadgen"
@@ -0,0 +1,275 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadgen // import "go.opentelemetry.io/otel/sdk/metric/loadgen"
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+)
+
+// ScopeName is the instrumentation scope name of the generated
+// instruments.
+const ScopeName = "go.opentelemetry.io/otel/sdk/metric/loadgen"
+
+// Default configuration values.
+const (
+	DefaultCounters    = 10
+	DefaultHistograms  = 0
+	DefaultCardinality = 100
+	DefaultDuration    = 10 * time.Second
+)
+
+// config contains the configuration of a run.
+type config struct {
+	counters    int
+	histograms  int
+	cardinality int
+	rate        float64
+	duration    time.Duration
+	workers     int
+}
+
+// Option configures a run.
+type Option interface {
+	apply(config) config
+}
+
+type optionFunc func(config) config
+
+func (fn optionFunc) apply(cfg config) config {
+	return fn(cfg)
+}
+
+// WithInstruments sets the number of counters and histograms updated.
+// The defaults are DefaultCounters and DefaultHistograms.
+func WithInstruments(counters, histograms int) Option {
+	return optionFunc(func(cfg config) config {
+		if counters >= 0 && histograms >= 0 {
+			cfg.counters = counters
+			cfg.histograms = histograms
+		}
+		return cfg
+	})
+}
+
+// WithCardinality sets the number of distinct attribute sets of every
+// instrument.  The default is DefaultCardinality.
+func WithCardinality(n int) Option {
+	return optionFunc(func(cfg config) config {
+		if n > 0 {
+			cfg.cardinality = n
+		}
+		return cfg
+	})
+}
+
+// WithRate sets the number of measurements per second of all the
+// workers together.  The default, zero, makes measurements as fast as
+// possible.
+func WithRate(perSecond float64) Option {
+	return optionFunc(func(cfg config) config {
+		if perSecond >= 0 {
+			cfg.rate = perSecond
+		}
+		return cfg
+	})
+}
+
+// WithDuration sets how long measurements are made.  The default is
+// DefaultDuration.
+func WithDuration(d time.Duration) Option {
+	return optionFunc(func(cfg config) config {
+		if d > 0 {
+			cfg.duration = d
+		}
+		return cfg
+	})
+}
+
+// WithWorkers sets the number of goroutines making measurements.  The
+// default is runtime.GOMAXPROCS(0).
+func WithWorkers(n int) Option {
+	return optionFunc(func(cfg config) config {
+		if n > 0 {
+			cfg.workers = n
+		}
+		return cfg
+	})
+}
+
+// Report describes the load generated by Run.
+type Report struct {
+	// Measurements is the number of measurements made.
+	Measurements uint64
+	// Elapsed is the duration of the measurements.
+	Elapsed time.Duration
+	// Series is the number of distinct instrument and attribute
+	// set combinations updated.
+	Series int
+	// AllocatedBytes is the memory allocated by the process while
+	// the measurements were made.
+	AllocatedBytes uint64
+	// RetainedBytes is the growth of the live heap after the
+	// measurements, which includes the state of the new series.
+	// It is negative when the heap shrank.
+	RetainedBytes int64
+}
+
+// Throughput returns the number of measurements per second.
+func (r Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Measurements) / r.Elapsed.Seconds()
+}
+
+// String returns a one-line description of the Report.
+func (r Report) String() string {
+	perMeasurement := 0.0
+	if r.Measurements != 0 {
+		perMeasurement = float64(r.AllocatedBytes) / float64(r.Measurements)
+	}
+	return fmt.Sprintf("%d measurements of %d series in %v (%.0f/s), %.1f bytes allocated per measurement, %d bytes retained",
+		r.Measurements, r.Series, r.Elapsed, r.Throughput(), perMeasurement, r.RetainedBytes)
+}
+
+// instruments are the instruments of a run.
+type instruments struct {
+	counters   []syncint64.Counter
+	histograms []syncfloat64.Histogram
+}
+
+// Run creates the instruments with a Meter of provider and makes
+// measurements until the configured duration elapses or ctx is done.
+// It returns an error if the instruments cannot be created.
+func Run(ctx context.Context, provider metric.MeterProvider, opts ...Option) (Report, error) {
+	cfg := config{
+		counters:    DefaultCounters,
+		histograms:  DefaultHistograms,
+		cardinality: DefaultCardinality,
+		duration:    DefaultDuration,
+		workers:     runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range opts {
+		cfg = opt.apply(cfg)
+	}
+
+	insts, err := newInstruments(provider.Meter(ScopeName), cfg)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{Series: (cfg.counters + cfg.histograms) * cfg.cardinality}
+	if report.Series == 0 {
+		return report, nil
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	var (
+		wg    sync.WaitGroup
+		count uint64
+		start = time.Now()
+	)
+	ctx, cancel := context.WithDeadline(ctx, start.Add(cfg.duration))
+	defer cancel()
+	for w := 0; w < cfg.workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			n := insts.work(ctx, cfg, w, start)
+			atomic.AddUint64(&count, n)
+		}(w)
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+	report.Measurements = count
+
+	runtime.ReadMemStats(&after)
+	report.AllocatedBytes = after.TotalAlloc - before.TotalAlloc
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	report.RetainedBytes = int64(after.HeapAlloc) - int64(before.HeapAlloc)
+	return report, nil
+}
+
+func newInstruments(meter metric.Meter, cfg config) (*instruments, error) {
+	insts := &instruments{}
+	for i := 0; i < cfg.counters; i++ {
+		c, err := meter.SyncInt64().Counter(fmt.Sprintf("loadgen.counter.%d", i))
+		if err != nil {
+			return nil, err
+		}
+		insts.counters = append(insts.counters, c)
+	}
+	for i := 0; i < cfg.histograms; i++ {
+		h, err := meter.SyncFloat64().Histogram(fmt.Sprintf("loadgen.histogram.%d", i))
+		if err != nil {
+			return nil, err
+		}
+		insts.histograms = append(insts.histograms, h)
+	}
+	return insts, nil
+}
+
+// work makes the measurements of worker w and returns their number.
+// Workers start at different series and instruments, and pace their
+// measurements to their share of the configured rate.
+func (insts *instruments) work(ctx context.Context, cfg config, w int, start time.Time) uint64 {
+	// Each worker owns its attribute slices, which the SDK may sort.
+	sets := make([][]attribute.KeyValue, cfg.cardinality)
+	for i := range sets {
+		sets[i] = []attribute.KeyValue{attribute.Int("series", i)}
+	}
+	var interval time.Duration
+	if cfg.rate > 0 {
+		interval = time.Duration(float64(time.Second) * float64(cfg.workers) / cfg.rate)
+	}
+
+	numInsts := len(insts.counters) + len(insts.histograms)
+	var n uint64
+	for i := w; ; i++ {
+		if n%64 == 0 && ctx.Err() != nil {
+			return n
+		}
+		if interval > 0 {
+			next := start.Add(time.Duration(n) * interval)
+			if d := time.Until(next); d > 0 {
+				select {
+				case <-ctx.Done():
+					return n
+				case <-time.After(d):
+				}
+			}
+		}
+		attrs := sets[i%cfg.cardinality]
+		if inst := (i / cfg.cardinality) % numInsts; inst < len(insts.counters) {
+			insts.counters[inst].Add(ctx, 1, attrs...)
+		} else {
+			insts.histograms[inst-len(insts.counters)].Record(ctx, float64(i%1000), attrs...)
+		}
+		n++
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadgen
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	processor "go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+func newController() *controller.Controller {
+	return controller.New(
+		processor.NewFactory(simple.NewWithInexpensiveDistribution(), aggregation.CumulativeTemporalitySelector()),
+		controller.WithCollectPeriod(0),
+	)
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	cont := newController()
+	report, err := Run(ctx, cont,
+		WithInstruments(2, 1),
+		WithCardinality(5),
+		WithDuration(50*time.Millisecond),
+		WithWorkers(2),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 15, report.Series)
+	require.Greater(t, report.Measurements, uint64(15))
+	require.GreaterOrEqual(t, report.Elapsed, 50*time.Millisecond)
+	require.Greater(t, report.Throughput(), 0.0)
+	require.Contains(t, report.String(), " measurements of 15 series in ")
+
+	require.NoError(t, cont.Collect(ctx))
+	series := map[string]int{}
+	require.NoError(t, cont.ForEach(func(_ instrumentation.Library, reader export.Reader) error {
+		return reader.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+			series[rec.Descriptor().Name()]++
+			return nil
+		})
+	}))
+	require.Equal(t, map[string]int{
+		"loadgen.counter.0":   5,
+		"loadgen.counter.1":   5,
+		"loadgen.histogram.0": 5,
+	}, series)
+}
+
+func TestRunRate(t *testing.T) {
+	report, err := Run(context.Background(), newController(),
+		WithRate(200),
+		WithDuration(100*time.Millisecond),
+		WithWorkers(2),
+	)
+	require.NoError(t, err)
+	// 200 measurements per second for 100ms, plus the first
+	// measurement of each worker.
+	require.LessOrEqual(t, report.Measurements, uint64(22))
+	require.Greater(t, report.Measurements, uint64(0))
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Run(ctx, newController(), WithDuration(time.Hour))
+	require.NoError(t, err)
+	require.Less(t, report.Elapsed, time.Hour)
+}
@@ -193,5 +193,5 @@ func subSet(attributesA, attributesB []attribute.KeyValue) bool {
 // descriptors using standard options.
 func NewDescriptor(name string, ikind sdkapi.InstrumentKind, nkind number.Kind, opts ...instrument.Option) sdkapi.Descriptor {
 	cfg := instrument.NewConfig(opts...)
-	return sdkapi.NewDescriptor(name, ikind, nkind, cfg.Description(), cfg.Unit())
+	return sdkapi.NewDescriptorWithAttributeKeys(name, ikind, nkind, cfg.Description(), cfg.Unit(), cfg.AttributeKeys())
 }
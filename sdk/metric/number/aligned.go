@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package number // import "go.opentelemetry.io/otel/sdk/metric/number"
+
+import (
+	"unsafe"
+)
+
+// AlignedNumber holds a Number that is aligned for 64-bit atomic
+// operations wherever the AlignedNumber is placed.
+//
+// On 32-bit platforms only the first word of an allocated struct, array
+// or slice is guaranteed to be 64-bit aligned, so a Number field that
+// follows fields of other sizes cannot be passed to the atomic methods
+// of Number.  AlignedNumber reserves one extra word and Ptr returns the
+// address of whichever 8 bytes are aligned.  Prefer placing Number
+// fields first; use AlignedNumber where that is not possible, such as
+// in a struct that is embedded in other structs.
+type AlignedNumber struct {
+	raw [3]uint32
+}
+
+// Ptr returns a pointer to the aligned Number.
+func (a *AlignedNumber) Ptr() *Number {
+	if uintptr(unsafe.Pointer(&a.raw))%8 == 0 {
+		return (*Number)(unsafe.Pointer(&a.raw[0]))
+	}
+	return (*Number)(unsafe.Pointer(&a.raw[1]))
+}
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build 386
+// +build 386
+
+package number
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMisalignedOffset verifies that the test struct exercises the
+// misaligned case on 32-bit platforms, where a plain Number in the
+// same position could not be used atomically.
+func TestMisalignedOffset(t *testing.T) {
+	require.Equal(t, uintptr(4), unsafe.Offsetof(misaligned{}.aligned))
+
+	// The first word of an allocated struct is 64-bit aligned.
+	v := new(misaligned)
+	require.Equal(t, unsafe.Pointer(&v.aligned.raw[1]), unsafe.Pointer(v.aligned.Ptr()))
+}
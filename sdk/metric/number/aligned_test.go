@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package number
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+// misaligned places an AlignedNumber after a 4-byte field, which is a
+// 4-byte offset on 32-bit platforms.
+type misaligned struct {
+	flag    int32
+	aligned AlignedNumber
+}
+
+func TestAlignedNumber(t *testing.T) {
+	values := make([]misaligned, 3)
+	for i := range values {
+		n := values[i].aligned.Ptr()
+		require.Zero(t, uintptr(unsafe.Pointer(n))%8)
+		require.Same(t, n, values[i].aligned.Ptr())
+
+		n.SetInt64Atomic(5)
+		require.Equal(t, int64(5), n.AsInt64Atomic())
+		require.Equal(t, int32(0), values[i].flag)
+	}
+}
+
+func TestAlignedNumberConcurrent(t *testing.T) {
+	const goroutines, adds = 8, 1000
+	var v misaligned
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < adds; j++ {
+				v.aligned.Ptr().AddFloat64Atomic(0.5)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, float64(goroutines*adds)/2, v.aligned.Ptr().AsFloat64Atomic())
+}
@@ -174,7 +174,7 @@ func (b *Processor) Process(accum export.Accumulation) error {
 	// Check if there is an existing value.
 	value, ok := b.state.values[key]
 	if !ok {
-		stateful := b.TemporalityFor(desc, agg.Aggregation().Kind()).MemoryRequired(desc.InstrumentKind())
+		stateful := aggregation.ResolveTemporality(b, desc, agg.Aggregation().Kind()).MemoryRequired(desc.InstrumentKind())
 
 		newValue := &stateValue{
 			attrs:    accum.Attributes(),
@@ -197,6 +197,9 @@ func (b *Processor) Process(accum export.Accumulation) error {
 			b.AggregatorFor(desc, &newValue.cumulative)
 		}
 		b.state.values[key] = newValue
+		if created := b.config.SeriesHooks.Created; created != nil {
+			created(desc, newValue.attrs)
+		}
 		return nil
 	}
 
@@ -303,6 +306,15 @@ func (b *Processor) FinishCollection() error {
 			// over the previous full collection interval.
 			if stale && stateless && !b.config.Memory {
 				delete(b.values, key)
+				if expired := b.config.SeriesHooks.Expired; expired != nil {
+					expired(key.descriptor, value.attrs)
+				}
+			}
+			if stale && !stateless && b.config.CompressAfter > 0 &&
+				b.finishedCollection-value.updated >= b.config.CompressAfter {
+				if c, ok := value.cumulative.(aggregator.Compressor); ok {
+					c.Compress()
+				}
 			}
 			continue
 		}
@@ -334,7 +346,7 @@ func (b *state) ForEach(exporter aggregation.TemporalitySelector, f func(export.
 		var agg aggregation.Aggregation
 		var start time.Time
 
-		aggTemp := exporter.TemporalityFor(key.descriptor, value.current.Aggregation().Kind())
+		aggTemp := aggregation.ResolveTemporality(exporter, key.descriptor, value.current.Aggregation().Kind())
 
 		switch aggTemp {
 		case aggregation.CumulativeTemporality:
@@ -364,11 +376,15 @@ func (b *state) ForEach(exporter aggregation.TemporalitySelector, f func(export.
 
 		// If the processor does not have Config.Memory and it was not updated
 		// in the prior round, do not visit this value.
-		if !b.config.Memory && value.updated != (b.finishedCollection-1) {
-			continue
+		newRecord := export.NewRecord
+		if value.updated != (b.finishedCollection - 1) {
+			if !b.config.Memory {
+				continue
+			}
+			newRecord = export.NewStaleRecord
 		}
 
-		if err := f(export.NewRecord(
+		if err := f(newRecord(
 			key.descriptor,
 			value.attrs,
 			agg,
@@ -30,6 +30,7 @@ import (
 	sdk "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/aggregatortest"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
 	"go.opentelemetry.io/otel/sdk/metric/export"
 	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 	"go.opentelemetry.io/otel/sdk/metric/metrictest"
@@ -126,8 +127,11 @@ func testProcessor(
 	akind aggregation.Kind,
 ) {
 	// This code tests for errors when the export kind is Delta
-	// and the instrument kind is PrecomputedSum().
-	expectConversion := !(aggTemp == aggregation.DeltaTemporality && mkind.PrecomputedSum())
+	// and the instrument kind is PrecomputedSum(), except for
+	// UpDownCounterObservers, which are exported as Cumulative.
+	resolvedDesc := metrictest.NewDescriptor("inst", mkind, nkind)
+	resolved := aggregation.ResolveTemporality(aggregation.ConstantTemporalitySelector(aggTemp), &resolvedDesc, akind)
+	expectConversion := !(resolved == aggregation.DeltaTemporality && mkind.PrecomputedSum())
 	requireConversion := func(t *testing.T, err error) {
 		if expectConversion {
 			require.NoError(t, err)
@@ -203,14 +207,14 @@ func testProcessor(
 					// number of Accumulators, unless LastValue aggregation.
 					// If a precomputed sum, we expect cumulative inputs.
 					if mkind.PrecomputedSum() {
-						require.NotEqual(t, aggTemp, aggregation.DeltaTemporality)
+						require.NotEqual(t, resolved, aggregation.DeltaTemporality)
 						if akind == aggregation.LastValueKind {
 							multiplier = cumulativeMultiplier
 						} else {
 							multiplier = cumulativeMultiplier * int64(nAccum)
 						}
 					} else {
-						if aggTemp == aggregation.CumulativeTemporality && akind != aggregation.LastValueKind {
+						if resolved == aggregation.CumulativeTemporality && akind != aggregation.LastValueKind {
 							multiplier = cumulativeMultiplier * int64(nAccum)
 						} else if akind == aggregation.LastValueKind {
 							multiplier = 1
@@ -222,7 +226,7 @@ func testProcessor(
 					// Synchronous accumulate results from multiple accumulators,
 					// use that number as the baseline multiplier.
 					multiplier = int64(nAccum)
-					if aggTemp == aggregation.CumulativeTemporality {
+					if resolved == aggregation.CumulativeTemporality {
 						// If a cumulative exporter, include prior checkpoints.
 						multiplier *= cumulativeMultiplier
 					}
@@ -511,3 +515,152 @@ func TestCounterObserverEndToEnd(t *testing.T) {
 	requireNotAfter(t, endTime[0], endTime[1])
 	requireNotAfter(t, endTime[1], endTime[2])
 }
+
+// compressRecorder counts the Compress calls of a histogram.
+type compressRecorder struct {
+	*histogram.Aggregator
+	compressed *int
+}
+
+func (c compressRecorder) Compress() {
+	*c.compressed++
+	c.Aggregator.Compress()
+}
+
+func (c compressRecorder) Merge(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	if o, ok := oa.(compressRecorder); ok {
+		oa = o.Aggregator
+	}
+	return c.Aggregator.Merge(oa, desc)
+}
+
+type compressSelector struct {
+	compressed int
+}
+
+func (s *compressSelector) AggregatorFor(desc *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	aggs := histogram.New(len(aggPtrs), desc)
+	for i := range aggPtrs {
+		*aggPtrs[i] = compressRecorder{Aggregator: &aggs[i], compressed: &s.compressed}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	aggTempSel := aggregation.CumulativeTemporalitySelector()
+	desc := metrictest.NewDescriptor("inst.histogram", sdkapi.HistogramInstrumentKind, number.Int64Kind)
+	selector := &compressSelector{}
+	processor := basic.New(selector, aggTempSel, basic.WithMemory(true), basic.WithCompression(2))
+	reader := processor.Reader()
+
+	collect := func(values ...int64) []uint64 {
+		processor.StartCollection()
+		for _, v := range values {
+			require.NoError(t, processor.Process(updateFor(t, &desc, selector, v)))
+		}
+		require.NoError(t, processor.FinishCollection())
+
+		var counts []uint64
+		require.NoError(t, reader.ForEach(aggTempSel, func(rec export.Record) error {
+			buckets, err := rec.Aggregation().(aggregation.Histogram).Histogram()
+			counts = buckets.Counts
+			return err
+		}))
+		return counts
+	}
+
+	want := collect(1e4)
+	require.Equal(t, want, collect())
+	require.Equal(t, 0, selector.compressed)
+
+	// The series is compressed after two idle collections, and
+	// exported unchanged.
+	require.Equal(t, want, collect())
+	require.Equal(t, 1, selector.compressed)
+	require.Equal(t, want, collect())
+
+	// An update decodes it.
+	counts := collect(1e4)
+	for i := range want {
+		require.Equal(t, 2*want[i], counts[i])
+	}
+}
+
+func TestSeriesHooks(t *testing.T) {
+	aggTempSel := aggregation.DeltaTemporalitySelector()
+	desc := metrictest.NewDescriptor("inst.sum", sdkapi.CounterInstrumentKind, number.Int64Kind)
+	selector := processorTest.AggregatorSelector()
+
+	var events []string
+	hook := func(event string) func(*sdkapi.Descriptor, *attribute.Set) {
+		return func(desc *sdkapi.Descriptor, attrs *attribute.Set) {
+			events = append(events, fmt.Sprintf("%s %s{%s}", event, desc.Name(), attrs.Encoded(attribute.DefaultEncoder())))
+		}
+	}
+	processor := basic.New(selector, aggTempSel, basic.WithSeriesHooks(basic.SeriesHooks{
+		Created: hook("created"),
+		Expired: hook("expired"),
+	}))
+
+	collect := func(attrs ...attribute.KeyValue) {
+		processor.StartCollection()
+		for _, kv := range attrs {
+			require.NoError(t, processor.Process(updateFor(t, &desc, selector, 1, kv)))
+		}
+		require.NoError(t, processor.FinishCollection())
+	}
+
+	collect(attribute.String("A", "1"), attribute.String("A", "2"))
+	require.Equal(t, []string{"created inst.sum{A=1}", "created inst.sum{A=2}"}, events)
+
+	// A=2 was not updated during the collection.
+	events = nil
+	collect(attribute.String("A", "1"))
+	require.Equal(t, []string{"expired inst.sum{A=2}"}, events)
+
+	events = nil
+	collect(attribute.String("A", "2"))
+	require.ElementsMatch(t, []string{"created inst.sum{A=2}", "expired inst.sum{A=1}"}, events)
+
+	// Cumulative sums are never expired.
+	events = nil
+	processor = basic.New(selector, aggregation.CumulativeTemporalitySelector(), basic.WithSeriesHooks(basic.SeriesHooks{
+		Created: hook("created"),
+		Expired: hook("expired"),
+	}))
+	collect(attribute.String("A", "1"))
+	collect()
+	collect()
+	require.Equal(t, []string{"created inst.sum{A=1}"}, events)
+}
+
+func TestStaleRecords(t *testing.T) {
+	aggTempSel := aggregation.CumulativeTemporalitySelector()
+	desc := metrictest.NewDescriptor("inst.lastvalue", sdkapi.GaugeObserverInstrumentKind, number.Int64Kind)
+	selector := processorTest.AggregatorSelector()
+	processor := basic.New(selector, aggTempSel, basic.WithMemory(true))
+	reader := processor.Reader()
+
+	collect := func(attrs ...attribute.KeyValue) map[string]bool {
+		processor.StartCollection()
+		for _, kv := range attrs {
+			require.NoError(t, processor.Process(updateFor(t, &desc, selector, 1, kv)))
+		}
+		require.NoError(t, processor.FinishCollection())
+
+		stale := map[string]bool{}
+		require.NoError(t, reader.ForEach(aggTempSel, func(rec export.Record) error {
+			stale[rec.Attributes().Encoded(attribute.DefaultEncoder())] = rec.Stale()
+			return nil
+		}))
+		return stale
+	}
+
+	require.Equal(t, map[string]bool{"A=1": false, "A=2": false},
+		collect(attribute.String("A", "1"), attribute.String("A", "2")))
+	// A=2 was not observed, its last value is remembered.
+	require.Equal(t, map[string]bool{"A=1": false, "A=2": true},
+		collect(attribute.String("A", "1")))
+	require.Equal(t, map[string]bool{"A=1": true, "A=2": true}, collect())
+	require.Equal(t, map[string]bool{"A=1": true, "A=2": false},
+		collect(attribute.String("A", "2")))
+}
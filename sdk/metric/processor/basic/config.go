@@ -14,6 +14,11 @@
 
 package basic // import "go.opentelemetry.io/otel/sdk/metric/processor/basic"
 
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
 // config contains the options for configuring a basic metric processor.
 type config struct {
 	// Memory controls whether the processor remembers metric instruments and
@@ -21,6 +26,33 @@ type config struct {
 	// Reader.ForEach() will visit metrics that were not updated in the most
 	// recent interval.
 	Memory bool
+
+	// CompressAfter is the number of collections after which the
+	// cumulative state of a series that has not been updated is
+	// compressed.  Zero disables compression.
+	CompressAfter int64
+
+	// SeriesHooks are called when the processor creates or expires
+	// the state of a series.
+	SeriesHooks SeriesHooks
+}
+
+// SeriesHooks are called by a Processor when it starts and stops
+// tracking a series, the stream of one instrument and attribute set, in
+// order to observe the sources of cardinality growth as they appear or
+// to account series against a quota.  The hooks are called during the
+// collection, with the Processor locked: they must not block or use the
+// Processor.  Nil hooks are not called.
+type SeriesHooks struct {
+	// Created is called when the first Accumulation of a series
+	// is processed.
+	Created func(desc *sdkapi.Descriptor, attrs *attribute.Set)
+
+	// Expired is called when the state of a series is removed
+	// after a collection interval without updates.  Series whose
+	// cumulative state is kept, and every series of a Processor
+	// with memory, never expire.
+	Expired func(desc *sdkapi.Descriptor, attrs *attribute.Set)
 }
 
 type Option interface {
@@ -40,3 +72,34 @@ func (m memoryOption) applyProcessor(cfg config) config {
 	cfg.Memory = bool(m)
 	return cfg
 }
+
+// WithCompression compresses the cumulative state of series that were
+// not updated for the given number of collections, when their
+// Aggregator implements aggregator.Compressor.  The state is decoded
+// again when the series is next updated, and a copy is decoded each time
+// it is exported.  This trades CPU for memory when a cumulative exporter
+// keeps many series that are rarely updated, for example histograms of a
+// high-cardinality instrument.  A value of zero disables compression.
+func WithCompression(collections int) Option {
+	return compressionOption(collections)
+}
+
+type compressionOption int
+
+func (c compressionOption) applyProcessor(cfg config) config {
+	cfg.CompressAfter = int64(c)
+	return cfg
+}
+
+// WithSeriesHooks calls hooks when the Processor creates or expires the
+// state of a series.
+func WithSeriesHooks(hooks SeriesHooks) Option {
+	return seriesHooksOption(hooks)
+}
+
+type seriesHooksOption SeriesHooks
+
+func (h seriesHooksOption) applyProcessor(cfg config) config {
+	cfg.SeriesHooks = SeriesHooks(h)
+	return cfg
+}
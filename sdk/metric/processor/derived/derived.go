@@ -0,0 +1,271 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package derived // import "go.opentelemetry.io/otel/sdk/metric/processor/derived"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// Gauge describes a float64 gauge whose value is computed from
+	// the other metrics collected by the same Checkpointer.
+	Gauge struct {
+		Name        string
+		Description string
+		Unit        unit.Unit
+
+		// Compute returns the current value of the gauge.  When
+		// the second return value is false, no point is exported
+		// for the gauge during this collection.
+		Compute func(*Snapshot) (float64, bool)
+	}
+
+	// Snapshot is the set of records exported by the wrapped
+	// Checkpointer during a single visit of its Reader.
+	Snapshot struct {
+		records []export.Record
+	}
+
+	// Processor implements derived gauges by appending computed
+	// records to the Reader of the wrapped Checkpointer.
+	Processor struct {
+		export.Checkpointer
+		reader *reader
+	}
+
+	gauge struct {
+		Gauge
+		descriptor *sdkapi.Descriptor
+	}
+
+	reader struct {
+		export.Reader
+		gauges []gauge
+	}
+
+	factory struct {
+		factory export.CheckpointerFactory
+		gauges  []Gauge
+	}
+)
+
+var _ export.Processor = &Processor{}
+var _ export.Checkpointer = &Processor{}
+var _ export.Reader = &reader{}
+var _ export.LibraryCheckpointerFactory = factory{}
+
+// New returns a Processor that passes data to the next stage in an
+// export pipeline and adds the given derived gauges to its Reader.
+func New(ckpter export.Checkpointer, gauges ...Gauge) *Processor {
+	r := &reader{
+		Reader: ckpter.Reader(),
+		gauges: make([]gauge, len(gauges)),
+	}
+	for i, g := range gauges {
+		desc := sdkapi.NewDescriptor(g.Name, sdkapi.GaugeObserverInstrumentKind, number.Float64Kind, g.Description, g.Unit)
+		r.gauges[i] = gauge{
+			Gauge:      g,
+			descriptor: &desc,
+		}
+	}
+	return &Processor{
+		Checkpointer: ckpter,
+		reader:       r,
+	}
+}
+
+// NewFactory returns a CheckpointerFactory that wraps each Checkpointer
+// produced by the given factory with the derived gauges.
+func NewFactory(ckptFactory export.CheckpointerFactory, gauges ...Gauge) export.CheckpointerFactory {
+	return factory{
+		factory: ckptFactory,
+		gauges:  gauges,
+	}
+}
+
+func (f factory) NewCheckpointer() export.Checkpointer {
+	return New(f.factory.NewCheckpointer(), f.gauges...)
+}
+
+func (f factory) NewLibraryCheckpointer(library instrumentation.Library) export.Checkpointer {
+	return New(export.NewLibraryCheckpointer(f.factory, library), f.gauges...)
+}
+
+// Reader returns the Reader of the wrapped Checkpointer extended with
+// the derived gauges.
+func (p *Processor) Reader() export.Reader {
+	return p.reader
+}
+
+// ForEach implements export.Reader.  The records of the wrapped Reader
+// are visited first, followed by one record for each derived gauge
+// that computed a value from them.
+func (r *reader) ForEach(tempSelector aggregation.TemporalitySelector, f func(export.Record) error) error {
+	snap := &Snapshot{}
+	if err := r.Reader.ForEach(tempSelector, func(rec export.Record) error {
+		snap.records = append(snap.records, rec)
+		return f(rec)
+	}); err != nil {
+		return err
+	}
+
+	var start, end time.Time
+	if len(snap.records) != 0 {
+		start = snap.records[0].StartTime()
+		end = snap.records[0].EndTime()
+	} else {
+		end = time.Now()
+		start = end
+	}
+
+	for _, g := range r.gauges {
+		value, ok := g.Compute(snap)
+		if !ok {
+			continue
+		}
+		num := number.NewFloat64Number(value)
+		if err := aggregator.RangeTest(num, g.descriptor); err != nil {
+			otel.Handle(err)
+			continue
+		}
+		agg := &lastvalue.New(1)[0]
+		if err := agg.Update(context.Background(), num, g.descriptor); err != nil {
+			return err
+		}
+		if err := f(export.NewRecord(
+			g.descriptor,
+			attribute.EmptySet(),
+			agg.Aggregation(),
+			start,
+			end,
+		)); err != nil && !errors.Is(err, aggregation.ErrNoData) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Records returns all records in the snapshot.
+func (s *Snapshot) Records() []export.Record {
+	return s.records
+}
+
+// Sum returns the total of the Sum aggregations of the records with the
+// given instrument name and a superset of the given attributes.  The
+// second return value is false when no such record exists.
+func (s *Snapshot) Sum(name string, attrs ...attribute.KeyValue) (float64, bool) {
+	var total float64
+	found := false
+	for _, rec := range s.records {
+		if !matches(rec, name, attrs) {
+			continue
+		}
+		agg, ok := rec.Aggregation().(aggregation.Sum)
+		if !ok {
+			continue
+		}
+		sum, err := agg.Sum()
+		if err != nil {
+			continue
+		}
+		total += sum.CoerceToFloat64(rec.Descriptor().NumberKind())
+		found = true
+	}
+	return total, found
+}
+
+// Count returns the total of the Count aggregations of the records with
+// the given instrument name and a superset of the given attributes.
+// The second return value is false when no such record exists.
+func (s *Snapshot) Count(name string, attrs ...attribute.KeyValue) (uint64, bool) {
+	var total uint64
+	found := false
+	for _, rec := range s.records {
+		if !matches(rec, name, attrs) {
+			continue
+		}
+		agg, ok := rec.Aggregation().(aggregation.Count)
+		if !ok {
+			continue
+		}
+		count, err := agg.Count()
+		if err != nil {
+			continue
+		}
+		total += count
+		found = true
+	}
+	return total, found
+}
+
+// LastValue returns the most recent of the LastValue aggregations of the
+// records with the given instrument name and a superset of the given
+// attributes.  The second return value is false when no such record
+// exists.
+func (s *Snapshot) LastValue(name string, attrs ...attribute.KeyValue) (float64, bool) {
+	var (
+		last      float64
+		timestamp time.Time
+		found     bool
+	)
+	for _, rec := range s.records {
+		if !matches(rec, name, attrs) {
+			continue
+		}
+		agg, ok := rec.Aggregation().(aggregation.LastValue)
+		if !ok {
+			continue
+		}
+		value, ts, err := agg.LastValue()
+		if err != nil {
+			continue
+		}
+		if found && ts.Before(timestamp) {
+			continue
+		}
+		last = value.CoerceToFloat64(rec.Descriptor().NumberKind())
+		timestamp = ts
+		found = true
+	}
+	return last, found
+}
+
+// matches returns whether rec has the given name and contains all of
+// the given attributes.
+func matches(rec export.Record, name string, attrs []attribute.KeyValue) bool {
+	if rec.Descriptor().Name() != name {
+		return false
+	}
+	set := rec.Attributes()
+	for _, kv := range attrs {
+		if v, ok := set.Value(kv.Key); !ok || v != kv.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package derived_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/processor/derived"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+var hitRatio = derived.Gauge{
+	Name: "cache.ratio.lastvalue",
+	Compute: func(s *derived.Snapshot) (float64, bool) {
+		hits, ok1 := s.Sum("cache.hits.sum")
+		misses, ok2 := s.Sum("cache.misses.sum")
+		if !ok1 || !ok2 || hits+misses == 0 {
+			return 0, false
+		}
+		return hits / (hits + misses), true
+	},
+}
+
+var regionHits = derived.Gauge{
+	Name: "cache.east.lastvalue",
+	Compute: func(s *derived.Snapshot) (float64, bool) {
+		return s.Sum("cache.hits.sum", attribute.String("region", "east"))
+	},
+}
+
+var missing = derived.Gauge{
+	Name: "cache.missing.lastvalue",
+	Compute: func(s *derived.Snapshot) (float64, bool) {
+		return s.Sum("cache.none.sum")
+	},
+}
+
+func collect(t *testing.T, proc *derived.Processor, accum *metricsdk.Accumulator) map[string]float64 {
+	proc.StartCollection()
+	accum.Collect(context.Background())
+	require.NoError(t, proc.FinishCollection())
+
+	exporter := processorTest.New(aggregation.CumulativeTemporalitySelector(), attribute.DefaultEncoder())
+	res := resource.NewSchemaless(attribute.String("R", "V"))
+	require.NoError(t, exporter.Export(context.Background(), res, processorTest.OneInstrumentationLibraryReader(instrumentation.Library{
+		Name: "test",
+	}, proc.Reader())))
+	return exporter.Values()
+}
+
+func TestDerivedGauge(t *testing.T) {
+	ctx := context.Background()
+	proc := derived.New(
+		basic.New(processorTest.AggregatorSelector(), aggregation.CumulativeTemporalitySelector(), basic.WithMemory(true)),
+		hitRatio, regionHits, missing,
+	)
+	accum := metricsdk.NewAccumulator(proc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	hits, err := meter.SyncInt64().Counter("cache.hits.sum")
+	require.NoError(t, err)
+	misses, err := meter.SyncInt64().Counter("cache.misses.sum")
+	require.NoError(t, err)
+
+	hits.Add(ctx, 2, attribute.String("region", "east"))
+	hits.Add(ctx, 1, attribute.String("region", "west"))
+	misses.Add(ctx, 1)
+
+	require.EqualValues(t, map[string]float64{
+		"cache.hits.sum/region=east/R=V": 2,
+		"cache.hits.sum/region=west/R=V": 1,
+		"cache.misses.sum//R=V":          1,
+		"cache.ratio.lastvalue//R=V":     0.75,
+		"cache.east.lastvalue//R=V":      2,
+	}, collect(t, proc, accum))
+
+	// The derived gauges follow the cumulative inputs.
+	misses.Add(ctx, 3)
+
+	require.EqualValues(t, map[string]float64{
+		"cache.hits.sum/region=east/R=V": 2,
+		"cache.hits.sum/region=west/R=V": 1,
+		"cache.misses.sum//R=V":          4,
+		"cache.ratio.lastvalue//R=V":     3.0 / 7.0,
+		"cache.east.lastvalue//R=V":      2,
+	}, collect(t, proc, accum))
+}
+
+func TestDerivedGaugeNoInputs(t *testing.T) {
+	proc := derived.New(
+		basic.New(processorTest.AggregatorSelector(), aggregation.CumulativeTemporalitySelector()),
+		hitRatio, missing,
+	)
+	accum := metricsdk.NewAccumulator(proc)
+
+	require.EqualValues(t, map[string]float64{}, collect(t, proc, accum))
+}
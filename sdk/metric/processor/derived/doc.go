@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package derived implements a metrics Processor component that computes
+gauges from the values of other metrics at collection time.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+The Processor this package implements wraps another Checkpointer.  When
+its Reader is visited by an exporter, the records of the wrapped
+Checkpointer are gathered first and each configured Gauge is then
+evaluated against that same snapshot, so that derived values are
+mutually consistent with the values they were computed from.
+
+For example, to export a cache hit ratio computed from two counters:
+
+	hitRatio := derived.Gauge{
+	        Name: "cache.hit_ratio",
+	        Compute: func(s *derived.Snapshot) (float64, bool) {
+	                hits, ok1 := s.Sum("cache.hits")
+	                misses, ok2 := s.Sum("cache.misses")
+	                if !ok1 || !ok2 || hits+misses == 0 {
+	                        return 0, false
+	                }
+	                return hits / (hits + misses), true
+	        },
+	}
+
+	cont := controller.New(
+	        derived.NewFactory(
+	                basic.NewFactory(selector, exporter),
+	                hitRatio,
+	        ),
+	        controller.WithExporter(exporter),
+	)
+
+Derived values are computed from the records as they are presented to
+the exporter, so the temporality selected by the exporter applies to the
+inputs: a delta exporter computes a ratio over the last interval, a
+cumulative exporter computes it since the start of the process.
+*/
+package derived // import "go.opentelemetry.io/otel/sdk/metric/processor/derived"
@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onchange // import "go.opentelemetry.io/otel/sdk/metric/processor/onchange"
+
+import "time"
+
+// config contains the options of a Processor.
+type config struct {
+	heartbeat time.Duration
+}
+
+// Option configures a Processor.
+type Option interface {
+	apply(config) config
+}
+
+type optionFunc func(config) config
+
+func (fn optionFunc) apply(cfg config) config {
+	return fn(cfg)
+}
+
+// WithHeartbeat passes the value of an unchanged series again when the
+// interval elapsed since it was last passed.  By default, or when the
+// interval is not positive, unchanged values are never passed again.
+func WithHeartbeat(interval time.Duration) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.heartbeat = interval
+		return cfg
+	})
+}
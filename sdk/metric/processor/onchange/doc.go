@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package onchange implements a metrics Processor component that exports
+the points of gauges only when their value changes.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+Asynchronous gauges of mostly static values, such as configuration values
+and limits, are observed and exported at every collection.  The
+Processor this package provides passes the LastValue Accumulations of a
+series to the next stage in the pipeline only when their value differs
+from the last one it passed, or when the heartbeat interval elapsed
+since then, so that a receiver can tell an unchanged series from a
+vanished one.  Accumulations of other aggregations are passed unchanged.
+For example, to re-export unchanged gauges every ten minutes:
+
+	cont := controller.New(
+	        onchange.NewFactory(
+	                basic.NewFactory(simple.NewWithInexpensiveDistribution(), exporter),
+	                onchange.WithHeartbeat(10*time.Minute),
+	        ),
+	        controller.WithExporter(exporter),
+	)
+
+The Processor relies on the next stage to export only the series
+processed during a collection, which the basic Processor does unless it
+is configured with memory.
+*/
+package onchange // import "go.opentelemetry.io/otel/sdk/metric/processor/onchange"
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onchange // import "go.opentelemetry.io/otel/sdk/metric/processor/onchange"
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// Processor passes the LastValue Accumulations of a series to
+	// the next stage in an export pipeline only when their value
+	// changed since the last one passed, or when the heartbeat
+	// interval elapsed.
+	Processor struct {
+		export.Checkpointer
+		config config
+
+		lock       sync.Mutex
+		collection int64
+		last       map[seriesKey]*point
+	}
+
+	// seriesKey identifies the series of an instrument and an
+	// attribute set.
+	seriesKey struct {
+		descriptor *sdkapi.Descriptor
+		distinct   attribute.Distinct
+	}
+
+	// point is the last value of a series passed to the next stage.
+	point struct {
+		value  number.Number
+		passed time.Time
+		// seen is the last collection that processed the
+		// series.
+		seen int64
+	}
+
+	factory struct {
+		factory export.CheckpointerFactory
+		opts    []Option
+	}
+)
+
+var _ export.Processor = &Processor{}
+var _ export.Checkpointer = &Processor{}
+var _ export.LibraryCheckpointerFactory = factory{}
+
+// New returns a Processor that passes data to the next stage in an
+// export pipeline, dropping the LastValue Accumulations that repeat the
+// last value passed for their series.
+func New(ckpter export.Checkpointer, opts ...Option) *Processor {
+	var cfg config
+	for _, opt := range opts {
+		cfg = opt.apply(cfg)
+	}
+	return &Processor{
+		Checkpointer: ckpter,
+		config:       cfg,
+		last:         map[seriesKey]*point{},
+	}
+}
+
+// NewFactory returns a CheckpointerFactory that wraps each Checkpointer
+// produced by the given factory with a Processor.
+func NewFactory(ckptFactory export.CheckpointerFactory, opts ...Option) export.CheckpointerFactory {
+	return factory{
+		factory: ckptFactory,
+		opts:    opts,
+	}
+}
+
+func (f factory) NewCheckpointer() export.Checkpointer {
+	return New(f.factory.NewCheckpointer(), f.opts...)
+}
+
+func (f factory) NewLibraryCheckpointer(library instrumentation.Library) export.Checkpointer {
+	return New(export.NewLibraryCheckpointer(f.factory, library), f.opts...)
+}
+
+// StartCollection implements export.Checkpointer.
+func (p *Processor) StartCollection() {
+	p.lock.Lock()
+	p.collection++
+	p.lock.Unlock()
+	p.Checkpointer.StartCollection()
+}
+
+// FinishCollection implements export.Checkpointer.  The last values of
+// the series not processed during the collection are forgotten, so
+// that a series reappearing later is passed again.
+func (p *Processor) FinishCollection() error {
+	p.lock.Lock()
+	for key, pt := range p.last {
+		if pt.seen != p.collection {
+			delete(p.last, key)
+		}
+	}
+	p.lock.Unlock()
+	return p.Checkpointer.FinishCollection()
+}
+
+// Process implements export.Processor.
+func (p *Processor) Process(accum export.Accumulation) error {
+	lv, ok := accum.Aggregator().Aggregation().(aggregation.LastValue)
+	if !ok {
+		return p.Checkpointer.Process(accum)
+	}
+	value, _, err := lv.LastValue()
+	if err != nil {
+		// There is no value to compare, let the next stage
+		// handle the Accumulation.
+		return p.Checkpointer.Process(accum)
+	}
+	if !p.changed(accum, value) {
+		return nil
+	}
+	return p.Checkpointer.Process(accum)
+}
+
+// changed records the value of the series of accum and returns true if
+// the Accumulation is to be passed to the next stage.
+func (p *Processor) changed(accum export.Accumulation, value number.Number) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := seriesKey{
+		descriptor: accum.Descriptor(),
+		distinct:   accum.Attributes().Equivalent(),
+	}
+	now := time.Now()
+	pt, ok := p.last[key]
+	if !ok {
+		p.last[key] = &point{value: value, passed: now, seen: p.collection}
+		return true
+	}
+	pt.seen = p.collection
+	if pt.value == value && (p.config.heartbeat <= 0 || now.Sub(pt.passed) < p.config.heartbeat) {
+		return false
+	}
+	pt.value = value
+	pt.passed = now
+	return true
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onchange_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/processor/onchange"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// collect runs a collection of accum and returns the last values
+// exported by proc, and zero for the other aggregations.
+func collect(t *testing.T, accum *metricsdk.Accumulator, proc *onchange.Processor) map[string]int64 {
+	ctx := context.Background()
+	proc.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, proc.FinishCollection())
+
+	values := map[string]int64{}
+	require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		var v int64
+		switch agg := rec.Aggregation().(type) {
+		case aggregation.LastValue:
+			n, _, err := agg.LastValue()
+			require.NoError(t, err)
+			v = n.AsInt64()
+		}
+		values[rec.Descriptor().Name()+"/"+rec.Attributes().Encoded(attribute.DefaultEncoder())] = v
+		return nil
+	}))
+	return values
+}
+
+func TestProcessor(t *testing.T) {
+	for _, temporality := range []aggregation.TemporalitySelector{
+		aggregation.CumulativeTemporalitySelector(),
+		aggregation.DeltaTemporalitySelector(),
+	} {
+		proc := onchange.New(basic.New(processorTest.AggregatorSelector(), temporality))
+		accum := metricsdk.NewAccumulator(proc)
+		meter := sdkapi.WrapMeterImpl(accum)
+
+		limit, err := meter.AsyncInt64().Gauge("limit.lastvalue")
+		require.NoError(t, err)
+		calls, err := meter.SyncInt64().Counter("calls.sum")
+		require.NoError(t, err)
+		observed := map[string]int64{"a": 1, "b": 2}
+		require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{limit}, func(ctx context.Context) {
+			for k, v := range observed {
+				limit.Observe(ctx, v, attribute.String("k", k))
+			}
+			calls.Add(ctx, 1)
+		}))
+
+		require.Equal(t, map[string]int64{
+			"limit.lastvalue/k=a": 1,
+			"limit.lastvalue/k=b": 2,
+			"calls.sum/":          0,
+		}, collect(t, accum, proc))
+
+		// Unchanged gauges are not exported, other aggregations are.
+		require.Equal(t, map[string]int64{
+			"calls.sum/": 0,
+		}, collect(t, accum, proc))
+
+		observed["b"] = 3
+		require.Equal(t, map[string]int64{
+			"limit.lastvalue/k=b": 3,
+			"calls.sum/":          0,
+		}, collect(t, accum, proc))
+
+		// A series that stopped being observed is exported again
+		// when it reappears.
+		delete(observed, "a")
+		require.Equal(t, map[string]int64{
+			"calls.sum/": 0,
+		}, collect(t, accum, proc))
+		observed["a"] = 1
+		require.Equal(t, map[string]int64{
+			"limit.lastvalue/k=a": 1,
+			"calls.sum/":          0,
+		}, collect(t, accum, proc))
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	proc := onchange.New(
+		basic.New(processorTest.AggregatorSelector(), aggregation.CumulativeTemporalitySelector()),
+		onchange.WithHeartbeat(100*time.Millisecond),
+	)
+	accum := metricsdk.NewAccumulator(proc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	limit, err := meter.AsyncInt64().Gauge("limit.lastvalue")
+	require.NoError(t, err)
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{limit}, func(ctx context.Context) {
+		limit.Observe(ctx, 1)
+	}))
+
+	want := map[string]int64{"limit.lastvalue/": 1}
+	require.Equal(t, want, collect(t, accum, proc))
+	require.Empty(t, collect(t, accum, proc))
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, want, collect(t, accum, proc))
+}
+
+func TestFactory(t *testing.T) {
+	factory := onchange.NewFactory(basic.NewFactory(processorTest.AggregatorSelector(), aggregation.CumulativeTemporalitySelector()))
+	require.IsType(t, &onchange.Processor{}, factory.NewCheckpointer())
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package partition implements a metrics Processor component that splits
+the measurements of one instrument into several streams according to
+their attributes.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+A Partition names an instrument, a predicate over its attribute sets and
+the name of the stream that matching measurements are exported as.  The
+predicate is evaluated once per attribute set, when the SDK creates the
+record for it on the first Update, so that each partition may use its
+own kind of Aggregator, for example a histogram with different
+boundaries.  For example, to export server errors separately with finer
+buckets:
+
+	errors := partition.Partition{
+	        Instrument: "http.server.duration",
+	        Name:       "http.server.duration.errors",
+	        Match:      partition.AttributeEquals("status_class", "5xx"),
+	        Aggregator: simple.NewWithHistogramDistribution(
+	                histogram.WithExplicitBoundaries([]float64{.1, .25, .5, 1, 2, 4, 8}),
+	        ),
+	}
+	selector := partition.NewSelector(simple.NewWithHistogramDistribution(), errors)
+	cont := controller.New(
+	        partition.NewFactory(
+	                basic.NewFactory(selector, exporter),
+	                errors,
+	        ),
+	        controller.WithExporter(exporter),
+	)
+
+The Processor must be passed directly to the SDK, or be the outermost
+Checkpointer of the controller, since the SDK only detects per-attribute
+selection on the Processor it is configured with.  Processors following
+it in the pipeline see the partition streams as separate instruments and
+allocate their Aggregators through their own AggregatorSelector, which
+should therefore be the one returned by NewSelector.
+
+The first matching Partition of an instrument applies.  Measurements
+matching no Partition are exported unchanged.
+
+The same partitions can be applied by a View of the
+go.opentelemetry.io/otel/sdk/metric/processor/view package with its
+WithPartitions option, together with the other options of the View.
+*/
+package partition // import "go.opentelemetry.io/otel/sdk/metric/processor/partition"
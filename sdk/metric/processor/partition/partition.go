@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition // import "go.opentelemetry.io/otel/sdk/metric/processor/partition"
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// Partition describes a stream of the measurements of an
+	// instrument that match a predicate.
+	Partition struct {
+		// Instrument is the name of the partitioned instrument.
+		Instrument string
+
+		// Name is the name the matching measurements are
+		// exported with.
+		Name string
+
+		// Match reports whether an attribute set belongs to
+		// the partition.
+		Match func(*attribute.Set) bool
+
+		// Aggregator selects the Aggregator of the partition.
+		// When nil, the AggregatorSelector of the wrapped
+		// Checkpointer is used.
+		Aggregator export.AggregatorSelector
+	}
+
+	// Processor routes the measurements of partitioned instruments
+	// to their partition streams.
+	Processor struct {
+		export.Checkpointer
+		partitions map[string][]Partition
+
+		lock        sync.Mutex
+		descriptors map[descriptorKey]*sdkapi.Descriptor
+	}
+
+	descriptorKey struct {
+		descriptor *sdkapi.Descriptor
+		name       string
+	}
+
+	selector struct {
+		export.AggregatorSelector
+		partitions map[string]export.AggregatorSelector
+	}
+
+	factory struct {
+		factory    export.CheckpointerFactory
+		partitions []Partition
+	}
+)
+
+var _ export.Processor = &Processor{}
+var _ export.Checkpointer = &Processor{}
+var _ export.AttributeAggregatorSelector = &Processor{}
+var _ export.LibraryCheckpointerFactory = factory{}
+
+// New returns a Processor that passes data to the next stage in an
+// export pipeline, exporting the measurements matching a Partition under
+// the name of the partition.
+func New(ckpter export.Checkpointer, partitions ...Partition) *Processor {
+	byInstrument := map[string][]Partition{}
+	for _, p := range partitions {
+		byInstrument[p.Instrument] = append(byInstrument[p.Instrument], p)
+	}
+	return &Processor{
+		Checkpointer: ckpter,
+		partitions:   byInstrument,
+		descriptors:  map[descriptorKey]*sdkapi.Descriptor{},
+	}
+}
+
+// NewFactory returns a CheckpointerFactory that wraps each Checkpointer
+// produced by the given factory with the partitions.
+func NewFactory(ckptFactory export.CheckpointerFactory, partitions ...Partition) export.CheckpointerFactory {
+	return factory{
+		factory:    ckptFactory,
+		partitions: partitions,
+	}
+}
+
+func (f factory) NewCheckpointer() export.Checkpointer {
+	return New(f.factory.NewCheckpointer(), f.partitions...)
+}
+
+func (f factory) NewLibraryCheckpointer(library instrumentation.Library) export.Checkpointer {
+	return New(export.NewLibraryCheckpointer(f.factory, library), f.partitions...)
+}
+
+// NewSelector returns an AggregatorSelector that uses the Aggregator of
+// each Partition for its stream, and inner for all other instruments.
+// It is meant for the Checkpointer wrapped by the Processor.
+func NewSelector(inner export.AggregatorSelector, partitions ...Partition) export.AggregatorSelector {
+	s := selector{
+		AggregatorSelector: inner,
+		partitions:         map[string]export.AggregatorSelector{},
+	}
+	for _, p := range partitions {
+		if p.Aggregator != nil {
+			s.partitions[p.Name] = p.Aggregator
+		}
+	}
+	return s
+}
+
+func (s selector) AggregatorFor(desc *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	if sel, ok := s.partitions[desc.Name()]; ok {
+		sel.AggregatorFor(desc, aggPtrs...)
+		return
+	}
+	s.AggregatorSelector.AggregatorFor(desc, aggPtrs...)
+}
+
+// AggregatorForAttributes implements export.AttributeAggregatorSelector.
+func (p *Processor) AggregatorForAttributes(desc *sdkapi.Descriptor, attrs *attribute.Set, aggPtrs ...*aggregator.Aggregator) {
+	part, ok := p.partitionFor(desc, attrs)
+	if !ok {
+		p.Checkpointer.AggregatorFor(desc, aggPtrs...)
+		return
+	}
+	pdesc := p.descriptorFor(desc, part)
+	if part.Aggregator != nil {
+		part.Aggregator.AggregatorFor(pdesc, aggPtrs...)
+		return
+	}
+	p.Checkpointer.AggregatorFor(pdesc, aggPtrs...)
+}
+
+// Process implements export.Processor.
+func (p *Processor) Process(accum export.Accumulation) error {
+	part, ok := p.partitionFor(accum.Descriptor(), accum.Attributes())
+	if !ok {
+		return p.Checkpointer.Process(accum)
+	}
+	return p.Checkpointer.Process(
+		export.NewAccumulation(
+			p.descriptorFor(accum.Descriptor(), part),
+			accum.Attributes(),
+			accum.Aggregator(),
+		),
+	)
+}
+
+func (p *Processor) partitionFor(desc *sdkapi.Descriptor, attrs *attribute.Set) (Partition, bool) {
+	for _, part := range p.partitions[desc.Name()] {
+		if part.Match(attrs) {
+			return part, true
+		}
+	}
+	return Partition{}, false
+}
+
+// descriptorFor returns the descriptor of the partition stream of the
+// instrument described by desc.
+func (p *Processor) descriptorFor(desc *sdkapi.Descriptor, part Partition) *sdkapi.Descriptor {
+	key := descriptorKey{
+		descriptor: desc,
+		name:       part.Name,
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if pdesc, ok := p.descriptors[key]; ok {
+		return pdesc
+	}
+	pdesc := sdkapi.NewDescriptorWithAttributeKeys(
+		part.Name,
+		desc.InstrumentKind(),
+		desc.NumberKind(),
+		desc.Description(),
+		desc.Unit(),
+		desc.AttributeKeys(),
+	)
+	p.descriptors[key] = &pdesc
+	return &pdesc
+}
+
+// AttributeEquals returns a predicate matching attribute sets in which
+// the attribute key has the string value.
+func AttributeEquals(key attribute.Key, value string) func(*attribute.Set) bool {
+	return func(attrs *attribute.Set) bool {
+		v, ok := attrs.Value(key)
+		return ok && v.Type() == attribute.STRING && v.AsString() == value
+	}
+}
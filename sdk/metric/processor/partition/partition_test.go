@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partition_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/processor/partition"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+var (
+	statusClass = attribute.Key("status_class")
+
+	// The test AggregatorSelector chooses the Aggregator by name
+	// suffix, so the errors stream is aggregated as a sum while
+	// the instrument is a histogram.
+	errorsPartition = partition.Partition{
+		Instrument: "duration.histogram",
+		Name:       "duration.errors.sum",
+		Match:      partition.AttributeEquals(statusClass, "5xx"),
+		Aggregator: processorTest.AggregatorSelector(),
+	}
+	slowPartition = partition.Partition{
+		Instrument: "duration.histogram",
+		Name:       "duration.slow.histogram",
+		Match: func(attrs *attribute.Set) bool {
+			return attrs.HasValue("slow")
+		},
+	}
+)
+
+func TestPartitionProcessor(t *testing.T) {
+	ctx := context.Background()
+	parts := []partition.Partition{errorsPartition, slowPartition}
+	selector := partition.NewSelector(processorTest.AggregatorSelector(), parts...)
+	proc := partition.New(basic.New(selector, aggregation.CumulativeTemporalitySelector()), parts...)
+	accum := metricsdk.NewAccumulator(proc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	hist, err := meter.SyncFloat64().Histogram("duration.histogram")
+	require.NoError(t, err)
+	counter, err := meter.SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+
+	hist.Record(ctx, 1, statusClass.String("2xx"))
+	hist.Record(ctx, 2, statusClass.String("2xx"))
+	hist.Record(ctx, 4, statusClass.String("5xx"))
+	hist.Record(ctx, 8, statusClass.String("5xx"), attribute.Bool("slow", true))
+	hist.Record(ctx, 16, statusClass.String("4xx"), attribute.Bool("slow", true))
+	counter.Add(ctx, 1, statusClass.String("5xx"))
+
+	proc.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, proc.FinishCollection())
+
+	kinds := map[string]aggregation.Kind{}
+	require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		kinds[rec.Descriptor().Name()] = rec.Aggregation().Kind()
+		return nil
+	}))
+
+	exporter := processorTest.New(aggregation.CumulativeTemporalitySelector(), attribute.DefaultEncoder())
+	require.NoError(t, exporter.Export(ctx, resource.Empty(), processorTest.OneInstrumentationLibraryReader(
+		instrumentation.Library{Name: "test"}, proc.Reader(),
+	)))
+	require.EqualValues(t, map[string]float64{
+		"duration.histogram/status_class=2xx/":                3,
+		"duration.errors.sum/status_class=5xx/":               4,
+		"duration.errors.sum/slow=true,status_class=5xx/":     8,
+		"duration.slow.histogram/slow=true,status_class=4xx/": 16,
+		"requests.sum/status_class=5xx/":                      1,
+	}, exporter.Values())
+
+	require.Equal(t, aggregation.HistogramKind, kinds["duration.histogram"])
+	require.Equal(t, aggregation.SumKind, kinds["duration.errors.sum"])
+	require.Equal(t, aggregation.HistogramKind, kinds["duration.slow.histogram"])
+	require.Equal(t, aggregation.SumKind, kinds["requests.sum"])
+}
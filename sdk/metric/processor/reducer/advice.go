@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reducer // import "go.opentelemetry.io/otel/sdk/metric/processor/reducer"
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// adviceConfig contains the options for configuring an advice filter
+// selector.
+type adviceConfig struct {
+	// fullCardinality contains the names of instruments whose
+	// attributes are kept regardless of advice.
+	fullCardinality map[string]struct{}
+}
+
+// AdviceOption configures the AttributeFilterSelector returned by
+// NewAdviceFilterSelector.
+type AdviceOption interface {
+	applyAdvice(adviceConfig) adviceConfig
+}
+
+type fullCardinalityOption []string
+
+func (o fullCardinalityOption) applyAdvice(cfg adviceConfig) adviceConfig {
+	for _, name := range o {
+		cfg.fullCardinality[name] = struct{}{}
+	}
+	return cfg
+}
+
+// WithFullCardinality exempts the named instruments from advice
+// filtering, keeping all of their attributes.
+func WithFullCardinality(names ...string) AdviceOption {
+	return fullCardinalityOption(names)
+}
+
+type adviceFilterSelector struct {
+	config adviceConfig
+
+	lock    sync.Mutex
+	filters map[*sdkapi.Descriptor]attribute.Filter
+}
+
+var _ AttributeFilterSelector = &adviceFilterSelector{}
+
+// NewAdviceFilterSelector returns an AttributeFilterSelector that keeps
+// only the attribute keys an instrument recommends through
+// instrument.WithAttributeKeys.  Instruments that recommend no keys keep
+// all of their attributes, their filter is nil.
+//
+// The basic controller filters the attributes of its instruments with
+// this selector by default, see its WithAttributeAdvice option.
+func NewAdviceFilterSelector(opts ...AdviceOption) AttributeFilterSelector {
+	cfg := adviceConfig{
+		fullCardinality: map[string]struct{}{},
+	}
+	for _, opt := range opts {
+		cfg = opt.applyAdvice(cfg)
+	}
+	return &adviceFilterSelector{
+		config:  cfg,
+		filters: map[*sdkapi.Descriptor]attribute.Filter{},
+	}
+}
+
+// AttributeFilterFor implements AttributeFilterSelector.
+func (s *adviceFilterSelector) AttributeFilterFor(desc *sdkapi.Descriptor) attribute.Filter {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if f, ok := s.filters[desc]; ok {
+		return f
+	}
+	f := s.newFilter(desc)
+	s.filters[desc] = f
+	return f
+}
+
+func (s *adviceFilterSelector) newFilter(desc *sdkapi.Descriptor) attribute.Filter {
+	keys := desc.AttributeKeys()
+	if _, ok := s.config.fullCardinality[desc.Name()]; ok || keys == nil {
+		return nil
+	}
+	allowed := make(map[attribute.Key]struct{}, len(keys))
+	for _, k := range keys {
+		allowed[k] = struct{}{}
+	}
+	return func(kv attribute.KeyValue) bool {
+		_, ok := allowed[kv.Key]
+		return ok
+	}
+}
@@ -49,13 +49,13 @@ func New(filterSelector AttributeFilterSelector, ckpter export.Checkpointer) *Pr
 
 // Process implements export.Processor.
 func (p *Processor) Process(accum export.Accumulation) error {
+	filter := p.filterSelector.AttributeFilterFor(accum.Descriptor())
+	if filter == nil {
+		return p.Checkpointer.Process(accum)
+	}
 	// Note: the removed attributes are returned and ignored here.
 	// Conceivably these inputs could be useful to a sampler.
-	reduced, _ := accum.Attributes().Filter(
-		p.filterSelector.AttributeFilterFor(
-			accum.Descriptor(),
-		),
-	)
+	reduced, _ := accum.Attributes().Filter(filter)
 	return p.Checkpointer.Process(
 		export.NewAccumulation(
 			accum.Descriptor(),
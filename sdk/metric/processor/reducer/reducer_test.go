@@ -116,3 +116,47 @@ func TestFilterBasicProcessor(t *testing.T) {
 		"observer.sum/A=1,C=3/R=V": 20,
 	}, exporter.Values())
 }
+
+func generateAdvisedData(t *testing.T, impl sdkapi.MeterImpl) {
+	ctx := context.Background()
+	meter := sdkapi.WrapMeterImpl(impl)
+
+	advised, err := meter.SyncFloat64().Counter("advised.sum", instrument.WithAttributeKeys("A", "C"))
+	require.NoError(t, err)
+	advised.Add(ctx, 100, kvs1...)
+	advised.Add(ctx, 100, kvs2...)
+
+	full, err := meter.SyncFloat64().Counter("full.sum", instrument.WithAttributeKeys("A"))
+	require.NoError(t, err)
+	full.Add(ctx, 100, kvs1...)
+	full.Add(ctx, 100, kvs2...)
+
+	plain, err := meter.SyncFloat64().Counter("plain.sum")
+	require.NoError(t, err)
+	plain.Add(ctx, 100, kvs1...)
+	plain.Add(ctx, 100, kvs2...)
+}
+
+func TestAdviceFilterProcessor(t *testing.T) {
+	testProc := processorTest.NewProcessor(
+		processorTest.AggregatorSelector(),
+		attribute.DefaultEncoder(),
+	)
+	accum := metricsdk.NewAccumulator(
+		reducer.New(
+			reducer.NewAdviceFilterSelector(reducer.WithFullCardinality("full.sum")),
+			processorTest.NewCheckpointer(testProc),
+		),
+	)
+	generateAdvisedData(t, accum)
+
+	accum.Collect(context.Background())
+
+	require.EqualValues(t, map[string]float64{
+		"advised.sum/A=1,C=3/":   200,
+		"full.sum/A=1,B=0,C=3/":  100,
+		"full.sum/A=1,B=2,C=3/":  100,
+		"plain.sum/A=1,B=0,C=3/": 100,
+		"plain.sum/A=1,B=2,C=3/": 100,
+	}, testProc.Values())
+}
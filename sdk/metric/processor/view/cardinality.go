@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// OverflowKey is the key of the attribute of the series of a stream that
+// aggregates the measurements of the attribute sets beyond its
+// cardinality limit, see WithCardinalityLimit.
+const OverflowKey = attribute.Key("otel.metric.overflow")
+
+// overflowSet is the attribute set of overflow series.
+var overflowSet = attribute.NewSet(OverflowKey.Bool(true))
+
+// cardinalityLimit holds the attribute sets of a stream with a
+// cardinality limit.
+type cardinalityLimit struct {
+	limit int
+
+	lock       sync.Mutex
+	sets       map[attribute.Distinct]struct{}
+	overflowed bool
+}
+
+// newCardinalityLimit returns the state of a stream limited to n
+// attribute sets, nil when n is zero.
+func newCardinalityLimit(n int) *cardinalityLimit {
+	if n == 0 {
+		return nil
+	}
+	return &cardinalityLimit{
+		limit: n,
+		sets:  map[attribute.Distinct]struct{}{},
+	}
+}
+
+// apply returns attrs, or the overflow set when attrs would exceed the
+// limit of the stream described by desc.
+func (c *cardinalityLimit) apply(desc *sdkapi.Descriptor, attrs *attribute.Set) *attribute.Set {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.sets[attrs.Equivalent()]; ok {
+		return attrs
+	}
+	if len(c.sets) < c.limit {
+		c.sets[attrs.Equivalent()] = struct{}{}
+		return attrs
+	}
+	if !c.overflowed {
+		c.overflowed = true
+		otel.Handle(fmt.Errorf("stream %s reached its cardinality limit of %d attribute sets", desc.Name(), c.limit))
+	}
+	return &overflowSet
+}
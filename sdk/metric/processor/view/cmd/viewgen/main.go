@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Viewgen generates strongly-typed builders of views for the metrics of
+// semantic conventions.
+//
+// It reads metric groups in the YAML format of the semantic conventions:
+//
+//	groups:
+//	  - id: metric.http.server.duration
+//	    type: metric
+//	    metric_name: http.server.duration
+//	    brief: "Measures the duration of inbound HTTP requests."
+//	    instrument: histogram
+//	    unit: "ms"
+//	    attributes:
+//	      - ref: http.method
+//
+// and writes a Go file with, for each metric, a function returning a
+// builder of views of the metric, such as HTTPServerDuration, and a
+// type of the attribute keys of the metric, so that
+//
+//	v, err := semconvviews.HTTPServerDuration().
+//	        WithBuckets(.005, .01, .05, .1, .5, 1, 5).
+//	        WithAttributes(semconvviews.HTTPServerDurationHTTPMethod).
+//	        View()
+//
+// fails to compile when the metric, one of its attributes or its
+// histogram buckets are misspelled.  Usage:
+//
+//	viewgen -input metrics.yaml -output views.go -package semconvviews
+package main // import "go.opentelemetry.io/otel/sdk/metric/processor/view/cmd/viewgen"
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	input  = flag.String("input", "", "semantic conventions YAML file")
+	output = flag.String("output", "", "generated Go file, standard output if empty")
+	pkg    = flag.String("package", "semconvviews", "package of the generated file")
+)
+
+type (
+	// document is the structure of semantic conventions files.  Groups
+	// other than metrics are ignored.
+	document struct {
+		Groups []group `yaml:"groups"`
+	}
+
+	group struct {
+		ID         string         `yaml:"id"`
+		Type       string         `yaml:"type"`
+		Prefix     string         `yaml:"prefix"`
+		MetricName string         `yaml:"metric_name"`
+		Brief      string         `yaml:"brief"`
+		Instrument string         `yaml:"instrument"`
+		Unit       string         `yaml:"unit"`
+		Attributes []attributeRef `yaml:"attributes"`
+	}
+
+	attributeRef struct {
+		ID  string `yaml:"id"`
+		Ref string `yaml:"ref"`
+	}
+
+	// Metric is a metric of the generated file.
+	Metric struct {
+		Name       string
+		GoName     string
+		Brief      string
+		Instrument string
+		Unit       string
+		Attributes []Attribute
+	}
+
+	// Attribute is an attribute key of a Metric.
+	Attribute struct {
+		Key    string
+		GoName string
+	}
+)
+
+// instruments are the instruments of the semantic conventions.
+var instruments = map[string]bool{
+	"counter":       true,
+	"updowncounter": true,
+	"histogram":     true,
+	"gauge":         true,
+}
+
+// initialisms are the name parts written in upper case in Go names.
+var initialisms = map[string]bool{
+	"api": true, "cpu": true, "db": true, "dns": true, "gc": true,
+	"http": true, "id": true, "io": true, "ip": true, "jvm": true,
+	"os": true, "rpc": true, "sql": true, "tcp": true, "tls": true,
+	"udp": true, "uid": true, "uri": true, "url": true,
+}
+
+// goName returns the exported Go name of a dotted name such as
+// "http.server.duration", "HTTPServerDuration".
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if initialisms[part] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		rs := []rune(part)
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+	return b.String()
+}
+
+// metrics returns the metrics of the document, sorted by name.
+func metrics(doc document) ([]Metric, error) {
+	var ms []Metric
+	seen := map[string]string{}
+	for _, g := range doc.Groups {
+		if g.Type != "metric" {
+			continue
+		}
+		if g.MetricName == "" {
+			return nil, fmt.Errorf("group %q: missing metric_name", g.ID)
+		}
+		if !instruments[g.Instrument] {
+			return nil, fmt.Errorf("metric %q: unknown instrument %q", g.MetricName, g.Instrument)
+		}
+		m := Metric{
+			Name:       g.MetricName,
+			GoName:     goName(g.MetricName),
+			Brief:      strings.Join(strings.Fields(g.Brief), " "),
+			Instrument: g.Instrument,
+			Unit:       g.Unit,
+		}
+		if other, ok := seen[m.GoName]; ok {
+			return nil, fmt.Errorf("metrics %q and %q have the same Go name %s", other, m.Name, m.GoName)
+		}
+		seen[m.GoName] = m.Name
+		keys := map[string]bool{}
+		for _, a := range g.Attributes {
+			key := a.Ref
+			if key == "" && a.ID != "" {
+				key = a.ID
+				if g.Prefix != "" {
+					key = g.Prefix + "." + a.ID
+				}
+			}
+			if key == "" {
+				return nil, fmt.Errorf("metric %q: attribute without id or ref", m.Name)
+			}
+			if keys[key] {
+				continue
+			}
+			keys[key] = true
+			m.Attributes = append(m.Attributes, Attribute{Key: key, GoName: m.GoName + goName(key)})
+		}
+		sort.Slice(m.Attributes, func(i, j int) bool { return m.Attributes[i].Key < m.Attributes[j].Key })
+		ms = append(ms, m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Name < ms[j].Name })
+	return ms, nil
+}
+
+// hasHistogram returns true if a metric of ms is a histogram.
+func hasHistogram(ms []Metric) bool {
+	for _, m := range ms {
+		if m.Instrument == "histogram" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAttributes returns true if a metric of ms has attributes.
+func hasAttributes(ms []Metric) bool {
+	for _, m := range ms {
+		if len(m.Attributes) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// generate returns the formatted Go source of the package named pkg
+// with the view builders of the metrics of the YAML document data.
+func generate(data []byte, pkg string) ([]byte, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	ms, err := metrics(doc)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct {
+		Package       string
+		Metrics       []Metric
+		HasHistogram  bool
+		HasAttributes bool
+	}{pkg, ms, hasHistogram(ms), hasAttributes(ms)}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func main() {
+	flag.Parse()
+	if *input == "" {
+		log.Fatal("viewgen: -input is required")
+	}
+	data, err := os.ReadFile(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(data, *pkg)
+	if err != nil {
+		log.Fatalf("viewgen: %s: %v", *input, err)
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*output, src, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerated checks that the generated semconvviews package is up to
+// date with its metrics.yaml.
+func TestGenerated(t *testing.T) {
+	data, err := os.ReadFile("../../semconvviews/metrics.yaml")
+	require.NoError(t, err)
+	want, err := os.ReadFile("../../semconvviews/views.go")
+	require.NoError(t, err)
+
+	got, err := generate(data, "semconvviews")
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got), "run go generate in semconvviews")
+}
+
+func TestGoName(t *testing.T) {
+	for name, want := range map[string]string{
+		"http.server.duration":        "HTTPServerDuration",
+		"http.server.active_requests": "HTTPServerActiveRequests",
+		"process.runtime.jvm.gc.time": "ProcessRuntimeJVMGCTime",
+		"db.client.connections.usage": "DBClientConnectionsUsage",
+	} {
+		assert.Equal(t, want, goName(name))
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, doc := range []string{
+		`groups: [{id: m, type: metric, instrument: counter}]`,
+		`groups: [{id: m, type: metric, metric_name: m, instrument: meter}]`,
+		`groups: [{id: m, type: metric, metric_name: m, instrument: counter, attributes: [{brief: b}]}]`,
+		`groups: [{type: metric, metric_name: a.b, instrument: counter}, {type: metric, metric_name: a_b, instrument: gauge}]`,
+		`groups: {`,
+	} {
+		_, err := generate([]byte(doc), "p")
+		assert.Error(t, err, doc)
+	}
+}
+
+func TestGeneratePrefixedAttributes(t *testing.T) {
+	src, err := generate([]byte(`
+groups:
+  - id: attributes.queue
+    type: attribute_group
+    prefix: queue
+  - id: metric.queue.depth
+    type: metric
+    prefix: queue
+    metric_name: queue.depth
+    instrument: gauge
+    attributes:
+      - id: name
+      - ref: net.peer.name
+`), "queueviews")
+	require.NoError(t, err)
+	assert.Contains(t, string(src), `QueueDepthQueueName   QueueDepthAttribute = "queue.name"`)
+	assert.Contains(t, string(src), `QueueDepthNetPeerName QueueDepthAttribute = "net.peer.name"`)
+	assert.NotContains(t, string(src), "WithBuckets")
+	assert.NotContains(t, string(src), "histogram")
+}
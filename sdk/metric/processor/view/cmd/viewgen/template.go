@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "text/template"
+
+var tmpl = template.Must(template.New("views").Parse(`// Code generated by viewgen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .HasAttributes}}
+	"go.opentelemetry.io/otel/attribute"
+{{- end}}
+{{- if .HasHistogram}}
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+{{- end}}
+	"go.opentelemetry.io/otel/sdk/metric/processor/view"
+{{- if .HasHistogram}}
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+{{- end}}
+)
+
+// builder holds the options of a view of one instrument.  Its methods
+// return copies, so that a builder can be shared by several views.
+type builder struct {
+	opts []view.Option
+}
+
+func newBuilder(name string) builder {
+	return builder{opts: []view.Option{view.WithInstrumentName(name)}}
+}
+
+func (b builder) with(opt view.Option) builder {
+	opts := make([]view.Option, len(b.opts), len(b.opts)+1)
+	copy(opts, b.opts)
+	return builder{opts: append(opts, opt)}
+}
+{{range $m := .Metrics}}
+{{- if .Attributes}}
+// {{.GoName}}Attribute is an attribute key of the {{.Name}} metric.
+type {{.GoName}}Attribute attribute.Key
+
+// The attribute keys of the {{.Name}} metric.
+const (
+{{- range .Attributes}}
+	{{.GoName}} {{$m.GoName}}Attribute = "{{.Key}}"
+{{- end}}
+)
+{{end}}
+// {{.GoName}}View builds a view of the {{.Name}} {{.Instrument}}.
+type {{.GoName}}View struct {
+	b builder
+}
+
+// {{.GoName}} returns a builder of a view of the {{.Name}}
+// {{.Instrument}}{{if .Unit}}, measured in {{.Unit}}{{end}}.
+{{- if .Brief}}
+//
+// {{.Brief}}
+{{- end}}
+func {{.GoName}}() {{.GoName}}View {
+	return {{.GoName}}View{newBuilder("{{.Name}}")}
+}
+
+// WithName returns a copy of v exporting the stream as name.
+func (v {{.GoName}}View) WithName(name string) {{.GoName}}View {
+	return {{.GoName}}View{v.b.with(view.WithName(name))}
+}
+
+// WithDescription returns a copy of v exporting the stream with the
+// given description.
+func (v {{.GoName}}View) WithDescription(description string) {{.GoName}}View {
+	return {{.GoName}}View{v.b.with(view.WithDescription(description))}
+}
+{{if .Attributes}}
+// WithAttributes returns a copy of v keeping only the given attributes.
+func (v {{.GoName}}View) WithAttributes(keys ...{{.GoName}}Attribute) {{.GoName}}View {
+	ks := make([]attribute.Key, len(keys))
+	for i, k := range keys {
+		ks[i] = attribute.Key(k)
+	}
+	return {{.GoName}}View{v.b.with(view.WithAttributeKeys(ks...))}
+}
+{{end}}
+{{- if eq .Instrument "histogram"}}
+// WithBuckets returns a copy of v aggregating the histogram into
+// buckets with the given boundaries.
+func (v {{.GoName}}View) WithBuckets(boundaries ...float64) {{.GoName}}View {
+	return {{.GoName}}View{v.b.with(view.WithAggregatorSelector(
+		simple.NewWithHistogramDistribution(histogram.WithExplicitBoundaries(boundaries)),
+	))}
+}
+{{end}}
+// WithDrop returns a copy of v dropping the stream.
+func (v {{.GoName}}View) WithDrop() {{.GoName}}View {
+	return {{.GoName}}View{v.b.with(view.WithDrop())}
+}
+
+// View returns the view, or an error wrapping view.ErrInvalidView.
+func (v {{.GoName}}View) View() (view.View, error) {
+	return view.New(v.b.opts...)
+}
+{{end -}}
+`))
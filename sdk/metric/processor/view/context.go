@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"context"
+	"runtime/pprof"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// ContextAttributes returns the pprof labels and baggage members of ctx
+// selected by the View of the instrument described by desc, see
+// WithPprofLabels and WithBaggageKeys.  It implements the
+// ContextAttributes interface of the go.opentelemetry.io/otel/sdk/metric
+// package, so that the Registry can be passed to its
+// WithContextAttributes option:
+//
+//	cont := controller.New(
+//	        registry.Factory(processor.NewFactory(selector, exporter)),
+//	        controller.WithExporter(exporter),
+//	        controller.WithContextAttributes(registry),
+//	)
+func (r *Registry) ContextAttributes(ctx context.Context, desc *sdkapi.Descriptor) []attribute.KeyValue {
+	e := r.contextEntry(desc)
+	if e == nil {
+		return nil
+	}
+	var kvs []attribute.KeyValue
+	if e.view.pprofLabels != nil {
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			if _, ok := e.view.pprofLabels[key]; ok || len(e.view.pprofLabels) == 0 {
+				kvs = append(kvs, attribute.String(key, value))
+			}
+			return true
+		})
+	}
+	if len(e.view.baggageKeys) != 0 {
+		bag := baggage.FromContext(ctx)
+		for _, key := range e.view.baggageKeys {
+			if m := bag.Member(key); m.Key() != "" {
+				kvs = append(kvs, attribute.String(key, m.Value()))
+			}
+		}
+	}
+	return kvs
+}
+
+// contextEntry returns the entry of the View of the instrument described
+// by desc when it adds attributes from the context, or nil.  The entries
+// are cached per version of the Registry, since this is called for every
+// measurement.
+func (r *Registry) contextEntry(desc *sdkapi.Descriptor) *entry {
+	r.contextLock.Lock()
+	defer r.contextLock.Unlock()
+
+	if version := r.currentVersion(); version != r.contextVersion || r.contextEntries == nil {
+		r.contextEntries = map[*sdkapi.Descriptor]*entry{}
+		r.contextVersion = version
+		r.contextEnabled = r.hasContextAttributes()
+	}
+	if !r.contextEnabled {
+		return nil
+	}
+	e, ok := r.contextEntries[desc]
+	if !ok {
+		e = r.match(instrumentation.Library{}, desc)
+		if e != nil && !e.view.addsContextAttributes() {
+			e = nil
+		}
+		r.contextEntries[desc] = e
+	}
+	return e
+}
+
+// hasContextAttributes returns true if a View of the Registry adds
+// attributes from the context.
+func (r *Registry) hasContextAttributes() bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, e := range r.entries {
+		if e.view.addsContextAttributes() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package view implements a metrics Processor component that applies views
+to the instruments of the SDK: renaming their streams, choosing their
+Aggregators, or dropping them.
+
+This package is currently in a pre-GA phase. Backwards incompatible changes
+may be introduced in subsequent minor version releases as we work to track the
+evolving OpenTelemetry specification and user feedback.
+
+A View selects instruments, by exact name, by a shell-style pattern such
+as `rpc.*.duration`, by a regular expression matched against the name
+by unit or by instrument kind, possibly excluding some names with
+WithInstrumentNameExcept, and describes how the stream of each selected instrument is exported.
+For example, to re-bucket every HTTP server
+histogram and drop a noisy instrument:
+
+	buckets, err := view.New(
+	        view.WithInstrumentNameRegexp(regexp.MustCompile(`^http\.server\..*`)),
+	        view.WithAggregatorSelector(simple.NewWithHistogramDistribution(
+	                histogram.WithExplicitBoundaries([]float64{.005, .01, .05, .1, .5, 1, 5}),
+	        )),
+	)
+	if err != nil {
+	        return err
+	}
+	noisy, err := view.New(view.WithInstrumentName("runtime.gc.pause"), view.WithDrop())
+	if err != nil {
+	        return err
+	}
+	views := []view.View{buckets, noisy}
+	factory, err := view.NewFactory(
+	        basic.NewFactory(view.NewSelector(simple.NewWithHistogramDistribution(), views...), exporter),
+	        views...,
+	)
+	if err != nil {
+	        return err
+	}
+	cont := controller.New(factory, controller.WithExporter(exporter))
+
+WithHistogramPreset uses the boundaries of a named preset of the
+histogram package instead, such as histogram.PresetHTTPLatencyMs.
+
+New rejects a View whose options contradict each other, and NewProcessor
+and NewFactory reject views that give the same name to the streams of
+different instruments, unless the views export them identically.  A View
+selecting several instruments may rename them with a template such as
+"{scope}.{instrument}", see WithName.  Collisions that only appear once
+instruments are created, such as a stream renamed to the name of another
+instrument, are reported to the global error handler and listed by
+Registry.Conflicts.  Streams of different instruments with the same
+name, instrument kind, number kind, description, unit, attribute keys
+and aggregation are not collisions: they are merged into one stream.
+
+Views may keep only some attributes of the streams with WithKeysInclude,
+or remove some, such as a high-cardinality "user.id", with
+WithKeysExclude, and truncate long string values, such as URLs, with
+WithAttributeValueLengthLimit, and bound the attribute sets of streams
+with WithCardinalityLimit.  WithAttributeHashing replaces the values
+of identifiers such as user IDs by their salted hashes, so that streams
+can still be joined on them without exporting them.  WithIncrementHistogram exports a
+Counter as a histogram of its increments, such as the sizes of the
+writes counted by a bytes Counter, and WithRate exports Counters and
+CounterObservers as per-second rates.  Views may also rename the attributes of the streams, for example to
+export "http.status_code" as "status" with
+WithAttributeRename(map[string]string{"http.status_code": "status"}),
+add constant attributes such as the owning team with
+WithExtraAttributes(attribute.String("team", "payments")), and drop the
+measurements of some attribute sets, such as the requests
+of health checks, with WithDropMeasurements.  The SDK does not aggregate
+dropped measurements.  WithPartitions splits the stream of an instrument
+by attribute values instead, with the partitions of the
+go.opentelemetry.io/otel/sdk/metric/processor/partition package,
+exporting for example GET requests and the other requests as two streams
+with different names and Aggregators.
+
+WithPprofLabels adds the pprof labels of the goroutine recording a
+measurement to its attributes, so that metrics can be broken down by the
+same dimensions as CPU profiles, and WithBaggageKeys adds baggage
+members such as the tenant of a request.  The SDK reads the labels and
+the baggage from the context of measurements when the Registry is
+passed to its WithContextAttributes option.
+
+WithUnitConversion exports streams in a different unit, for example
+seconds rather than milliseconds, scaling measurements as they are
+recorded, while WithUnit and WithDescription only replace the unit and
+description of the exported streams.  WithTransform applies any function to the measurements before
+they are aggregated, for example to clamp them to a range.
+
+The Processor matches each instrument against the views once, when the
+SDK selects its Aggregators, and caches the result for the descriptor.
+The first matching View of an instrument applies; instruments matching
+no View are exported unchanged, unless the views end with
+DropUnmatched, which drops them so that the views act as an allow-list.  Processors following it in the pipeline
+see the renamed streams and allocate their Aggregators through their own
+AggregatorSelector, which should therefore be the one returned by
+NewSelector.
+
+Views may select the instruments of some instrumentation libraries only,
+by name, schema URL or a range of versions, for example to aggregate the
+instruments of old releases of a library differently:
+
+	legacy, err := view.New(
+	        view.WithLibraryName("go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"),
+	        view.WithLibraryVersionRange("", "v0.30.0"),
+	        view.WithInstrumentNameGlob("http.server.*"),
+	        view.WithAggregatorSelector(simple.NewWithInexpensiveDistribution()),
+	)
+
+The CheckpointerFactory returned by NewFactory gives the controller one
+Processor per library.  An AggregatorSelector returned by NewSelector
+does not know the library of a stream and ignores these views, so a
+pipeline using them to choose Aggregators takes its Processors and
+AggregatorSelector from one Registry, as described below.
+
+Views can also change while the process runs.  A Registry provides the
+Processor, CheckpointerFactory and AggregatorSelector of a pipeline from
+one list of views, to which Register adds a View until the returned
+function is called:
+
+	registry, err := view.NewRegistry(views...)
+	if err != nil {
+	        return err
+	}
+	cont := controller.New(
+	        registry.Factory(basic.NewFactory(registry.Selector(simple.NewWithInexpensiveDistribution()), exporter)),
+	        controller.WithExporter(exporter),
+	)
+	...
+	unregister, err := registry.Register(debugHistogram)
+	if err != nil {
+	        return err
+	}
+	defer unregister()
+
+Each controller exports through its own pipeline, and the views of a
+Registry apply only to the pipelines built from it.  A program exporting
+to several destinations scopes views to one of them by giving each
+controller its own Registry, for example full-fidelity histograms to
+OTLP and coarse sums to Prometheus:
+
+	otlpViews, err := view.NewRegistry(detailedHistograms)
+	if err != nil {
+	        return err
+	}
+	promViews, err := view.NewRegistry(coarseSums)
+	if err != nil {
+	        return err
+	}
+	otlpCont := controller.New(
+	        otlpViews.Factory(basic.NewFactory(otlpViews.Selector(simple.NewWithHistogramDistribution()), otlpExporter)),
+	        controller.WithExporter(otlpExporter),
+	)
+	promCont := controller.New(
+	        promViews.Factory(basic.NewFactory(promViews.Selector(simple.NewWithInexpensiveDistribution()), promExporter)),
+	)
+
+A registered View takes precedence over the views already held.  The
+instruments that match a different View after a change are compiled
+again at the next collection, their streams start over, and the SDK
+replaces their records so that later measurements use the new
+Aggregators.
+
+Processor.DebugString describes the stream compiled for an instrument
+and the View that applies to it, for debugging output.
+
+Views may also be managed as configuration rather than code.  FromFile
+reads them from a YAML or JSON document, see Parse for its fields:
+
+	views, err := view.FromFile("/etc/otel/views.yaml")
+	if err != nil {
+	        return err
+	}
+	registry, err := view.NewRegistry(views...)
+*/
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
@@ -0,0 +1,347 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/tdigest"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// document is the structure of the files read by FromFile.
+	document struct {
+		Views         []viewDocument `yaml:"views"`
+		DropUnmatched bool           `yaml:"drop_unmatched"`
+	}
+
+	viewDocument struct {
+		Instrument     string            `yaml:"instrument"`
+		Glob           string            `yaml:"glob"`
+		Regexp         string            `yaml:"regexp"`
+		Except         []string          `yaml:"except"`
+		InstrumentUnit string            `yaml:"instrument_unit"`
+		Kinds          []string          `yaml:"kinds"`
+		Library        libraryDocument   `yaml:"library"`
+		Name           string            `yaml:"name"`
+		Description    string            `yaml:"description"`
+		Unit           string            `yaml:"unit"`
+		Aggregation    string            `yaml:"aggregation"`
+		Boundaries     []float64         `yaml:"boundaries"`
+		Preset         string            `yaml:"preset"`
+		Quantiles      []float64         `yaml:"quantiles"`
+		Accuracy       float64           `yaml:"relative_accuracy"`
+		Increments     bool              `yaml:"increment_histogram"`
+		Rate           bool              `yaml:"rate"`
+		Keys           []string          `yaml:"keys"`
+		ExcludeKeys    []string          `yaml:"exclude_keys"`
+		MaxValueLength int               `yaml:"max_value_length"`
+		Cardinality    int               `yaml:"cardinality_limit"`
+		Hash           *hashDocument     `yaml:"hash"`
+		PprofLabels    []string          `yaml:"pprof_labels"`
+		BaggageKeys    []string          `yaml:"baggage_keys"`
+		Rename         map[string]string `yaml:"rename"`
+		Attributes     map[string]string `yaml:"attributes"`
+		Drop           bool              `yaml:"drop"`
+	}
+
+	libraryDocument struct {
+		Name       string `yaml:"name"`
+		MinVersion string `yaml:"min_version"`
+		MaxVersion string `yaml:"max_version"`
+		SchemaURL  string `yaml:"schema_url"`
+	}
+
+	hashDocument struct {
+		Keys   []string `yaml:"keys"`
+		Salt   string   `yaml:"salt"`
+		Length int      `yaml:"length"`
+	}
+
+	// fixedSelector is the AggregatorSelector of the aggregation
+	// named in a document, used for every selected instrument.
+	fixedSelector struct {
+		aggregation string
+		boundaries  []float64
+		quantiles   []float64
+		accuracy    float64
+	}
+)
+
+var _ export.AggregatorSelector = fixedSelector{}
+
+// instrumentKinds are the names of the instrument kinds in documents.
+var instrumentKinds = map[string]sdkapi.InstrumentKind{
+	"histogram":                sdkapi.HistogramInstrumentKind,
+	"gauge_observer":           sdkapi.GaugeObserverInstrumentKind,
+	"counter":                  sdkapi.CounterInstrumentKind,
+	"up_down_counter":          sdkapi.UpDownCounterInstrumentKind,
+	"counter_observer":         sdkapi.CounterObserverInstrumentKind,
+	"up_down_counter_observer": sdkapi.UpDownCounterObserverInstrumentKind,
+}
+
+// FromFile returns the views described by the YAML or JSON document in
+// the named file, see Parse.
+func FromFile(path string) ([]View, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	views, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return views, nil
+}
+
+// Parse returns the views described by a YAML or JSON document, so that
+// the views of a program can be managed as configuration:
+//
+//	views:
+//	  - glob: "http.server.*"
+//	    except: ["http.server.active_requests"]
+//	    aggregation: histogram
+//	    boundaries: [0.005, 0.01, 0.05, 0.1, 0.5, 1, 5]
+//	    keys: [http.method, http.status_code]
+//	    rename: {http.status_code: status}
+//	  - instrument: runtime.gc.pause
+//	    drop: true
+//
+// The fields of a view correspond to the Options of this package:
+// instrument, glob, regexp, except, instrument_unit and kinds select
+// instruments by name, unit or kind ("counter", "up_down_counter",
+// "histogram", "counter_observer", "up_down_counter_observer" or
+// "gauge_observer"), and library by the name, min_version,
+// max_version and schema_url of their instrumentation library.  name,
+// description and unit set those of the streams, keys keeps some
+// attributes, exclude_keys removes some, max_value_length truncates
+// string values, cardinality_limit limits the attribute sets of
+// streams, hash hashes the values of its keys with its salt and
+// length, pprof_labels adds pprof labels, baggage_keys adds baggage
+// members, rename renames attributes and attributes adds string
+// attributes.  aggregation is one of "sum", "lastvalue",
+// "minmaxsumcount", "histogram", with boundaries or the name of a
+// preset of the histogram package, such as "http-latency-ms", and
+// "exponential", a base-2 exponential histogram, "tdigest", with the
+// quantiles it reports, and "ddsketch", with the quantiles it reports and
+// its relative_accuracy, and applies to every selected instrument,
+// increment_histogram: true exports Counters as histograms of their
+// increments, with boundaries, rate: true exports Counters and
+// CounterObservers as per-second rates, and drop drops them.  With drop_unmatched: true at the top level of the
+// document, a DropUnmatched View follows the others, so that only the
+// instruments they select are exported.  Unknown fields are rejected,
+// and errors of New are returned wrapping ErrInvalidView.
+func Parse(data []byte) ([]View, error) {
+	var doc document
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	views := make([]View, 0, len(doc.Views))
+	for i, vd := range doc.Views {
+		opts, err := vd.options()
+		if err == nil {
+			var v View
+			v, err = New(opts...)
+			views = append(views, v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("view %d: %w", i, err)
+		}
+	}
+	if doc.DropUnmatched {
+		views = append(views, DropUnmatched())
+	}
+	return views, nil
+}
+
+// options returns the Options of the View described by vd.
+func (vd viewDocument) options() ([]Option, error) {
+	var opts []Option
+	add := func(set bool, opt Option) {
+		if set {
+			opts = append(opts, opt)
+		}
+	}
+	add(vd.Instrument != "", WithInstrumentName(vd.Instrument))
+	add(vd.Glob != "", WithInstrumentNameGlob(vd.Glob))
+	if vd.Regexp != "" {
+		re, err := regexp.Compile(vd.Regexp)
+		if err != nil {
+			return nil, fmt.Errorf("%w: instrument name regexp: %v", ErrInvalidView, err)
+		}
+		opts = append(opts, WithInstrumentNameRegexp(re))
+	}
+	add(vd.Except != nil, WithInstrumentNameExcept(vd.Except...))
+	add(vd.InstrumentUnit != "", WithInstrumentUnit(unit.Unit(vd.InstrumentUnit)))
+	if vd.Kinds != nil {
+		kinds := make([]sdkapi.InstrumentKind, len(vd.Kinds))
+		for i, name := range vd.Kinds {
+			kind, ok := instrumentKinds[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown instrument kind %q", ErrInvalidView, name)
+			}
+			kinds[i] = kind
+		}
+		opts = append(opts, WithInstrumentKind(kinds...))
+	}
+	add(vd.Library.Name != "", WithLibraryName(vd.Library.Name))
+	add(vd.Library.MinVersion != "" || vd.Library.MaxVersion != "",
+		WithLibraryVersionRange(vd.Library.MinVersion, vd.Library.MaxVersion))
+	add(vd.Library.SchemaURL != "", WithLibrarySchemaURL(vd.Library.SchemaURL))
+
+	add(vd.Name != "", WithName(vd.Name))
+	add(vd.Description != "", WithDescription(vd.Description))
+	add(vd.Unit != "", WithUnit(unit.Unit(vd.Unit)))
+	if vd.Boundaries != nil && vd.Aggregation != "histogram" && !vd.Increments {
+		return nil, fmt.Errorf("%w: boundaries require the histogram aggregation", ErrInvalidView)
+	}
+	if vd.Preset != "" && (vd.Boundaries != nil || (vd.Aggregation != "" && vd.Aggregation != "histogram")) {
+		return nil, fmt.Errorf("%w: a preset replaces boundaries and requires the histogram aggregation", ErrInvalidView)
+	}
+	if vd.Quantiles != nil && vd.Aggregation != "tdigest" && vd.Aggregation != "ddsketch" {
+		return nil, fmt.Errorf("%w: quantiles require the tdigest or ddsketch aggregation", ErrInvalidView)
+	}
+	if vd.Accuracy != 0 && vd.Aggregation != "ddsketch" {
+		return nil, fmt.Errorf("%w: relative_accuracy requires the ddsketch aggregation", ErrInvalidView)
+	}
+	add(vd.Increments, WithIncrementHistogram(vd.Boundaries...))
+	add(vd.Preset != "", WithHistogramPreset(vd.Preset))
+	add(vd.Rate, WithRate())
+	switch {
+	case vd.Aggregation == "" || vd.Preset != "":
+	case vd.Aggregation == "sum" || vd.Aggregation == "lastvalue" || vd.Aggregation == "minmaxsumcount" ||
+		vd.Aggregation == "histogram" || vd.Aggregation == "exponential" || vd.Aggregation == "tdigest" ||
+		vd.Aggregation == "ddsketch":
+		opts = append(opts, WithAggregatorSelector(fixedSelector{
+			aggregation: vd.Aggregation,
+			boundaries:  vd.Boundaries,
+			quantiles:   vd.Quantiles,
+			accuracy:    vd.Accuracy,
+		}))
+	default:
+		return nil, fmt.Errorf("%w: unknown aggregation %q", ErrInvalidView, vd.Aggregation)
+	}
+	if vd.Keys != nil {
+		keys := make([]attribute.Key, len(vd.Keys))
+		for i, k := range vd.Keys {
+			keys[i] = attribute.Key(k)
+		}
+		opts = append(opts, WithAttributeKeys(keys...))
+	}
+	if vd.ExcludeKeys != nil {
+		keys := make([]attribute.Key, len(vd.ExcludeKeys))
+		for i, k := range vd.ExcludeKeys {
+			keys[i] = attribute.Key(k)
+		}
+		opts = append(opts, WithKeysExclude(keys...))
+	}
+	add(vd.MaxValueLength != 0, WithAttributeValueLengthLimit(vd.MaxValueLength))
+	add(vd.Cardinality != 0, WithCardinalityLimit(vd.Cardinality))
+	if vd.Hash != nil {
+		keys := make([]attribute.Key, len(vd.Hash.Keys))
+		for i, k := range vd.Hash.Keys {
+			keys[i] = attribute.Key(k)
+		}
+		opts = append(opts, WithAttributeHashing([]byte(vd.Hash.Salt), vd.Hash.Length, keys...))
+	}
+	add(vd.PprofLabels != nil, WithPprofLabels(vd.PprofLabels...))
+	add(vd.BaggageKeys != nil, WithBaggageKeys(vd.BaggageKeys...))
+	add(vd.Rename != nil, WithAttributeRename(vd.Rename))
+	if vd.Attributes != nil {
+		var kvs []attribute.KeyValue
+		for k, v := range vd.Attributes {
+			kvs = append(kvs, attribute.String(k, v))
+		}
+		opts = append(opts, WithExtraAttributes(kvs...))
+	}
+	add(vd.Drop, WithDrop())
+	return opts, nil
+}
+
+// AggregatorFor implements export.AggregatorSelector.
+func (s fixedSelector) AggregatorFor(desc *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	switch s.aggregation {
+	case "lastvalue":
+		aggs := lastvalue.New(len(aggPtrs))
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	case "minmaxsumcount":
+		aggs := minmaxsumcount.New(len(aggPtrs))
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	case "exponential":
+		aggs := exponential.New(len(aggPtrs), desc)
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	case "tdigest":
+		var opts []tdigest.Option
+		if s.quantiles != nil {
+			opts = append(opts, tdigest.WithQuantiles(s.quantiles...))
+		}
+		aggs := tdigest.New(len(aggPtrs), desc, opts...)
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	case "ddsketch":
+		var opts []ddsketch.Option
+		if s.quantiles != nil {
+			opts = append(opts, ddsketch.WithQuantiles(s.quantiles...))
+		}
+		if s.accuracy != 0 {
+			opts = append(opts, ddsketch.WithRelativeAccuracy(s.accuracy))
+		}
+		aggs := ddsketch.New(len(aggPtrs), desc, opts...)
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	case "histogram":
+		var opts []histogram.Option
+		if s.boundaries != nil {
+			opts = append(opts, histogram.WithExplicitBoundaries(s.boundaries))
+		}
+		aggs := histogram.New(len(aggPtrs), desc, opts...)
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	default:
+		aggs := sum.New(len(aggPtrs))
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/tdigest"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/processor/view"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+const viewsYAML = `
+views:
+  - glob: "http.server.*"
+    except: ["http.server.active"]
+    aggregation: histogram
+    boundaries: [1, 10]
+    keys: [http.method, http.status_code]
+    rename: {http.status_code: status}
+    attributes: {team: web}
+  - instrument: runtime.gc.pause
+    drop: true
+  - instrument: queue.depth
+    name: queue.latest
+    description: Latest queue depth
+    unit: "{items}"
+    aggregation: lastvalue
+`
+
+func TestFromFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "views.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(viewsYAML), 0o600))
+	views, err := view.FromFile(path)
+	require.NoError(t, err)
+	require.Len(t, views, 3)
+	require.Equal(t, `View{instrument="runtime.gc.pause" drop}`, views[1].String())
+
+	registry, err := view.NewRegistry(views...)
+	require.NoError(t, err)
+	ckpt := basic.New(registry.Selector(simple.NewWithInexpensiveDistribution()), aggregation.CumulativeTemporalitySelector())
+	accum := metricsdk.NewAccumulator(registry.Processor(ckpt))
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	duration, err := meter.SyncFloat64().Counter("http.server.duration")
+	require.NoError(t, err)
+	duration.Add(ctx, 5, attribute.String("http.method", "GET"), attribute.Int("http.status_code", 200), attribute.String("peer", "a"))
+	duration.Add(ctx, 20, attribute.String("http.method", "GET"), attribute.Int("http.status_code", 200), attribute.String("peer", "b"))
+	active, err := meter.SyncInt64().UpDownCounter("http.server.active")
+	require.NoError(t, err)
+	active.Add(ctx, 1)
+	pause, err := meter.SyncFloat64().Histogram("runtime.gc.pause")
+	require.NoError(t, err)
+	pause.Record(ctx, 1)
+	depth, err := meter.SyncInt64().Counter("queue.depth")
+	require.NoError(t, err)
+	depth.Add(ctx, 3)
+
+	ckpt.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, ckpt.FinishCollection())
+
+	records := map[string]export.Record{}
+	require.NoError(t, ckpt.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		records[rec.Descriptor().Name()] = rec
+		return nil
+	}))
+	require.Len(t, records, 3)
+
+	rec := records["http.server.duration"]
+	require.Equal(t, "http.method=GET,status=200,team=web", rec.Attributes().Encoded(attribute.DefaultEncoder()))
+	buckets, err := rec.Aggregation().(aggregation.Histogram).Histogram()
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 10}, buckets.Boundaries)
+	require.Equal(t, []uint64{0, 1, 1}, buckets.Counts)
+
+	require.Equal(t, aggregation.SumKind, records["http.server.active"].Aggregation().Kind())
+
+	rec = records["queue.latest"]
+	require.Equal(t, aggregation.LastValueKind, rec.Aggregation().Kind())
+	require.Equal(t, "Latest queue depth", rec.Descriptor().Description())
+	require.EqualValues(t, "{items}", rec.Descriptor().Unit())
+}
+
+func TestParse(t *testing.T) {
+	views, err := view.Parse([]byte(`{"views": [{"instrument": "a", "keys": ["k"]}]}`))
+	require.NoError(t, err)
+	require.Equal(t, []view.View{mustView(t,
+		view.WithInstrumentName("a"),
+		view.WithAttributeKeys("k"),
+	)}, views)
+
+	views, err = view.Parse([]byte(`views: [{instrument: a, exclude_keys: [user.id], max_value_length: 64, pprof_labels: [endpoint], baggage_keys: [tenant]}]`))
+	require.NoError(t, err)
+	require.Equal(t, []view.View{mustView(t,
+		view.WithInstrumentName("a"),
+		view.WithKeysExclude("user.id"),
+		view.WithAttributeValueLengthLimit(64),
+		view.WithPprofLabels("endpoint"),
+		view.WithBaggageKeys("tenant"),
+	)}, views)
+
+	views, err = view.Parse([]byte(`views: [{instrument: a, aggregation: histogram, preset: queue-depth}]`))
+	require.NoError(t, err)
+	require.Equal(t, []view.View{mustView(t,
+		view.WithInstrumentName("a"),
+		view.WithHistogramPreset("queue-depth"),
+	)}, views)
+	_, err = view.Parse([]byte(`views: [{instrument: a, preset: queue-depth, boundaries: [1]}]`))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+
+	views, err = view.Parse([]byte(`views: [{instrument: a, aggregation: exponential}]`))
+	require.NoError(t, err)
+	desc := metrictest.NewDescriptor("a", sdkapi.HistogramInstrumentKind, number.Float64Kind)
+	var agg aggregator.Aggregator
+	view.NewSelector(simple.NewWithInexpensiveDistribution(), views...).AggregatorFor(&desc, &agg)
+	require.IsType(t, (*exponential.Aggregator)(nil), agg)
+
+	views, err = view.Parse([]byte(`views: [{instrument: a, aggregation: minmaxsumcount}]`))
+	require.NoError(t, err)
+	view.NewSelector(simple.NewWithInexpensiveDistribution(), views...).AggregatorFor(&desc, &agg)
+	require.IsType(t, (*minmaxsumcount.Aggregator)(nil), agg)
+
+	views, err = view.Parse([]byte(`views: [{instrument: a, aggregation: tdigest, quantiles: [0.9, 0.5]}]`))
+	require.NoError(t, err)
+	view.NewSelector(simple.NewWithInexpensiveDistribution(), views...).AggregatorFor(&desc, &agg)
+	require.IsType(t, (*tdigest.Aggregator)(nil), agg)
+	quantiles, err := agg.(*tdigest.Aggregator).Quantiles()
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.5, 0.9}, quantiles)
+
+	views, err = view.Parse([]byte(`views: [{instrument: a, aggregation: ddsketch, relative_accuracy: 0.02, quantiles: [0.99]}]`))
+	require.NoError(t, err)
+	view.NewSelector(simple.NewWithInexpensiveDistribution(), views...).AggregatorFor(&desc, &agg)
+	require.IsType(t, (*ddsketch.Aggregator)(nil), agg)
+	quantiles, err = agg.(*ddsketch.Aggregator).Quantiles()
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.99}, quantiles)
+
+	views, err = view.Parse([]byte(`views: [{instrument: a, hash: {keys: [user.id], salt: s, length: 16}}]`))
+	require.NoError(t, err)
+	require.Equal(t, []view.View{mustView(t,
+		view.WithInstrumentName("a"),
+		view.WithAttributeHashing([]byte("s"), 16, "user.id"),
+	)}, views)
+
+	views, err = view.Parse([]byte(`views: [{kinds: [counter, gauge_observer], drop: true}]`))
+	require.NoError(t, err)
+	require.Equal(t, []view.View{mustView(t,
+		view.WithInstrumentKind(sdkapi.CounterInstrumentKind, sdkapi.GaugeObserverInstrumentKind),
+		view.WithDrop(),
+	)}, views)
+
+	views, err = view.Parse([]byte(`{"views": [{"instrument": "a"}], "drop_unmatched": true}`))
+	require.NoError(t, err)
+	require.Equal(t, []view.View{mustView(t, view.WithInstrumentName("a")), view.DropUnmatched()}, views)
+
+	views, err = view.Parse(nil)
+	require.NoError(t, err)
+	require.Empty(t, views)
+
+	for _, doc := range []string{
+		`views: [{instrument: a, aggregation: median}]`,
+		`views: [{instrument: a, aggregation: sum, boundaries: [1]}]`,
+		`views: [{instrument: a, aggregation: histogram, quantiles: [0.5]}]`,
+		`views: [{instrument: a, aggregation: tdigest, relative_accuracy: 0.01}]`,
+		`views: [{regexp: "("}]`,
+		`views: [{glob: "a.*", name: fixed}]`,
+		`views: [{kinds: [gauge]}]`,
+	} {
+		_, err := view.Parse([]byte(doc))
+		require.ErrorIs(t, err, view.ErrInvalidView, doc)
+	}
+	_, err = view.Parse([]byte(`views: [{instrument: a, aggregaton: sum}]`))
+	require.Error(t, err)
+}
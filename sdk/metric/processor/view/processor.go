@@ -0,0 +1,639 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// Processor applies views to the instruments of the SDK and
+	// passes their streams to the next stage in an export pipeline.
+	Processor struct {
+		export.Checkpointer
+		registry *Registry
+		library  instrumentation.Library
+
+		lock sync.Mutex
+		// version is the version of the registry that compiled
+		// holds the streams of.
+		version  uint64
+		compiled map[*sdkapi.Descriptor]*stream
+		// previous holds the streams compiled for prior versions
+		// of the registry, which receive the Accumulations of
+		// records that the SDK has not replaced yet.
+		previous map[*sdkapi.Descriptor]*stream
+	}
+
+	// stream is the result of applying the views to one instrument.
+	stream struct {
+		entry            *entry
+		descriptor       *sdkapi.Descriptor
+		aggregator       export.AggregatorSelector
+		drop             bool
+		dropMeasurements func(*attribute.Set) bool
+		attributeKeys    map[attribute.Key]struct{}
+		excludedKeys     map[attribute.Key]struct{}
+		maxValueLength   int
+		hashedKeys       map[attribute.Key]struct{}
+		hashSalt         []byte
+		hashLength       int
+		attributeRenames map[attribute.Key]attribute.Key
+		extraAttributes  []attribute.KeyValue
+		// rate holds the sums of a stream exported as a rate, and
+		// is nil otherwise.
+		rate *rateState
+		// limit holds the attribute sets of a stream with a
+		// cardinality limit, and is nil otherwise.
+		limit *cardinalityLimit
+		// transform converts the measurements of a stream that
+		// converts units or transforms values, and is nil otherwise.
+		transform func(float64) float64
+
+		// partitions are the streams of the measurements matching
+		// the partitions of the View.
+		partitions []*stream
+		// match reports whether an attribute set belongs to a
+		// partition stream.
+		match func(*attribute.Set) bool
+
+		// replaced is the stream of the instrument for a prior
+		// version of the registry, when it was different.
+		replaced *stream
+		// sample is an Aggregator of the stream, compared with
+		// the Aggregators of Accumulations when replaced is set.
+		sample aggregator.Aggregator
+	}
+
+	selector struct {
+		export.AggregatorSelector
+		registry *Registry
+	}
+
+	factory struct {
+		factory  export.CheckpointerFactory
+		registry *Registry
+	}
+)
+
+var _ export.Processor = &Processor{}
+var _ export.Checkpointer = &Processor{}
+var _ export.AttributeAggregatorSelector = &Processor{}
+var _ export.VersionedAggregatorSelector = &Processor{}
+var _ export.LibraryCheckpointerFactory = factory{}
+
+// NewProcessor returns a Processor that applies the views to the
+// instruments of the SDK and passes their streams to ckpter, or an error
+// wrapping ErrInvalidView when the views conflict with each other.
+func NewProcessor(ckpter export.Checkpointer, views ...View) (*Processor, error) {
+	r, err := NewRegistry(views...)
+	if err != nil {
+		return nil, err
+	}
+	return r.Processor(ckpter), nil
+}
+
+// NewFactory returns a CheckpointerFactory that wraps each Checkpointer
+// produced by the given factory with a Processor applying the views, or
+// an error wrapping ErrInvalidView when the views conflict with each
+// other.
+func NewFactory(ckptFactory export.CheckpointerFactory, views ...View) (export.CheckpointerFactory, error) {
+	r, err := NewRegistry(views...)
+	if err != nil {
+		return nil, err
+	}
+	return r.Factory(ckptFactory), nil
+}
+
+func (f factory) NewCheckpointer() export.Checkpointer {
+	return f.registry.Processor(f.factory.NewCheckpointer())
+}
+
+func (f factory) NewLibraryCheckpointer(library instrumentation.Library) export.Checkpointer {
+	return f.registry.LibraryProcessor(library, export.NewLibraryCheckpointer(f.factory, library))
+}
+
+// NewSelector returns an AggregatorSelector that uses the Aggregators of
+// the views for their streams, and inner for all other instruments.  It
+// is meant for the Checkpointer wrapped by the Processor.
+func NewSelector(inner export.AggregatorSelector, views ...View) export.AggregatorSelector {
+	return newRegistry(views).Selector(inner)
+}
+
+// SelectionVersion implements export.VersionedAggregatorSelector.
+func (p *Processor) SelectionVersion() uint64 {
+	return p.registry.currentVersion()
+}
+
+// AggregatorFor implements export.AggregatorSelector.  Dropped
+// instruments are given no Aggregator, which disables them.
+func (p *Processor) AggregatorFor(desc *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	s := p.compile(desc)
+	if !s.drop {
+		p.recordAggregatorFor(s, aggPtrs...)
+	}
+}
+
+// AggregatorForAttributes implements export.AttributeAggregatorSelector.
+// The records of measurements dropped by a View are given no
+// Aggregator, so that their measurements are not aggregated.
+func (p *Processor) AggregatorForAttributes(desc *sdkapi.Descriptor, attrs *attribute.Set, aggPtrs ...*aggregator.Aggregator) {
+	s := p.compile(desc)
+	if s.drop || (s.dropMeasurements != nil && s.dropMeasurements(attrs)) {
+		return
+	}
+	p.recordAggregatorFor(s.partition(attrs), aggPtrs...)
+}
+
+// Process implements export.Processor.
+func (p *Processor) Process(accum export.Accumulation) error {
+	s := p.compile(accum.Descriptor())
+	ps := s.partition(accum.Attributes())
+	agg := untransformed(accum.Aggregator())
+	for s.replaced != nil && !s.drop && !compatible(ps.sample, agg) {
+		// The record was created for a prior version of the
+		// registry and is exported for the last time.
+		s = s.replaced
+		ps = s.partition(accum.Attributes())
+	}
+	if s.drop {
+		return nil
+	}
+	attrs := ps.attributes(accum.Attributes())
+	if ps.limit != nil {
+		attrs = ps.limit.apply(ps.descriptor, attrs)
+	}
+	if ps.rate != nil {
+		return ps.rate.add(accum.Descriptor(), attrs, agg)
+	}
+	if ps.descriptor == accum.Descriptor() && attrs == accum.Attributes() && agg == accum.Aggregator() {
+		return p.Checkpointer.Process(accum)
+	}
+	return p.Checkpointer.Process(
+		export.NewAccumulation(
+			ps.descriptor,
+			attrs,
+			agg,
+		),
+	)
+}
+
+// FinishCollection implements export.Checkpointer.  The rates of the
+// streams exported by WithRate are passed to the Checkpointer first.
+func (p *Processor) FinishCollection() error {
+	p.lock.Lock()
+	var rates []*stream
+	for _, streams := range []map[*sdkapi.Descriptor]*stream{p.compiled, p.previous} {
+		for _, s := range streams {
+			for _, rs := range append([]*stream{s}, s.partitions...) {
+				if rs.rate != nil {
+					rates = append(rates, rs)
+				}
+			}
+		}
+	}
+	p.lock.Unlock()
+
+	now := time.Now()
+	seen := map[*rateState]struct{}{}
+	for _, rs := range rates {
+		if _, ok := seen[rs.rate]; ok {
+			continue
+		}
+		seen[rs.rate] = struct{}{}
+		if err := rs.rate.collect(now, rs.descriptor, rs.aggregator, p.Checkpointer); err != nil {
+			otel.Handle(err)
+		}
+	}
+	return p.Checkpointer.FinishCollection()
+}
+
+// isMonotonicSum returns true for the instrument kinds exported as
+// monotonic sums.
+func isMonotonicSum(kind sdkapi.InstrumentKind) bool {
+	return kind == sdkapi.CounterInstrumentKind || kind == sdkapi.CounterObserverInstrumentKind
+}
+
+// partition returns the stream of the measurements with the attribute
+// set attrs: the first partition stream they match, s otherwise.
+func (s *stream) partition(attrs *attribute.Set) *stream {
+	for _, ps := range s.partitions {
+		if ps.match(attrs) {
+			return ps
+		}
+	}
+	return s
+}
+
+// attributes returns the attribute set of the stream for the attribute
+// set of a measurement, attrs itself when it is unchanged.
+func (s *stream) attributes(attrs *attribute.Set) *attribute.Set {
+	if s.attributeKeys == nil && len(s.excludedKeys) == 0 && s.maxValueLength == 0 && s.hashedKeys == nil && len(s.attributeRenames) == 0 && len(s.extraAttributes) == 0 {
+		return attrs
+	}
+	var kvs, renamed []attribute.KeyValue
+	filtered := false
+	for iter := attrs.Iter(); iter.Next(); {
+		kv := iter.Attribute()
+		if _, ok := s.attributeKeys[kv.Key]; s.attributeKeys != nil && !ok {
+			filtered = true
+			continue
+		}
+		if _, ok := s.excludedKeys[kv.Key]; ok {
+			filtered = true
+			continue
+		}
+		if _, ok := s.hashedKeys[kv.Key]; ok {
+			kv.Value = hashValue(kv.Value, s.hashSalt, s.hashLength)
+			filtered = true
+		} else if s.maxValueLength > 0 {
+			if v, ok := truncateValue(kv.Value, s.maxValueLength); ok {
+				kv.Value = v
+				filtered = true
+			}
+		}
+		if to, ok := s.attributeRenames[kv.Key]; ok {
+			renamed = append(renamed, attribute.KeyValue{Key: to, Value: kv.Value})
+			continue
+		}
+		kvs = append(kvs, kv)
+	}
+	if !filtered && renamed == nil && len(s.extraAttributes) == 0 {
+		return attrs
+	}
+	// NewSet keeps the last of duplicate keys, so renamed attributes
+	// replace the attributes they collide with, and extra attributes
+	// replace both.
+	kvs = append(kvs, renamed...)
+	set := attribute.NewSet(append(kvs, s.extraAttributes...)...)
+	return &set
+}
+
+// hashSize is the length of the hexadecimal SHA-256 hashes of hashed
+// attribute values.
+const hashSize = 2 * sha256.Size
+
+// hashValue returns the hexadecimal SHA-256 hash of salt followed by the
+// string form of v, truncated to n characters unless n is zero.
+func hashValue(v attribute.Value, salt []byte, n int) attribute.Value {
+	h := sha256.New()
+	_, _ = h.Write(salt)
+	_, _ = h.Write([]byte(v.Emit()))
+	sum := hex.EncodeToString(h.Sum(nil))
+	if n > 0 {
+		sum = sum[:n]
+	}
+	return attribute.StringValue(sum)
+}
+
+// truncateValue returns the string value v, or the string slice value v,
+// truncated to at most n bytes per string, and true if v was too long.
+func truncateValue(v attribute.Value, n int) (attribute.Value, bool) {
+	switch v.Type() {
+	case attribute.STRING:
+		if str := v.AsString(); len(str) > n {
+			return attribute.StringValue(truncateString(str, n)), true
+		}
+	case attribute.STRINGSLICE:
+		strs := v.AsStringSlice()
+		truncated := false
+		for i, str := range strs {
+			if len(str) > n {
+				strs[i] = truncateString(str, n)
+				truncated = true
+			}
+		}
+		if truncated {
+			return attribute.StringSliceValue(strs), true
+		}
+	}
+	return v, false
+}
+
+// truncateString returns the longest prefix of str of at most n bytes
+// that does not split a UTF-8 character.
+func truncateString(str string, n int) string {
+	for n > 0 && !utf8.RuneStart(str[n]) {
+		n--
+	}
+	return str[:n]
+}
+
+// compile returns the stream of the instrument described by desc.  The
+// views are matched once per descriptor and version of the registry and
+// the result is cached, so that regular expressions are not evaluated on
+// every collection and every record of an instrument refers to the same
+// descriptor.
+func (p *Processor) compile(desc *sdkapi.Descriptor) *stream {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if version := p.registry.currentVersion(); version != p.version {
+		for d, s := range p.compiled {
+			p.previous[d] = s
+		}
+		p.compiled = map[*sdkapi.Descriptor]*stream{}
+		p.version = version
+	}
+	if s, ok := p.compiled[desc]; ok {
+		return s
+	}
+
+	e := p.registry.match(p.library, desc)
+	prev := p.previous[desc]
+	if prev != nil && prev.entry == e {
+		// The instrument is not affected by the change.
+		p.compiled[desc] = prev
+		p.named(desc, prev)
+		return prev
+	}
+
+	s := &stream{entry: e, descriptor: desc}
+	if e != nil {
+		s.aggregator = e.view.aggregatorSelector()
+		s.drop = e.view.drop
+		s.dropMeasurements = e.view.dropMeasurements
+		s.attributeKeys = e.view.attributeKeys
+		s.excludedKeys = e.view.excludedKeys
+		s.maxValueLength = e.view.maxValueLength
+		s.hashedKeys = e.view.hashedKeys
+		s.hashSalt = e.view.hashSalt
+		s.hashLength = e.view.hashLength
+		s.attributeRenames = e.view.attributeRenames
+		s.extraAttributes = e.view.extraAttributes
+		s.transform = e.view.valueTransform()
+		increments := e.view.increments && desc.InstrumentKind() == sdkapi.CounterInstrumentKind
+		if increments {
+			s.aggregator = e.view.streamAggregator()
+		} else if e.view.increments {
+			otel.Handle(fmt.Errorf("%w: increment histogram of %s %s", ErrInvalidView, desc.InstrumentKind(), desc.Name()))
+		}
+		s.limit = newCardinalityLimit(e.view.cardinality)
+		rate := e.view.rate && isMonotonicSum(desc.InstrumentKind())
+		if rate {
+			s.aggregator = e.view.streamAggregator()
+			s.rate = newRateState(desc)
+		} else if e.view.rate {
+			otel.Handle(fmt.Errorf("%w: rate of %s %s", ErrInvalidView, desc.InstrumentKind(), desc.Name()))
+		}
+		if e.view.name != "" || e.view.unit != "" || e.view.description != "" || increments || rate {
+			name := e.view.name
+			if isNameTemplate(name) {
+				name = expandName(name, desc.Name(), p.library.Name, func() string {
+					return p.aggregationKind(s, desc)
+				})
+			}
+			s.descriptor = streamDescriptor(desc, name, e.view)
+		}
+		for _, part := range e.view.partitions {
+			ps := &stream{
+				entry:            e,
+				descriptor:       streamDescriptor(desc, part.Name, e.view),
+				aggregator:       part.Aggregator,
+				attributeKeys:    s.attributeKeys,
+				excludedKeys:     s.excludedKeys,
+				maxValueLength:   s.maxValueLength,
+				hashedKeys:       s.hashedKeys,
+				hashSalt:         s.hashSalt,
+				hashLength:       s.hashLength,
+				attributeRenames: s.attributeRenames,
+				extraAttributes:  s.extraAttributes,
+				transform:        s.transform,
+				limit:            newCardinalityLimit(e.view.cardinality),
+				match:            part.Match,
+			}
+			if s.rate != nil {
+				ps.aggregator = s.aggregator
+				ps.rate = newRateState(desc)
+			}
+			if ps.aggregator == nil {
+				ps.aggregator = s.aggregator
+			}
+			s.partitions = append(s.partitions, ps)
+		}
+	}
+	if prev != nil && s.descriptor == desc {
+		// A new descriptor resets the state of the stream in the
+		// following stages, which may hold different Aggregators.
+		copied := *desc
+		s.descriptor = &copied
+	}
+	p.named(desc, s)
+	if prev != nil {
+		s.replaced = prev
+		if !s.drop {
+			p.inputAggregatorFor(s, &s.sample)
+			for _, ps := range s.partitions {
+				p.inputAggregatorFor(ps, &ps.sample)
+			}
+		}
+	}
+	p.compiled[desc] = s
+	p.registry.compiled(s.descriptor, s.aggregator)
+	for _, ps := range s.partitions {
+		p.registry.compiled(ps.descriptor, ps.aggregator)
+	}
+	return s
+}
+
+// named records the names of the exported streams of the instrument
+// described by desc in the Registry, which reports their conflicts, and
+// merges them into the identical streams of other instruments by giving
+// them the same descriptor.
+func (p *Processor) named(desc *sdkapi.Descriptor, s *stream) {
+	if s.drop {
+		return
+	}
+	for _, ns := range append([]*stream{s}, s.partitions...) {
+		var sample aggregator.Aggregator
+		p.aggregatorFor(ns, &sample)
+		ns.descriptor = p.registry.named(p.version, p.library, desc, ns.descriptor, sample, s.entry)
+	}
+}
+
+// streamDescriptor returns a copy of desc with the given name and the
+// description and unit of the View, those of desc when empty.
+func streamDescriptor(desc *sdkapi.Descriptor, name string, v View) *sdkapi.Descriptor {
+	if name == "" {
+		name = desc.Name()
+	}
+	description := v.description
+	if description == "" {
+		description = desc.Description()
+	}
+	u := v.unit
+	if u == "" {
+		u = desc.Unit()
+	}
+	kind, nkind := desc.InstrumentKind(), desc.NumberKind()
+	if v.increments && kind == sdkapi.CounterInstrumentKind {
+		kind = sdkapi.HistogramInstrumentKind
+	}
+	if v.rate && isMonotonicSum(kind) {
+		kind, nkind = sdkapi.GaugeObserverInstrumentKind, number.Float64Kind
+		if v.unit == "" {
+			if u == "" {
+				u = "1"
+			}
+			u += "/s"
+		}
+	}
+	d := sdkapi.NewDescriptorWithAttributeKeys(
+		name,
+		kind,
+		nkind,
+		description,
+		u,
+		desc.AttributeKeys(),
+	)
+	return &d
+}
+
+// DebugString describes how the instrument described by desc is
+// exported: the name, aggregation and attribute keys of its stream, the
+// temporality chosen by tsel when it is not nil, and the View that
+// applies to it.  It is meant for debugging output and error messages.
+func (p *Processor) DebugString(desc *sdkapi.Descriptor, tsel aggregation.TemporalitySelector) string {
+	s := p.compile(desc)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%v, %v)", desc.Name(), desc.InstrumentKind(), desc.NumberKind())
+	if s.drop {
+		fmt.Fprintf(&b, " dropped by %v", s.entry.view)
+		return b.String()
+	}
+	fmt.Fprintf(&b, " exported as %s", s.descriptor.Name())
+
+	var agg aggregator.Aggregator
+	p.aggregatorFor(s, &agg)
+	if agg == nil {
+		b.WriteString(" without aggregation")
+	} else {
+		kind := agg.Aggregation().Kind()
+		fmt.Fprintf(&b, " aggregation=%v", kind)
+		if tsel != nil {
+			fmt.Fprintf(&b, " temporality=%v", aggregation.ResolveTemporality(tsel, s.descriptor, kind))
+		}
+	}
+	if keys := s.descriptor.AttributeKeys(); keys != nil {
+		fmt.Fprintf(&b, " keys=%v", keys)
+	}
+	for _, ps := range s.partitions {
+		fmt.Fprintf(&b, " partition=%s", ps.descriptor.Name())
+		var agg aggregator.Aggregator
+		p.aggregatorFor(ps, &agg)
+		if agg != nil {
+			fmt.Fprintf(&b, "(%v)", agg.Aggregation().Kind())
+		}
+	}
+	if s.entry != nil {
+		fmt.Fprintf(&b, " view=%v", s.entry.view)
+	}
+	return b.String()
+}
+
+// recordAggregatorFor allocates Aggregators for the records of the SDK
+// in the stream, which convert the units or transform the values of
+// measurements when the View does.
+func (p *Processor) recordAggregatorFor(s *stream, aggPtrs ...*aggregator.Aggregator) {
+	p.inputAggregatorFor(s, aggPtrs...)
+	if s.transform == nil {
+		return
+	}
+	for _, ptr := range aggPtrs {
+		if *ptr != nil {
+			*ptr = &transformedAggregator{Aggregator: *ptr, transform: s.transform}
+		}
+	}
+}
+
+// inputAggregatorFor allocates Aggregators for the Accumulations of the
+// stream: those of the stream, but sums for a rate.
+func (p *Processor) inputAggregatorFor(s *stream, aggPtrs ...*aggregator.Aggregator) {
+	if s.rate != nil {
+		sumSelector.AggregatorFor(s.descriptor, aggPtrs...)
+		return
+	}
+	p.aggregatorFor(s, aggPtrs...)
+}
+
+// aggregationKind returns the lowercase kind of the aggregation of the
+// instrument in the stream, which is not renamed yet.
+func (p *Processor) aggregationKind(s *stream, desc *sdkapi.Descriptor) string {
+	var agg aggregator.Aggregator
+	if s.aggregator != nil {
+		s.aggregator.AggregatorFor(desc, &agg)
+	} else {
+		p.Checkpointer.AggregatorFor(desc, &agg)
+	}
+	if agg == nil {
+		return ""
+	}
+	return strings.ToLower(string(agg.Aggregation().Kind()))
+}
+
+// aggregatorFor allocates Aggregators for the stream.
+func (p *Processor) aggregatorFor(s *stream, aggPtrs ...*aggregator.Aggregator) {
+	if s.aggregator != nil {
+		s.aggregator.AggregatorFor(s.descriptor, aggPtrs...)
+		return
+	}
+	p.Checkpointer.AggregatorFor(s.descriptor, aggPtrs...)
+}
+
+// compatible returns true if the Aggregators of a stream hold the same
+// kind of aggregation, histograms having the same boundaries.
+func compatible(a, b aggregator.Aggregator) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	ha, ok := a.Aggregation().(aggregation.Histogram)
+	if !ok {
+		return true
+	}
+	hb, ok := b.Aggregation().(aggregation.Histogram)
+	if !ok {
+		return false
+	}
+	ba, erra := ha.Histogram()
+	bb, errb := hb.Histogram()
+	if erra != nil || errb != nil || len(ba.Boundaries) != len(bb.Boundaries) {
+		return false
+	}
+	for i := range ba.Boundaries {
+		if ba.Boundaries[i] != bb.Boundaries[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// rateState holds the sums of a stream exported as a rate by
+	// WithRate, between and across collections.
+	rateState struct {
+		lock sync.Mutex
+		// cumulative is true when the Accumulations of the
+		// stream hold the cumulative values of a CounterObserver,
+		// rather than the increments of a Counter since the prior
+		// collection.
+		cumulative bool
+		// collected is the time the rates were last computed, or
+		// the stream compiled.
+		collected time.Time
+		series    map[attribute.Distinct]*rateSeries
+	}
+
+	// rateSeries holds the sums of one attribute set of a rate
+	// stream.
+	rateSeries struct {
+		attrs *attribute.Set
+		// sum is the sum of the Accumulations of the current
+		// collection, and updated is true once there is one.
+		sum     float64
+		updated bool
+		// last is the cumulative value of the prior collection,
+		// and observed is true once there is one.
+		last     float64
+		observed bool
+	}
+)
+
+// sumSelector allocates the Aggregators of the SDK records of rate
+// streams, whose rates are computed from sums.
+var sumSelector = fixedSelector{aggregation: "sum"}
+
+func newRateState(desc *sdkapi.Descriptor) *rateState {
+	return &rateState{
+		cumulative: !desc.InstrumentKind().Synchronous(),
+		collected:  time.Now(),
+		series:     map[attribute.Distinct]*rateSeries{},
+	}
+}
+
+// add adds the sum of agg, an Aggregator of the instrument described by
+// desc, to the series of attrs.
+func (r *rateState) add(desc *sdkapi.Descriptor, attrs *attribute.Set, agg aggregator.Aggregator) error {
+	s, ok := agg.Aggregation().(aggregation.Sum)
+	if !ok {
+		return fmt.Errorf("%w: rate of %s from %T", aggregation.ErrInconsistentType, desc.Name(), agg)
+	}
+	sum, err := s.Sum()
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	rs, ok := r.series[attrs.Equivalent()]
+	if !ok {
+		rs = &rateSeries{attrs: attrs}
+		r.series[attrs.Equivalent()] = rs
+	}
+	rs.sum += sum.CoerceToFloat64(desc.NumberKind())
+	rs.updated = true
+	return nil
+}
+
+// collect passes the per-second rates of the series of the stream
+// described by desc since the prior collection to ckpter, with
+// Aggregators allocated by sel.  Series of Counters without increments
+// have a zero rate, the first observations of CounterObservers have
+// none, and a cumulative value less than the prior one is taken as a
+// reset of the observed counter, which counted up from zero since.
+func (r *rateState) collect(now time.Time, desc *sdkapi.Descriptor, sel export.AggregatorSelector, ckpter export.Checkpointer) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	elapsed := now.Sub(r.collected).Seconds()
+	r.collected = now
+	if elapsed <= 0 {
+		return nil
+	}
+	for _, rs := range r.series {
+		increase := rs.sum
+		if r.cumulative {
+			if !rs.updated {
+				continue
+			}
+			if rs.observed && rs.sum >= rs.last {
+				increase = rs.sum - rs.last
+			}
+			first := !rs.observed
+			rs.last, rs.observed = rs.sum, true
+			if first {
+				rs.sum, rs.updated = 0, false
+				continue
+			}
+		}
+		rs.sum, rs.updated = 0, false
+
+		var agg aggregator.Aggregator
+		sel.AggregatorFor(desc, &agg)
+		if agg == nil {
+			return nil
+		}
+		if err := agg.Update(context.Background(), number.NewFloat64Number(increase/elapsed), desc); err != nil {
+			return err
+		}
+		if err := ckpter.Process(export.NewAccumulation(desc, rs.attrs, agg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
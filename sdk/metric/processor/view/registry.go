@@ -0,0 +1,427 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// Registry holds the views applied by a Processor and by the
+	// AggregatorSelector of the Checkpointer it wraps, so that views
+	// can be registered and unregistered while the SDK is running,
+	// for example to enable a debugging histogram temporarily.
+	Registry struct {
+		// version is incremented by every change of the views.
+		version uint64
+
+		lock    sync.RWMutex
+		entries []*entry
+
+		streamsLock sync.Mutex
+		// streams holds the AggregatorSelector compiled by the
+		// Processors for each stream descriptor, nil for the
+		// streams without one, so that the AggregatorSelector
+		// agrees with the Processors on views that select
+		// libraries.
+		streams map[*sdkapi.Descriptor]export.AggregatorSelector
+
+		namesLock sync.Mutex
+		// namesVersion is the version of the registry the streams
+		// in names were compiled for.
+		namesVersion uint64
+		// names holds the exported streams compiled for
+		// namesVersion, by library and stream name.
+		names map[streamName]*namedStream
+
+		contextLock sync.Mutex
+		// contextVersion is the version of the registry that
+		// contextEntries and contextEnabled hold the state of.
+		contextVersion uint64
+		// contextEntries holds the entries adding attributes
+		// from the context to the measurements of each
+		// instrument, nil for none.
+		contextEntries map[*sdkapi.Descriptor]*entry
+		// contextEnabled is true if a View adds attributes from
+		// the context.
+		contextEnabled bool
+	}
+
+	// namedStream is the first exported stream with a name, those
+	// of other instruments being merged into it when identical.
+	namedStream struct {
+		descriptor *sdkapi.Descriptor
+		sample     aggregator.Aggregator
+		// conflict lists the instruments exported with the
+		// name whose streams are not merged.
+		conflict Conflict
+	}
+
+	// streamName identifies the exported streams of a library with
+	// the same name.
+	streamName struct {
+		library instrumentation.Library
+		name    string
+	}
+
+	// Conflict describes the streams of different instruments that
+	// the views of a Registry export with the same name, which
+	// exporters cannot tell apart.  The SDK exports the streams all
+	// the same.  Identical streams, with the same instrument kind,
+	// number kind, description, unit, attribute keys and
+	// aggregation, do not conflict: they are merged into one.
+	Conflict struct {
+		// Name is the name the streams are exported with.
+		Name string
+		// Library is the instrumentation library of the
+		// instruments.
+		Library instrumentation.Library
+		// Instruments are the names of the instruments, in the
+		// order their streams were compiled.
+		Instruments []string
+		// Views are the views applied to Instruments, the zero
+		// View for an instrument matching no View.
+		Views []View
+	}
+
+	// entry is a registered View.  Its address identifies the
+	// View, which is not comparable.
+	entry struct {
+		view View
+	}
+)
+
+// NewRegistry returns a Registry holding the views, or an error wrapping
+// ErrInvalidView when the views conflict with each other.
+func NewRegistry(views ...View) (*Registry, error) {
+	if err := validateViews(views); err != nil {
+		return nil, err
+	}
+	return newRegistry(views), nil
+}
+
+func newRegistry(views []View) *Registry {
+	r := &Registry{
+		streams: map[*sdkapi.Descriptor]export.AggregatorSelector{},
+		names:   map[streamName]*namedStream{},
+	}
+	for _, v := range views {
+		r.entries = append(r.entries, &entry{view: v})
+	}
+	return r
+}
+
+// Register adds v to the views of the Registry, taking precedence over
+// the views it already holds, and returns a function that removes it.
+// It returns an error wrapping ErrInvalidView when v conflicts with the
+// views of the Registry.
+//
+// Instruments matching a different View as a result are recompiled at
+// the next collection: their streams are reset, and the SDK replaces
+// their records so that later measurements are aggregated as the new
+// View describes.
+func (r *Registry) Register(v View) (unregister func(), err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	views := []View{v}
+	for _, e := range r.entries {
+		views = append(views, e.view)
+	}
+	if err := validateViews(views); err != nil {
+		return nil, err
+	}
+	e := &entry{view: v}
+	r.entries = append([]*entry{e}, r.entries...)
+	atomic.AddUint64(&r.version, 1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { r.remove(e) })
+	}, nil
+}
+
+func (r *Registry) remove(e *entry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i, x := range r.entries {
+		if x == e {
+			r.entries = append(r.entries[:i:i], r.entries[i+1:]...)
+			atomic.AddUint64(&r.version, 1)
+			return
+		}
+	}
+}
+
+// Processor returns a Processor that applies the views of the Registry
+// to the instruments of the SDK and passes their streams to ckpter.
+// Views that select instrumentation libraries do not apply to its
+// instruments; see LibraryProcessor.
+func (r *Registry) Processor(ckpter export.Checkpointer) *Processor {
+	return r.LibraryProcessor(instrumentation.Library{}, ckpter)
+}
+
+// LibraryProcessor returns a Processor that applies the views of the
+// Registry to the instruments of library and passes their streams to
+// ckpter.
+func (r *Registry) LibraryProcessor(library instrumentation.Library, ckpter export.Checkpointer) *Processor {
+	return &Processor{
+		Checkpointer: ckpter,
+		registry:     r,
+		library:      library,
+		version:      r.currentVersion(),
+		compiled:     map[*sdkapi.Descriptor]*stream{},
+		previous:     map[*sdkapi.Descriptor]*stream{},
+	}
+}
+
+// Factory returns a CheckpointerFactory that wraps each Checkpointer
+// produced by ckptFactory with a Processor applying the views of the
+// Registry.  It implements export.LibraryCheckpointerFactory, so that
+// views selecting instrumentation libraries apply to the Checkpointers
+// of a controller.
+func (r *Registry) Factory(ckptFactory export.CheckpointerFactory) export.CheckpointerFactory {
+	return factory{
+		factory:  ckptFactory,
+		registry: r,
+	}
+}
+
+// Selector returns an AggregatorSelector that uses the Aggregators of
+// the views of the Registry for their streams, and inner for all other
+// instruments.  It is meant for the Checkpointer wrapped by the
+// Processor.
+func (r *Registry) Selector(inner export.AggregatorSelector) export.AggregatorSelector {
+	return selector{
+		AggregatorSelector: inner,
+		registry:           r,
+	}
+}
+
+func (r *Registry) currentVersion() uint64 {
+	return atomic.LoadUint64(&r.version)
+}
+
+// match returns the first entry whose View selects the instrument
+// described by desc of library, or nil.
+func (r *Registry) match(library instrumentation.Library, desc *sdkapi.Descriptor) *entry {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, e := range r.entries {
+		if e.view.matches(library, desc) {
+			return e
+		}
+	}
+	return nil
+}
+
+// compiled records that the stream described by desc was compiled
+// with the AggregatorSelector sel, nil for the default one.
+func (r *Registry) compiled(desc *sdkapi.Descriptor, sel export.AggregatorSelector) {
+	r.streamsLock.Lock()
+	defer r.streamsLock.Unlock()
+
+	r.streams[desc] = sel
+}
+
+// named records that the instrument described by desc of library is
+// exported as the stream described by sd for version of the Registry,
+// with the View of e, nil for none, and an Aggregator sample of the
+// stream.  It returns the descriptor of the stream of another instrument
+// that is identical, into which sd is merged, sd otherwise.  Every new
+// conflict is reported to the global error handler.
+func (r *Registry) named(version uint64, library instrumentation.Library, desc, sd *sdkapi.Descriptor, sample aggregator.Aggregator, e *entry) *sdkapi.Descriptor {
+	r.namesLock.Lock()
+	if version < r.namesVersion {
+		r.namesLock.Unlock()
+		return sd
+	}
+	if version > r.namesVersion {
+		r.names = map[streamName]*namedStream{}
+		r.namesVersion = version
+	}
+	key := streamName{library: library, name: sd.Name()}
+	ns, ok := r.names[key]
+	if !ok {
+		ns = &namedStream{
+			descriptor: sd,
+			sample:     sample,
+			conflict:   Conflict{Name: sd.Name(), Library: library},
+		}
+		r.names[key] = ns
+	}
+	c := &ns.conflict
+	for _, inst := range c.Instruments {
+		if inst == desc.Name() {
+			r.namesLock.Unlock()
+			return sd
+		}
+	}
+	if len(c.Instruments) > 0 && identical(ns.descriptor, sd) && compatible(ns.sample, sample) {
+		r.namesLock.Unlock()
+		return ns.descriptor
+	}
+	var v View
+	if e != nil {
+		v = e.view
+	}
+	c.Instruments = append(c.Instruments, desc.Name())
+	c.Views = append(c.Views, v)
+	var err error
+	if len(c.Instruments) > 1 {
+		err = fmt.Errorf("%w: %v", ErrInvalidView, *c)
+	}
+	r.namesLock.Unlock()
+
+	if err != nil {
+		otel.Handle(err)
+	}
+	return sd
+}
+
+// identical returns true if the streams described by a and b are
+// exported the same way, so that they can be merged.
+func identical(a, b *sdkapi.Descriptor) bool {
+	if a.Name() != b.Name() ||
+		a.InstrumentKind() != b.InstrumentKind() ||
+		a.NumberKind() != b.NumberKind() ||
+		a.Description() != b.Description() ||
+		a.Unit() != b.Unit() {
+		return false
+	}
+	ka, kb := a.AttributeKeys(), b.AttributeKeys()
+	if (ka == nil) != (kb == nil) || len(ka) != len(kb) {
+		return false
+	}
+	for i := range ka {
+		if ka[i] != kb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Conflicts returns the conflicts between the streams compiled for the
+// current views of the Registry, sorted by library and name, so that
+// programs and tests can detect views exporting different instruments
+// under one name.  Streams are compiled when their instruments are first
+// used, so instruments not used since the last change of the views are
+// not considered.
+func (r *Registry) Conflicts() []Conflict {
+	r.namesLock.Lock()
+	defer r.namesLock.Unlock()
+
+	if r.namesVersion != r.currentVersion() {
+		return nil
+	}
+	var conflicts []Conflict
+	for _, ns := range r.names {
+		c := &ns.conflict
+		if len(c.Instruments) < 2 {
+			continue
+		}
+		conflicts = append(conflicts, Conflict{
+			Name:        c.Name,
+			Library:     c.Library,
+			Instruments: append([]string(nil), c.Instruments...),
+			Views:       append([]View(nil), c.Views...),
+		})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		a, b := conflicts[i], conflicts[j]
+		if a.Library != b.Library {
+			return a.Library.Name+"\x00"+a.Library.Version+"\x00"+a.Library.SchemaURL <
+				b.Library.Name+"\x00"+b.Library.Version+"\x00"+b.Library.SchemaURL
+		}
+		return a.Name < b.Name
+	})
+	return conflicts
+}
+
+// String describes the conflict.
+func (c Conflict) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "instruments %q", c.Instruments)
+	if c.Library.Name != "" {
+		fmt.Fprintf(&b, " of library %q", c.Library.Name)
+	}
+	fmt.Fprintf(&b, " are exported as %q by views", c.Name)
+	for _, v := range c.Views {
+		fmt.Fprintf(&b, " %v", v)
+	}
+	return b.String()
+}
+
+// aggregatorFor returns the AggregatorSelector of the views for the
+// stream described by desc, or nil.
+func (r *Registry) aggregatorFor(desc *sdkapi.Descriptor) export.AggregatorSelector {
+	r.streamsLock.Lock()
+	sel, ok := r.streams[desc]
+	r.streamsLock.Unlock()
+	if ok {
+		return sel
+	}
+
+	// The stream was not compiled by a Processor of the Registry,
+	// as when the selector comes from NewSelector: its library is
+	// unknown and views selecting libraries are ignored, as are
+	// the streams named by templates.
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, e := range r.entries {
+		if e.view.selectsLibrary() {
+			continue
+		}
+		if e.view.name == desc.Name() && e.view.streamAggregator() != nil {
+			return e.view.streamAggregator()
+		}
+		for _, part := range e.view.partitions {
+			if part.Name != desc.Name() {
+				continue
+			}
+			if part.Aggregator != nil {
+				return part.Aggregator
+			}
+			return e.view.streamAggregator()
+		}
+	}
+	for _, e := range r.entries {
+		if e.view.name == "" && e.view.matches(instrumentation.Library{}, desc) {
+			return e.view.streamAggregator()
+		}
+	}
+	return nil
+}
+
+func (s selector) AggregatorFor(desc *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	if sel := s.registry.aggregatorFor(desc); sel != nil {
+		sel.AggregatorFor(desc, aggPtrs...)
+		return
+	}
+	s.AggregatorSelector.AggregatorFor(desc, aggPtrs...)
+}
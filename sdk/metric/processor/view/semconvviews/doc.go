@@ -0,0 +1,30 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package semconvviews provides strongly-typed builders of views for the
+// HTTP and RPC metrics of the semantic conventions, generated by viewgen
+// from metrics.yaml.  A misspelled metric, attribute, or histogram option
+// is a compilation error rather than a View that silently matches
+// nothing:
+//
+//	v, err := semconvviews.HTTPServerDuration().
+//	        WithBuckets(.005, .01, .05, .1, .5, 1, 5).
+//	        WithAttributes(semconvviews.HTTPServerDurationHTTPMethod, semconvviews.HTTPServerDurationHTTPStatusCode).
+//	        View()
+//
+// Programs with metrics of their own can generate such builders with
+// go.opentelemetry.io/otel/sdk/metric/processor/view/cmd/viewgen.
+package semconvviews // import "go.opentelemetry.io/otel/sdk/metric/processor/view/semconvviews"
+
+//go:generate go run ../cmd/viewgen -input metrics.yaml -output views.go -package semconvviews
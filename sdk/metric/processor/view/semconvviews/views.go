@@ -0,0 +1,335 @@
+// Code generated by viewgen; DO NOT EDIT.
+
+package semconvviews
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/processor/view"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+// builder holds the options of a view of one instrument.  Its methods
+// return copies, so that a builder can be shared by several views.
+type builder struct {
+	opts []view.Option
+}
+
+func newBuilder(name string) builder {
+	return builder{opts: []view.Option{view.WithInstrumentName(name)}}
+}
+
+func (b builder) with(opt view.Option) builder {
+	opts := make([]view.Option, len(b.opts), len(b.opts)+1)
+	copy(opts, b.opts)
+	return builder{opts: append(opts, opt)}
+}
+
+// HTTPClientDurationAttribute is an attribute key of the http.client.duration metric.
+type HTTPClientDurationAttribute attribute.Key
+
+// The attribute keys of the http.client.duration metric.
+const (
+	HTTPClientDurationHTTPFlavor     HTTPClientDurationAttribute = "http.flavor"
+	HTTPClientDurationHTTPMethod     HTTPClientDurationAttribute = "http.method"
+	HTTPClientDurationHTTPStatusCode HTTPClientDurationAttribute = "http.status_code"
+	HTTPClientDurationHTTPURL        HTTPClientDurationAttribute = "http.url"
+	HTTPClientDurationNetPeerName    HTTPClientDurationAttribute = "net.peer.name"
+	HTTPClientDurationNetPeerPort    HTTPClientDurationAttribute = "net.peer.port"
+)
+
+// HTTPClientDurationView builds a view of the http.client.duration histogram.
+type HTTPClientDurationView struct {
+	b builder
+}
+
+// HTTPClientDuration returns a builder of a view of the http.client.duration
+// histogram, measured in ms.
+//
+// Measures the duration of outbound HTTP requests.
+func HTTPClientDuration() HTTPClientDurationView {
+	return HTTPClientDurationView{newBuilder("http.client.duration")}
+}
+
+// WithName returns a copy of v exporting the stream as name.
+func (v HTTPClientDurationView) WithName(name string) HTTPClientDurationView {
+	return HTTPClientDurationView{v.b.with(view.WithName(name))}
+}
+
+// WithDescription returns a copy of v exporting the stream with the
+// given description.
+func (v HTTPClientDurationView) WithDescription(description string) HTTPClientDurationView {
+	return HTTPClientDurationView{v.b.with(view.WithDescription(description))}
+}
+
+// WithAttributes returns a copy of v keeping only the given attributes.
+func (v HTTPClientDurationView) WithAttributes(keys ...HTTPClientDurationAttribute) HTTPClientDurationView {
+	ks := make([]attribute.Key, len(keys))
+	for i, k := range keys {
+		ks[i] = attribute.Key(k)
+	}
+	return HTTPClientDurationView{v.b.with(view.WithAttributeKeys(ks...))}
+}
+
+// WithBuckets returns a copy of v aggregating the histogram into
+// buckets with the given boundaries.
+func (v HTTPClientDurationView) WithBuckets(boundaries ...float64) HTTPClientDurationView {
+	return HTTPClientDurationView{v.b.with(view.WithAggregatorSelector(
+		simple.NewWithHistogramDistribution(histogram.WithExplicitBoundaries(boundaries)),
+	))}
+}
+
+// WithDrop returns a copy of v dropping the stream.
+func (v HTTPClientDurationView) WithDrop() HTTPClientDurationView {
+	return HTTPClientDurationView{v.b.with(view.WithDrop())}
+}
+
+// View returns the view, or an error wrapping view.ErrInvalidView.
+func (v HTTPClientDurationView) View() (view.View, error) {
+	return view.New(v.b.opts...)
+}
+
+// HTTPServerActiveRequestsAttribute is an attribute key of the http.server.active_requests metric.
+type HTTPServerActiveRequestsAttribute attribute.Key
+
+// The attribute keys of the http.server.active_requests metric.
+const (
+	HTTPServerActiveRequestsHTTPFlavor     HTTPServerActiveRequestsAttribute = "http.flavor"
+	HTTPServerActiveRequestsHTTPHost       HTTPServerActiveRequestsAttribute = "http.host"
+	HTTPServerActiveRequestsHTTPMethod     HTTPServerActiveRequestsAttribute = "http.method"
+	HTTPServerActiveRequestsHTTPScheme     HTTPServerActiveRequestsAttribute = "http.scheme"
+	HTTPServerActiveRequestsHTTPServerName HTTPServerActiveRequestsAttribute = "http.server_name"
+)
+
+// HTTPServerActiveRequestsView builds a view of the http.server.active_requests updowncounter.
+type HTTPServerActiveRequestsView struct {
+	b builder
+}
+
+// HTTPServerActiveRequests returns a builder of a view of the http.server.active_requests
+// updowncounter, measured in {requests}.
+//
+// Measures the number of concurrent HTTP requests that are currently in-flight.
+func HTTPServerActiveRequests() HTTPServerActiveRequestsView {
+	return HTTPServerActiveRequestsView{newBuilder("http.server.active_requests")}
+}
+
+// WithName returns a copy of v exporting the stream as name.
+func (v HTTPServerActiveRequestsView) WithName(name string) HTTPServerActiveRequestsView {
+	return HTTPServerActiveRequestsView{v.b.with(view.WithName(name))}
+}
+
+// WithDescription returns a copy of v exporting the stream with the
+// given description.
+func (v HTTPServerActiveRequestsView) WithDescription(description string) HTTPServerActiveRequestsView {
+	return HTTPServerActiveRequestsView{v.b.with(view.WithDescription(description))}
+}
+
+// WithAttributes returns a copy of v keeping only the given attributes.
+func (v HTTPServerActiveRequestsView) WithAttributes(keys ...HTTPServerActiveRequestsAttribute) HTTPServerActiveRequestsView {
+	ks := make([]attribute.Key, len(keys))
+	for i, k := range keys {
+		ks[i] = attribute.Key(k)
+	}
+	return HTTPServerActiveRequestsView{v.b.with(view.WithAttributeKeys(ks...))}
+}
+
+// WithDrop returns a copy of v dropping the stream.
+func (v HTTPServerActiveRequestsView) WithDrop() HTTPServerActiveRequestsView {
+	return HTTPServerActiveRequestsView{v.b.with(view.WithDrop())}
+}
+
+// View returns the view, or an error wrapping view.ErrInvalidView.
+func (v HTTPServerActiveRequestsView) View() (view.View, error) {
+	return view.New(v.b.opts...)
+}
+
+// HTTPServerDurationAttribute is an attribute key of the http.server.duration metric.
+type HTTPServerDurationAttribute attribute.Key
+
+// The attribute keys of the http.server.duration metric.
+const (
+	HTTPServerDurationHTTPFlavor     HTTPServerDurationAttribute = "http.flavor"
+	HTTPServerDurationHTTPHost       HTTPServerDurationAttribute = "http.host"
+	HTTPServerDurationHTTPMethod     HTTPServerDurationAttribute = "http.method"
+	HTTPServerDurationHTTPScheme     HTTPServerDurationAttribute = "http.scheme"
+	HTTPServerDurationHTTPServerName HTTPServerDurationAttribute = "http.server_name"
+	HTTPServerDurationHTTPStatusCode HTTPServerDurationAttribute = "http.status_code"
+	HTTPServerDurationNetHostName    HTTPServerDurationAttribute = "net.host.name"
+	HTTPServerDurationNetHostPort    HTTPServerDurationAttribute = "net.host.port"
+)
+
+// HTTPServerDurationView builds a view of the http.server.duration histogram.
+type HTTPServerDurationView struct {
+	b builder
+}
+
+// HTTPServerDuration returns a builder of a view of the http.server.duration
+// histogram, measured in ms.
+//
+// Measures the duration of inbound HTTP requests.
+func HTTPServerDuration() HTTPServerDurationView {
+	return HTTPServerDurationView{newBuilder("http.server.duration")}
+}
+
+// WithName returns a copy of v exporting the stream as name.
+func (v HTTPServerDurationView) WithName(name string) HTTPServerDurationView {
+	return HTTPServerDurationView{v.b.with(view.WithName(name))}
+}
+
+// WithDescription returns a copy of v exporting the stream with the
+// given description.
+func (v HTTPServerDurationView) WithDescription(description string) HTTPServerDurationView {
+	return HTTPServerDurationView{v.b.with(view.WithDescription(description))}
+}
+
+// WithAttributes returns a copy of v keeping only the given attributes.
+func (v HTTPServerDurationView) WithAttributes(keys ...HTTPServerDurationAttribute) HTTPServerDurationView {
+	ks := make([]attribute.Key, len(keys))
+	for i, k := range keys {
+		ks[i] = attribute.Key(k)
+	}
+	return HTTPServerDurationView{v.b.with(view.WithAttributeKeys(ks...))}
+}
+
+// WithBuckets returns a copy of v aggregating the histogram into
+// buckets with the given boundaries.
+func (v HTTPServerDurationView) WithBuckets(boundaries ...float64) HTTPServerDurationView {
+	return HTTPServerDurationView{v.b.with(view.WithAggregatorSelector(
+		simple.NewWithHistogramDistribution(histogram.WithExplicitBoundaries(boundaries)),
+	))}
+}
+
+// WithDrop returns a copy of v dropping the stream.
+func (v HTTPServerDurationView) WithDrop() HTTPServerDurationView {
+	return HTTPServerDurationView{v.b.with(view.WithDrop())}
+}
+
+// View returns the view, or an error wrapping view.ErrInvalidView.
+func (v HTTPServerDurationView) View() (view.View, error) {
+	return view.New(v.b.opts...)
+}
+
+// RPCClientDurationAttribute is an attribute key of the rpc.client.duration metric.
+type RPCClientDurationAttribute attribute.Key
+
+// The attribute keys of the rpc.client.duration metric.
+const (
+	RPCClientDurationNetPeerName RPCClientDurationAttribute = "net.peer.name"
+	RPCClientDurationRPCMethod   RPCClientDurationAttribute = "rpc.method"
+	RPCClientDurationRPCService  RPCClientDurationAttribute = "rpc.service"
+	RPCClientDurationRPCSystem   RPCClientDurationAttribute = "rpc.system"
+)
+
+// RPCClientDurationView builds a view of the rpc.client.duration histogram.
+type RPCClientDurationView struct {
+	b builder
+}
+
+// RPCClientDuration returns a builder of a view of the rpc.client.duration
+// histogram, measured in ms.
+//
+// Measures the duration of outbound RPCs.
+func RPCClientDuration() RPCClientDurationView {
+	return RPCClientDurationView{newBuilder("rpc.client.duration")}
+}
+
+// WithName returns a copy of v exporting the stream as name.
+func (v RPCClientDurationView) WithName(name string) RPCClientDurationView {
+	return RPCClientDurationView{v.b.with(view.WithName(name))}
+}
+
+// WithDescription returns a copy of v exporting the stream with the
+// given description.
+func (v RPCClientDurationView) WithDescription(description string) RPCClientDurationView {
+	return RPCClientDurationView{v.b.with(view.WithDescription(description))}
+}
+
+// WithAttributes returns a copy of v keeping only the given attributes.
+func (v RPCClientDurationView) WithAttributes(keys ...RPCClientDurationAttribute) RPCClientDurationView {
+	ks := make([]attribute.Key, len(keys))
+	for i, k := range keys {
+		ks[i] = attribute.Key(k)
+	}
+	return RPCClientDurationView{v.b.with(view.WithAttributeKeys(ks...))}
+}
+
+// WithBuckets returns a copy of v aggregating the histogram into
+// buckets with the given boundaries.
+func (v RPCClientDurationView) WithBuckets(boundaries ...float64) RPCClientDurationView {
+	return RPCClientDurationView{v.b.with(view.WithAggregatorSelector(
+		simple.NewWithHistogramDistribution(histogram.WithExplicitBoundaries(boundaries)),
+	))}
+}
+
+// WithDrop returns a copy of v dropping the stream.
+func (v RPCClientDurationView) WithDrop() RPCClientDurationView {
+	return RPCClientDurationView{v.b.with(view.WithDrop())}
+}
+
+// View returns the view, or an error wrapping view.ErrInvalidView.
+func (v RPCClientDurationView) View() (view.View, error) {
+	return view.New(v.b.opts...)
+}
+
+// RPCServerDurationAttribute is an attribute key of the rpc.server.duration metric.
+type RPCServerDurationAttribute attribute.Key
+
+// The attribute keys of the rpc.server.duration metric.
+const (
+	RPCServerDurationNetPeerName RPCServerDurationAttribute = "net.peer.name"
+	RPCServerDurationRPCMethod   RPCServerDurationAttribute = "rpc.method"
+	RPCServerDurationRPCService  RPCServerDurationAttribute = "rpc.service"
+	RPCServerDurationRPCSystem   RPCServerDurationAttribute = "rpc.system"
+)
+
+// RPCServerDurationView builds a view of the rpc.server.duration histogram.
+type RPCServerDurationView struct {
+	b builder
+}
+
+// RPCServerDuration returns a builder of a view of the rpc.server.duration
+// histogram, measured in ms.
+//
+// Measures the duration of inbound RPCs.
+func RPCServerDuration() RPCServerDurationView {
+	return RPCServerDurationView{newBuilder("rpc.server.duration")}
+}
+
+// WithName returns a copy of v exporting the stream as name.
+func (v RPCServerDurationView) WithName(name string) RPCServerDurationView {
+	return RPCServerDurationView{v.b.with(view.WithName(name))}
+}
+
+// WithDescription returns a copy of v exporting the stream with the
+// given description.
+func (v RPCServerDurationView) WithDescription(description string) RPCServerDurationView {
+	return RPCServerDurationView{v.b.with(view.WithDescription(description))}
+}
+
+// WithAttributes returns a copy of v keeping only the given attributes.
+func (v RPCServerDurationView) WithAttributes(keys ...RPCServerDurationAttribute) RPCServerDurationView {
+	ks := make([]attribute.Key, len(keys))
+	for i, k := range keys {
+		ks[i] = attribute.Key(k)
+	}
+	return RPCServerDurationView{v.b.with(view.WithAttributeKeys(ks...))}
+}
+
+// WithBuckets returns a copy of v aggregating the histogram into
+// buckets with the given boundaries.
+func (v RPCServerDurationView) WithBuckets(boundaries ...float64) RPCServerDurationView {
+	return RPCServerDurationView{v.b.with(view.WithAggregatorSelector(
+		simple.NewWithHistogramDistribution(histogram.WithExplicitBoundaries(boundaries)),
+	))}
+}
+
+// WithDrop returns a copy of v dropping the stream.
+func (v RPCServerDurationView) WithDrop() RPCServerDurationView {
+	return RPCServerDurationView{v.b.with(view.WithDrop())}
+}
+
+// View returns the view, or an error wrapping view.ErrInvalidView.
+func (v RPCServerDurationView) View() (view.View, error) {
+	return view.New(v.b.opts...)
+}
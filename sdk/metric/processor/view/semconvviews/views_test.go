@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semconvviews_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/processor/view"
+	"go.opentelemetry.io/otel/sdk/metric/processor/view/semconvviews"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+func TestHTTPServerDuration(t *testing.T) {
+	ctx := context.Background()
+	base := semconvviews.HTTPServerDuration().WithAttributes(semconvviews.HTTPServerDurationHTTPMethod)
+	v, err := base.WithBuckets(10, 100).View()
+	require.NoError(t, err)
+	require.Equal(t, `View{instrument="http.server.duration" aggregator=simple.selectorHistogram keys=[http.method]}`, v.String())
+
+	// The builder is unchanged by the views built from it.
+	plain, err := base.View()
+	require.NoError(t, err)
+	require.Equal(t, `View{instrument="http.server.duration" keys=[http.method]}`, plain.String())
+
+	ckpt := basic.New(view.NewSelector(simple.NewWithInexpensiveDistribution(), v), aggregation.CumulativeTemporalitySelector())
+	proc, err := view.NewProcessor(ckpt, v)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(proc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	duration, err := meter.SyncFloat64().Histogram("http.server.duration")
+	require.NoError(t, err)
+	duration.Record(ctx, 5, attribute.String("http.method", "GET"), attribute.Int("http.status_code", 200))
+	duration.Record(ctx, 50, attribute.String("http.method", "GET"), attribute.Int("http.status_code", 500))
+
+	ckpt.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, ckpt.FinishCollection())
+
+	var records []export.Record
+	require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		records = append(records, rec)
+		return nil
+	}))
+	require.Len(t, records, 1)
+	require.Equal(t, "http.method=GET", records[0].Attributes().Encoded(attribute.DefaultEncoder()))
+	buckets, err := records[0].Aggregation().(aggregation.Histogram).Histogram()
+	require.NoError(t, err)
+	require.Equal(t, []float64{10, 100}, buckets.Boundaries)
+	require.Equal(t, []uint64{1, 1, 0}, buckets.Counts)
+}
+
+func TestDrop(t *testing.T) {
+	v, err := semconvviews.RPCClientDuration().WithDrop().View()
+	require.NoError(t, err)
+	require.Equal(t, `View{instrument="rpc.client.duration" drop}`, v.String())
+
+	_, err = semconvviews.RPCClientDuration().WithName("rpc.duration").WithDrop().View()
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
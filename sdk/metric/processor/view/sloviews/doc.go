@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sloviews configures the views of latency histograms for
+// service level objectives, and computes their burn rates.  An Objective
+// names a Histogram instrument, the latency threshold of good requests
+// and the target ratio of good requests:
+//
+//	objective := sloviews.Objective{
+//	        Instrument: "http.server.duration",
+//	        Threshold:  300,
+//	        Target:     0.999,
+//	        Boundaries: []float64{50, 100, 500, 1000},
+//	}
+//	v, err := objective.View()
+//
+// The View aggregates the histogram with a bucket boundary at the
+// threshold, so that the good requests are the cumulative count of the
+// buckets below it, the bucket le="300" of Prometheus, and all requests
+// are the count of the histogram.  Alerting rules compare the burn rate,
+// the ratio of bad requests over the ratio allowed by the target, over
+// the long and short windows of Alerts.
+package sloviews // import "go.opentelemetry.io/otel/sdk/metric/processor/view/sloviews"
@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sloviews // import "go.opentelemetry.io/otel/sdk/metric/processor/view/sloviews"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/processor/view"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+type (
+	// Objective is a latency service level objective of the
+	// measurements of a Histogram instrument.
+	Objective struct {
+		// Instrument is the name of the Histogram.
+		Instrument string
+		// Threshold is the latency below which a measurement is
+		// good, in the unit of the Histogram.
+		Threshold float64
+		// Target is the ratio of good measurements of the
+		// objective, such as 0.999, between zero and one.
+		Target float64
+		// Boundaries are the other bucket boundaries of the
+		// histogram.  Threshold is added to them.
+		Boundaries []float64
+	}
+
+	// Alert is a multi-window burn rate alert: it fires when the
+	// burn rates over both the Long and the Short windows reach
+	// BurnRate, so that it fires quickly on a fast burn and stops
+	// firing soon after the burn ends.
+	Alert struct {
+		Long     time.Duration
+		Short    time.Duration
+		BurnRate float64
+	}
+)
+
+// ErrInvalidObjective is returned by View for an Objective without an
+// instrument, with a threshold that is not a positive number, or with a
+// target that is not between zero and one.
+var ErrInvalidObjective = errors.New("invalid service level objective")
+
+// Alerts are the burn rate alerts recommended for a 30-day objective by
+// the Site Reliability Workbook: the first two consume 2% and 5% of the
+// error budget and page, the last two consume 10% and 100% of it and
+// open tickets.
+var Alerts = []Alert{
+	{Long: time.Hour, Short: 5 * time.Minute, BurnRate: 14.4},
+	{Long: 6 * time.Hour, Short: 30 * time.Minute, BurnRate: 6},
+	{Long: 24 * time.Hour, Short: 2 * time.Hour, BurnRate: 3},
+	{Long: 72 * time.Hour, Short: 6 * time.Hour, BurnRate: 1},
+}
+
+func (o Objective) validate() error {
+	switch {
+	case o.Instrument == "":
+		return fmt.Errorf("%w: no instrument", ErrInvalidObjective)
+	case !(o.Threshold > 0) || math.IsInf(o.Threshold, 1):
+		return fmt.Errorf("%w: threshold %v", ErrInvalidObjective, o.Threshold)
+	case !(o.Target > 0 && o.Target < 1):
+		return fmt.Errorf("%w: target %v", ErrInvalidObjective, o.Target)
+	}
+	return nil
+}
+
+// boundaries returns the sorted bucket boundaries of the histogram, with
+// Threshold.
+func (o Objective) boundaries() []float64 {
+	boundaries := []float64{o.Threshold}
+	for _, b := range o.Boundaries {
+		if b != o.Threshold {
+			boundaries = append(boundaries, b)
+		}
+	}
+	sort.Float64s(boundaries)
+	return boundaries
+}
+
+// View returns the View of the Histogram of the objective, with the
+// given options, or an error wrapping ErrInvalidObjective or
+// view.ErrInvalidView.  The options may rename the stream or select its
+// attributes, but must not change its aggregation.
+func (o Objective) View(opts ...view.Option) (view.View, error) {
+	if err := o.validate(); err != nil {
+		return view.View{}, err
+	}
+	return view.New(append([]view.Option{
+		view.WithInstrumentName(o.Instrument),
+		view.WithAggregatorSelector(simple.NewWithHistogramDistribution(
+			histogram.WithExplicitBoundaries(o.boundaries()),
+		)),
+	}, opts...)...)
+}
+
+// Counts returns the counts of the good measurements, those less than
+// Threshold, and of all measurements of a histogram exported by the
+// View of the objective.  The counts are cumulative or deltas like the
+// histogram, and the burn rate over a window is that of the difference
+// of two cumulative counts.
+func (o Objective) Counts(h aggregation.Histogram) (good, total uint64, err error) {
+	buckets, err := h.Histogram()
+	if err != nil {
+		return 0, 0, err
+	}
+	i := sort.SearchFloat64s(buckets.Boundaries, o.Threshold)
+	if i == len(buckets.Boundaries) || buckets.Boundaries[i] != o.Threshold {
+		return 0, 0, fmt.Errorf("%w: threshold %v is not a bucket boundary", ErrInvalidObjective, o.Threshold)
+	}
+	for _, count := range buckets.Counts[:i+1] {
+		good += count
+	}
+	total, err = h.Count()
+	if err != nil {
+		return 0, 0, err
+	}
+	return good, total, nil
+}
+
+// BurnRate returns the rate at which the measurements of a window
+// consume the error budget of the objective: the ratio of bad
+// measurements over the ratio allowed by Target, a rate of one consuming
+// the budget in exactly the period of the objective.  A window without
+// measurements has a zero burn rate.
+func (o Objective) BurnRate(good, total uint64) float64 {
+	if total == 0 || good >= total {
+		return 0
+	}
+	return float64(total-good) / float64(total) / (1 - o.Target)
+}
+
+// Firing returns true if the alert fires for the burn rates of its long
+// and short windows.
+func (a Alert) Firing(long, short float64) bool {
+	return long >= a.BurnRate && short >= a.BurnRate
+}
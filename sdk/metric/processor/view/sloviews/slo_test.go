@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sloviews_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/processor/view"
+	"go.opentelemetry.io/otel/sdk/metric/processor/view/sloviews"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+func TestObjective(t *testing.T) {
+	ctx := context.Background()
+	objective := sloviews.Objective{
+		Instrument: "http.server.duration",
+		Threshold:  300,
+		Target:     0.9,
+		Boundaries: []float64{1000, 100, 300},
+	}
+	v, err := objective.View(view.WithName("http.server.duration.slo"))
+	require.NoError(t, err)
+
+	ckpt := basic.New(view.NewSelector(simple.NewWithInexpensiveDistribution(), v), aggregation.CumulativeTemporalitySelector())
+	proc, err := view.NewProcessor(ckpt, v)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(proc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	duration, err := meter.SyncFloat64().Histogram("http.server.duration")
+	require.NoError(t, err)
+	for _, ms := range []float64{5, 50, 150, 250, 299, 300, 800, 2000} {
+		duration.Record(ctx, ms)
+	}
+
+	ckpt.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, ckpt.FinishCollection())
+
+	var records []export.Record
+	require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		records = append(records, rec)
+		return nil
+	}))
+	require.Len(t, records, 1)
+	require.Equal(t, "http.server.duration.slo", records[0].Descriptor().Name())
+	h := records[0].Aggregation().(aggregation.Histogram)
+	buckets, err := h.Histogram()
+	require.NoError(t, err)
+	require.Equal(t, []float64{100, 300, 1000}, buckets.Boundaries)
+
+	good, total, err := objective.Counts(h)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), good)
+	require.Equal(t, uint64(8), total)
+	require.InDelta(t, 3.75, objective.BurnRate(good, total), 1e-9)
+	require.Equal(t, float64(0), objective.BurnRate(0, 0))
+
+	require.True(t, sloviews.Alerts[3].Firing(3.75, 2))
+	require.False(t, sloviews.Alerts[0].Firing(20, 3.75))
+
+	other := objective
+	other.Threshold = 200
+	_, _, err = other.Counts(h)
+	require.ErrorIs(t, err, sloviews.ErrInvalidObjective)
+}
+
+func TestObjectiveValidation(t *testing.T) {
+	for _, objective := range []sloviews.Objective{
+		{Threshold: 1, Target: 0.99},
+		{Instrument: "latency", Target: 0.99},
+		{Instrument: "latency", Threshold: 1, Target: 1},
+	} {
+		_, err := objective.View()
+		require.ErrorIs(t, err, sloviews.ErrInvalidObjective)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The placeholders of stream name templates, see WithName.
+const (
+	instrumentPlaceholder  = "{instrument}"
+	aggregationPlaceholder = "{aggregation}"
+	scopePlaceholder       = "{scope}"
+)
+
+// isNameTemplate returns true if the stream name contains placeholders.
+// Instrument names cannot contain braces.
+func isNameTemplate(name string) bool {
+	return strings.ContainsAny(name, "{}")
+}
+
+// validateNameTemplate returns an error wrapping ErrInvalidView if the
+// template contains unbalanced braces or unknown placeholders.
+func validateNameTemplate(template string) error {
+	rest := template
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if rest[open] != '{' || end < 0 {
+			return fmt.Errorf("%w: unbalanced braces in name template %q", ErrInvalidView, template)
+		}
+		switch placeholder := rest[open : open+end+1]; placeholder {
+		case instrumentPlaceholder, aggregationPlaceholder, scopePlaceholder:
+		default:
+			return fmt.Errorf("%w: unknown placeholder %s in name template %q", ErrInvalidView, placeholder, template)
+		}
+		rest = rest[open+end+1:]
+	}
+	return nil
+}
+
+// expandName returns the stream name of an instrument given by a
+// template.  The aggregation is only computed when the template refers
+// to it.
+func expandName(template, instrument, scope string, aggregation func() string) string {
+	pairs := []string{instrumentPlaceholder, instrument, scopePlaceholder, scope}
+	if strings.Contains(template, aggregationPlaceholder) {
+		pairs = append(pairs, aggregationPlaceholder, aggregation())
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"context"
+	"math"
+
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// transformedAggregator is the Aggregator of an SDK record of a stream
+// that converts units or transforms values.  It applies transform to
+// measurements before updating the Aggregator it wraps, and is unwrapped
+// by Process, so that later stages of the pipeline only see the wrapped
+// Aggregator.
+type transformedAggregator struct {
+	aggregator.Aggregator
+	transform func(float64) float64
+}
+
+var _ aggregator.SliceUpdater = &transformedAggregator{}
+
+// Update implements aggregator.Aggregator.
+func (a *transformedAggregator) Update(ctx context.Context, num number.Number, desc *sdkapi.Descriptor) error {
+	num, ok := a.apply(num, desc.NumberKind())
+	if !ok {
+		return nil
+	}
+	return a.Aggregator.Update(ctx, num, desc)
+}
+
+// UpdateSlice implements aggregator.SliceUpdater.
+func (a *transformedAggregator) UpdateSlice(ctx context.Context, nums []number.Number, desc *sdkapi.Descriptor) error {
+	transformed := make([]number.Number, 0, len(nums))
+	for _, num := range nums {
+		if num, ok := a.apply(num, desc.NumberKind()); ok {
+			transformed = append(transformed, num)
+		}
+	}
+	if su, ok := a.Aggregator.(aggregator.SliceUpdater); ok {
+		return su.UpdateSlice(ctx, transformed, desc)
+	}
+	for _, num := range transformed {
+		if err := a.Aggregator.Update(ctx, num, desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SynchronizedMove implements aggregator.Aggregator.
+func (a *transformedAggregator) SynchronizedMove(dest aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	return a.Aggregator.SynchronizedMove(untransformed(dest), desc)
+}
+
+// Merge implements aggregator.Aggregator.
+func (a *transformedAggregator) Merge(other aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	return a.Aggregator.Merge(untransformed(other), desc)
+}
+
+// apply transforms a measurement.  Integer measurements are rounded to
+// the nearest integer.  It returns false when the result is not a finite
+// number, and the measurement is dropped.
+func (a *transformedAggregator) apply(num number.Number, kind number.Kind) (number.Number, bool) {
+	v := a.transform(num.CoerceToFloat64(kind))
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	if kind == number.Int64Kind {
+		return number.NewInt64Number(int64(math.Round(v))), true
+	}
+	return number.NewFloat64Number(v), true
+}
+
+// untransformed returns the Aggregator wrapped by agg, or agg itself.
+func untransformed(agg aggregator.Aggregator) aggregator.Aggregator {
+	if t, ok := agg.(*transformedAggregator); ok {
+		return t.Aggregator
+	}
+	return agg
+}
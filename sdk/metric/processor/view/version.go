@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"strconv"
+	"strings"
+)
+
+// version is a parsed semantic version.  Build metadata is ignored.
+type version struct {
+	core       [3]uint64
+	prerelease []string
+}
+
+// parseVersion parses a semantic version with an optional "v" prefix,
+// such as "v1.2.3" or "0.30.0-rc.1".  Missing minor and patch numbers
+// are zero.
+func parseVersion(s string) (version, bool) {
+	var ver version
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		if i == len(s)-1 {
+			return version{}, false
+		}
+		ver.prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > len(ver.core) {
+		return version{}, false
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return version{}, false
+		}
+		ver.core[i] = n
+	}
+	return ver, true
+}
+
+// compareVersions returns -1, 0 or 1 as the semantic version a is less
+// than, equal to or greater than b, both being valid.
+func compareVersions(a, b string) int {
+	va, _ := parseVersion(a)
+	vb, _ := parseVersion(b)
+	for i := range va.core {
+		if c := compareUint(va.core[i], vb.core[i]); c != 0 {
+			return c
+		}
+	}
+	// A pre-release precedes the release.
+	switch {
+	case va.prerelease == nil && vb.prerelease == nil:
+		return 0
+	case va.prerelease == nil:
+		return 1
+	case vb.prerelease == nil:
+		return -1
+	}
+	for i := 0; i < len(va.prerelease) && i < len(vb.prerelease); i++ {
+		if c := compareIdentifiers(va.prerelease[i], vb.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(va.prerelease)), uint64(len(vb.prerelease)))
+}
+
+// compareIdentifiers compares pre-release identifiers: numeric
+// identifiers numerically and before alphanumeric ones, which compare
+// lexically.
+func compareIdentifiers(a, b string) int {
+	na, erra := strconv.ParseUint(a, 10, 64)
+	nb, errb := strconv.ParseUint(b, 10, 64)
+	switch {
+	case erra == nil && errb == nil:
+		return compareUint(na, nb)
+	case erra == nil:
+		return -1
+	case errb == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
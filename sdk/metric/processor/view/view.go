@@ -0,0 +1,976 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view // import "go.opentelemetry.io/otel/sdk/metric/processor/view"
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/processor/partition"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// ErrInvalidView is returned by New for a View whose options
+// contradict each other.
+var ErrInvalidView = fmt.Errorf("invalid view")
+
+// View selects instruments and describes how their streams are
+// exported.  The zero View selects every instrument and exports it
+// unchanged.
+type View struct {
+	instrumentName   string
+	instrumentRegexp *regexp.Regexp
+	instrumentGlob   string
+	instrumentExcept []string
+	instrumentUnit   unit.Unit
+	instrumentKinds  []sdkapi.InstrumentKind
+
+	libraryName       string
+	libraryMinVersion string
+	libraryMaxVersion string
+	librarySchemaURL  string
+
+	name             string
+	description      string
+	aggregator       export.AggregatorSelector
+	preset           string
+	increments       bool
+	incrementBounds  []float64
+	rate             bool
+	drop             bool
+	dropMeasurements func(*attribute.Set) bool
+	attributeKeys    map[attribute.Key]struct{}
+	excludedKeys     map[attribute.Key]struct{}
+	maxValueLength   int
+	cardinality      int
+	hashedKeys       map[attribute.Key]struct{}
+	hashSalt         []byte
+	hashLength       int
+	pprofLabels      map[string]struct{}
+	baggageKeys      []string
+	attributeRenames map[attribute.Key]attribute.Key
+	extraAttributes  []attribute.KeyValue
+	partitions       []partition.Partition
+	unit             unit.Unit
+	unitFactor       float64
+	transform        func(float64) float64
+}
+
+// Option configures a View.
+type Option interface {
+	apply(View) View
+}
+
+type optionFunc func(View) View
+
+func (fn optionFunc) apply(v View) View {
+	return fn(v)
+}
+
+// New returns a View configured with opts, or an error wrapping
+// ErrInvalidView when opts contradict each other.
+func New(opts ...Option) (View, error) {
+	var v View
+	for _, opt := range opts {
+		v = opt.apply(v)
+	}
+	if err := v.validate(); err != nil {
+		return View{}, err
+	}
+	return v, nil
+}
+
+func (v View) validate() error {
+	if v.instrumentGlob != "" {
+		if _, err := path.Match(v.instrumentGlob, ""); err != nil {
+			return fmt.Errorf("%w: instrument name pattern %q: %v", ErrInvalidView, v.instrumentGlob, err)
+		}
+	}
+	for _, pattern := range v.instrumentExcept {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: excluded instrument name pattern %q: %v", ErrInvalidView, pattern, err)
+		}
+	}
+	for _, bound := range []string{v.libraryMinVersion, v.libraryMaxVersion} {
+		if _, ok := parseVersion(bound); bound != "" && !ok {
+			return fmt.Errorf("%w: library version %q", ErrInvalidView, bound)
+		}
+	}
+	if v.libraryMinVersion != "" && v.libraryMaxVersion != "" &&
+		compareVersions(v.libraryMinVersion, v.libraryMaxVersion) >= 0 {
+		return fmt.Errorf("%w: empty library version range [%s, %s)", ErrInvalidView, v.libraryMinVersion, v.libraryMaxVersion)
+	}
+	templated := isNameTemplate(v.name)
+	if templated {
+		if err := validateNameTemplate(v.name); err != nil {
+			return err
+		}
+		if !v.selectsOneInstrument() && !strings.Contains(v.name, instrumentPlaceholder) {
+			return fmt.Errorf("%w: name template %q of several instruments must contain %s", ErrInvalidView, v.name, instrumentPlaceholder)
+		}
+	}
+	if (v.name != "" && !templated) || v.partitions != nil {
+		if v.instrumentRegexp != nil || hasWildcard(v.instrumentGlob) {
+			return fmt.Errorf("%w: a name cannot be given to the streams of a regexp or wildcard selector", ErrInvalidView)
+		}
+		if v.instrumentName == "" && v.instrumentGlob == "" {
+			return fmt.Errorf("%w: a name requires a single-instrument selector (WithInstrumentName)", ErrInvalidView)
+		}
+	}
+	if v.unit != "" || v.unitFactor != 0 {
+		if v.unit == "" || !(v.unitFactor > 0) || math.IsInf(v.unitFactor, 0) {
+			return fmt.Errorf("%w: unit conversion to %q by %v", ErrInvalidView, v.unit, v.unitFactor)
+		}
+	}
+	for _, part := range v.partitions {
+		if part.Name == "" || part.Match == nil {
+			return fmt.Errorf("%w: a partition requires a name and a predicate", ErrInvalidView)
+		}
+		if part.Instrument != "" && part.Instrument != v.instrumentName && part.Instrument != v.instrumentGlob {
+			return fmt.Errorf("%w: partition %q of instrument %q applied to another instrument", ErrInvalidView, part.Name, part.Instrument)
+		}
+	}
+	if v.preset != "" {
+		if _, ok := histogram.PresetBoundaries(v.preset); !ok {
+			return fmt.Errorf("%w: unknown histogram preset %q", ErrInvalidView, v.preset)
+		}
+	}
+	if v.increments && v.aggregator != nil {
+		return fmt.Errorf("%w: an increment histogram has its own aggregation", ErrInvalidView)
+	}
+	if v.preset != "" && v.aggregator != nil {
+		return fmt.Errorf("%w: a histogram preset has its own aggregation", ErrInvalidView)
+	}
+	if v.rate && (v.aggregator != nil || v.increments || v.preset != "") {
+		return fmt.Errorf("%w: a rate has its own aggregation", ErrInvalidView)
+	}
+	if v.drop && (v.name != "" || v.aggregator != nil || v.preset != "" || v.increments || v.rate || v.attributeKeys != nil || v.excludedKeys != nil || v.maxValueLength != 0 || v.cardinality != 0 || v.hashedKeys != nil || v.pprofLabels != nil || v.baggageKeys != nil || v.attributeRenames != nil || v.extraAttributes != nil || v.partitions != nil || v.unit != "" || v.description != "" || v.transform != nil) {
+		return fmt.Errorf("%w: a dropped stream cannot be renamed or aggregated", ErrInvalidView)
+	}
+	if v.maxValueLength < 0 {
+		return fmt.Errorf("%w: negative attribute value length limit %d", ErrInvalidView, v.maxValueLength)
+	}
+	if v.cardinality < 0 {
+		return fmt.Errorf("%w: negative cardinality limit %d", ErrInvalidView, v.cardinality)
+	}
+	if v.hashedKeys != nil && (len(v.hashedKeys) == 0 || v.hashLength < 0 || v.hashLength > hashSize) {
+		return fmt.Errorf("%w: attribute hashing requires keys and a length of at most %d", ErrInvalidView, hashSize)
+	}
+	for from, to := range v.attributeRenames {
+		if from == "" || to == "" {
+			return fmt.Errorf("%w: empty attribute key in rename %q to %q", ErrInvalidView, from, to)
+		}
+	}
+	for _, kv := range v.extraAttributes {
+		if !kv.Valid() {
+			return fmt.Errorf("%w: invalid extra attribute %q", ErrInvalidView, kv.Key)
+		}
+	}
+	return nil
+}
+
+// validateViews returns an error wrapping ErrInvalidView when two views
+// export their streams with the same name, since the streams of
+// different instruments cannot share a name, unless the views export
+// them identically so that they are merged into one stream.
+func validateViews(views []View) error {
+	names := map[string]View{}
+	for _, v := range views {
+		for _, name := range v.streamNames() {
+			if prev, ok := names[name]; ok && !(name == v.name && sameStream(prev, v)) {
+				return fmt.Errorf("%w: duplicate stream name %q", ErrInvalidView, name)
+			}
+			names[name] = v
+		}
+	}
+	return nil
+}
+
+// sameStream returns true if the views a and b, which give their streams
+// the same name, export them identically.  Views that transform values
+// or partition their streams are never the same, their functions not
+// being comparable.
+func sameStream(a, b View) bool {
+	return a.name == b.name &&
+		a.description == b.description &&
+		a.unit == b.unit &&
+		a.unitFactor == b.unitFactor &&
+		a.transform == nil && b.transform == nil &&
+		a.partitions == nil && b.partitions == nil &&
+		reflect.DeepEqual(a.aggregator, b.aggregator) &&
+		a.preset == b.preset &&
+		a.increments == b.increments &&
+		reflect.DeepEqual(a.incrementBounds, b.incrementBounds) &&
+		a.rate == b.rate &&
+		reflect.DeepEqual(a.attributeKeys, b.attributeKeys) &&
+		reflect.DeepEqual(a.excludedKeys, b.excludedKeys) &&
+		a.maxValueLength == b.maxValueLength &&
+		a.cardinality == b.cardinality &&
+		reflect.DeepEqual(a.hashedKeys, b.hashedKeys) &&
+		bytes.Equal(a.hashSalt, b.hashSalt) &&
+		a.hashLength == b.hashLength &&
+		reflect.DeepEqual(a.pprofLabels, b.pprofLabels) &&
+		reflect.DeepEqual(a.baggageKeys, b.baggageKeys) &&
+		reflect.DeepEqual(a.attributeRenames, b.attributeRenames) &&
+		reflect.DeepEqual(a.extraAttributes, b.extraAttributes)
+}
+
+// selectsOneInstrument returns true if the name selectors of the View
+// match a single instrument name.
+func (v View) selectsOneInstrument() bool {
+	return v.instrumentRegexp == nil && !hasWildcard(v.instrumentGlob) &&
+		(v.instrumentName != "" || v.instrumentGlob != "")
+}
+
+// streamNames returns the names the View gives to streams, omitting those
+// given by a name template.
+func (v View) streamNames() []string {
+	var names []string
+	if v.name != "" && !isNameTemplate(v.name) {
+		names = append(names, v.name)
+	}
+	for _, part := range v.partitions {
+		names = append(names, part.Name)
+	}
+	return names
+}
+
+// WithInstrumentName selects the instrument with the given name.
+func WithInstrumentName(name string) Option {
+	return optionFunc(func(v View) View {
+		v.instrumentName = name
+		return v
+	})
+}
+
+// WithInstrumentNameRegexp selects the instruments whose names match re,
+// so that one View applies to a family of instruments such as
+// `^http\.server\..*`.  Combined with WithInstrumentName, both must
+// match.
+func WithInstrumentNameRegexp(re *regexp.Regexp) Option {
+	return optionFunc(func(v View) View {
+		v.instrumentRegexp = re
+		return v
+	})
+}
+
+// WithInstrumentNameGlob selects the instruments whose names match the
+// shell-style pattern, in which '*' matches any sequence of characters,
+// '?' matches any single character and '[...]' matches a character
+// class, as in `rpc.*.duration` or `db.?.calls`.  It is a lighter-weight
+// alternative to WithInstrumentNameRegexp; the pattern syntax is that of
+// path.Match.  Combined with other name selectors, all must match.
+func WithInstrumentNameGlob(pattern string) Option {
+	return optionFunc(func(v View) View {
+		v.instrumentGlob = pattern
+		return v
+	})
+}
+
+// WithInstrumentNameExcept excludes the instruments whose names match
+// any of the shell-style patterns, with the syntax of
+// WithInstrumentNameGlob, so that a catch-all View can apply to every
+// instrument but a few, as in WithInstrumentNameExcept("go.runtime.*").
+// Combined with other selectors, the instruments they select are
+// excluded.
+func WithInstrumentNameExcept(patterns ...string) Option {
+	copied := append([]string(nil), patterns...)
+	return optionFunc(func(v View) View {
+		v.instrumentExcept = copied
+		return v
+	})
+}
+
+// WithInstrumentUnit selects the instruments with the given unit, so
+// that one View applies to, for example, every duration instrument
+// measured in milliseconds regardless of its name.  Combined with name
+// selectors, all must match.
+func WithInstrumentUnit(u unit.Unit) Option {
+	return optionFunc(func(v View) View {
+		v.instrumentUnit = u
+		return v
+	})
+}
+
+// WithInstrumentKind selects the instruments of any of the given kinds,
+// so that one View applies to, for example, every GaugeObserver.
+// Combined with other selectors, all must match.
+func WithInstrumentKind(kinds ...sdkapi.InstrumentKind) Option {
+	copied := append([]sdkapi.InstrumentKind(nil), kinds...)
+	return optionFunc(func(v View) View {
+		v.instrumentKinds = copied
+		return v
+	})
+}
+
+// WithLibraryName selects the instruments of the instrumentation
+// library with the given name.
+func WithLibraryName(name string) Option {
+	return optionFunc(func(v View) View {
+		v.libraryName = name
+		return v
+	})
+}
+
+// WithLibraryVersionRange selects the instruments of the instrumentation
+// libraries whose semantic version is at least min and less than max,
+// for example to aggregate the instruments of releases of a library
+// before "v0.30.0" differently.  An empty bound leaves that side of the
+// range open; libraries whose version is not a semantic version are not
+// selected.
+func WithLibraryVersionRange(min, max string) Option {
+	return optionFunc(func(v View) View {
+		v.libraryMinVersion = min
+		v.libraryMaxVersion = max
+		return v
+	})
+}
+
+// WithLibrarySchemaURL selects the instruments of the instrumentation
+// libraries that emit telemetry with the given schema URL.
+func WithLibrarySchemaURL(url string) Option {
+	return optionFunc(func(v View) View {
+		v.librarySchemaURL = url
+		return v
+	})
+}
+
+// WithName exports the stream of the selected instrument with the given
+// name.  It requires WithInstrumentName, or a WithInstrumentNameGlob
+// pattern without wildcards, and cannot be combined with selectors that
+// match several instruments, since streams of different instruments
+// cannot share a name.
+//
+// The name may instead be a template naming the stream of each selected
+// instrument, in which {instrument} is replaced by the name of the
+// instrument, {scope} by the name of its instrumentation library and
+// {aggregation} by the lowercase kind of its aggregation, for example
+// "sum" or "histogram".  Templates such as "{scope}.{instrument}" may be
+// combined with any selector, and must contain {instrument} unless the
+// View selects a single instrument.
+func WithName(name string) Option {
+	return optionFunc(func(v View) View {
+		v.name = name
+		return v
+	})
+}
+
+// WithAggregatorSelector aggregates the selected instruments with the
+// Aggregators chosen by sel, for example a histogram with different
+// boundaries.
+func WithAggregatorSelector(sel export.AggregatorSelector) Option {
+	return optionFunc(func(v View) View {
+		v.aggregator = sel
+		return v
+	})
+}
+
+// WithHistogramPreset aggregates the selected instruments into
+// histograms with the boundaries of the named preset of the histogram
+// package, such as histogram.PresetHTTPLatencyMs, rather than copying the
+// same boundaries across programs.  It cannot be combined with
+// WithAggregatorSelector, and sets the boundaries of
+// WithIncrementHistogram when it is given none.  An unknown name is an
+// error of New.
+func WithHistogramPreset(name string) Option {
+	return optionFunc(func(v View) View {
+		v.preset = name
+		return v
+	})
+}
+
+// WithIncrementHistogram exports the stream of a selected Counter as a
+// histogram of the values passed to its Add method, with the given
+// boundaries or the default ones, for example to see the distribution of
+// the sizes of the bytes written with each call.  The stream is described
+// as a Histogram instrument, so that exporters and temporality selectors
+// do not treat it as a monotonic sum, while the measurements are still
+// checked to be non-negative like those of the Counter.  Instruments of
+// other kinds are exported as usual, and the error is reported to the
+// global error handler.
+func WithIncrementHistogram(boundaries ...float64) Option {
+	copied := append([]float64(nil), boundaries...)
+	return optionFunc(func(v View) View {
+		v.increments = true
+		v.incrementBounds = copied
+		return v
+	})
+}
+
+// WithRate exports the stream of a selected Counter or CounterObserver as
+// the per-second rate of its increase between collections, for example
+// to export requests per second rather than a count of requests.  The
+// stream is described as a float64 GaugeObserver, with the last value
+// aggregation, and its unit is suffixed with "/s" unless WithUnit sets
+// it.  The rate of a Counter is the sum of its increments since the
+// prior collection over the elapsed time; that of a CounterObserver is
+// the increase of its observed value, and a value less than the prior
+// one is taken as a reset of the observed counter, such as a restarted
+// process, so that the stream never has negative rates.  Instruments of
+// other kinds are exported as usual, and the error is reported to the
+// global error handler.
+func WithRate() Option {
+	return optionFunc(func(v View) View {
+		v.rate = true
+		return v
+	})
+}
+
+// streamAggregator returns the AggregatorSelector of the streams of the
+// View, nil for the one of the Checkpointer.
+func (v View) streamAggregator() export.AggregatorSelector {
+	switch {
+	case v.increments && v.incrementBounds == nil:
+		preset, _ := histogram.PresetBoundaries(v.preset)
+		return fixedSelector{aggregation: "histogram", boundaries: preset}
+	case v.increments:
+		return fixedSelector{aggregation: "histogram", boundaries: v.incrementBounds}
+	case v.rate:
+		return fixedSelector{aggregation: "lastvalue"}
+	}
+	return v.aggregatorSelector()
+}
+
+// aggregatorSelector returns the AggregatorSelector of WithHistogramPreset
+// or WithAggregatorSelector, nil for none.
+func (v View) aggregatorSelector() export.AggregatorSelector {
+	if preset, ok := histogram.PresetBoundaries(v.preset); ok {
+		return fixedSelector{aggregation: "histogram", boundaries: preset}
+	}
+	return v.aggregator
+}
+
+// WithAttributeKeys keeps only the attributes of the selected instruments
+// with the given keys, aggregating together the measurements whose other
+// attributes differ.  Keys are those of the measurements, before
+// WithAttributeRename applies.
+func WithAttributeKeys(keys ...attribute.Key) Option {
+	kept := make(map[attribute.Key]struct{}, len(keys))
+	for _, k := range keys {
+		kept[k] = struct{}{}
+	}
+	return optionFunc(func(v View) View {
+		v.attributeKeys = kept
+		return v
+	})
+}
+
+// WithKeysInclude keeps only the attributes of the selected instruments
+// with the given keys, like WithAttributeKeys, adding keys to those kept
+// by prior options rather than replacing them.  Combined with
+// WithKeysExclude, an attribute is kept if its key is included and not
+// excluded.
+func WithKeysInclude(keys ...attribute.Key) Option {
+	return optionFunc(func(v View) View {
+		v.attributeKeys = addKeys(v.attributeKeys, keys)
+		return v
+	})
+}
+
+// WithKeysExclude removes the attributes of the selected instruments with
+// the given keys, aggregating together the measurements that only differ
+// by them, for example to remove a high-cardinality "user.id" while
+// keeping every other attribute.  Keys are those of the measurements,
+// before WithAttributeRename applies, and are added to those excluded by
+// prior options.
+func WithKeysExclude(keys ...attribute.Key) Option {
+	return optionFunc(func(v View) View {
+		v.excludedKeys = addKeys(v.excludedKeys, keys)
+		return v
+	})
+}
+
+// addKeys returns a new set holding the keys of set and keys.
+func addKeys(set map[attribute.Key]struct{}, keys []attribute.Key) map[attribute.Key]struct{} {
+	added := make(map[attribute.Key]struct{}, len(set)+len(keys))
+	for k := range set {
+		added[k] = struct{}{}
+	}
+	for _, k := range keys {
+		added[k] = struct{}{}
+	}
+	return added
+}
+
+// WithAttributeValueLengthLimit truncates the string attribute values of
+// the selected instruments, and the elements of their string slice
+// values, to at most n bytes without splitting UTF-8 characters, so that
+// user-supplied values such as URLs do not bloat the exported streams.
+// Measurements whose values only differ beyond n bytes are aggregated
+// together.  A limit of zero, the default, does not truncate values.
+func WithAttributeValueLengthLimit(n int) Option {
+	return optionFunc(func(v View) View {
+		v.maxValueLength = n
+		return v
+	})
+}
+
+// WithCardinalityLimit limits the streams of the selected instruments to
+// n attribute sets: once a stream has exported n sets, the measurements
+// of further sets are aggregated into a single series with the attribute
+// otel.metric.overflow=true, so that unbounded attributes such as user
+// input do not grow the memory of the export pipeline without bounds.
+// The sets are counted after the other options apply, the first overflow
+// of a stream is reported to the global error handler, and the sets stay
+// counted for the lifetime of the stream.  A limit of zero, the default,
+// does not limit streams.
+func WithCardinalityLimit(n int) Option {
+	return optionFunc(func(v View) View {
+		v.cardinality = n
+		return v
+	})
+}
+
+// WithAttributeHashing replaces the values of the attributes of the
+// selected instruments with the given keys by the hexadecimal SHA-256
+// hash of their salted value, truncated to length characters, all 64 of
+// them when length is zero, so that the streams of user identifiers can
+// still be joined without exporting them.  The hashes are computed over
+// salt followed by the string form of the value, as given by Emit, and
+// the salt is kept out of String.  Keys are those of the measurements, before
+// WithAttributeRename applies, and hashed values are not truncated by
+// WithAttributeValueLengthLimit.
+func WithAttributeHashing(salt []byte, length int, keys ...attribute.Key) Option {
+	copied := append([]byte(nil), salt...)
+	return optionFunc(func(v View) View {
+		v.hashedKeys = addKeys(v.hashedKeys, keys)
+		v.hashSalt = copied
+		v.hashLength = length
+		return v
+	})
+}
+
+// WithPprofLabels adds the pprof labels of the goroutine recording a
+// measurement of the selected synchronous instruments, set with
+// pprof.Do or pprof.SetGoroutineLabels, to its attributes, so that the
+// dimensions of CPU profiles and metrics match.  Only the labels with the
+// given keys are added, every label when none is given.  The attributes
+// of the measurement take precedence over labels with the same key, and
+// WithAttributeKeys must keep the keys of the labels.
+//
+// The labels are read from the context of the measurement by the SDK,
+// which requires the Registry to be passed to the WithContextAttributes
+// option of the SDK or of its controller.  Views selecting
+// instrumentation libraries do not add labels.
+func WithPprofLabels(keys ...string) Option {
+	labels := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		labels[k] = struct{}{}
+	}
+	return optionFunc(func(v View) View {
+		v.pprofLabels = labels
+		return v
+	})
+}
+
+// WithBaggageKeys adds the members of the baggage of the context of a
+// measurement of the selected synchronous instruments with the given
+// keys to its attributes, so that request-scoped dimensions such as a
+// tenant or a region propagated in baggage are added to measurements
+// without every call site adding them.  Members that are not set are not
+// added, and keys are added to those of prior options.  Like
+// WithPprofLabels, this requires the Registry to be passed to the
+// WithContextAttributes option of the SDK or of its controller, the
+// attributes of the measurement take precedence over members with the
+// same key, and WithAttributeKeys must keep their keys.
+func WithBaggageKeys(keys ...string) Option {
+	return optionFunc(func(v View) View {
+		added := append([]string(nil), v.baggageKeys...)
+		v.baggageKeys = append(added, keys...)
+		return v
+	})
+}
+
+// addsContextAttributes returns true if the View adds attributes from
+// the context of measurements.
+func (v View) addsContextAttributes() bool {
+	return v.pprofLabels != nil || len(v.baggageKeys) != 0
+}
+
+// WithAttributeRename renames the attributes of the selected instruments
+// whose keys are keys of renames to the corresponding value, for example
+// to export "http.status_code" as "status" to one backend without
+// changing the instrumentation.  A renamed attribute replaces an
+// attribute that already has the new key.
+func WithAttributeRename(renames map[string]string) Option {
+	copied := make(map[attribute.Key]attribute.Key, len(renames))
+	for from, to := range renames {
+		copied[attribute.Key(from)] = attribute.Key(to)
+	}
+	return optionFunc(func(v View) View {
+		v.attributeRenames = copied
+		return v
+	})
+}
+
+// WithExtraAttributes adds constant attributes to the streams of the
+// selected instruments, for example the team owning a service or its
+// deployment ring, without changing the instrumentation.  An extra
+// attribute replaces an attribute of a measurement, renamed or not, that
+// has the same key.
+func WithExtraAttributes(kvs ...attribute.KeyValue) Option {
+	copied := append([]attribute.KeyValue(nil), kvs...)
+	return optionFunc(func(v View) View {
+		v.extraAttributes = copied
+		return v
+	})
+}
+
+// WithPartitions splits the stream of the selected instrument as the
+// Processor of the go.opentelemetry.io/otel/sdk/metric/processor/partition
+// package does, exporting the measurements whose attribute set matches a
+// partition as the stream of the first such partition, for example to
+// export GET requests with a histogram and others as a sum:
+//
+//	view.New(
+//	        view.WithInstrumentName("http.server.duration"),
+//	        view.WithName("http.server.duration.other"),
+//	        view.WithAggregatorSelector(simple.NewWithInexpensiveDistribution()),
+//	        view.WithPartitions(partition.Partition{
+//	                Name:       "http.server.duration.get",
+//	                Match:      partition.AttributeEquals("http.method", "GET"),
+//	                Aggregator: simple.NewWithHistogramDistribution(),
+//	        }),
+//	)
+//
+// Other measurements are exported as the stream of the View, and
+// partitions without an Aggregator use the Aggregators of the View.  The
+// Instrument of a partition may be left empty, and otherwise must name
+// the selected instrument.  Like WithName, it requires a single-instrument
+// selector.  The SDK evaluates the predicates once for each attribute
+// set, when it creates the record of the set, so that each partition may
+// use its own kind of Aggregator.
+func WithPartitions(partitions ...partition.Partition) Option {
+	copied := append([]partition.Partition(nil), partitions...)
+	return optionFunc(func(v View) View {
+		v.partitions = copied
+		return v
+	})
+}
+
+// WithUnitConversion exports the streams of the selected instruments in
+// unit u, multiplying every measurement by factor, for example
+// WithUnitConversion(unit.Seconds, 1e-3) for instruments measured in
+// milliseconds or WithUnitConversion("MiBy", 1.0/(1<<20)) for instruments
+// measured in bytes.  Measurements are converted when they are recorded,
+// so that histogram boundaries are in unit u.  The measurements of
+// integer instruments are rounded to the nearest integer.
+func WithUnitConversion(u unit.Unit, factor float64) Option {
+	return optionFunc(func(v View) View {
+		v.unit = u
+		v.unitFactor = factor
+		return v
+	})
+}
+
+// WithDescription exports the streams of the selected instruments with
+// the given description instead of that of the instrument.
+func WithDescription(description string) Option {
+	return optionFunc(func(v View) View {
+		v.description = description
+		return v
+	})
+}
+
+// WithUnit exports the streams of the selected instruments with unit u
+// instead of that of the instrument, without converting measurements, for
+// example to set the missing unit of instruments of a library.  See
+// WithUnitConversion to convert measurements to another unit.
+func WithUnit(u unit.Unit) Option {
+	return WithUnitConversion(u, 1)
+}
+
+// WithTransform applies fn to every measurement of the selected
+// instruments before it is aggregated, for example to clamp values or to
+// convert ratios to percentages.  With WithUnitConversion, fn transforms
+// measurements in the converted unit.  The results of integer instruments
+// are rounded to the nearest integer, and measurements that fn maps to NaN
+// or an infinity are dropped.  fn may be called concurrently.
+func WithTransform(fn func(float64) float64) Option {
+	return optionFunc(func(v View) View {
+		v.transform = fn
+		return v
+	})
+}
+
+// valueTransform returns the function converting the measurements of the
+// streams of the View, or nil when they are aggregated unchanged.
+func (v View) valueTransform() func(float64) float64 {
+	factor, fn := v.unitFactor, v.transform
+	switch {
+	case factor == 0 || factor == 1:
+		return fn
+	case fn == nil:
+		return func(x float64) float64 { return x * factor }
+	}
+	return func(x float64) float64 { return fn(x * factor) }
+}
+
+// WithDrop drops the selected instruments.  Their measurements are not
+// aggregated.
+func WithDrop() Option {
+	return optionFunc(func(v View) View {
+		v.drop = true
+		return v
+	})
+}
+
+// DropUnmatched returns a View dropping every instrument.  Given last,
+// after the views of the instruments to export, it turns the views into
+// an allow-list: instruments matching no other View are dropped instead
+// of being exported with their default aggregation.  Views registered
+// later take precedence over it, see Registry.Register.
+func DropUnmatched() View {
+	return View{drop: true}
+}
+
+// WithDropMeasurements drops the measurements of the selected
+// instruments whose attribute set matches, for example the requests of
+// health checks:
+//
+//	view.WithDropMeasurements(func(attrs *attribute.Set) bool {
+//	        route, _ := attrs.Value("http.route")
+//	        return route.AsString() == "/healthz"
+//	})
+//
+// The SDK evaluates match once for each attribute set of an instrument,
+// when it creates the record of the set, and does not aggregate the
+// measurements of its records that match.  The attribute set is the one
+// of the measurements, before attributes are renamed.
+func WithDropMeasurements(match func(*attribute.Set) bool) Option {
+	return optionFunc(func(v View) View {
+		v.dropMeasurements = match
+		return v
+	})
+}
+
+// matches returns true if the View selects the instrument described by
+// desc of the given library.
+func (v View) matches(library instrumentation.Library, desc *sdkapi.Descriptor) bool {
+	if !v.matchesLibrary(library) {
+		return false
+	}
+	if v.instrumentName != "" && v.instrumentName != desc.Name() {
+		return false
+	}
+	if v.instrumentRegexp != nil && !v.instrumentRegexp.MatchString(desc.Name()) {
+		return false
+	}
+	if v.instrumentUnit != "" && v.instrumentUnit != desc.Unit() {
+		return false
+	}
+	if v.instrumentKinds != nil && !v.matchesKind(desc.InstrumentKind()) {
+		return false
+	}
+	if v.instrumentGlob != "" {
+		// The pattern is validated by New.
+		if ok, _ := path.Match(v.instrumentGlob, desc.Name()); !ok {
+			return false
+		}
+	}
+	for _, pattern := range v.instrumentExcept {
+		if ok, _ := path.Match(pattern, desc.Name()); ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (v View) matchesKind(kind sdkapi.InstrumentKind) bool {
+	for _, k := range v.instrumentKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (v View) matchesLibrary(library instrumentation.Library) bool {
+	if v.libraryName != "" && v.libraryName != library.Name {
+		return false
+	}
+	if v.librarySchemaURL != "" && v.librarySchemaURL != library.SchemaURL {
+		return false
+	}
+	if v.libraryMinVersion == "" && v.libraryMaxVersion == "" {
+		return true
+	}
+	if _, ok := parseVersion(library.Version); !ok {
+		return false
+	}
+	if v.libraryMinVersion != "" && compareVersions(library.Version, v.libraryMinVersion) < 0 {
+		return false
+	}
+	if v.libraryMaxVersion != "" && compareVersions(library.Version, v.libraryMaxVersion) >= 0 {
+		return false
+	}
+	return true
+}
+
+// selectsLibrary returns true if the View selects instruments by their
+// instrumentation library.
+func (v View) selectsLibrary() bool {
+	return v.libraryName != "" || v.libraryMinVersion != "" || v.libraryMaxVersion != "" || v.librarySchemaURL != ""
+}
+
+// String returns a description of the selectors and behaviour of the
+// View, for debugging.
+func (v View) String() string {
+	var parts []string
+	if v.instrumentName != "" {
+		parts = append(parts, fmt.Sprintf("instrument=%q", v.instrumentName))
+	}
+	if v.instrumentRegexp != nil {
+		parts = append(parts, fmt.Sprintf("regexp=%q", v.instrumentRegexp.String()))
+	}
+	if v.instrumentGlob != "" {
+		parts = append(parts, fmt.Sprintf("glob=%q", v.instrumentGlob))
+	}
+	if len(v.instrumentExcept) != 0 {
+		parts = append(parts, "except=["+strings.Join(v.instrumentExcept, " ")+"]")
+	}
+	if v.instrumentUnit != "" {
+		parts = append(parts, fmt.Sprintf("unit=%q", v.instrumentUnit))
+	}
+	if v.instrumentKinds != nil {
+		var kinds []string
+		for _, k := range v.instrumentKinds {
+			kinds = append(kinds, k.String())
+		}
+		parts = append(parts, "kinds=["+strings.Join(kinds, " ")+"]")
+	}
+	if v.libraryName != "" {
+		parts = append(parts, fmt.Sprintf("library=%q", v.libraryName))
+	}
+	if v.libraryMinVersion != "" || v.libraryMaxVersion != "" {
+		parts = append(parts, fmt.Sprintf("versions=[%s,%s)", v.libraryMinVersion, v.libraryMaxVersion))
+	}
+	if v.librarySchemaURL != "" {
+		parts = append(parts, fmt.Sprintf("schemaURL=%q", v.librarySchemaURL))
+	}
+	if v.name != "" {
+		parts = append(parts, fmt.Sprintf("name=%q", v.name))
+	}
+	if v.aggregator != nil {
+		parts = append(parts, fmt.Sprintf("aggregator=%T", v.aggregator))
+	}
+	if v.preset != "" {
+		parts = append(parts, fmt.Sprintf("preset=%q", v.preset))
+	}
+	if v.increments {
+		parts = append(parts, fmt.Sprintf("increments=%v", v.incrementBounds))
+	}
+	if v.rate {
+		parts = append(parts, "rate")
+	}
+	if v.description != "" {
+		parts = append(parts, fmt.Sprintf("description=%q", v.description))
+	}
+	if v.unit != "" && v.unitFactor == 1 {
+		parts = append(parts, fmt.Sprintf("setUnit=%q", v.unit))
+	} else if v.unit != "" {
+		parts = append(parts, fmt.Sprintf("convert=%q*%v", v.unit, v.unitFactor))
+	}
+	if v.transform != nil {
+		parts = append(parts, "transform")
+	}
+	if v.attributeKeys != nil {
+		var keys []string
+		for k := range v.attributeKeys {
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+		parts = append(parts, "keys=["+strings.Join(keys, " ")+"]")
+	}
+	if v.excludedKeys != nil {
+		var keys []string
+		for k := range v.excludedKeys {
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+		parts = append(parts, "exclude=["+strings.Join(keys, " ")+"]")
+	}
+	if v.maxValueLength != 0 {
+		parts = append(parts, fmt.Sprintf("maxValueLength=%d", v.maxValueLength))
+	}
+	if v.cardinality != 0 {
+		parts = append(parts, fmt.Sprintf("cardinalityLimit=%d", v.cardinality))
+	}
+	if v.hashedKeys != nil {
+		var keys []string
+		for k := range v.hashedKeys {
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+		parts = append(parts, fmt.Sprintf("hash=[%s]/%d", strings.Join(keys, " "), v.hashLength))
+	}
+	if v.pprofLabels != nil {
+		var keys []string
+		for k := range v.pprofLabels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts = append(parts, "pprof=["+strings.Join(keys, " ")+"]")
+	}
+	if len(v.baggageKeys) != 0 {
+		parts = append(parts, "baggage=["+strings.Join(v.baggageKeys, " ")+"]")
+	}
+	if len(v.attributeRenames) != 0 {
+		var renames []string
+		for from, to := range v.attributeRenames {
+			renames = append(renames, fmt.Sprintf("%s->%s", from, to))
+		}
+		sort.Strings(renames)
+		parts = append(parts, "renames=["+strings.Join(renames, " ")+"]")
+	}
+	if len(v.extraAttributes) != 0 {
+		var extra []string
+		for _, kv := range v.extraAttributes {
+			extra = append(extra, fmt.Sprintf("%s=%s", kv.Key, kv.Value.Emit()))
+		}
+		parts = append(parts, "extra=["+strings.Join(extra, " ")+"]")
+	}
+	if len(v.partitions) != 0 {
+		var names []string
+		for _, part := range v.partitions {
+			names = append(names, part.Name)
+		}
+		parts = append(parts, "partitions=["+strings.Join(names, " ")+"]")
+	}
+	if v.dropMeasurements != nil {
+		parts = append(parts, "dropMeasurements")
+	}
+	if v.drop {
+		parts = append(parts, "drop")
+	}
+	return "View{" + strings.Join(parts, " ") + "}"
+}
+
+// hasWildcard returns true if the glob pattern may match more than one
+// name.
+func hasWildcard(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
@@ -0,0 +1,1457 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"runtime/pprof"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
+	controller "go.opentelemetry.io/otel/sdk/metric/controller/basic"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/processor/basic"
+	"go.opentelemetry.io/otel/sdk/metric/processor/partition"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/processor/view"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+)
+
+func mustView(t *testing.T, opts ...view.Option) view.View {
+	v, err := view.New(opts...)
+	require.NoError(t, err)
+	return v
+}
+
+func TestViewProcessor(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t,
+			view.WithInstrumentNameRegexp(regexp.MustCompile(`^http\.server\..*`)),
+			view.WithAggregatorSelector(simple.NewWithHistogramDistribution(
+				histogram.WithExplicitBoundaries([]float64{10}),
+			)),
+		),
+		mustView(t, view.WithInstrumentName("requests.sum"), view.WithName("requests.total.sum")),
+		mustView(t, view.WithInstrumentNameRegexp(regexp.MustCompile(`^noisy\.`)), view.WithDrop()),
+	}
+	selector := view.NewSelector(processorTest.AggregatorSelector(), views...)
+	proc, err := view.NewProcessor(basic.New(selector, aggregation.CumulativeTemporalitySelector()), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(proc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	duration, err := meter.SyncFloat64().Histogram("http.server.duration.histogram")
+	require.NoError(t, err)
+	size, err := meter.SyncInt64().Histogram("http.server.size.histogram")
+	require.NoError(t, err)
+	requests, err := meter.SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	noisy, err := meter.SyncInt64().Counter("noisy.sum")
+	require.NoError(t, err)
+	other, err := meter.SyncInt64().Counter("other.sum")
+	require.NoError(t, err)
+
+	for _, v := range []float64{1, 20} {
+		duration.Record(ctx, v, attribute.String("A", "B"))
+		duration.Record(ctx, v, attribute.String("A", "C"))
+	}
+	size.Record(ctx, 100)
+	requests.Add(ctx, 2)
+	noisy.Add(ctx, 3)
+	other.Add(ctx, 4)
+
+	proc.StartCollection()
+	require.Equal(t, 5, accum.Collect(ctx))
+	require.NoError(t, proc.FinishCollection())
+
+	boundaries := map[string][]float64{}
+	values := map[string]float64{}
+	require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		key := rec.Descriptor().Name() + "/" + rec.Attributes().Encoded(attribute.DefaultEncoder())
+		if h, ok := rec.Aggregation().(aggregation.Histogram); ok {
+			buckets, err := h.Histogram()
+			require.NoError(t, err)
+			boundaries[rec.Descriptor().Name()] = buckets.Boundaries
+		}
+		sum, err := rec.Aggregation().(aggregation.Sum).Sum()
+		require.NoError(t, err)
+		values[key] = sum.CoerceToFloat64(rec.Descriptor().NumberKind())
+		return nil
+	}))
+	require.Equal(t, map[string][]float64{
+		"http.server.duration.histogram": {10},
+		"http.server.size.histogram":     {10},
+	}, boundaries)
+	require.Equal(t, map[string]float64{
+		"http.server.duration.histogram/A=B": 21,
+		"http.server.duration.histogram/A=C": 21,
+		"http.server.size.histogram/":        100,
+		"requests.total.sum/":                2,
+		"other.sum/":                         4,
+	}, values)
+}
+
+func TestNewValidation(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []view.Option
+	}{
+		{"name without instrument", []view.Option{view.WithName("x")}},
+		{"name with regexp", []view.Option{
+			view.WithInstrumentNameRegexp(regexp.MustCompile(`.*`)),
+			view.WithName("x"),
+		}},
+		{"name with exact and regexp", []view.Option{
+			view.WithInstrumentName("a"),
+			view.WithInstrumentNameRegexp(regexp.MustCompile(`^a$`)),
+			view.WithName("x"),
+		}},
+		{"name with glob", []view.Option{
+			view.WithInstrumentNameGlob("rpc.*.duration"),
+			view.WithName("x"),
+		}},
+		{"bad glob", []view.Option{
+			view.WithInstrumentNameGlob("rpc.[.duration"),
+		}},
+		{"drop with name", []view.Option{
+			view.WithInstrumentName("a"),
+			view.WithName("x"),
+			view.WithDrop(),
+		}},
+		{"empty attribute key", []view.Option{
+			view.WithAttributeRename(map[string]string{"a": ""}),
+		}},
+		{"drop with aggregator", []view.Option{
+			view.WithInstrumentName("a"),
+			view.WithAggregatorSelector(simple.NewWithInexpensiveDistribution()),
+			view.WithDrop(),
+		}},
+		{"bad library version", []view.Option{
+			view.WithLibraryVersionRange("latest", ""),
+		}},
+		{"empty library version range", []view.Option{
+			view.WithLibraryVersionRange("v1.2.0", "v1.2"),
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := view.New(tc.opts...)
+			require.ErrorIs(t, err, view.ErrInvalidView)
+		})
+	}
+
+	_, err := view.New(view.WithInstrumentNameGlob("db.calls"), view.WithName("b"))
+	require.NoError(t, err)
+}
+
+func TestNewProcessorValidation(t *testing.T) {
+	views := []view.View{
+		mustView(t, view.WithInstrumentName("a.sum"), view.WithName("x.sum")),
+		mustView(t, view.WithInstrumentName("b.sum"), view.WithName("x.sum"), view.WithDescription("b")),
+	}
+	ckpter := processorTest.NewCheckpointer(processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder()))
+	_, err := view.NewProcessor(ckpter, views...)
+	require.ErrorIs(t, err, view.ErrInvalidView)
+
+	_, err = view.NewFactory(processorTest.NewCheckpointerFactory(processorTest.AggregatorSelector(), attribute.DefaultEncoder()), views...)
+	require.ErrorIs(t, err, view.ErrInvalidView)
+
+	_, err = view.NewProcessor(ckpter, views[0], mustView(t, view.WithInstrumentName("b.sum"), view.WithName("y.sum")))
+	require.NoError(t, err)
+}
+
+func TestInstrumentNameGlob(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentNameGlob("rpc.*.duration.sum"), view.WithDrop()),
+		mustView(t, view.WithInstrumentNameGlob("db.?.calls.sum"), view.WithDrop()),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	viewProc, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(viewProc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	for _, name := range []string{
+		"rpc.client.duration.sum",
+		"rpc.server.duration.sum",
+		"rpc.duration.sum",
+		"db.a.calls.sum",
+		"db.ab.calls.sum",
+	} {
+		counter, err := meter.SyncInt64().Counter(name)
+		require.NoError(t, err)
+		counter.Add(ctx, 1)
+	}
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"rpc.duration.sum//": 1,
+		"db.ab.calls.sum//":  1,
+	}, proc.Values())
+}
+
+func TestInstrumentNameExcept(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentNameExcept("go.runtime.*", "process.*"), view.WithDrop()),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	viewProc, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(viewProc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	for _, name := range []string{
+		"go.runtime.gc.sum",
+		"process.cpu.sum",
+		"requests.sum",
+		"go.calls.sum",
+	} {
+		counter, err := meter.SyncInt64().Counter(name)
+		require.NoError(t, err)
+		counter.Add(ctx, 1)
+	}
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"go.runtime.gc.sum//": 1,
+		"process.cpu.sum//":   1,
+	}, proc.Values())
+	require.Equal(t, "View{except=[go.runtime.* process.*] drop}", views[0].String())
+
+	_, err = view.New(view.WithInstrumentNameExcept("go.["))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestInstrumentKind(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentKind(sdkapi.UpDownCounterInstrumentKind, sdkapi.HistogramInstrumentKind), view.WithDrop()),
+		mustView(t, view.WithInstrumentKind(sdkapi.CounterInstrumentKind), view.WithInstrumentNameGlob("other.*"), view.WithDrop()),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	viewProc, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(viewProc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	requests, err := meter.SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	other, err := meter.SyncInt64().Counter("other.sum")
+	require.NoError(t, err)
+	active, err := meter.SyncInt64().UpDownCounter("active.sum")
+	require.NoError(t, err)
+	latency, err := meter.SyncFloat64().Histogram("latency.sum")
+	require.NoError(t, err)
+
+	requests.Add(ctx, 1)
+	other.Add(ctx, 2)
+	active.Add(ctx, 4)
+	latency.Record(ctx, 8)
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"requests.sum//": 1,
+	}, proc.Values())
+	require.Equal(t, "View{kinds=[UpDownCounterInstrumentKind HistogramInstrumentKind] drop}", views[0].String())
+}
+
+func TestDropUnmatched(t *testing.T) {
+	ctx := context.Background()
+	registry, err := view.NewRegistry(
+		mustView(t, view.WithInstrumentName("requests.sum")),
+		mustView(t, view.WithInstrumentNameGlob("db.*"), view.WithAttributeKeys("db.system")),
+		view.DropUnmatched(),
+	)
+	require.NoError(t, err)
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	accum := metricsdk.NewAccumulator(registry.Processor(processorTest.NewCheckpointer(proc)))
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	// A registered View takes precedence over DropUnmatched.
+	unregister, err := registry.Register(mustView(t, view.WithInstrumentName("debug.sum")))
+	require.NoError(t, err)
+	defer unregister()
+
+	for _, name := range []string{"requests.sum", "db.calls.sum", "other.sum", "debug.sum"} {
+		counter, err := meter.SyncInt64().Counter(name)
+		require.NoError(t, err)
+		counter.Add(ctx, 1, attribute.String("db.system", "pg"), attribute.String("db.user", "u"))
+	}
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/db.system=pg,db.user=u/": 1,
+		"db.calls.sum/db.system=pg/":           1,
+		"debug.sum/db.system=pg,db.user=u/":    1,
+	}, proc.Values())
+	require.Equal(t, "View{drop}", view.DropUnmatched().String())
+}
+
+func TestKeysIncludeExclude(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentName("excluded.sum"), view.WithKeysExclude("user.id"), view.WithKeysExclude("session")),
+		mustView(t, view.WithInstrumentName("both.sum"), view.WithKeysInclude("A"), view.WithKeysInclude("B", "user.id"), view.WithKeysExclude("user.id")),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	vp, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(vp)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	for _, name := range []string{"excluded.sum", "both.sum"} {
+		counter, err := meter.SyncInt64().Counter(name)
+		require.NoError(t, err)
+		for _, user := range []string{"u1", "u2"} {
+			counter.Add(ctx, 1,
+				attribute.String("A", "a"),
+				attribute.String("B", "b"),
+				attribute.String("C", "c"),
+				attribute.String("session", user),
+				attribute.String("user.id", user),
+			)
+		}
+	}
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"excluded.sum/A=a,B=b,C=c/": 2,
+		"both.sum/A=a,B=b/":         2,
+	}, proc.Values())
+	require.Equal(t, `View{instrument="excluded.sum" exclude=[session user.id]}`, views[0].String())
+	require.Equal(t, `View{instrument="both.sum" keys=[A B user.id] exclude=[user.id]}`, views[1].String())
+}
+
+func TestAttributeValueLengthLimit(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentName("requests.sum"), view.WithAttributeValueLengthLimit(6)),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	vp, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(vp)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	counter, err := meter.SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	counter.Add(ctx, 1, attribute.String("url", "/users/1"), attribute.Int("code", 200))
+	counter.Add(ctx, 1, attribute.String("url", "/users/2"), attribute.Int("code", 200))
+	// "é" takes two bytes and is not split.
+	counter.Add(ctx, 1, attribute.String("url", "/xcafé"), attribute.StringSlice("tags", []string{"short", "toolong"}))
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/code=200,url=/users/":           2,
+		"requests.sum/tags=[short toolon],url=/xcaf/": 1,
+	}, proc.Values())
+	require.Equal(t, `View{instrument="requests.sum" maxValueLength=6}`, views[0].String())
+
+	_, err = view.New(view.WithAttributeValueLengthLimit(-1))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestHistogramPreset(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentName("http.server.duration"), view.WithHistogramPreset(histogram.PresetHTTPLatencyMs)),
+		mustView(t, view.WithInstrumentName("writes.sum"), view.WithIncrementHistogram(), view.WithHistogramPreset(histogram.PresetPayloadSizeBytes)),
+	}
+	selector := view.NewSelector(processorTest.AggregatorSelector(), views...)
+	proc := basic.New(selector, aggregation.CumulativeTemporalitySelector())
+	vp, err := view.NewProcessor(proc, views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(vp)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	duration, err := meter.SyncFloat64().Histogram("http.server.duration")
+	require.NoError(t, err)
+	duration.Record(ctx, 42)
+	writes, err := meter.SyncInt64().Counter("writes.sum")
+	require.NoError(t, err)
+	writes.Add(ctx, 512)
+
+	proc.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, proc.FinishCollection())
+	boundaries := map[string][]float64{}
+	require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		buckets, err := rec.Aggregation().(aggregation.Histogram).Histogram()
+		require.NoError(t, err)
+		boundaries[rec.Descriptor().Name()] = buckets.Boundaries
+		return nil
+	}))
+	latency, _ := histogram.PresetBoundaries(histogram.PresetHTTPLatencyMs)
+	sizes, _ := histogram.PresetBoundaries(histogram.PresetPayloadSizeBytes)
+	require.Equal(t, map[string][]float64{
+		"http.server.duration": latency,
+		"writes.sum":           sizes,
+	}, boundaries)
+	require.Equal(t, `View{instrument="http.server.duration" preset="http-latency-ms"}`, views[0].String())
+
+	_, err = view.New(view.WithHistogramPreset("unknown"))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+	_, err = view.New(view.WithHistogramPreset(histogram.PresetQueueDepth), view.WithAggregatorSelector(processorTest.AggregatorSelector()))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestIncrementHistogram(t *testing.T) {
+	ctx := context.Background()
+	var handled []error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { handled = append(handled, err) }))
+	defer otel.SetErrorHandler(otel.ErrorHandlerFunc(func(error) {}))
+
+	views := []view.View{
+		mustView(t, view.WithInstrumentName("bytes.sum"), view.WithIncrementHistogram(10, 100)),
+		mustView(t, view.WithInstrumentName("queue.sum"), view.WithIncrementHistogram()),
+	}
+	selector := view.NewSelector(processorTest.AggregatorSelector(), views...)
+	proc := basic.New(selector, aggregation.CumulativeTemporalitySelector())
+	vp, err := view.NewProcessor(proc, views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(vp)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	bytes, err := meter.SyncInt64().Counter("bytes.sum")
+	require.NoError(t, err)
+	queue, err := meter.SyncInt64().UpDownCounter("queue.sum")
+	require.NoError(t, err)
+	for _, n := range []int64{1, 5, 50, 500} {
+		bytes.Add(ctx, n)
+	}
+	// Negative increments are still rejected by the Counter.
+	bytes.Add(ctx, -1)
+	queue.Add(ctx, 2)
+
+	proc.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, proc.FinishCollection())
+	records := map[string]export.Record{}
+	require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		records[rec.Descriptor().Name()] = rec
+		return nil
+	}))
+	require.Len(t, records, 2)
+
+	rec := records["bytes.sum"]
+	require.Equal(t, sdkapi.HistogramInstrumentKind, rec.Descriptor().InstrumentKind())
+	buckets, err := rec.Aggregation().(aggregation.Histogram).Histogram()
+	require.NoError(t, err)
+	require.Equal(t, []float64{10, 100}, buckets.Boundaries)
+	require.Equal(t, []uint64{2, 1, 1}, buckets.Counts)
+
+	// Only Counters are exported as histograms of their increments.
+	rec = records["queue.sum"]
+	require.Equal(t, sdkapi.UpDownCounterInstrumentKind, rec.Descriptor().InstrumentKind())
+	sum, err := rec.Aggregation().(aggregation.Sum).Sum()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), sum.AsInt64())
+	require.Len(t, handled, 2)
+	require.ErrorIs(t, handled[1], view.ErrInvalidView)
+	require.Equal(t, `View{instrument="bytes.sum" increments=[10 100]}`, views[0].String())
+
+	_, err = view.New(view.WithIncrementHistogram(), view.WithAggregatorSelector(processorTest.AggregatorSelector()))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestCardinalityLimit(t *testing.T) {
+	ctx := context.Background()
+	var handled []error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { handled = append(handled, err) }))
+	defer otel.SetErrorHandler(otel.ErrorHandlerFunc(func(error) {}))
+
+	views := []view.View{
+		mustView(t, view.WithInstrumentName("requests.sum"), view.WithCardinalityLimit(2)),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	vp, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(vp)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	counter, err := meter.SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	// The order of the attribute sets of one collection is not defined.
+	counter.Add(ctx, 1, attribute.String("user", "a"))
+	counter.Add(ctx, 1, attribute.String("user", "b"))
+	accum.Collect(ctx)
+	for _, user := range []string{"c", "d", "a"} {
+		counter.Add(ctx, 1, attribute.String("user", user))
+	}
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/user=a/":                    2,
+		"requests.sum/user=b/":                    1,
+		"requests.sum/otel.metric.overflow=true/": 2,
+	}, proc.Values())
+	require.Len(t, handled, 1)
+	require.Equal(t, `View{instrument="requests.sum" cardinalityLimit=2}`, views[0].String())
+
+	_, err = view.New(view.WithCardinalityLimit(-1))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestAttributeHashing(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentName("requests.sum"), view.WithAttributeHashing([]byte("salt"), 8, "user.id", "account")),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	vp, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(vp)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	counter, err := meter.SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	counter.Add(ctx, 1, attribute.String("user.id", "alice"), attribute.String("code", "200"))
+	counter.Add(ctx, 2, attribute.String("user.id", "alice"), attribute.String("code", "200"))
+	counter.Add(ctx, 1, attribute.String("user.id", "bob"), attribute.Int("account", 42))
+	accum.Collect(ctx)
+
+	hash := func(value string) string {
+		sum := sha256.Sum256([]byte("salt" + value))
+		return hex.EncodeToString(sum[:])[:8]
+	}
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/code=200,user.id=" + hash("alice") + "/":                 3,
+		"requests.sum/account=" + hash("42") + ",user.id=" + hash("bob") + "/": 1,
+	}, proc.Values())
+	require.Equal(t, `View{instrument="requests.sum" hash=[account user.id]/8}`, views[0].String())
+
+	_, err = view.New(view.WithAttributeHashing(nil, 0))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+	_, err = view.New(view.WithAttributeHashing(nil, 65, "user.id"))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestRate(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentNameGlob("*.total"), view.WithRate()),
+	}
+	selector := view.NewSelector(processorTest.AggregatorSelector(), views...)
+	proc := basic.New(selector, aggregation.CumulativeTemporalitySelector())
+	vp, err := view.NewProcessor(proc, views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(vp)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	start := time.Now()
+	requests, err := meter.SyncInt64().Counter("requests.total", instrument.WithUnit("{requests}"))
+	require.NoError(t, err)
+	bytes, err := meter.AsyncInt64().Counter("bytes.total")
+	require.NoError(t, err)
+	observed := []int64{100, 150, 20}
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{bytes}, func(ctx context.Context) {
+		bytes.Observe(ctx, observed[0])
+		observed = observed[1:]
+	}))
+	requests.Add(ctx, 10)
+
+	// collect returns the rates of the collection, and the bounds of the
+	// time elapsed since the prior one.
+	var prior time.Time
+	collect := func() (map[string]float64, time.Duration, time.Duration) {
+		time.Sleep(10 * time.Millisecond)
+		before := time.Now()
+		proc.StartCollection()
+		accum.Collect(ctx)
+		require.NoError(t, vp.FinishCollection())
+		after := time.Now()
+		min, max := before.Sub(prior), after.Sub(start)
+		start, prior = before, after
+		rates := map[string]float64{}
+		require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+			require.Equal(t, sdkapi.GaugeObserverInstrumentKind, rec.Descriptor().InstrumentKind())
+			require.Equal(t, number.Float64Kind, rec.Descriptor().NumberKind())
+			value, _, err := rec.Aggregation().(aggregation.LastValue).LastValue()
+			require.NoError(t, err)
+			rates[fmt.Sprintf("%s[%s]", rec.Descriptor().Name(), rec.Descriptor().Unit())] = value.AsFloat64()
+			return nil
+		}))
+		return rates, min, max
+	}
+	inRange := func(rate, increase float64, min, max time.Duration) {
+		require.GreaterOrEqual(t, rate, increase/max.Seconds())
+		require.LessOrEqual(t, rate, increase/min.Seconds())
+	}
+
+	// The first observation of bytes.total has no rate.
+	prior = time.Now()
+	rates, min, max := collect()
+	require.Len(t, rates, 1)
+	inRange(rates["requests.total[{requests}/s]"], 10, min, max)
+
+	requests.Add(ctx, 5)
+	rates, min, max = collect()
+	require.Len(t, rates, 2)
+	inRange(rates["requests.total[{requests}/s]"], 5, min, max)
+	inRange(rates["bytes.total[1/s]"], 50, min, max)
+
+	// The observed counter was reset, and requests.total had no
+	// increments.
+	rates, min, max = collect()
+	require.Equal(t, float64(0), rates["requests.total[{requests}/s]"])
+	inRange(rates["bytes.total[1/s]"], 20, min, max)
+	require.Equal(t, `View{glob="*.total" rate}`, views[0].String())
+
+	_, err = view.New(view.WithRate(), view.WithIncrementHistogram())
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestPprofLabels(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentName("requests.sum"), view.WithPprofLabels("endpoint")),
+		mustView(t, view.WithInstrumentName("jobs.sum"), view.WithPprofLabels()),
+	}
+	registry, err := view.NewRegistry(views...)
+	require.NoError(t, err)
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	accum := metricsdk.NewAccumulator(registry.Processor(processorTest.NewCheckpointer(proc)), metricsdk.WithContextAttributes(registry))
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	for _, name := range []string{"requests.sum", "jobs.sum", "other.sum"} {
+		counter, err := meter.SyncInt64().Counter(name)
+		require.NoError(t, err)
+		pprof.Do(ctx, pprof.Labels("endpoint", "/a", "worker", "w1"), func(ctx context.Context) {
+			counter.Add(ctx, 1)
+			counter.Add(ctx, 1, attribute.String("endpoint", "explicit"))
+		})
+		counter.Add(ctx, 1)
+	}
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/endpoint=/a/":             1,
+		"requests.sum/endpoint=explicit/":       1,
+		"requests.sum//":                        1,
+		"jobs.sum/endpoint=/a,worker=w1/":       1,
+		"jobs.sum/endpoint=explicit,worker=w1/": 1,
+		"jobs.sum//":                            1,
+		"other.sum//":                           2,
+		"other.sum/endpoint=explicit/":          1,
+	}, proc.Values())
+	require.Equal(t, `View{instrument="requests.sum" pprof=[endpoint]}`, views[0].String())
+	require.Equal(t, `View{instrument="jobs.sum" pprof=[]}`, views[1].String())
+}
+
+func TestBaggageKeys(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentName("requests.sum"), view.WithBaggageKeys("tenant", "region")),
+	}
+	registry, err := view.NewRegistry(views...)
+	require.NoError(t, err)
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	accum := metricsdk.NewAccumulator(registry.Processor(processorTest.NewCheckpointer(proc)), metricsdk.WithContextAttributes(registry))
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	tenant, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	secret, err := baggage.NewMember("session", "s3cr3t")
+	require.NoError(t, err)
+	bag, err := baggage.New(tenant, secret)
+	require.NoError(t, err)
+	bctx := baggage.ContextWithBaggage(ctx, bag)
+
+	for _, name := range []string{"requests.sum", "other.sum"} {
+		counter, err := meter.SyncInt64().Counter(name)
+		require.NoError(t, err)
+		counter.Add(bctx, 1)
+		counter.Add(bctx, 1, attribute.String("tenant", "explicit"))
+		counter.Add(ctx, 1)
+	}
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/tenant=acme/":     1,
+		"requests.sum/tenant=explicit/": 1,
+		"requests.sum//":                1,
+		"other.sum//":                   2,
+		"other.sum/tenant=explicit/":    1,
+	}, proc.Values())
+	require.Equal(t, `View{instrument="requests.sum" baggage=[tenant region]}`, views[0].String())
+}
+
+func TestAttributeRename(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t,
+			view.WithInstrumentName("requests.sum"),
+			view.WithAttributeRename(map[string]string{"http.status_code": "status"}),
+		),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	viewProc, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(viewProc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	requests, err := meter.SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	other, err := meter.SyncInt64().Counter("other.sum")
+	require.NoError(t, err)
+
+	requests.Add(ctx, 1, attribute.Int("http.status_code", 200), attribute.String("method", "GET"))
+	requests.Add(ctx, 2, attribute.Int("http.status_code", 500), attribute.String("status", "replaced"))
+	requests.Add(ctx, 4, attribute.String("method", "POST"))
+	other.Add(ctx, 8, attribute.Int("http.status_code", 200))
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/method=GET,status=200/": 1,
+		"requests.sum/status=500/":            2,
+		"requests.sum/method=POST/":           4,
+		"other.sum/http.status_code=200/":     8,
+	}, proc.Values())
+}
+
+func TestConflicts(t *testing.T) {
+	ctx := context.Background()
+	var handled []error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { handled = append(handled, err) }))
+	defer otel.SetErrorHandler(otel.ErrorHandlerFunc(func(error) {}))
+
+	registry, err := view.NewRegistry(
+		mustView(t, view.WithInstrumentName("c.sum"), view.WithPartitions(partition.Partition{
+			Name:  "d.sum",
+			Match: func(*attribute.Set) bool { return true },
+		})),
+		mustView(t, view.WithInstrumentName("d.sum"), view.WithDrop()),
+	)
+	require.NoError(t, err)
+	rename := mustView(t, view.WithInstrumentName("a.sum"), view.WithName("b.sum"), view.WithDescription("renamed"))
+	unregister, err := registry.Register(rename)
+	require.NoError(t, err)
+
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	accum := metricsdk.NewAccumulator(registry.Processor(processorTest.NewCheckpointer(proc)))
+	meter := sdkapi.WrapMeterImpl(accum)
+	for _, name := range []string{"a.sum", "b.sum", "c.sum", "d.sum"} {
+		counter, err := meter.SyncInt64().Counter(name)
+		require.NoError(t, err)
+		counter.Add(ctx, 1)
+	}
+	accum.Collect(ctx)
+
+	// The partition of c.sum does not conflict with the dropped d.sum.
+	require.Equal(t, []view.Conflict{{
+		Name:        "b.sum",
+		Instruments: []string{"a.sum", "b.sum"},
+		Views:       []view.View{rename, {}},
+	}}, registry.Conflicts())
+	require.Len(t, handled, 1)
+	require.ErrorIs(t, handled[0], view.ErrInvalidView)
+	require.Equal(t,
+		`invalid view: instruments ["a.sum" "b.sum"] are exported as "b.sum" by views View{instrument="a.sum" name="b.sum" description="renamed"} View{}`,
+		handled[0].Error())
+
+	unregister()
+	require.Empty(t, registry.Conflicts())
+	accum.Collect(ctx)
+	require.Empty(t, registry.Conflicts())
+	require.Len(t, handled, 1)
+}
+
+func TestDedupStreams(t *testing.T) {
+	ctx := context.Background()
+	var handled []error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { handled = append(handled, err) }))
+	defer otel.SetErrorHandler(otel.ErrorHandlerFunc(func(error) {}))
+
+	// Identical streams of different instruments are merged.
+	registry, err := view.NewRegistry(
+		mustView(t, view.WithInstrumentName("a.sum"), view.WithName("x.sum"), view.WithAttributeKeys("A")),
+		mustView(t, view.WithInstrumentName("b.sum"), view.WithName("x.sum"), view.WithAttributeKeys("A")),
+		mustView(t, view.WithInstrumentName("c.sum"), view.WithName("y.sum")),
+		mustView(t, view.WithInstrumentName("d.sum"), view.WithName("y.sum")),
+	)
+	require.NoError(t, err)
+	proc := registry.Processor(basic.New(processorTest.AggregatorSelector(), aggregation.CumulativeTemporalitySelector()))
+	accum := metricsdk.NewAccumulator(proc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	a, err := meter.SyncInt64().Counter("a.sum")
+	require.NoError(t, err)
+	b, err := meter.SyncInt64().Counter("b.sum")
+	require.NoError(t, err)
+	c, err := meter.SyncInt64().Counter("c.sum")
+	require.NoError(t, err)
+	d, err := meter.SyncInt64().UpDownCounter("d.sum")
+	require.NoError(t, err)
+	a.Add(ctx, 1, attribute.String("A", "a"), attribute.String("B", "b"))
+	b.Add(ctx, 2, attribute.String("A", "a"))
+	c.Add(ctx, 3)
+	d.Add(ctx, 4)
+
+	proc.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, proc.FinishCollection())
+	var records []string
+	require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		sum, err := rec.Aggregation().(aggregation.Sum).Sum()
+		require.NoError(t, err)
+		records = append(records, fmt.Sprintf("%s/%s/%v", rec.Descriptor().Name(), rec.Attributes().Encoded(attribute.DefaultEncoder()), sum.AsInt64()))
+		return nil
+	}))
+	sort.Strings(records)
+
+	// The streams of c.sum and d.sum differ by instrument kind: they
+	// are exported separately, and reported as a conflict.
+	require.Equal(t, []string{"x.sum/A=a/3", "y.sum//3", "y.sum//4"}, records)
+	conflicts := registry.Conflicts()
+	require.Len(t, conflicts, 1)
+	require.Equal(t, []string{"c.sum", "d.sum"}, conflicts[0].Instruments)
+	require.Len(t, handled, 1)
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	registry, err := view.NewRegistry()
+	require.NoError(t, err)
+	selector := registry.Selector(processorTest.AggregatorSelector())
+	proc := registry.Processor(basic.New(selector, aggregation.CumulativeTemporalitySelector()))
+	accum := metricsdk.NewAccumulator(proc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	latency, err := meter.SyncFloat64().Histogram("latency.histogram")
+	require.NoError(t, err)
+
+	type point struct {
+		boundaries []float64
+		count      uint64
+	}
+	collect := func() []point {
+		proc.StartCollection()
+		accum.Collect(ctx)
+		require.NoError(t, proc.FinishCollection())
+
+		var points []point
+		require.NoError(t, proc.Reader().ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+			require.Equal(t, "latency.histogram", rec.Descriptor().Name())
+			buckets, err := rec.Aggregation().(aggregation.Histogram).Histogram()
+			require.NoError(t, err)
+			count, err := rec.Aggregation().(aggregation.Count).Count()
+			require.NoError(t, err)
+			points = append(points, point{buckets.Boundaries, count})
+			return nil
+		}))
+		return points
+	}
+	require.Empty(t, collect())
+
+	latency.Record(ctx, 1)
+	points := collect()
+	require.Len(t, points, 1)
+	defaultBoundaries := points[0].boundaries
+	require.NotEqual(t, []float64{10}, defaultBoundaries)
+
+	unregister, err := registry.Register(mustView(t,
+		view.WithInstrumentName("latency.histogram"),
+		view.WithAggregatorSelector(simple.NewWithHistogramDistribution(
+			histogram.WithExplicitBoundaries([]float64{10}),
+		)),
+	))
+	require.NoError(t, err)
+
+	// The measurement made before the next collection is exported
+	// by the prior stream, later ones by the new View.
+	latency.Record(ctx, 2)
+	require.Equal(t, []point{{defaultBoundaries, 2}}, collect())
+	latency.Record(ctx, 3)
+	require.Equal(t, []point{{[]float64{10}, 1}}, collect())
+
+	unregister()
+	unregister()
+	latency.Record(ctx, 4)
+	require.Equal(t, []point{{[]float64{10}, 2}}, collect())
+	latency.Record(ctx, 5)
+	require.Equal(t, []point{{defaultBoundaries, 1}}, collect())
+}
+
+func TestRegistryValidation(t *testing.T) {
+	registry, err := view.NewRegistry(mustView(t, view.WithInstrumentName("a.sum"), view.WithName("x.sum")))
+	require.NoError(t, err)
+
+	_, err = registry.Register(mustView(t, view.WithInstrumentName("b.sum"), view.WithName("x.sum"), view.WithDescription("b")))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+
+	_, err = view.NewRegistry(
+		mustView(t, view.WithInstrumentName("a.sum"), view.WithName("x.sum")),
+		mustView(t, view.WithInstrumentName("b.sum"), view.WithName("x.sum"), view.WithDescription("b")),
+	)
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestDebugString(t *testing.T) {
+	rename := mustView(t,
+		view.WithInstrumentName("requests.sum"),
+		view.WithName("requests.total.sum"),
+		view.WithAttributeRename(map[string]string{"b": "c", "a": "z"}),
+	)
+	drop := mustView(t, view.WithInstrumentNameRegexp(regexp.MustCompile(`^noisy\.`)), view.WithDrop())
+	require.Equal(t, `View{instrument="requests.sum" name="requests.total.sum" renames=[a->z b->c]}`, rename.String())
+	require.Equal(t, `View{regexp="^noisy\\." drop}`, drop.String())
+	require.Equal(t, `View{}`, view.View{}.String())
+
+	ckpter := processorTest.NewCheckpointer(processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder()))
+	proc, err := view.NewProcessor(ckpter, rename, drop)
+	require.NoError(t, err)
+
+	requests := sdkapi.NewDescriptorWithAttributeKeys("requests.sum", sdkapi.CounterInstrumentKind, number.Int64Kind, "", "", []attribute.Key{"a"})
+	require.Equal(t,
+		`requests.sum (CounterInstrumentKind, Int64Kind) exported as requests.total.sum aggregation=Sum temporality=DeltaTemporality keys=[a] view=`+rename.String(),
+		proc.DebugString(&requests, aggregation.DeltaTemporalitySelector()))
+
+	noisy := sdkapi.NewDescriptor("noisy.sum", sdkapi.CounterInstrumentKind, number.Int64Kind, "", "")
+	require.Equal(t, `noisy.sum (CounterInstrumentKind, Int64Kind) dropped by `+drop.String(), proc.DebugString(&noisy, nil))
+
+	other := sdkapi.NewDescriptor("other.lastvalue", sdkapi.GaugeObserverInstrumentKind, number.Float64Kind, "", "")
+	require.Equal(t, `other.lastvalue (GaugeObserverInstrumentKind, Float64Kind) exported as other.lastvalue aggregation=Lastvalue`, proc.DebugString(&other, nil))
+}
+
+func TestInstrumentUnit(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t, view.WithInstrumentUnit(unit.Milliseconds), view.WithDrop()),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	viewProc, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(viewProc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	for _, u := range []unit.Unit{unit.Milliseconds, unit.Seconds, unit.Dimensionless} {
+		counter, err := meter.SyncInt64().Counter(string(u)+".sum", instrument.WithUnit(u))
+		require.NoError(t, err)
+		counter.Add(ctx, 1)
+	}
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"s.sum//": 1,
+		"1.sum//": 1,
+	}, proc.Values())
+	require.Equal(t, `View{unit="ms" drop}`, views[0].String())
+}
+
+func TestLibraryVersionRange(t *testing.T) {
+	ctx := context.Background()
+	v := mustView(t,
+		view.WithLibraryName("lib"),
+		view.WithLibraryVersionRange("v0.20", "v0.30.0"),
+		view.WithDrop(),
+	)
+	require.Equal(t, `View{library="lib" versions=[v0.20,v0.30.0) drop}`, v.String())
+	registry, err := view.NewRegistry(v)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		library instrumentation.Library
+		dropped bool
+	}{
+		{instrumentation.Library{Name: "lib", Version: "v0.20.0"}, true},
+		{instrumentation.Library{Name: "lib", Version: "0.29.9"}, true},
+		{instrumentation.Library{Name: "lib", Version: "v0.30.0-rc.1"}, true},
+		{instrumentation.Library{Name: "lib", Version: "v0.30.0"}, false},
+		{instrumentation.Library{Name: "lib", Version: "v0.19.10+build"}, false},
+		{instrumentation.Library{Name: "lib", Version: "dev"}, false},
+		{instrumentation.Library{Name: "lib"}, false},
+		{instrumentation.Library{Name: "other", Version: "v0.25.0"}, false},
+	} {
+		t.Run(tc.library.Name+"@"+tc.library.Version, func(t *testing.T) {
+			proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+			accum := metricsdk.NewAccumulator(registry.LibraryProcessor(tc.library, processorTest.NewCheckpointer(proc)))
+			counter, err := sdkapi.WrapMeterImpl(accum).SyncInt64().Counter("c.sum")
+			require.NoError(t, err)
+			counter.Add(ctx, 1)
+			accum.Collect(ctx)
+
+			if tc.dropped {
+				require.Empty(t, proc.Values())
+			} else {
+				require.EqualValues(t, map[string]float64{"c.sum//": 1}, proc.Values())
+			}
+		})
+	}
+}
+
+func TestLibraryViews(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t,
+			view.WithLibraryName("lib"),
+			view.WithLibraryVersionRange("", "v0.30.0"),
+			view.WithInstrumentName("requests.sum"),
+			view.WithName("requests.legacy.sum"),
+		),
+		mustView(t,
+			view.WithLibrarySchemaURL("https://opentelemetry.io/schemas/1.7.0"),
+			view.WithInstrumentNameGlob("*.histogram"),
+			view.WithAggregatorSelector(simple.NewWithInexpensiveDistribution()),
+		),
+	}
+	registry, err := view.NewRegistry(views...)
+	require.NoError(t, err)
+	factory := registry.Factory(basic.NewFactory(
+		registry.Selector(simple.NewWithHistogramDistribution()),
+		aggregation.CumulativeTemporalitySelector(),
+	))
+	cont := controller.New(factory, controller.WithCollectPeriod(0))
+
+	for _, m := range []struct {
+		meter metric.Meter
+		name  string
+	}{
+		{cont.Meter("lib", metric.WithInstrumentationVersion("v0.29.0")), "requests.sum"},
+		{cont.Meter("lib", metric.WithInstrumentationVersion("v0.30.0")), "requests.sum"},
+		{cont.Meter("lib", metric.WithInstrumentationVersion("v0.31.0")), "other.sum"},
+	} {
+		counter, err := m.meter.SyncInt64().Counter(m.name)
+		require.NoError(t, err)
+		counter.Add(ctx, 1)
+	}
+	for _, m := range []struct {
+		meter metric.Meter
+		name  string
+	}{
+		{cont.Meter("new", metric.WithSchemaURL("https://opentelemetry.io/schemas/1.7.0")), "new.histogram"},
+		{cont.Meter("old", metric.WithSchemaURL("https://opentelemetry.io/schemas/1.4.0")), "old.histogram"},
+	} {
+		h, err := m.meter.SyncFloat64().Histogram(m.name)
+		require.NoError(t, err)
+		h.Record(ctx, 1)
+		h.Record(ctx, 3)
+	}
+	require.NoError(t, cont.Collect(ctx))
+
+	kinds := map[string]aggregation.Kind{}
+	require.NoError(t, cont.ForEach(func(_ instrumentation.Library, reader export.Reader) error {
+		return reader.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+			kinds[rec.Descriptor().Name()] = rec.Aggregation().Kind()
+			return nil
+		})
+	}))
+	require.Equal(t, map[string]aggregation.Kind{
+		"requests.legacy.sum": aggregation.SumKind,
+		"requests.sum":        aggregation.SumKind,
+		"other.sum":           aggregation.SumKind,
+		"new.histogram":       aggregation.SumKind,
+		"old.histogram":       aggregation.HistogramKind,
+	}, kinds)
+}
+
+func TestDropMeasurements(t *testing.T) {
+	ctx := context.Background()
+	healthz := func(attrs *attribute.Set) bool {
+		route, _ := attrs.Value("http.route")
+		return route.AsString() == "/healthz"
+	}
+	views := []view.View{
+		mustView(t,
+			view.WithInstrumentName("requests.sum"),
+			view.WithDropMeasurements(healthz),
+			view.WithAttributeRename(map[string]string{"http.route": "route"}),
+		),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	viewProc, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(viewProc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	requests, err := meter.SyncInt64().Counter("requests.sum")
+	require.NoError(t, err)
+	other, err := meter.SyncInt64().Counter("other.sum")
+	require.NoError(t, err)
+	for _, route := range []string{"/healthz", "/api", "/healthz"} {
+		requests.Add(ctx, 1, attribute.String("http.route", route))
+		other.Add(ctx, 1, attribute.String("http.route", route))
+	}
+	// The record of the health checks is not checkpointed.
+	require.Equal(t, 3, accum.Collect(ctx))
+
+	require.EqualValues(t, map[string]float64{
+		"requests.sum/route=/api/":       1,
+		"other.sum/http.route=/api/":     1,
+		"other.sum/http.route=/healthz/": 2,
+	}, proc.Values())
+	require.Equal(t, `View{instrument="requests.sum" renames=[http.route->route] dropMeasurements}`, views[0].String())
+}
+
+func TestExtraAttributes(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t,
+			view.WithInstrumentNameGlob("rpc.*"),
+			view.WithAttributeRename(map[string]string{"owner": "team"}),
+			view.WithExtraAttributes(attribute.String("team", "payments"), attribute.Int("ring", 2)),
+		),
+	}
+	proc := processorTest.NewProcessor(processorTest.AggregatorSelector(), attribute.DefaultEncoder())
+	viewProc, err := view.NewProcessor(processorTest.NewCheckpointer(proc), views...)
+	require.NoError(t, err)
+	accum := metricsdk.NewAccumulator(viewProc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	calls, err := meter.SyncInt64().Counter("rpc.calls.sum")
+	require.NoError(t, err)
+	calls.Add(ctx, 1, attribute.String("method", "Get"))
+	calls.Add(ctx, 2, attribute.String("owner", "search"))
+	other, err := meter.SyncInt64().Counter("db.calls.sum")
+	require.NoError(t, err)
+	other.Add(ctx, 1, attribute.String("method", "Get"))
+	accum.Collect(ctx)
+
+	require.EqualValues(t, map[string]float64{
+		"rpc.calls.sum/method=Get,ring=2,team=payments/": 1,
+		"rpc.calls.sum/ring=2,team=payments/":            2,
+		"db.calls.sum/method=Get/":                       1,
+	}, proc.Values())
+	require.Equal(t, `View{glob="rpc.*" renames=[owner->team] extra=[team=payments ring=2]}`, views[0].String())
+
+	_, err = view.New(view.WithExtraAttributes(attribute.String("", "x")))
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestPartitions(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t,
+			view.WithInstrumentName("http.server.duration"),
+			view.WithName("http.server.duration.other"),
+			view.WithAggregatorSelector(simple.NewWithInexpensiveDistribution()),
+			view.WithPartitions(partition.Partition{
+				Name:       "http.server.duration.get",
+				Match:      partition.AttributeEquals("http.method", "GET"),
+				Aggregator: simple.NewWithHistogramDistribution(),
+			}),
+		),
+	}
+	registry, err := view.NewRegistry(views...)
+	require.NoError(t, err)
+	ckpt := basic.New(registry.Selector(simple.NewWithInexpensiveDistribution()), aggregation.CumulativeTemporalitySelector())
+	viewProc := registry.Processor(ckpt)
+	accum := metricsdk.NewAccumulator(viewProc)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	duration, err := meter.SyncFloat64().Histogram("http.server.duration")
+	require.NoError(t, err)
+	for _, method := range []string{"GET", "POST", "GET", "PUT"} {
+		duration.Record(ctx, 10, attribute.String("http.method", method))
+	}
+	ckpt.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, ckpt.FinishCollection())
+
+	type point struct {
+		name   string
+		method string
+		kind   aggregation.Kind
+	}
+	var points []point
+	require.NoError(t, ckpt.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		method, _ := rec.Attributes().Value("http.method")
+		points = append(points, point{rec.Descriptor().Name(), method.AsString(), rec.Aggregation().Kind()})
+		return nil
+	}))
+	require.ElementsMatch(t, []point{
+		{"http.server.duration.get", "GET", aggregation.HistogramKind},
+		{"http.server.duration.other", "POST", aggregation.SumKind},
+		{"http.server.duration.other", "PUT", aggregation.SumKind},
+	}, points)
+
+	desc := sdkapi.NewDescriptor("http.server.duration", sdkapi.HistogramInstrumentKind, number.Float64Kind, "", "")
+	require.Equal(t,
+		`http.server.duration (HistogramInstrumentKind, Float64Kind) exported as http.server.duration.other aggregation=Sum `+
+			`partition=http.server.duration.get(Histogram) `+
+			`view=View{instrument="http.server.duration" name="http.server.duration.other" aggregator=simple.selectorInexpensive partitions=[http.server.duration.get]}`,
+		viewProc.DebugString(&desc, nil))
+}
+
+func TestPartitionValidation(t *testing.T) {
+	match := func(*attribute.Set) bool { return true }
+	for _, opts := range [][]view.Option{
+		{view.WithPartitions(partition.Partition{Name: "a", Match: match})},
+		{view.WithInstrumentNameGlob("rpc.*"), view.WithPartitions(partition.Partition{Name: "a", Match: match})},
+		{view.WithInstrumentName("rpc"), view.WithPartitions(partition.Partition{Name: "a"})},
+		{view.WithInstrumentName("rpc"), view.WithPartitions(partition.Partition{Match: match})},
+		{view.WithInstrumentName("rpc"), view.WithDrop(), view.WithPartitions(partition.Partition{Name: "a", Match: match})},
+		{view.WithInstrumentName("rpc"), view.WithPartitions(partition.Partition{Instrument: "http", Name: "a", Match: match})},
+	} {
+		_, err := view.New(opts...)
+		require.ErrorIs(t, err, view.ErrInvalidView)
+	}
+
+	_, err := view.New(view.WithInstrumentName("rpc"), view.WithPartitions(partition.Partition{Instrument: "rpc", Name: "a", Match: match}))
+	require.NoError(t, err)
+
+	_, err = view.NewRegistry(
+		mustView(t, view.WithInstrumentName("a"), view.WithName("x")),
+		mustView(t, view.WithInstrumentName("b"), view.WithPartitions(partition.Partition{Name: "x", Match: match})),
+	)
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestUnitConversion(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t,
+			view.WithInstrumentUnit(unit.Milliseconds),
+			view.WithUnitConversion(unit.Seconds, 1e-3),
+			view.WithAggregatorSelector(simple.NewWithHistogramDistribution(
+				histogram.WithExplicitBoundaries([]float64{0.1, 1}),
+			)),
+		),
+		mustView(t,
+			view.WithInstrumentName("io.read"),
+			view.WithUnitConversion("KiBy", 1.0/(1<<10)),
+		),
+	}
+	registry, err := view.NewRegistry(views...)
+	require.NoError(t, err)
+	ckpt := basic.New(registry.Selector(simple.NewWithInexpensiveDistribution()), aggregation.CumulativeTemporalitySelector())
+	accum := metricsdk.NewAccumulator(registry.Processor(ckpt))
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	latency, err := meter.SyncFloat64().Histogram("rpc.latency", instrument.WithUnit(unit.Milliseconds))
+	require.NoError(t, err)
+	for _, ms := range []float64{50, 500, 1500} {
+		latency.Record(ctx, ms)
+	}
+	read, err := meter.SyncInt64().Counter("io.read", instrument.WithUnit(unit.Bytes))
+	require.NoError(t, err)
+	read.Add(ctx, 1<<20)
+	read.Add(ctx, 1500)
+
+	ckpt.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, ckpt.FinishCollection())
+
+	units := map[string]unit.Unit{}
+	require.NoError(t, ckpt.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		units[rec.Descriptor().Name()] = rec.Descriptor().Unit()
+		switch agg := rec.Aggregation().(type) {
+		case aggregation.Histogram:
+			buckets, err := agg.Histogram()
+			require.NoError(t, err)
+			require.Equal(t, []uint64{1, 1, 1}, buckets.Counts)
+			sum, err := agg.Sum()
+			require.NoError(t, err)
+			require.InDelta(t, 2.05, sum.AsFloat64(), 1e-9)
+		case aggregation.Sum:
+			sum, err := agg.Sum()
+			require.NoError(t, err)
+			// 1500 bytes are rounded to 1 KiB.
+			require.Equal(t, int64(1<<10+1), sum.AsInt64())
+		}
+		return nil
+	}))
+	require.Equal(t, map[string]unit.Unit{"rpc.latency": unit.Seconds, "io.read": "KiBy"}, units)
+	require.Equal(t, `View{instrument="io.read" convert="KiBy"*0.0009765625}`, views[1].String())
+
+	for _, opts := range [][]view.Option{
+		{view.WithUnitConversion(unit.Seconds, 0)},
+		{view.WithUnitConversion("", 1e-3)},
+		{view.WithUnitConversion(unit.Seconds, 1e-3), view.WithDrop()},
+	} {
+		_, err := view.New(opts...)
+		require.ErrorIs(t, err, view.ErrInvalidView)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	ctx := context.Background()
+	clamp := func(x float64) float64 { return math.Min(math.Max(x, 0), 100) }
+	views := []view.View{
+		mustView(t,
+			view.WithInstrumentName("cpu.utilization"),
+			view.WithTransform(func(x float64) float64 { return clamp(x * 100) }),
+		),
+		mustView(t,
+			view.WithInstrumentName("queue.depth"),
+			view.WithTransform(math.Log2),
+		),
+		mustView(t,
+			view.WithInstrumentName("rpc.latency"),
+			view.WithUnitConversion(unit.Seconds, 1e-3),
+			view.WithTransform(math.Ceil),
+		),
+	}
+	registry, err := view.NewRegistry(views...)
+	require.NoError(t, err)
+	ckpt := basic.New(registry.Selector(simple.NewWithInexpensiveDistribution()), aggregation.CumulativeTemporalitySelector())
+	accum := metricsdk.NewAccumulator(registry.Processor(ckpt))
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	cpu, err := meter.SyncFloat64().Counter("cpu.utilization")
+	require.NoError(t, err)
+	cpu.Add(ctx, 0.25)
+	cpu.Add(ctx, 1.5)
+	depth, err := meter.SyncInt64().Counter("queue.depth")
+	require.NoError(t, err)
+	// Log2(0) is an infinity, and the measurement is dropped.
+	depth.Add(ctx, 0)
+	depth.Add(ctx, 8)
+	depth.Add(ctx, 10)
+	latency, err := meter.SyncFloat64().Counter("rpc.latency", instrument.WithUnit(unit.Milliseconds))
+	require.NoError(t, err)
+	latency.Add(ctx, 1500)
+
+	ckpt.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, ckpt.FinishCollection())
+
+	sums := map[string]float64{}
+	require.NoError(t, ckpt.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		sum, err := rec.Aggregation().(aggregation.Sum).Sum()
+		require.NoError(t, err)
+		sums[rec.Descriptor().Name()] = sum.CoerceToFloat64(rec.Descriptor().NumberKind())
+		return nil
+	}))
+	require.Equal(t, map[string]float64{
+		"cpu.utilization": 125,
+		// Log2(10) is rounded to 3.
+		"queue.depth": 6,
+		"rpc.latency": 2,
+	}, sums)
+	require.Equal(t, `View{instrument="queue.depth" transform}`, views[1].String())
+
+	_, err = view.New(view.WithTransform(math.Log2), view.WithDrop())
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
+
+func TestNameTemplates(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t,
+			view.WithInstrumentNameRegexp(regexp.MustCompile(`^rpc\.`)),
+			view.WithName("{scope}.{instrument}.{aggregation}"),
+		),
+		mustView(t,
+			view.WithInstrumentName("queue.depth"),
+			view.WithName("{aggregation}.queue"),
+		),
+	}
+	registry, err := view.NewRegistry(views...)
+	require.NoError(t, err)
+	ckpt := basic.New(registry.Selector(simple.NewWithInexpensiveDistribution()), aggregation.CumulativeTemporalitySelector())
+	accum := metricsdk.NewAccumulator(registry.LibraryProcessor(instrumentation.Library{Name: "grpc"}, ckpt))
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	calls, err := meter.SyncInt64().Counter("rpc.calls")
+	require.NoError(t, err)
+	calls.Add(ctx, 1)
+	latency, err := meter.SyncFloat64().Histogram("rpc.latency")
+	require.NoError(t, err)
+	latency.Record(ctx, 1)
+	depth, err := meter.AsyncInt64().Gauge("queue.depth")
+	require.NoError(t, err)
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{depth}, func(ctx context.Context) {
+		depth.Observe(ctx, 3)
+	}))
+
+	ckpt.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, ckpt.FinishCollection())
+
+	var names []string
+	require.NoError(t, ckpt.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		names = append(names, rec.Descriptor().Name())
+		return nil
+	}))
+	require.ElementsMatch(t, []string{
+		"grpc.rpc.calls.sum",
+		"grpc.rpc.latency.sum",
+		"lastvalue.queue",
+	}, names)
+
+	for _, opts := range [][]view.Option{
+		{view.WithInstrumentNameGlob("rpc.*"), view.WithName("rpc.{aggregation}")},
+		{view.WithInstrumentName("rpc.calls"), view.WithName("{instrument}.{kind}")},
+		{view.WithInstrumentName("rpc.calls"), view.WithName("{instrument.total")},
+		{view.WithInstrumentName("rpc.calls"), view.WithName("instrument}.total")},
+	} {
+		_, err := view.New(opts...)
+		require.ErrorIs(t, err, view.ErrInvalidView)
+	}
+	_, err = view.New(view.WithInstrumentUnit("ms"), view.WithName("{instrument}.ms"))
+	require.NoError(t, err)
+}
+
+func TestDescriptionAndUnit(t *testing.T) {
+	ctx := context.Background()
+	views := []view.View{
+		mustView(t,
+			view.WithInstrumentName("jobs"),
+			view.WithDescription("Jobs processed by the worker pool"),
+			view.WithUnit("{jobs}"),
+		),
+	}
+	registry, err := view.NewRegistry(views...)
+	require.NoError(t, err)
+	ckpt := basic.New(registry.Selector(simple.NewWithInexpensiveDistribution()), aggregation.CumulativeTemporalitySelector())
+	accum := metricsdk.NewAccumulator(registry.Processor(ckpt))
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	jobs, err := meter.SyncInt64().Counter("jobs", instrument.WithDescription("jobs"))
+	require.NoError(t, err)
+	jobs.Add(ctx, 5)
+
+	ckpt.StartCollection()
+	accum.Collect(ctx)
+	require.NoError(t, ckpt.FinishCollection())
+
+	var descs []sdkapi.Descriptor
+	require.NoError(t, ckpt.ForEach(aggregation.CumulativeTemporalitySelector(), func(rec export.Record) error {
+		descs = append(descs, *rec.Descriptor())
+		sum, err := rec.Aggregation().(aggregation.Sum).Sum()
+		require.NoError(t, err)
+		require.Equal(t, int64(5), sum.AsInt64())
+		return nil
+	}))
+	require.Len(t, descs, 1)
+	require.Equal(t, "Jobs processed by the worker pool", descs[0].Description())
+	require.Equal(t, unit.Unit("{jobs}"), descs[0].Unit())
+	require.Equal(t, `View{instrument="jobs" description="Jobs processed by the worker pool" setUnit="{jobs}"}`, views[0].String())
+
+	_, err = view.New(view.WithDescription("dropped"), view.WithDrop())
+	require.ErrorIs(t, err, view.ErrInvalidView)
+}
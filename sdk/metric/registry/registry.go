@@ -29,6 +29,20 @@ type UniqueInstrumentMeterImpl struct {
 	lock  sync.Mutex
 	impl  sdkapi.MeterImpl
 	state map[string]sdkapi.InstrumentImpl
+	// names is shared with the UniqueInstrumentMeterImpls of other
+	// instrumentation libraries, nil when names are only unique
+	// within this one.
+	names *Names
+}
+
+// Names holds the descriptors of the instruments registered by several
+// UniqueInstrumentMeterImpls, so that instrument names are checked
+// across the instrumentation libraries sharing it.  Each library still
+// has its own instruments: a compatible instrument of the same name in
+// another library is a distinct instrument.
+type Names struct {
+	lock        sync.Mutex
+	descriptors map[string]sdkapi.Descriptor
 }
 
 var _ sdkapi.MeterImpl = (*UniqueInstrumentMeterImpl)(nil)
@@ -38,6 +52,15 @@ var _ sdkapi.MeterImpl = (*UniqueInstrumentMeterImpl)(nil)
 var ErrMetricKindMismatch = fmt.Errorf(
 	"a metric was already registered by this name with another kind or number type")
 
+// ErrInvalidInstrumentName is the standard error for an instrument name
+// that does not follow the instrument name syntax of the specification:
+// an ASCII letter followed by at most 62 ASCII letters, digits, '_', '.'
+// or '-'.
+var ErrInvalidInstrumentName = fmt.Errorf("invalid instrument name")
+
+// maxInstrumentNameLength is the maximum length of an instrument name.
+const maxInstrumentNameLength = 63
+
 // NewUniqueInstrumentMeterImpl returns a wrapped metric.MeterImpl
 // with the addition of instrument name uniqueness checking.
 func NewUniqueInstrumentMeterImpl(impl sdkapi.MeterImpl) *UniqueInstrumentMeterImpl {
@@ -47,6 +70,71 @@ func NewUniqueInstrumentMeterImpl(impl sdkapi.MeterImpl) *UniqueInstrumentMeterI
 	}
 }
 
+// NewNames returns an empty Names.
+func NewNames() *Names {
+	return &Names{
+		descriptors: map[string]sdkapi.Descriptor{},
+	}
+}
+
+// NewUniqueInstrumentMeterImplWithNames returns a wrapped
+// metric.MeterImpl with the addition of instrument name uniqueness
+// checking against the instruments of every UniqueInstrumentMeterImpl
+// sharing names, as needed by exporters with a single namespace for
+// all instrumentation libraries.  With nil names, it is equivalent to
+// NewUniqueInstrumentMeterImpl.
+func NewUniqueInstrumentMeterImplWithNames(impl sdkapi.MeterImpl, names *Names) *UniqueInstrumentMeterImpl {
+	u := NewUniqueInstrumentMeterImpl(impl)
+	u.names = names
+	return u
+}
+
+// check returns an ErrMetricKindMismatch error if an instrument
+// registered with the name of descriptor is not compatible with it,
+// and otherwise records descriptor for its name.
+func (n *Names) check(descriptor sdkapi.Descriptor) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	existing, ok := n.descriptors[descriptor.Name()]
+	if !ok {
+		n.descriptors[descriptor.Name()] = descriptor
+		return nil
+	}
+	if !Compatible(descriptor, existing) {
+		return NewMetricKindMismatchError(existing)
+	}
+	return nil
+}
+
+// ValidateInstrumentName returns an ErrInvalidInstrumentName error if
+// name does not follow the instrument name syntax.
+func ValidateInstrumentName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidInstrumentName)
+	}
+	if len(name) > maxInstrumentNameLength {
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidInstrumentName, name, maxInstrumentNameLength)
+	}
+	if !isAlpha(name[0]) {
+		return fmt.Errorf("%w: %q does not start with a letter", ErrInvalidInstrumentName, name)
+	}
+	for i := 1; i < len(name); i++ {
+		if c := name[i]; !isAlpha(c) && !isDigit(c) && c != '_' && c != '.' && c != '-' {
+			return fmt.Errorf("%w: %q contains %q", ErrInvalidInstrumentName, name, c)
+		}
+	}
+	return nil
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
 // MeterImpl gives the caller access to the underlying MeterImpl
 // used by this UniqueInstrumentMeterImpl.
 func (u *UniqueInstrumentMeterImpl) MeterImpl() sdkapi.MeterImpl {
@@ -70,14 +158,22 @@ func Compatible(candidate, existing sdkapi.Descriptor) bool {
 		candidate.NumberKind() == existing.NumberKind()
 }
 
-// checkUniqueness returns an ErrMetricKindMismatch error if there is
+// checkUniqueness returns an ErrInvalidInstrumentName error if the name
+// of descriptor is invalid, and an ErrMetricKindMismatch error if there is
 // a conflict between a descriptor that was already registered and the
 // `descriptor` argument.  If there is an existing compatible
 // registration, this returns the already-registered instrument.  If
 // there is no conflict and no prior registration, returns (nil, nil).
 func (u *UniqueInstrumentMeterImpl) checkUniqueness(descriptor sdkapi.Descriptor) (sdkapi.InstrumentImpl, error) {
+	if err := ValidateInstrumentName(descriptor.Name()); err != nil {
+		return nil, err
+	}
+
 	impl, ok := u.state[descriptor.Name()]
 	if !ok {
+		if u.names != nil {
+			return nil, u.names.check(descriptor)
+		}
 		return nil, nil
 	}
 
@@ -16,6 +16,7 @@ package registry_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -110,3 +111,68 @@ func TestRegistryDiffInstruments(t *testing.T) {
 		}
 	}
 }
+
+func TestRegistrySharedNames(t *testing.T) {
+	for origName, origf := range allNew {
+		names := registry.NewNames()
+		meter1 := sdkapi.WrapMeterImpl(registry.NewUniqueInstrumentMeterImplWithNames(metricsdk.NewAccumulator(nil), names))
+		meter2 := sdkapi.WrapMeterImpl(registry.NewUniqueInstrumentMeterImplWithNames(metricsdk.NewAccumulator(nil), names))
+
+		_, err := origf(meter1, "this")
+		require.NoError(t, err)
+
+		for newName, nf := range allNew {
+			other, err := nf(meter2, "this")
+			if newName == origName {
+				require.NoError(t, err)
+				continue
+			}
+			require.Nil(t, other)
+			require.True(t, errors.Is(err, registry.ErrMetricKindMismatch))
+		}
+	}
+}
+
+func TestRegistryNamesPerLibrary(t *testing.T) {
+	for origName, origf := range allNew {
+		meter1 := testMeterWithRegistry("meter1")
+
+		_, err := origf(meter1, "this")
+		require.NoError(t, err)
+
+		for newName, nf := range allNew {
+			if newName == origName {
+				continue
+			}
+			_, err := nf(testMeterWithRegistry("meter2"), "this")
+			require.NoError(t, err)
+		}
+	}
+}
+
+func TestRegistryInstrumentNames(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		valid bool
+	}{
+		{"a", true},
+		{"Http.server-duration_1", true},
+		{"a" + strings.Repeat("0", 62), true},
+		{"", false},
+		{"1a", false},
+		{"_a", false},
+		{"a b", false},
+		{"a/b", false},
+		{"aé", false},
+		{"a" + strings.Repeat("0", 63), false},
+	} {
+		for _, nf := range allNew {
+			_, err := nf(testMeterWithRegistry("meter"), tc.name)
+			if tc.valid {
+				require.NoError(t, err, "name %q", tc.name)
+				continue
+			}
+			require.True(t, errors.Is(err, registry.ErrInvalidInstrumentName), "name %q: %v", tc.name, err)
+		}
+	}
+}
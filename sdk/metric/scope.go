@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// Scope holds the measurements of synchronous instruments made during a
+// short-lived unit of work, such as one item of a batch job, so that they
+// can be inspected before they are merged into the Accumulators of the
+// instruments or discarded.
+//
+// Measurements made with a context returned by ContextWithScope are held
+// by the Scope instead of being aggregated.  Measurements that are not
+// valid for their instrument, such as negative counter increments, are
+// rejected when they are made.
+type Scope struct {
+	lock   sync.Mutex
+	points map[mapkey]*scopePoint
+}
+
+type scopeContextKey struct{}
+
+type scopePoint struct {
+	inst   *syncInstrument
+	attrs  attribute.Set
+	values []number.Number
+}
+
+// ScopePoint contains the measurements of one instrument with one
+// attribute set held by a Scope.
+type ScopePoint struct {
+	Descriptor sdkapi.Descriptor
+	Attributes attribute.Set
+	Values     []number.Number
+}
+
+// NewScope returns an empty Scope.
+func NewScope() *Scope {
+	return &Scope{
+		points: map[mapkey]*scopePoint{},
+	}
+}
+
+// ContextWithScope returns a copy of parent in which the measurements of
+// synchronous instruments are held by s.
+func ContextWithScope(parent context.Context, s *Scope) context.Context {
+	return context.WithValue(parent, scopeContextKey{}, s)
+}
+
+func scopeFromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeContextKey{}).(*Scope)
+	return s
+}
+
+// record holds the valid values of nums for the instrument.
+func (s *Scope) record(inst *syncInstrument, nums []number.Number, kvs []attribute.KeyValue) {
+	nums = rangeFilter(nums, &inst.descriptor)
+	if len(nums) == 0 {
+		return
+	}
+	attrs := attribute.NewSet(kvs...)
+	key := mapkey{
+		descriptor: &inst.descriptor,
+		ordered:    attrs.Equivalent(),
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p, ok := s.points[key]
+	if !ok {
+		p = &scopePoint{inst: inst, attrs: attrs}
+		s.points[key] = p
+	}
+	p.values = append(p.values, nums...)
+}
+
+// Points returns the measurements held by the Scope, ordered by
+// instrument name and attributes.
+func (s *Scope) Points() []ScopePoint {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	points := make([]ScopePoint, 0, len(s.points))
+	for _, p := range s.points {
+		points = append(points, ScopePoint{
+			Descriptor: p.inst.descriptor,
+			Attributes: p.attrs,
+			Values:     append([]number.Number(nil), p.values...),
+		})
+	}
+	enc := attribute.DefaultEncoder()
+	sort.Slice(points, func(i, j int) bool {
+		if ni, nj := points[i].Descriptor.Name(), points[j].Descriptor.Name(); ni != nj {
+			return ni < nj
+		}
+		return points[i].Attributes.Encoded(enc) < points[j].Attributes.Encoded(enc)
+	})
+	return points
+}
+
+// Merge records the measurements held by the Scope into their
+// instruments, as if they were made with ctx, and empties the Scope.
+func (s *Scope) Merge(ctx context.Context) {
+	s.lock.Lock()
+	points := s.points
+	s.points = map[mapkey]*scopePoint{}
+	s.lock.Unlock()
+
+	for _, p := range points {
+		kvs := p.attrs.ToSlice()
+		if len(p.values) == 1 {
+			p.inst.recordOne(ctx, p.values[0], kvs)
+			continue
+		}
+		p.inst.recordSlice(ctx, p.values, kvs)
+	}
+}
+
+// Discard empties the Scope without recording its measurements.
+func (s *Scope) Discard() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.points = map[mapkey]*scopePoint{}
+}
+
+// Sum returns the sum of the values as a float64.
+func (p ScopePoint) Sum() float64 {
+	kind := p.Descriptor.NumberKind()
+	var sum float64
+	for i := range p.Values {
+		sum += p.Values[i].CoerceToFloat64(kind)
+	}
+	return sum
+}
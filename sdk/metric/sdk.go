@@ -20,6 +20,7 @@ import (
 	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -54,8 +55,49 @@ type (
 		// processor is the configured processor+configuration.
 		processor export.Processor
 
+		// attributeSelector is the processor when it selects
+		// Aggregators per attribute set, otherwise nil.
+		attributeSelector export.AttributeAggregatorSelector
+
+		// versioned is the processor when its selection of
+		// Aggregators may change, otherwise nil.
+		versioned export.VersionedAggregatorSelector
+		// selectionVersion is the version of the selection of
+		// Aggregators of every current record.
+		selectionVersion uint64
+
 		// collectLock prevents simultaneous calls to Collect().
 		collectLock sync.Mutex
+
+		// batchLock is held for reading by RecordBatch and for
+		// writing while records are checkpointed, so that the
+		// measurements of a batch are collected together.
+		batchLock sync.RWMutex
+
+		// verboseKey is the baggage member that requests the verbose
+		// views of synchronous instruments, if not empty.
+		verboseKey string
+
+		// observationTolerance bounds the observation times of
+		// asynchronous observations in the future.
+		observationTolerance time.Duration
+
+		// usageSampling is the interval of the call sites sampled
+		// for the usage statistics, which are disabled when zero.
+		usageSampling int
+		usageLock     sync.Mutex
+		usage         []*instrumentUsage
+
+		// instrumentChecker checks new instruments, if not nil.
+		instrumentChecker InstrumentChecker
+
+		// contextAttributes derives attributes of synchronous
+		// measurements from their context, if not nil.
+		contextAttributes ContextAttributes
+
+		// attributeFilter selects the attribute filters of
+		// instruments, if not nil.
+		attributeFilter AttributeFilterSelector
 	}
 
 	callback struct {
@@ -73,6 +115,14 @@ type (
 	syncInstrument struct {
 		baseInstrument
 		instrument.Synchronous
+
+		// verbose is the verbose view of the instrument when
+		// enabled, otherwise nil.
+		verbose *syncInstrument
+
+		// usage counts the measurements of the instrument when
+		// usage statistics are enabled, otherwise nil.
+		usage *instrumentUsage
 	}
 
 	// mapkey uniquely describes a metric instrument in terms of its
@@ -102,10 +152,6 @@ type (
 		// where a attribute set is shared due to batch recording.
 		attrs attribute.Set
 
-		// sortSlice has a single purpose - as a temporary place for sorting
-		// during attributes creation to avoid allocation.
-		sortSlice attribute.Sortable
-
 		// inst is a pointer to the corresponding instrument.
 		inst *baseInstrument
 
@@ -119,16 +165,25 @@ type (
 	baseInstrument struct {
 		meter      *Accumulator
 		descriptor sdkapi.Descriptor
+
+		// attributeFilter filters the attributes of the
+		// records of the instrument, if not nil.
+		attributeFilter attribute.Filter
 	}
 )
 
 var (
-	_ sdkapi.MeterImpl = &Accumulator{}
+	_ sdkapi.MeterImpl     = &Accumulator{}
+	_ sdkapi.SyncSliceImpl = &syncInstrument{}
 
 	// ErrUninitializedInstrument is returned when an instrument is used when uninitialized.
 	ErrUninitializedInstrument = fmt.Errorf("use of an uninitialized instrument")
 
 	ErrBadInstrument = fmt.Errorf("use of a instrument from another SDK")
+
+	// ErrReentrantCollect is reported when a collection is requested
+	// from a callback of the collection in progress.
+	ErrReentrantCollect = fmt.Errorf("collect called from a callback of the collection")
 )
 
 func (b *baseInstrument) Descriptor() sdkapi.Descriptor {
@@ -143,21 +198,34 @@ func (s *syncInstrument) Implementation() interface{} {
 	return s
 }
 
+// sortablePool holds the temporary space used for sorting attributes
+// while a record is looked up, so that a record is only allocated when one
+// must be stored.
+var sortablePool = sync.Pool{
+	New: func() interface{} { return new(attribute.Sortable) },
+}
+
 // acquireHandle gets or creates a `*record` corresponding to `kvs`,
-// the input attributes.
+// the input attributes.  The attributes are copied into the attribute set
+// of the record, kvs is not retained.
 func (b *baseInstrument) acquireHandle(kvs []attribute.KeyValue) *record {
+	tmp := sortablePool.Get().(*attribute.Sortable)
+	attrs := attribute.NewSetWithSortable(kvs, tmp)
+	sortablePool.Put(tmp)
+	return b.acquireHandleSet(attrs)
+}
 
-	// This memory allocation may not be used, but it's
-	// needed for the `sortSlice` field, to avoid an
-	// allocation while sorting.
-	rec := &record{}
-	rec.attrs = attribute.NewSetWithSortable(kvs, &rec.sortSlice)
-
+// acquireHandleSet returns the current record of the attribute set, which
+// the caller computed already, mapping a new record when there is none.
+func (b *baseInstrument) acquireHandleSet(attrs attribute.Set) *record {
+	if b.attributeFilter != nil {
+		attrs, _ = attrs.Filter(b.attributeFilter)
+	}
 	// Create lookup key for sync.Map (one allocation, as this
 	// passes through an interface{})
 	mk := mapkey{
 		descriptor: &b.descriptor,
-		ordered:    rec.attrs.Equivalent(),
+		ordered:    attrs.Equivalent(),
 	}
 
 	if actual, ok := b.meter.current.Load(mk); ok {
@@ -171,10 +239,17 @@ func (b *baseInstrument) acquireHandle(kvs []attribute.KeyValue) *record {
 		// This entry is no longer mapped, try to add a new entry.
 	}
 
-	rec.refMapped = refcountMapped{value: 2}
-	rec.inst = b
+	rec := &record{
+		attrs:     attrs,
+		refMapped: refcountMapped{value: 2},
+		inst:      b,
+	}
 
-	b.meter.processor.AggregatorFor(&b.descriptor, &rec.current, &rec.checkpoint)
+	if sel := b.meter.attributeSelector; sel != nil {
+		sel.AggregatorForAttributes(&b.descriptor, &rec.attrs, &rec.current, &rec.checkpoint)
+	} else {
+		b.meter.processor.AggregatorFor(&b.descriptor, &rec.current, &rec.checkpoint)
+	}
 
 	for {
 		// Load/Store: there's a memory allocation to place `mk` into
@@ -201,7 +276,10 @@ func (b *baseInstrument) acquireHandle(kvs []attribute.KeyValue) *record {
 			runtime.Gosched()
 			continue
 		}
-		// The new entry was added to the map, good to go.
+		// The new entry was added to the map, good to go.  Since rec
+		// was fully initialized before it was published, concurrent
+		// callers that load it observe its aggregators, and the records
+		// of callers that lost the race are discarded unused.
 		return rec
 	}
 }
@@ -210,15 +288,113 @@ func (b *baseInstrument) acquireHandle(kvs []attribute.KeyValue) *record {
 //
 // The order of the input array `kvs` may be sorted after the function is called.
 func (s *syncInstrument) RecordOne(ctx context.Context, num number.Number, kvs []attribute.KeyValue) {
+	kvs = s.meter.withContextAttributes(ctx, &s.descriptor, kvs)
+	if s.usage != nil {
+		s.usage.record(1)
+	}
+	if sc := scopeFromContext(ctx); sc != nil {
+		sc.record(s, []number.Number{num}, kvs)
+		return
+	}
+	s.recordOne(ctx, num, kvs)
+}
+
+// recordOne captures a single synchronous metric event in the records of
+// the instrument.
+func (s *syncInstrument) recordOne(ctx context.Context, num number.Number, kvs []attribute.KeyValue) {
 	h := s.acquireHandle(kvs)
 	defer h.unbind()
 	h.captureOne(ctx, num)
+
+	if s.verbose != nil && s.meter.verbose(ctx) {
+		s.verbose.recordOne(ctx, num, kvs)
+	}
 }
 
-// ObserveOne captures a single asynchronous metric event.
+// RecordSlice captures a batch of synchronous metric events that share
+// one attribute set, looking up the record only once.
+//
+// The order of the input array `kvs` may be sorted after the function is called.
+func (s *syncInstrument) RecordSlice(ctx context.Context, nums []number.Number, kvs []attribute.KeyValue) {
+	kvs = s.meter.withContextAttributes(ctx, &s.descriptor, kvs)
+	if s.usage != nil {
+		s.usage.record(len(nums))
+	}
+	if sc := scopeFromContext(ctx); sc != nil {
+		sc.record(s, nums, kvs)
+		return
+	}
+	s.recordSlice(ctx, nums, kvs)
+}
+
+// recordSlice captures a batch of synchronous metric events in the
+// records of the instrument.
+func (s *syncInstrument) recordSlice(ctx context.Context, nums []number.Number, kvs []attribute.KeyValue) {
+	h := s.acquireHandle(kvs)
+	defer h.unbind()
+	h.captureSlice(ctx, nums)
+
+	if s.verbose != nil && s.meter.verbose(ctx) {
+		s.verbose.recordSlice(ctx, nums, kvs)
+	}
+}
 
+// RecordBatch implements sdkapi.BatchImpl.  The attribute set of the
+// measurements is computed once, and the measurements are aggregated in
+// the same collection.  Measurements of instruments of other MeterImpls,
+// and every measurement when WithContextAttributes is configured, are
+// passed to the RecordOne method of their instrument.
+//
+// The order of the input array `kvs` may be sorted after the function is called.
+func (m *Accumulator) RecordBatch(ctx context.Context, kvs []attribute.KeyValue, measurements ...sdkapi.Measurement) {
+	var attrs attribute.Set
+	computed := false
+	sc := scopeFromContext(ctx)
+
+	m.batchLock.RLock()
+	defer m.batchLock.RUnlock()
+
+	for _, meas := range measurements {
+		impl := meas.SyncImpl()
+		if impl == nil {
+			continue
+		}
+		s, ok := impl.Implementation().(*syncInstrument)
+		if !ok || s.meter != m || m.contextAttributes != nil {
+			// The attributes of the context may differ by
+			// instrument.
+			impl.RecordOne(ctx, meas.Number(), kvs)
+			continue
+		}
+		if s.usage != nil {
+			s.usage.record(1)
+		}
+		if sc != nil {
+			sc.record(s, []number.Number{meas.Number()}, kvs)
+			continue
+		}
+		if !computed {
+			attrs = attribute.NewSet(kvs...)
+			computed = true
+		}
+		h := s.acquireHandleSet(attrs)
+		h.captureOne(ctx, meas.Number())
+		h.unbind()
+
+		if s.verbose != nil && m.verbose(ctx) {
+			s.verbose.recordOne(ctx, meas.Number(), kvs)
+		}
+	}
+}
+
+// ObserveOne captures a single asynchronous metric event.  An observation
+// time carried by ctx is validated against the observation time tolerance.
+//
 // The order of the input array `kvs` may be sorted after the function is called.
 func (a *asyncInstrument) ObserveOne(ctx context.Context, num number.Number, attrs []attribute.KeyValue) {
+	if !a.meter.validObservation(ctx, &a.descriptor) {
+		return
+	}
 	h := a.acquireHandle(attrs)
 	defer h.unbind()
 	h.captureOne(ctx, num)
@@ -233,33 +409,68 @@ func (a *asyncInstrument) ObserveOne(ctx context.Context, num number.Number, att
 // processor will call Collect() when it receives a request to scrape
 // current metric values.  A push-based processor should configure its
 // own periodic collection.
-func NewAccumulator(processor export.Processor) *Accumulator {
-	return &Accumulator{
-		processor: processor,
-		callbacks: map[*callback]struct{}{},
+func NewAccumulator(processor export.Processor, opts ...Option) *Accumulator {
+	cfg := config{
+		observationTolerance: DefaultObservationTimeTolerance,
 	}
+	for _, opt := range opts {
+		cfg = opt.apply(cfg)
+	}
+	attributeSelector, _ := processor.(export.AttributeAggregatorSelector)
+	m := &Accumulator{
+		processor:         processor,
+		attributeSelector: attributeSelector,
+		callbacks:         map[*callback]struct{}{},
+		verboseKey:        cfg.verboseKey,
+
+		observationTolerance: cfg.observationTolerance,
+		usageSampling:        cfg.usageSampling,
+		instrumentChecker:    cfg.instrumentChecker,
+		contextAttributes:    cfg.contextAttributes,
+		attributeFilter:      cfg.attributeFilter,
+	}
+	if versioned, ok := processor.(export.VersionedAggregatorSelector); ok {
+		m.versioned = versioned
+		m.selectionVersion = versioned.SelectionVersion()
+	}
+	return m
 }
 
 var _ sdkapi.MeterImpl = &Accumulator{}
+var _ sdkapi.BatchImpl = &Accumulator{}
 
 // NewSyncInstrument implements sdkapi.MetricImpl.
 func (m *Accumulator) NewSyncInstrument(descriptor sdkapi.Descriptor) (sdkapi.SyncImpl, error) {
-	return &syncInstrument{
+	descriptor = m.checkInstrument(descriptor)
+	inst := &syncInstrument{
 		baseInstrument: baseInstrument{
 			descriptor: descriptor,
 			meter:      m,
 		},
-	}, nil
+	}
+	inst.attributeFilter = m.attributeFilterFor(&inst.descriptor)
+	m.trackUsage(inst)
+	if m.verboseKey != "" {
+		inst.verbose = &syncInstrument{
+			baseInstrument: baseInstrument{
+				descriptor: verboseDescriptor(descriptor),
+				meter:      m,
+			},
+		}
+	}
+	return inst, nil
 }
 
 // NewAsyncInstrument implements sdkapi.MetricImpl.
 func (m *Accumulator) NewAsyncInstrument(descriptor sdkapi.Descriptor) (sdkapi.AsyncImpl, error) {
+	descriptor = m.checkInstrument(descriptor)
 	a := &asyncInstrument{
 		baseInstrument: baseInstrument{
 			descriptor: descriptor,
 			meter:      m,
 		},
 	}
+	a.attributeFilter = m.attributeFilterFor(&a.descriptor)
 	return a, nil
 }
 
@@ -295,12 +506,24 @@ func (m *Accumulator) RegisterCallback(insts []instrument.Asynchronous, f func(c
 // one Export() call per current aggregation.
 //
 // Returns the number of records that were checkpointed.
+//
+// Callbacks may record measurements of synchronous instruments, which
+// are collected by the current collection, create instruments and
+// register callbacks, which run from the next collection.  A Collect
+// called with the context of a callback would wait for itself: it
+// reports ErrReentrantCollect to the global error handler and returns 0.
 func (m *Accumulator) Collect(ctx context.Context) int {
+	if a, _ := ctx.Value(asyncContextKey{}).(*Accumulator); a == m {
+		otel.Handle(ErrReentrantCollect)
+		return 0
+	}
 	m.collectLock.Lock()
 	defer m.collectLock.Unlock()
 
 	m.runAsyncCallbacks(ctx)
+	m.batchLock.Lock()
 	checkpointed := m.collectInstruments()
+	m.batchLock.Unlock()
 	m.currentEpoch++
 
 	return checkpointed
@@ -309,6 +532,16 @@ func (m *Accumulator) Collect(ctx context.Context) int {
 func (m *Accumulator) collectInstruments() int {
 	checkpointed := 0
 
+	// When the selection of Aggregators has changed, every record
+	// is replaced, which may take several collections for records
+	// that are in use.
+	var version uint64
+	reselect, retained := false, false
+	if m.versioned != nil {
+		version = m.versioned.SelectionVersion()
+		reselect = version != m.selectionVersion
+	}
+
 	m.current.Range(func(key interface{}, value interface{}) bool {
 		// Note: always continue to iterate over the entire
 		// map by returning `true` in this function.
@@ -322,12 +555,16 @@ func (m *Accumulator) collectInstruments() int {
 			// checkpoint and continue.
 			checkpointed += m.checkpointRecord(inuse)
 			inuse.collectedCount = mods
-			return true
+			if !reselect {
+				return true
+			}
 		}
 
-		// Having no updates since last collection, try to unmap:
+		// Having no updates since last collection, or Aggregators
+		// selected by a prior version, try to unmap:
 		if unmapped := inuse.refMapped.tryUnmap(); !unmapped {
 			// The record is referenced by a binding, continue.
+			retained = retained || reselect
 			return true
 		}
 
@@ -340,26 +577,43 @@ func (m *Accumulator) collectInstruments() int {
 		// `tryUnmap` in this function.  Since this is the
 		// last we'll see of this record, checkpoint
 		mods = atomic.LoadInt64(&inuse.updateCount)
-		if mods != coll {
+		if mods != inuse.collectedCount {
 			checkpointed += m.checkpointRecord(inuse)
 		}
 		return true
 	})
 
+	if reselect && !retained {
+		m.selectionVersion = version
+	}
 	return checkpointed
 }
 
+// runAsyncCallbacks runs the registered callbacks without holding
+// callbackLock, so that callbacks may register callbacks, which run from
+// the next collection.
 func (m *Accumulator) runAsyncCallbacks(ctx context.Context) {
 	m.callbackLock.Lock()
-	defer m.callbackLock.Unlock()
+	callbacks := make([]*callback, 0, len(m.callbacks))
+	for cb := range m.callbacks {
+		callbacks = append(callbacks, cb)
+	}
+	m.callbackLock.Unlock()
 
 	ctx = context.WithValue(ctx, asyncContextKey{}, m)
 
-	for cb := range m.callbacks {
+	for _, cb := range callbacks {
 		cb.f(ctx)
 	}
 }
 
+// InCallback returns true if ctx is the context of a callback of an
+// Accumulator, in which a collection is in progress.
+func InCallback(ctx context.Context) bool {
+	_, ok := ctx.Value(asyncContextKey{}).(*Accumulator)
+	return ok
+}
+
 func (m *Accumulator) checkpointRecord(r *record) int {
 	if r.current == nil {
 		return 0
@@ -396,6 +650,57 @@ func (r *record) captureOne(ctx context.Context, num number.Number) {
 	atomic.AddInt64(&r.updateCount, 1)
 }
 
+func (r *record) captureSlice(ctx context.Context, nums []number.Number) {
+	if r.current == nil {
+		// The instrument is disabled according to the AggregatorSelector.
+		return
+	}
+	nums = rangeFilter(nums, &r.inst.descriptor)
+	if len(nums) == 0 {
+		return
+	}
+	if su, ok := r.current.(aggregator.SliceUpdater); ok {
+		if err := su.UpdateSlice(ctx, nums, &r.inst.descriptor); err != nil {
+			otel.Handle(err)
+			return
+		}
+	} else {
+		for _, num := range nums {
+			if err := r.current.Update(ctx, num, &r.inst.descriptor); err != nil {
+				otel.Handle(err)
+				return
+			}
+		}
+	}
+	// Record was modified, inform the Collect() that things need
+	// to be collected while the record is still mapped.
+	atomic.AddInt64(&r.updateCount, 1)
+}
+
+// rangeFilter returns the values of nums that pass the RangeTest,
+// handling an error for each one that does not.  The input is returned
+// unmodified when every value is valid.
+func rangeFilter(nums []number.Number, desc *sdkapi.Descriptor) []number.Number {
+	var valid []number.Number
+	for i, num := range nums {
+		if err := aggregator.RangeTest(num, desc); err != nil {
+			otel.Handle(err)
+			if valid == nil {
+				valid = make([]number.Number, i, len(nums))
+				copy(valid, nums[:i])
+			}
+			continue
+		}
+		if valid != nil {
+			valid = append(valid, num)
+		}
+	}
+	if valid == nil {
+		return nums
+	}
+	return valid
+}
+
 func (r *record) unbind() {
 	r.refMapped.unref()
 }
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdkapi // import "go.opentelemetry.io/otel/sdk/metric/sdkapi"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+)
+
+type (
+	attributesMeterImpl struct {
+		MeterImpl
+		base []attribute.KeyValue
+	}
+	attributesSync struct {
+		SyncImpl
+		base []attribute.KeyValue
+	}
+	attributesAsync struct {
+		AsyncImpl
+		base []attribute.KeyValue
+	}
+)
+
+var (
+	_ MeterImpl     = attributesMeterImpl{}
+	_ BatchImpl     = attributesMeterImpl{}
+	_ SyncSliceImpl = attributesSync{}
+	_ AsyncImpl     = attributesAsync{}
+)
+
+// WithAttributes returns a Meter whose instruments include kvs in the
+// attributes of every measurement and observation.  Attributes passed with
+// a measurement take precedence over kvs sharing the same key.  The
+// attribute set is deduplicated and sorted once, so each measurement only
+// pays for joining it with the per-call attributes.
+//
+// Deriving from a derived Meter accumulates attributes, the most recently
+// added taking precedence.  Meters not created by WrapMeterImpl are
+// returned unchanged.
+func WithAttributes(m metric.Meter, kvs ...attribute.KeyValue) metric.Meter {
+	impl := UnwrapMeterImpl(m)
+	if impl == nil || len(kvs) == 0 {
+		return m
+	}
+	if am, ok := impl.(attributesMeterImpl); ok {
+		impl = am.MeterImpl
+		kvs = merge(am.base, kvs)
+	}
+	set := attribute.NewSet(kvs...)
+	return WrapMeterImpl(attributesMeterImpl{
+		MeterImpl: impl,
+		base:      set.ToSlice(),
+	})
+}
+
+// merge returns a new slice holding base followed by kvs.  A copy is made
+// on every call because the SDK may sort the slice it is given.
+func merge(base, kvs []attribute.KeyValue) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(base)+len(kvs))
+	attrs = append(attrs, base...)
+	return append(attrs, kvs...)
+}
+
+// NewSyncInstrument implements MeterImpl.
+func (m attributesMeterImpl) NewSyncInstrument(descriptor Descriptor) (SyncImpl, error) {
+	inst, err := m.MeterImpl.NewSyncInstrument(descriptor)
+	if inst == nil || err != nil {
+		return inst, err
+	}
+	return attributesSync{SyncImpl: inst, base: m.base}, nil
+}
+
+// NewAsyncInstrument implements MeterImpl.
+func (m attributesMeterImpl) NewAsyncInstrument(descriptor Descriptor) (AsyncImpl, error) {
+	inst, err := m.MeterImpl.NewAsyncInstrument(descriptor)
+	if inst == nil || err != nil {
+		return inst, err
+	}
+	return attributesAsync{AsyncImpl: inst, base: m.base}, nil
+}
+
+// RecordBatch implements BatchImpl.  The attributes of the Meter apply to
+// every measurement, so the instruments of the batch are unwrapped and
+// the batch is passed on with the attributes merged once.
+func (m attributesMeterImpl) RecordBatch(ctx context.Context, attrs []attribute.KeyValue, measurements ...Measurement) {
+	unwrapped := make([]Measurement, len(measurements))
+	for i, meas := range measurements {
+		if as, ok := meas.instrument.(attributesSync); ok {
+			meas.instrument = as.SyncImpl
+		}
+		unwrapped[i] = meas
+	}
+	recordBatch(ctx, m.MeterImpl, merge(m.base, attrs), unwrapped)
+}
+
+// RecordOne implements SyncImpl.
+func (s attributesSync) RecordOne(ctx context.Context, num number.Number, attrs []attribute.KeyValue) {
+	s.SyncImpl.RecordOne(ctx, num, merge(s.base, attrs))
+}
+
+// RecordSlice implements SyncSliceImpl.
+func (s attributesSync) RecordSlice(ctx context.Context, nums []number.Number, attrs []attribute.KeyValue) {
+	recordSlice(ctx, s.SyncImpl, nums, merge(s.base, attrs))
+}
+
+// ObserveOne implements AsyncImpl.
+func (a attributesAsync) ObserveOne(ctx context.Context, num number.Number, attrs []attribute.KeyValue) {
+	a.AsyncImpl.ObserveOne(ctx, num, merge(a.base, attrs))
+}
@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdkapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+)
+
+type recordingMeterImpl struct {
+	sets      []attribute.Set
+	callbacks []func(context.Context)
+}
+
+type recordingSync struct {
+	SyncImpl
+	m    *recordingMeterImpl
+	desc Descriptor
+}
+
+type recordingAsync struct {
+	AsyncImpl
+	m *recordingMeterImpl
+}
+
+func (m *recordingMeterImpl) NewSyncInstrument(desc Descriptor) (SyncImpl, error) {
+	return recordingSync{SyncImpl: NewNoopSyncInstrument(), m: m, desc: desc}, nil
+}
+
+func (m *recordingMeterImpl) NewAsyncInstrument(Descriptor) (AsyncImpl, error) {
+	return recordingAsync{AsyncImpl: NewNoopAsyncInstrument(), m: m}, nil
+}
+
+func (m *recordingMeterImpl) RegisterCallback(_ []instrument.Asynchronous, cb func(context.Context)) error {
+	m.callbacks = append(m.callbacks, cb)
+	return nil
+}
+
+func (s recordingSync) Descriptor() Descriptor {
+	return s.desc
+}
+
+func (s recordingSync) RecordOne(_ context.Context, _ number.Number, attrs []attribute.KeyValue) {
+	s.m.sets = append(s.m.sets, attribute.NewSet(attrs...))
+}
+
+func (a recordingAsync) ObserveOne(_ context.Context, _ number.Number, attrs []attribute.KeyValue) {
+	a.m.sets = append(a.m.sets, attribute.NewSet(attrs...))
+}
+
+func TestWithAttributes(t *testing.T) {
+	ctx := context.Background()
+	impl := &recordingMeterImpl{}
+	meter := WithAttributes(WrapMeterImpl(impl), attribute.String("A", "a"), attribute.String("B", "b"))
+
+	counter, err := meter.SyncInt64().Counter("counter")
+	require.NoError(t, err)
+	counter.Add(ctx, 1, attribute.String("B", "call"), attribute.String("C", "c"))
+	counter.Add(ctx, 1)
+
+	RecordInt64s(ctx, counter, []int64{1, 2})
+
+	derived := WithAttributes(meter, attribute.String("A", "derived"))
+	require.Equal(t, impl, UnwrapMeterImpl(derived).(attributesMeterImpl).MeterImpl)
+
+	gauge, err := derived.AsyncFloat64().Gauge("gauge")
+	require.NoError(t, err)
+	require.NoError(t, derived.RegisterCallback([]instrument.Asynchronous{gauge}, func(ctx context.Context) {
+		gauge.Observe(ctx, 1)
+	}))
+	require.Len(t, impl.callbacks, 1)
+	impl.callbacks[0](ctx)
+
+	base := attribute.NewSet(attribute.String("A", "a"), attribute.String("B", "b"))
+	require.Equal(t, []attribute.Set{
+		attribute.NewSet(attribute.String("A", "a"), attribute.String("B", "call"), attribute.String("C", "c")),
+		base,
+		base,
+		base,
+		attribute.NewSet(attribute.String("A", "derived"), attribute.String("B", "b")),
+	}, impl.sets)
+
+	plain := WrapMeterImpl(impl)
+	require.Equal(t, plain, WithAttributes(plain))
+}
+
+type batchingMeterImpl struct {
+	recordingMeterImpl
+	batches [][]Measurement
+}
+
+func (m *batchingMeterImpl) RecordBatch(_ context.Context, attrs []attribute.KeyValue, measurements ...Measurement) {
+	m.sets = append(m.sets, attribute.NewSet(attrs...))
+	m.batches = append(m.batches, measurements)
+}
+
+func TestRecordBatch(t *testing.T) {
+	ctx := context.Background()
+	impl := &recordingMeterImpl{}
+	meter := WrapMeterImpl(impl)
+
+	in, err := meter.SyncInt64().Counter("bytes_in")
+	require.NoError(t, err)
+	out, err := meter.SyncFloat64().Counter("bytes_out")
+	require.NoError(t, err)
+
+	// Without BatchImpl every measurement is recorded on its own, and
+	// instruments of other Meters are ignored.
+	attr := attribute.String("A", "a")
+	RecordBatch(ctx, meter, []attribute.KeyValue{attr},
+		Int64Measurement(in, 1),
+		Float64Measurement(out, 2),
+		Int64Measurement(nil, 3),
+	)
+	require.Equal(t, []attribute.Set{attribute.NewSet(attr), attribute.NewSet(attr)}, impl.sets)
+
+	batching := &batchingMeterImpl{}
+	derived := WithAttributes(WrapMeterImpl(batching), attribute.String("B", "b"))
+	in, err = derived.SyncInt64().Counter("bytes_in")
+	require.NoError(t, err)
+	out, err = derived.SyncFloat64().Counter("bytes_out")
+	require.NoError(t, err)
+
+	RecordBatch(ctx, derived, []attribute.KeyValue{attr},
+		Int64Measurement(in, 1),
+		Float64Measurement(out, 2),
+	)
+	require.Equal(t, []attribute.Set{attribute.NewSet(attr, attribute.String("B", "b"))}, batching.sets)
+	require.Len(t, batching.batches, 1)
+	require.Len(t, batching.batches[0], 2)
+	for _, meas := range batching.batches[0] {
+		_, ok := meas.SyncImpl().(recordingSync)
+		require.True(t, ok, "instrument not unwrapped: %T", meas.SyncImpl())
+	}
+	require.Equal(t, number.NewInt64Number(1), batching.batches[0][0].Number())
+	require.Equal(t, number.NewFloat64Number(2), batching.batches[0][1].Number())
+}
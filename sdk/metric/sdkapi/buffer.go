@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdkapi // import "go.opentelemetry.io/otel/sdk/metric/sdkapi"
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxPooledAttributes is the capacity beyond which a released
+// AttributeBuffer is not returned to the pool, so that an occasional large
+// attribute list does not stay allocated.
+const maxPooledAttributes = 128
+
+var attributeBufferPool = sync.Pool{
+	New: func() interface{} {
+		return &AttributeBuffer{kvs: make([]attribute.KeyValue, 0, 8)}
+	},
+}
+
+// AttributeBuffer is a reusable scratch buffer for the attributes of a
+// measurement.  Instruments created by a Meter returned from
+// WrapMeterImpl over a MeterImpl honoring the contract of SyncImpl and
+// AsyncImpl do not retain the attributes they are passed, so the buffer
+// can be reset and refilled as soon as the measurement returns:
+//
+//	buf := sdkapi.NewAttributeBuffer()
+//	defer buf.Release()
+//
+//	buf.Append(attribute.String("route", route), attribute.Int("code", code))
+//	requests.Add(ctx, 1, buf.Attributes()...)
+//
+// The SDK may reorder the attributes in the buffer while recording.  An
+// AttributeBuffer is not safe for concurrent use.
+type AttributeBuffer struct {
+	kvs []attribute.KeyValue
+}
+
+// NewAttributeBuffer returns an empty AttributeBuffer from a pool of
+// buffers.  Release returns it to the pool when it is no longer used.
+func NewAttributeBuffer() *AttributeBuffer {
+	return attributeBufferPool.Get().(*AttributeBuffer)
+}
+
+// Append adds kvs to the attributes of the buffer.
+func (b *AttributeBuffer) Append(kvs ...attribute.KeyValue) {
+	b.kvs = append(b.kvs, kvs...)
+}
+
+// Attributes returns the attributes of the buffer.  The slice is valid
+// until the next call to Reset or Release.
+func (b *AttributeBuffer) Attributes() []attribute.KeyValue {
+	return b.kvs
+}
+
+// Len returns the number of attributes in the buffer.
+func (b *AttributeBuffer) Len() int {
+	return len(b.kvs)
+}
+
+// Reset empties the buffer, keeping its capacity for reuse.
+func (b *AttributeBuffer) Reset() {
+	// Clear the values so that the pool does not keep the strings
+	// and slices the attributes refer to.
+	for i := range b.kvs {
+		b.kvs[i] = attribute.KeyValue{}
+	}
+	b.kvs = b.kvs[:0]
+}
+
+// Release resets the buffer and returns it to the pool.  The buffer must
+// not be used after Release.
+func (b *AttributeBuffer) Release() {
+	if cap(b.kvs) > maxPooledAttributes {
+		return
+	}
+	b.Reset()
+	attributeBufferPool.Put(b)
+}
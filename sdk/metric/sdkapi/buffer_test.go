@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdkapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestAttributeBuffer(t *testing.T) {
+	buf := NewAttributeBuffer()
+	require.Equal(t, 0, buf.Len())
+
+	buf.Append(attribute.String("A", "a"))
+	buf.Append(attribute.String("B", "b"), attribute.Int("C", 1))
+	require.Equal(t, 3, buf.Len())
+	require.Equal(t, []attribute.KeyValue{
+		attribute.String("A", "a"),
+		attribute.String("B", "b"),
+		attribute.Int("C", 1),
+	}, buf.Attributes())
+
+	kvs := buf.Attributes()
+	buf.Reset()
+	require.Equal(t, 0, buf.Len())
+	require.Empty(t, buf.Attributes())
+	// Reset clears the values the buffer held.
+	require.Equal(t, attribute.KeyValue{}, kvs[0])
+
+	buf.Append(attribute.Bool("D", true))
+	require.Equal(t, []attribute.KeyValue{attribute.Bool("D", true)}, buf.Attributes())
+	buf.Release()
+
+	// Released buffers are handed out empty.
+	require.Equal(t, 0, NewAttributeBuffer().Len())
+}
+
+func TestAttributeBufferReleaseLarge(t *testing.T) {
+	buf := NewAttributeBuffer()
+	for i := 0; i <= maxPooledAttributes; i++ {
+		buf.Append(attribute.Int("I", i))
+	}
+	kvs := buf.Attributes()
+	buf.Release()
+	// A buffer beyond the pooled capacity is left to the garbage
+	// collector untouched.
+	require.Equal(t, attribute.Int("I", 0), kvs[0])
+}
@@ -15,6 +15,11 @@
 package sdkapi // import "go.opentelemetry.io/otel/sdk/metric/sdkapi"
 
 import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric/unit"
 	"go.opentelemetry.io/otel/sdk/metric/number"
 )
@@ -28,6 +33,29 @@ type Descriptor struct {
 	numberKind     number.Kind
 	description    string
 	unit           unit.Unit
+	// attributeKeys is interned by internAttributeKeys, so that
+	// Descriptors with equal advice compare equal.
+	attributeKeys *[]attribute.Key
+}
+
+// attributeKeyAdvice maps the encoding of a list of attribute keys to
+// its interned copy.
+var attributeKeyAdvice sync.Map
+
+// internAttributeKeys returns the interned copy of keys.
+func internAttributeKeys(keys []attribute.Key) *[]attribute.Key {
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(strconv.Quote(string(k)))
+	}
+	encoded := b.String()
+	if interned, ok := attributeKeyAdvice.Load(encoded); ok {
+		return interned.(*[]attribute.Key)
+	}
+	// The caller may modify keys afterwards.
+	dup := append([]attribute.Key(nil), keys...)
+	interned, _ := attributeKeyAdvice.LoadOrStore(encoded, &dup)
+	return interned.(*[]attribute.Key)
 }
 
 // NewDescriptor returns a Descriptor with the given contents.
@@ -41,6 +69,16 @@ func NewDescriptor(name string, ikind InstrumentKind, nkind number.Kind, descrip
 	}
 }
 
+// NewDescriptorWithAttributeKeys returns a Descriptor with the given
+// contents, including the attribute keys recommended for the instrument.
+func NewDescriptorWithAttributeKeys(name string, ikind InstrumentKind, nkind number.Kind, description string, unit unit.Unit, keys []attribute.Key) Descriptor {
+	d := NewDescriptor(name, ikind, nkind, description, unit)
+	if len(keys) != 0 {
+		d.attributeKeys = internAttributeKeys(keys)
+	}
+	return d
+}
+
 // Name returns the metric instrument's name.
 func (d Descriptor) Name() string {
 	return d.name
@@ -68,3 +106,12 @@ func (d Descriptor) Unit() unit.Unit {
 func (d Descriptor) NumberKind() number.Kind {
 	return d.numberKind
 }
+
+// AttributeKeys returns the attribute keys recommended for the
+// instrument, or nil when the instrument made no recommendation.
+func (d Descriptor) AttributeKeys() []attribute.Key {
+	if d.attributeKeys == nil {
+		return nil
+	}
+	return *d.attributeKeys
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric/unit"
 	"go.opentelemetry.io/otel/sdk/metric/number"
 )
@@ -30,4 +31,27 @@ func TestDescriptorGetters(t *testing.T) {
 	require.Equal(t, number.Int64Kind, d.NumberKind())
 	require.Equal(t, "my description", d.Description())
 	require.Equal(t, unit.Unit("my unit"), d.Unit())
+	require.Nil(t, d.AttributeKeys())
+
+	keys := []attribute.Key{"A", "B"}
+	d = NewDescriptorWithAttributeKeys("name", HistogramInstrumentKind, number.Int64Kind, "my description", "my unit", keys)
+	require.Equal(t, "name", d.Name())
+	require.Equal(t, keys, d.AttributeKeys())
+}
+
+func TestDescriptorAttributeKeysEquality(t *testing.T) {
+	keys := []attribute.Key{"A", "B"}
+	a := NewDescriptorWithAttributeKeys("name", CounterInstrumentKind, number.Int64Kind, "", "", keys)
+	b := NewDescriptorWithAttributeKeys("name", CounterInstrumentKind, number.Int64Kind, "", "", []attribute.Key{"A", "B"})
+	require.True(t, a == b)
+
+	keys[0] = "C"
+	require.Equal(t, []attribute.Key{"A", "B"}, a.AttributeKeys())
+
+	c := NewDescriptorWithAttributeKeys("name", CounterInstrumentKind, number.Int64Kind, "", "", []attribute.Key{"A"})
+	require.False(t, a == c)
+	d := NewDescriptorWithAttributeKeys("name", CounterInstrumentKind, number.Int64Kind, "", "", []attribute.Key{"A,B"})
+	require.False(t, a == d)
+	require.True(t, NewDescriptor("name", CounterInstrumentKind, number.Int64Kind, "", "") ==
+		NewDescriptorWithAttributeKeys("name", CounterInstrumentKind, number.Int64Kind, "", "", nil))
 }
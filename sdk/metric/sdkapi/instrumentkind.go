@@ -16,6 +16,11 @@
 
 package sdkapi // import "go.opentelemetry.io/otel/sdk/metric/sdkapi"
 
+import (
+	"fmt"
+	"strings"
+)
+
 // InstrumentKind describes the kind of instrument.
 type InstrumentKind int8
 
@@ -78,3 +83,36 @@ func (k InstrumentKind) Monotonic() bool {
 func (k InstrumentKind) PrecomputedSum() bool {
 	return k.Adding() && k.Asynchronous()
 }
+
+// instrumentKindText holds the text encoding of each InstrumentKind,
+// used in configuration files.
+var instrumentKindText = map[InstrumentKind]string{
+	HistogramInstrumentKind:             "histogram",
+	GaugeObserverInstrumentKind:         "gauge_observer",
+	CounterInstrumentKind:               "counter",
+	UpDownCounterInstrumentKind:         "up_down_counter",
+	CounterObserverInstrumentKind:       "counter_observer",
+	UpDownCounterObserverInstrumentKind: "up_down_counter_observer",
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the kind as
+// its lower-case name without the InstrumentKind suffix, for example
+// "up_down_counter".
+func (k InstrumentKind) MarshalText() ([]byte, error) {
+	if text, ok := instrumentKindText[k]; ok {
+		return []byte(text), nil
+	}
+	return nil, fmt.Errorf("invalid instrument kind: %d", k)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.  It accepts the
+// encoding of MarshalText and the String of the kind, ignoring case.
+func (k *InstrumentKind) UnmarshalText(text []byte) error {
+	for kind, name := range instrumentKindText {
+		if strings.EqualFold(string(text), name) || strings.EqualFold(string(text), kind.String()) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown instrument kind: %q", text)
+}
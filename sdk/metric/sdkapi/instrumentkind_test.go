@@ -15,6 +15,7 @@
 package sdkapi_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -30,3 +31,35 @@ func TestInstrumentKinds(t *testing.T) {
 	require.Equal(t, sdkapi.CounterObserverInstrumentKind.String(), "CounterObserverInstrumentKind")
 	require.Equal(t, sdkapi.UpDownCounterObserverInstrumentKind.String(), "UpDownCounterObserverInstrumentKind")
 }
+
+func TestInstrumentKindText(t *testing.T) {
+	for _, kind := range []sdkapi.InstrumentKind{
+		sdkapi.HistogramInstrumentKind,
+		sdkapi.GaugeObserverInstrumentKind,
+		sdkapi.CounterInstrumentKind,
+		sdkapi.UpDownCounterInstrumentKind,
+		sdkapi.CounterObserverInstrumentKind,
+		sdkapi.UpDownCounterObserverInstrumentKind,
+	} {
+		text, err := kind.MarshalText()
+		require.NoError(t, err)
+
+		var got sdkapi.InstrumentKind
+		require.NoError(t, got.UnmarshalText(text))
+		require.Equal(t, kind, got)
+
+		got = -1
+		require.NoError(t, got.UnmarshalText([]byte(strings.ToLower(kind.String()))))
+		require.Equal(t, kind, got)
+	}
+
+	text, err := sdkapi.UpDownCounterObserverInstrumentKind.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "up_down_counter_observer", string(text))
+
+	_, err = sdkapi.InstrumentKind(100).MarshalText()
+	require.Error(t, err)
+
+	var kind sdkapi.InstrumentKind
+	require.Error(t, kind.UnmarshalText([]byte("gauge")))
+}
@@ -57,17 +57,40 @@ type SyncImpl interface {
 	InstrumentImpl
 	instrument.Synchronous
 
-	// RecordOne captures a single synchronous metric event.
+	// RecordOne captures a single synchronous metric event.  The
+	// implementation may reorder attrs but does not retain it, the
+	// attributes it stores are copied.
 	RecordOne(ctx context.Context, number number.Number, attrs []attribute.KeyValue)
 }
 
+// SyncSliceImpl is an optional interface implemented by synchronous
+// instruments that can capture many metric events sharing one attribute
+// set in a single call.
+type SyncSliceImpl interface {
+	// RecordSlice captures one synchronous metric event per number.
+	// The implementation does not retain the slice.
+	RecordSlice(ctx context.Context, numbers []number.Number, attrs []attribute.KeyValue)
+}
+
+// BatchImpl is an optional interface implemented by MeterImpls that can
+// capture the measurements of several synchronous instruments sharing
+// one attribute set in a single call.
+type BatchImpl interface {
+	// RecordBatch captures one synchronous metric event per
+	// measurement, all with the attributes attrs.  The
+	// implementation does not retain the slices.
+	RecordBatch(ctx context.Context, attrs []attribute.KeyValue, measurements ...Measurement)
+}
+
 // AsyncImpl is an implementation-level interface to an
 // asynchronous instrument (e.g., Observer instruments).
 type AsyncImpl interface {
 	InstrumentImpl
 	instrument.Asynchronous
 
-	// ObserveOne captures a single synchronous metric event.
+	// ObserveOne captures a single synchronous metric event.  The
+	// implementation may reorder attrs but does not retain it, the
+	// attributes it stores are copied.
 	ObserveOne(ctx context.Context, number number.Number, attrs []attribute.KeyValue)
 }
 
@@ -113,7 +136,8 @@ func NewMeasurement(instrument SyncImpl, number number.Number) Measurement {
 }
 
 // Measurement is a low-level type used with synchronous instruments
-// as a direct interface to the SDK via `RecordBatch`.
+// as a direct interface to the SDK via `RecordBatch`.  The zero
+// Measurement has no instrument and is ignored by RecordBatch.
 type Measurement struct {
 	// number needs to be aligned for 64-bit atomic operations.
 	number     number.Number
@@ -24,6 +24,7 @@ import (
 	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
 	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
 	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/nonrecording"
 	"go.opentelemetry.io/otel/sdk/metric/number"
 )
 
@@ -42,10 +43,51 @@ type (
 	fObserver struct{ AsyncImpl }
 )
 
+var (
+	_ metric.Meter                    = meter{}
+	_ syncint64.InstrumentProvider    = siMeter{}
+	_ syncfloat64.InstrumentProvider  = sfMeter{}
+	_ asyncint64.InstrumentProvider   = aiMeter{}
+	_ asyncfloat64.InstrumentProvider = afMeter{}
+	_ syncint64.Counter               = iAdder{}
+	_ syncint64.UpDownCounter         = iAdder{}
+	_ syncint64.Histogram             = iRecorder{}
+	_ syncfloat64.Counter             = fAdder{}
+	_ syncfloat64.UpDownCounter       = fAdder{}
+	_ syncfloat64.Histogram           = fRecorder{}
+	_ asyncint64.Counter              = iObserver{}
+	_ asyncint64.UpDownCounter        = iObserver{}
+	_ asyncint64.Gauge                = iObserver{}
+	_ asyncfloat64.Counter            = fObserver{}
+	_ asyncfloat64.UpDownCounter      = fObserver{}
+	_ asyncfloat64.Gauge              = fObserver{}
+	_ metric.MeterProvider            = MeterProviderFunc(nil)
+)
+
+// WrapMeterImpl adapts a MeterImpl to the metric.Meter API, so that
+// libraries instrumented with go.opentelemetry.io/otel/metric record
+// into it without changes.
 func WrapMeterImpl(impl MeterImpl) metric.Meter {
 	return meter{impl}
 }
 
+// MeterProviderFunc adapts a function returning the MeterImpl of each
+// instrumentation library to the metric.MeterProvider API, for
+// pipelines that are not built by a controller, which is itself a
+// MeterProvider.  The resulting provider can be installed with
+// go.opentelemetry.io/otel/metric/global.SetMeterProvider.  When the
+// function returns nil, the library is given a non-recording Meter.
+type MeterProviderFunc func(instrumentationName string, cfg metric.MeterConfig) MeterImpl
+
+// Meter implements metric.MeterProvider.
+func (f MeterProviderFunc) Meter(instrumentationName string, opts ...metric.MeterOption) metric.Meter {
+	impl := f(instrumentationName, metric.NewMeterConfig(opts...))
+	if impl == nil {
+		return nonrecording.NewNoopMeter()
+	}
+	return WrapMeterImpl(impl)
+}
+
 func UnwrapMeterImpl(m metric.Meter) MeterImpl {
 	mm, ok := m.(meter)
 	if !ok {
@@ -76,12 +118,12 @@ func (m meter) RegisterCallback(insts []instrument.Asynchronous, cb func(ctx con
 
 func (m meter) newSync(name string, ikind InstrumentKind, nkind number.Kind, opts []instrument.Option) (SyncImpl, error) {
 	cfg := instrument.NewConfig(opts...)
-	return m.NewSyncInstrument(NewDescriptor(name, ikind, nkind, cfg.Description(), cfg.Unit()))
+	return m.NewSyncInstrument(NewDescriptorWithAttributeKeys(name, ikind, nkind, cfg.Description(), cfg.Unit(), cfg.AttributeKeys()))
 }
 
 func (m meter) newAsync(name string, ikind InstrumentKind, nkind number.Kind, opts []instrument.Option) (AsyncImpl, error) {
 	cfg := instrument.NewConfig(opts...)
-	return m.NewAsyncInstrument(NewDescriptor(name, ikind, nkind, cfg.Description(), cfg.Unit()))
+	return m.NewAsyncInstrument(NewDescriptorWithAttributeKeys(name, ikind, nkind, cfg.Description(), cfg.Unit(), cfg.AttributeKeys()))
 }
 
 func (m afMeter) Counter(name string, opts ...instrument.Option) (asyncfloat64.Counter, error) {
@@ -179,3 +221,140 @@ func (a iObserver) Observe(ctx context.Context, value int64, attrs ...attribute.
 		a.AsyncImpl.ObserveOne(ctx, number.NewInt64Number(value), attrs)
 	}
 }
+
+// RecordInt64s records every value into a synchronous int64 instrument
+// created by a Meter returned from WrapMeterImpl.  All values share the
+// same attributes, and they are passed to the SDK in a single call when
+// the instrument implements SyncSliceImpl.  The values are converted
+// when the instrument is a float64 one.  Instruments not created by
+// WrapMeterImpl are ignored.
+func RecordInt64s(ctx context.Context, inst instrument.Synchronous, values []int64, attrs ...attribute.KeyValue) {
+	impl := syncImplOf(inst)
+	if impl == nil {
+		return
+	}
+	kind := impl.Descriptor().NumberKind()
+	nums := make([]number.Number, len(values))
+	for i, v := range values {
+		nums[i] = int64Number(kind, v)
+	}
+	recordSlice(ctx, impl, nums, attrs)
+}
+
+// RecordFloat64s records every value into a synchronous float64
+// instrument created by a Meter returned from WrapMeterImpl.  All values
+// share the same attributes, and they are passed to the SDK in a single
+// call when the instrument implements SyncSliceImpl.  The values are
+// truncated when the instrument is an int64 one.  Instruments not
+// created by WrapMeterImpl are ignored.
+func RecordFloat64s(ctx context.Context, inst instrument.Synchronous, values []float64, attrs ...attribute.KeyValue) {
+	impl := syncImplOf(inst)
+	if impl == nil {
+		return
+	}
+	kind := impl.Descriptor().NumberKind()
+	nums := make([]number.Number, len(values))
+	for i, v := range values {
+		nums[i] = float64Number(kind, v)
+	}
+	recordSlice(ctx, impl, nums, attrs)
+}
+
+// Int64Measurement returns a Measurement of value for a synchronous
+// int64 instrument created by a Meter returned from WrapMeterImpl, to be
+// passed to RecordBatch.  The value is converted when the instrument is
+// a float64 one.  The Measurements of other instruments are ignored.
+func Int64Measurement(inst instrument.Synchronous, value int64) Measurement {
+	impl := syncImplOf(inst)
+	if impl == nil {
+		return Measurement{}
+	}
+	return Measurement{instrument: impl, number: int64Number(impl.Descriptor().NumberKind(), value)}
+}
+
+// Float64Measurement returns a Measurement of value for a synchronous
+// float64 instrument created by a Meter returned from WrapMeterImpl, to
+// be passed to RecordBatch.  The value is truncated when the instrument
+// is an int64 one.  The Measurements of other instruments are ignored.
+func Float64Measurement(inst instrument.Synchronous, value float64) Measurement {
+	impl := syncImplOf(inst)
+	if impl == nil {
+		return Measurement{}
+	}
+	return Measurement{instrument: impl, number: float64Number(impl.Descriptor().NumberKind(), value)}
+}
+
+// RecordBatch records correlated measurements of several instruments
+// with the same attributes, for example the bytes received and sent by
+// one request:
+//
+//	sdkapi.RecordBatch(ctx, m, attrs,
+//	        sdkapi.Int64Measurement(bytesIn, in),
+//	        sdkapi.Int64Measurement(bytesOut, out),
+//	)
+//
+// When the MeterImpl of m implements BatchImpl, as the SDK does, the
+// measurements are passed to it in a single call, which computes the
+// attribute set once and aggregates the measurements in the same
+// collection.  Otherwise each measurement is recorded on its own.
+// Meters not created by WrapMeterImpl are ignored.
+func RecordBatch(ctx context.Context, m metric.Meter, attrs []attribute.KeyValue, measurements ...Measurement) {
+	if impl := UnwrapMeterImpl(m); impl != nil {
+		recordBatch(ctx, impl, attrs, measurements)
+	}
+}
+
+// int64Number returns value as a Number of kind, so that an int64
+// recorded into a float64 instrument is converted rather than
+// reinterpreted.
+func int64Number(kind number.Kind, value int64) number.Number {
+	if kind == number.Float64Kind {
+		return number.NewFloat64Number(float64(value))
+	}
+	return number.NewInt64Number(value)
+}
+
+// float64Number returns value as a Number of kind, truncating it for
+// int64 instruments.
+func float64Number(kind number.Kind, value float64) number.Number {
+	if kind == number.Int64Kind {
+		return number.NewInt64Number(int64(value))
+	}
+	return number.NewFloat64Number(value)
+}
+
+func syncImplOf(inst instrument.Synchronous) SyncImpl {
+	switch i := inst.(type) {
+	case iAdder:
+		return i.SyncImpl
+	case fAdder:
+		return i.SyncImpl
+	case iRecorder:
+		return i.SyncImpl
+	case fRecorder:
+		return i.SyncImpl
+	}
+	return nil
+}
+
+func recordSlice(ctx context.Context, impl SyncImpl, nums []number.Number, attrs []attribute.KeyValue) {
+	if si, ok := impl.(SyncSliceImpl); ok {
+		si.RecordSlice(ctx, nums, attrs)
+		return
+	}
+	for _, num := range nums {
+		impl.RecordOne(ctx, num, attrs)
+	}
+}
+
+func recordBatch(ctx context.Context, impl MeterImpl, attrs []attribute.KeyValue, measurements []Measurement) {
+	if bi, ok := impl.(BatchImpl); ok {
+		bi.RecordBatch(ctx, attrs, measurements...)
+		return
+	}
+	for _, meas := range measurements {
+		if meas.instrument != nil {
+			meas.instrument.RecordOne(ctx, meas.number, attrs)
+		}
+	}
+}
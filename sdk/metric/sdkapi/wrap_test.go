@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdkapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+func TestMeterProviderFunc(t *testing.T) {
+	ctx := context.Background()
+	impl := &recordingMeterImpl{}
+	var libraries []string
+	var provider metric.MeterProvider = MeterProviderFunc(func(name string, cfg metric.MeterConfig) MeterImpl {
+		libraries = append(libraries, name+"@"+cfg.InstrumentationVersion())
+		if name == "disabled" {
+			return nil
+		}
+		return impl
+	})
+
+	meter := provider.Meter("lib", metric.WithInstrumentationVersion("v1"))
+	require.Equal(t, impl, UnwrapMeterImpl(meter))
+	counter, err := meter.SyncInt64().Counter("counter")
+	require.NoError(t, err)
+	counter.Add(ctx, 1, attribute.String("A", "a"))
+
+	disabled := provider.Meter("disabled")
+	require.Nil(t, UnwrapMeterImpl(disabled))
+	histogram, err := disabled.SyncFloat64().Histogram("histogram")
+	require.NoError(t, err)
+	histogram.Record(ctx, 1)
+
+	require.Equal(t, []string{"lib@v1", "disabled@"}, libraries)
+	require.Equal(t, []attribute.Set{attribute.NewSet(attribute.String("A", "a"))}, impl.sets)
+}
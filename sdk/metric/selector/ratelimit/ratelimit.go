@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit implements an AggregatorSelector that limits the rate
+// at which selected instruments are updated.
+//
+// The limit is a guardrail against instrumentation accidentally placed
+// inside a hot loop, for example once per byte copied, which would
+// otherwise spend a significant share of the process's CPU on metric
+// updates.  Each limited instrument has a token bucket shared by all of
+// its attribute sets.  Updates that find the bucket empty are dropped
+// and counted.
+//
+// This package is currently in a pre-GA phase. Backwards incompatible changes
+// may be introduced in subsequent minor version releases as we work to track the
+// evolving OpenTelemetry specification and user feedback.
+package ratelimit // import "go.opentelemetry.io/otel/sdk/metric/selector/ratelimit"
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// DroppedMetricName is the name of the counter registered by
+// Selector.RegisterMetrics.
+const DroppedMetricName = "otel.sdk.metric.rate_limited_updates"
+
+// instrumentKey is the attribute key identifying the instrument in the
+// metric registered by Selector.RegisterMetrics.
+const instrumentKey = attribute.Key("instrument")
+
+type (
+	// Limit configures the rate limit of one instrument.
+	Limit struct {
+		// Instrument is the name of the limited instrument.
+		Instrument string
+		// Rate is the number of updates per second allowed on
+		// average.
+		Rate float64
+		// Burst is the number of updates allowed at once.  A Burst
+		// less than one is treated as one.
+		Burst int
+	}
+
+	// Selector is an AggregatorSelector that wraps the Aggregators
+	// of limited instruments so that they drop the updates exceeding
+	// the rate limit.
+	Selector struct {
+		export.AggregatorSelector
+		limits map[string]Limit
+		now    func() time.Time
+
+		lock    sync.Mutex
+		buckets map[string]*bucket
+	}
+
+	// bucket is the token bucket of one instrument.
+	bucket struct {
+		// dropped is first for 64-bit alignment.
+		dropped uint64
+
+		lock   sync.Mutex
+		rate   float64
+		burst  float64
+		tokens float64
+		last   time.Time
+		now    func() time.Time
+	}
+
+	// limited is an Aggregator that updates the wrapped Aggregator
+	// only while its bucket has tokens.
+	limited struct {
+		aggregator.Aggregator
+		bucket *bucket
+	}
+)
+
+var _ export.AggregatorSelector = &Selector{}
+var _ aggregator.Aggregator = &limited{}
+
+// NewSelector returns a Selector that limits the instruments named by
+// limits, and selects Aggregators using inner.
+func NewSelector(inner export.AggregatorSelector, limits ...Limit) *Selector {
+	s := &Selector{
+		AggregatorSelector: inner,
+		limits:             map[string]Limit{},
+		now:                time.Now,
+		buckets:            map[string]*bucket{},
+	}
+	for _, l := range limits {
+		s.limits[l.Instrument] = l
+	}
+	return s
+}
+
+// AggregatorFor implements export.AggregatorSelector.
+func (s *Selector) AggregatorFor(desc *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	s.AggregatorSelector.AggregatorFor(desc, aggPtrs...)
+
+	b := s.bucketFor(desc.Name())
+	if b == nil {
+		return
+	}
+	for _, ptr := range aggPtrs {
+		if *ptr != nil {
+			*ptr = &limited{Aggregator: *ptr, bucket: b}
+		}
+	}
+}
+
+// bucketFor returns the bucket of the named instrument, or nil when the
+// instrument is not limited.
+func (s *Selector) bucketFor(name string) *bucket {
+	l, ok := s.limits[name]
+	if !ok {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if b, ok := s.buckets[name]; ok {
+		return b
+	}
+	burst := float64(l.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	b := &bucket{
+		rate:   l.Rate,
+		burst:  burst,
+		tokens: burst,
+		last:   s.now(),
+		now:    s.now,
+	}
+	s.buckets[name] = b
+	return b
+}
+
+// Dropped returns the number of updates dropped so far, by instrument
+// name.
+func (s *Selector) Dropped() map[string]uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	dropped := make(map[string]uint64, len(s.buckets))
+	for name, b := range s.buckets {
+		dropped[name] = atomic.LoadUint64(&b.dropped)
+	}
+	return dropped
+}
+
+// RegisterMetrics registers an asynchronous counter named
+// DroppedMetricName with meter, reporting Dropped with one series per
+// limited instrument.
+func (s *Selector) RegisterMetrics(meter metric.Meter) error {
+	counter, err := meter.AsyncInt64().Counter(
+		DroppedMetricName,
+		instrument.WithDescription("Number of updates dropped by the rate limit"),
+	)
+	if err != nil {
+		return err
+	}
+	return meter.RegisterCallback([]instrument.Asynchronous{counter}, func(ctx context.Context) {
+		for name, count := range s.Dropped() {
+			counter.Observe(ctx, int64(count), instrumentKey.String(name))
+		}
+	})
+}
+
+// allow takes a token from the bucket, returning false when it is empty.
+func (b *bucket) allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		atomic.AddUint64(&b.dropped, 1)
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Aggregation implements aggregator.Aggregator.
+func (l *limited) Aggregation() aggregation.Aggregation {
+	return l.Aggregator.Aggregation()
+}
+
+// Update implements aggregator.Aggregator, dropping the update when the
+// rate limit is exceeded.
+func (l *limited) Update(ctx context.Context, num number.Number, desc *sdkapi.Descriptor) error {
+	if !l.bucket.allow() {
+		return nil
+	}
+	return l.Aggregator.Update(ctx, num, desc)
+}
+
+// SynchronizedMove implements aggregator.Aggregator.
+func (l *limited) SynchronizedMove(destination aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	return l.Aggregator.SynchronizedMove(unwrap(destination), desc)
+}
+
+// Merge implements aggregator.Aggregator.
+func (l *limited) Merge(oa aggregator.Aggregator, desc *sdkapi.Descriptor) error {
+	return l.Aggregator.Merge(unwrap(oa), desc)
+}
+
+func unwrap(agg aggregator.Aggregator) aggregator.Aggregator {
+	if l, ok := agg.(*limited); ok {
+		return l.Aggregator
+	}
+	return agg
+}
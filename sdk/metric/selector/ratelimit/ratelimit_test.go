@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	ottest "go.opentelemetry.io/otel/internal/internaltest"
+	"go.opentelemetry.io/otel/metric/instrument"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	processorTest "go.opentelemetry.io/otel/sdk/metric/processor/processortest"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// Ensure struct alignment prior to running tests.
+func TestMain(m *testing.M) {
+	fields := []ottest.FieldOffset{
+		{
+			Name:   "bucket.dropped",
+			Offset: unsafe.Offsetof(bucket{}.dropped),
+		},
+	}
+	if !ottest.Aligned8Byte(fields, os.Stderr) {
+		os.Exit(1)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestSelectorDropsExcessUpdates(t *testing.T) {
+	ctx := context.Background()
+	selector := NewSelector(processorTest.AggregatorSelector(), Limit{
+		Instrument: "hot.sum",
+		Rate:       0,
+		Burst:      3,
+	})
+	testProc := processorTest.NewProcessor(selector, attribute.DefaultEncoder())
+	accum := metricsdk.NewAccumulator(processorTest.NewCheckpointer(testProc))
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	hot, err := meter.SyncInt64().Counter("hot.sum")
+	require.NoError(t, err)
+	cold, err := meter.SyncInt64().Counter("cold.sum")
+	require.NoError(t, err)
+	gauge, err := meter.AsyncInt64().Gauge("hot.lastvalue")
+	require.NoError(t, err)
+	require.NoError(t, meter.RegisterCallback([]instrument.Asynchronous{gauge}, func(ctx context.Context) {
+		gauge.Observe(ctx, 1)
+	}))
+
+	for i := 0; i < 10; i++ {
+		// The bucket is shared by all attribute sets.
+		hot.Add(ctx, 1, attribute.Int("I", i%2))
+		cold.Add(ctx, 1)
+	}
+	accum.Collect(ctx)
+
+	require.Equal(t, map[string]float64{
+		"hot.sum/I=0/":    2,
+		"hot.sum/I=1/":    1,
+		"cold.sum//":      10,
+		"hot.lastvalue//": 1,
+	}, testProc.Values())
+	require.Equal(t, map[string]uint64{"hot.sum": 7}, selector.Dropped())
+}
+
+func TestBucketRefill(t *testing.T) {
+	now := time.Unix(1000, 0)
+	selector := NewSelector(processorTest.AggregatorSelector(), Limit{
+		Instrument: "hot.sum",
+		Rate:       10,
+		Burst:      2,
+	})
+	selector.now = func() time.Time { return now }
+	b := selector.bucketFor("hot.sum")
+	require.Same(t, b, selector.bucketFor("hot.sum"))
+	require.Nil(t, selector.bucketFor("cold.sum"))
+
+	require.True(t, b.allow())
+	require.True(t, b.allow())
+	require.False(t, b.allow())
+
+	// One token is added every 100ms, up to the burst.
+	now = now.Add(100 * time.Millisecond)
+	require.True(t, b.allow())
+	require.False(t, b.allow())
+
+	now = now.Add(time.Hour)
+	require.True(t, b.allow())
+	require.True(t, b.allow())
+	require.False(t, b.allow())
+	require.Equal(t, map[string]uint64{"hot.sum": 3}, selector.Dropped())
+}
@@ -15,35 +15,74 @@
 package simple // import "go.opentelemetry.io/otel/sdk/metric/selector/simple"
 
 import (
+	"time"
+
 	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/tdigest"
 	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
 )
 
 type (
-	selectorInexpensive struct{}
-	selectorHistogram   struct {
+	selectorInexpensive    struct{}
+	selectorMinMaxSumCount struct{}
+	selectorHistogram      struct {
 		options []histogram.Option
 	}
+	selectorExponential struct {
+		options []exponential.Option
+	}
+	selectorTDigest struct {
+		options []tdigest.Option
+	}
+	selectorDDSketch struct {
+		options []ddsketch.Option
+	}
+	selectorCompatible struct {
+		inner        export.AggregatorSelector
+		capabilities export.Capabilities
+	}
+	selectorTimeSource struct {
+		inner export.AggregatorSelector
+		now   func() time.Time
+	}
 )
 
 var (
 	_ export.AggregatorSelector = selectorInexpensive{}
+	_ export.AggregatorSelector = selectorMinMaxSumCount{}
 	_ export.AggregatorSelector = selectorHistogram{}
+	_ export.AggregatorSelector = selectorExponential{}
+	_ export.AggregatorSelector = selectorTDigest{}
+	_ export.AggregatorSelector = selectorDDSketch{}
+	_ export.AggregatorSelector = selectorCompatible{}
+	_ export.AggregatorSelector = selectorTimeSource{}
 )
 
 // NewWithInexpensiveDistribution returns a simple aggregator selector
-// that uses minmaxsumcount aggregators for `Histogram`
-// instruments.  This selector is faster and uses less memory than the
-// others in this package because minmaxsumcount aggregators maintain
-// the least information about the distribution among these choices.
+// that uses sum aggregators for `Histogram` instruments.  This selector
+// is faster and uses less memory than the others in this package
+// because sum aggregators maintain the least information about the
+// distribution among these choices.
 func NewWithInexpensiveDistribution() export.AggregatorSelector {
 	return selectorInexpensive{}
 }
 
+// NewWithMinMaxSumCountDistribution returns a simple aggregator selector
+// that uses minmaxsumcount aggregators for `Histogram` instruments.
+// These keep the minimum and maximum values, which sums do not, at a
+// fraction of the cost of histograms.
+func NewWithMinMaxSumCountDistribution() export.AggregatorSelector {
+	return selectorMinMaxSumCount{}
+}
+
 // NewWithHistogramDistribution returns a simple aggregator selector
 // that uses histogram aggregators for `Histogram` instruments.
 // This selector is a good default choice for most metric exporters.
@@ -51,6 +90,55 @@ func NewWithHistogramDistribution(options ...histogram.Option) export.Aggregator
 	return selectorHistogram{options: options}
 }
 
+// NewWithExponentialDistribution returns a simple aggregator selector
+// that uses base-2 exponential histogram aggregators for `Histogram`
+// instruments.  These need no boundaries and adjust their resolution
+// to the range of the values.
+func NewWithExponentialDistribution(options ...exponential.Option) export.AggregatorSelector {
+	return selectorExponential{options: options}
+}
+
+// NewWithTDigestDistribution returns a simple aggregator selector that
+// uses t-digest aggregators for `Histogram` instruments.  These estimate
+// quantiles with bounded memory, for exporters whose backends do not
+// support histograms.
+func NewWithTDigestDistribution(options ...tdigest.Option) export.AggregatorSelector {
+	return selectorTDigest{options: options}
+}
+
+// NewWithDDSketchDistribution returns a simple aggregator selector that
+// uses DDSketch aggregators for `Histogram` instruments.  These estimate
+// quantiles within a relative accuracy, which suits values with a wide
+// dynamic range better than fixed histogram boundaries.
+func NewWithDDSketchDistribution(options ...ddsketch.Option) export.AggregatorSelector {
+	return selectorDDSketch{options: options}
+}
+
+// NewCompatible returns an aggregator selector that uses the aggregators
+// selected by inner when the capabilities support their kind of
+// aggregation.  Otherwise `Histogram` instruments fall back to sum
+// aggregators, as with NewWithInexpensiveDistribution, and other
+// instruments are disabled.
+func NewCompatible(inner export.AggregatorSelector, capabilities export.Capabilities) export.AggregatorSelector {
+	return selectorCompatible{
+		inner:        inner,
+		capabilities: capabilities,
+	}
+}
+
+// NewWithTimeSource returns an aggregator selector that uses the
+// aggregators selected by inner, except that the lastvalue aggregators
+// timestamp updates with now instead of time.Now, see
+// lastvalue.WithTimeSource.  The Now method of a controller/time.CoarseClock
+// installed with the WithCoarseClock option of the controller/basic
+// package is such a source, refreshed by every collection.
+func NewWithTimeSource(inner export.AggregatorSelector, now func() time.Time) export.AggregatorSelector {
+	return selectorTimeSource{
+		inner: inner,
+		now:   now,
+	}
+}
+
 func sumAggs(aggPtrs []*aggregator.Aggregator) {
 	aggs := sum.New(len(aggPtrs))
 	for i := range aggPtrs {
@@ -79,6 +167,20 @@ func (selectorInexpensive) AggregatorFor(descriptor *sdkapi.Descriptor, aggPtrs
 	}
 }
 
+func (selectorMinMaxSumCount) AggregatorFor(descriptor *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	switch descriptor.InstrumentKind() {
+	case sdkapi.GaugeObserverInstrumentKind:
+		lastValueAggs(aggPtrs)
+	case sdkapi.HistogramInstrumentKind:
+		aggs := minmaxsumcount.New(len(aggPtrs))
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	default:
+		sumAggs(aggPtrs)
+	}
+}
+
 func (s selectorHistogram) AggregatorFor(descriptor *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
 	switch descriptor.InstrumentKind() {
 	case sdkapi.GaugeObserverInstrumentKind:
@@ -92,3 +194,88 @@ func (s selectorHistogram) AggregatorFor(descriptor *sdkapi.Descriptor, aggPtrs
 		sumAggs(aggPtrs)
 	}
 }
+
+func (s selectorExponential) AggregatorFor(descriptor *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	switch descriptor.InstrumentKind() {
+	case sdkapi.GaugeObserverInstrumentKind:
+		lastValueAggs(aggPtrs)
+	case sdkapi.HistogramInstrumentKind:
+		aggs := exponential.New(len(aggPtrs), descriptor, s.options...)
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	default:
+		sumAggs(aggPtrs)
+	}
+}
+
+func (s selectorTDigest) AggregatorFor(descriptor *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	switch descriptor.InstrumentKind() {
+	case sdkapi.GaugeObserverInstrumentKind:
+		lastValueAggs(aggPtrs)
+	case sdkapi.HistogramInstrumentKind:
+		aggs := tdigest.New(len(aggPtrs), descriptor, s.options...)
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	default:
+		sumAggs(aggPtrs)
+	}
+}
+
+func (s selectorDDSketch) AggregatorFor(descriptor *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	switch descriptor.InstrumentKind() {
+	case sdkapi.GaugeObserverInstrumentKind:
+		lastValueAggs(aggPtrs)
+	case sdkapi.HistogramInstrumentKind:
+		aggs := ddsketch.New(len(aggPtrs), descriptor, s.options...)
+		for i := range aggPtrs {
+			*aggPtrs[i] = &aggs[i]
+		}
+	default:
+		sumAggs(aggPtrs)
+	}
+}
+
+func (s selectorCompatible) AggregatorFor(descriptor *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	s.inner.AggregatorFor(descriptor, aggPtrs...)
+	if len(aggPtrs) == 0 || *aggPtrs[0] == nil {
+		return
+	}
+	kind := (*aggPtrs[0]).Aggregation().Kind()
+	switch {
+	case s.capabilities.SupportsAggregation(kind):
+	case isDistribution(kind) && s.capabilities.SupportsAggregation(aggregation.SumKind):
+		sumAggs(aggPtrs)
+	default:
+		for i := range aggPtrs {
+			*aggPtrs[i] = nil
+		}
+	}
+}
+
+func (s selectorTimeSource) AggregatorFor(descriptor *sdkapi.Descriptor, aggPtrs ...*aggregator.Aggregator) {
+	s.inner.AggregatorFor(descriptor, aggPtrs...)
+	if len(aggPtrs) == 0 {
+		return
+	}
+	lv, ok := (*aggPtrs[0]).(*lastvalue.Aggregator)
+	if !ok {
+		return
+	}
+	aggs := lastvalue.New(len(aggPtrs), lastvalue.WithOrdering(lv.Ordering()), lastvalue.WithTimeSource(s.now))
+	for i := range aggPtrs {
+		*aggPtrs[i] = &aggs[i]
+	}
+}
+
+// isDistribution returns whether kind is an aggregation of the values of
+// `Histogram` instruments that sums can replace.
+func isDistribution(kind aggregation.Kind) bool {
+	switch kind {
+	case aggregation.HistogramKind, aggregation.ExponentialHistogramKind,
+		aggregation.MinMaxSumCountKind, aggregation.TDigestKind, aggregation.DDSketchKind:
+		return true
+	}
+	return false
+}
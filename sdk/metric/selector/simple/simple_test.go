@@ -15,15 +15,22 @@
 package simple_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/sdk/metric/aggregator"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/ddsketch"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/exponential"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/histogram"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/lastvalue"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/minmaxsumcount"
 	"go.opentelemetry.io/otel/sdk/metric/aggregator/sum"
+	"go.opentelemetry.io/otel/sdk/metric/aggregator/tdigest"
 	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/export/aggregation"
 	"go.opentelemetry.io/otel/sdk/metric/metrictest"
 	"go.opentelemetry.io/otel/sdk/metric/number"
 	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
@@ -59,8 +66,85 @@ func TestInexpensiveDistribution(t *testing.T) {
 	testFixedSelectors(t, inex)
 }
 
+func TestMinMaxSumCountDistribution(t *testing.T) {
+	mmsc := simple.NewWithMinMaxSumCountDistribution()
+	require.IsType(t, (*minmaxsumcount.Aggregator)(nil), oneAgg(mmsc, &testHistogramDesc))
+	testFixedSelectors(t, mmsc)
+
+	sums := simple.NewCompatible(mmsc, export.Capabilities{
+		Aggregations: []aggregation.Kind{aggregation.SumKind, aggregation.LastValueKind},
+	})
+	require.IsType(t, (*sum.Aggregator)(nil), oneAgg(sums, &testHistogramDesc))
+}
+
 func TestHistogramDistribution(t *testing.T) {
 	hist := simple.NewWithHistogramDistribution()
 	require.IsType(t, (*histogram.Aggregator)(nil), oneAgg(hist, &testHistogramDesc))
 	testFixedSelectors(t, hist)
 }
+
+func TestExponentialDistribution(t *testing.T) {
+	expo := simple.NewWithExponentialDistribution(exponential.WithMaxSize(20))
+	require.IsType(t, (*exponential.Aggregator)(nil), oneAgg(expo, &testHistogramDesc))
+	testFixedSelectors(t, expo)
+
+	sums := simple.NewCompatible(expo, export.Capabilities{
+		Aggregations: []aggregation.Kind{aggregation.SumKind, aggregation.LastValueKind},
+	})
+	require.IsType(t, (*sum.Aggregator)(nil), oneAgg(sums, &testHistogramDesc))
+}
+
+func TestTDigestDistribution(t *testing.T) {
+	digest := simple.NewWithTDigestDistribution(tdigest.WithCompression(50))
+	require.IsType(t, (*tdigest.Aggregator)(nil), oneAgg(digest, &testHistogramDesc))
+	testFixedSelectors(t, digest)
+
+	sums := simple.NewCompatible(digest, export.Capabilities{
+		Aggregations: []aggregation.Kind{aggregation.SumKind, aggregation.LastValueKind},
+	})
+	require.IsType(t, (*sum.Aggregator)(nil), oneAgg(sums, &testHistogramDesc))
+}
+
+func TestDDSketchDistribution(t *testing.T) {
+	sketch := simple.NewWithDDSketchDistribution(ddsketch.WithRelativeAccuracy(0.02))
+	require.IsType(t, (*ddsketch.Aggregator)(nil), oneAgg(sketch, &testHistogramDesc))
+	testFixedSelectors(t, sketch)
+
+	sums := simple.NewCompatible(sketch, export.Capabilities{
+		Aggregations: []aggregation.Kind{aggregation.SumKind, aggregation.LastValueKind},
+	})
+	require.IsType(t, (*sum.Aggregator)(nil), oneAgg(sums, &testHistogramDesc))
+}
+
+func TestCompatible(t *testing.T) {
+	hist := simple.NewWithHistogramDistribution()
+
+	all := simple.NewCompatible(hist, export.Capabilities{})
+	require.IsType(t, (*histogram.Aggregator)(nil), oneAgg(all, &testHistogramDesc))
+	testFixedSelectors(t, all)
+
+	sums := simple.NewCompatible(hist, export.Capabilities{
+		Aggregations: []aggregation.Kind{aggregation.SumKind, aggregation.LastValueKind},
+	})
+	require.IsType(t, (*sum.Aggregator)(nil), oneAgg(sums, &testHistogramDesc))
+	testFixedSelectors(t, sums)
+
+	onlySums := simple.NewCompatible(hist, export.Capabilities{
+		Aggregations: []aggregation.Kind{aggregation.SumKind},
+	})
+	require.Nil(t, oneAgg(onlySums, &testGaugeObserverDesc))
+	require.IsType(t, (*sum.Aggregator)(nil), oneAgg(onlySums, &testCounterDesc))
+}
+
+func TestTimeSource(t *testing.T) {
+	stamp := time.Unix(1000, 0)
+	sel := simple.NewWithTimeSource(simple.NewWithInexpensiveDistribution(), func() time.Time { return stamp })
+	require.IsType(t, (*sum.Aggregator)(nil), oneAgg(sel, &testHistogramDesc))
+	testFixedSelectors(t, sel)
+
+	agg := oneAgg(sel, &testGaugeObserverDesc)
+	require.NoError(t, agg.Update(context.Background(), number.NewInt64Number(1), &testGaugeObserverDesc))
+	_, ts, err := agg.(*lastvalue.Aggregator).LastValue()
+	require.NoError(t, err)
+	require.Equal(t, stamp, ts)
+}
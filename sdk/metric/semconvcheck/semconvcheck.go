@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package semconvcheck checks the instruments of the SDK against the
+// metric semantic conventions of OpenTelemetry when they are created,
+// warning of instruments such as an http.server.duration Counter
+// measured in seconds.
+//
+// A Checker is installed with the WithInstrumentChecker option of the
+// Accumulator or the basic controller:
+//
+//	cont := controller.New(factory, controller.WithInstrumentChecker(semconvcheck.New()))
+//
+// The instruments that do not follow their convention are created as
+// requested and an error wrapping ErrConvention is reported to the
+// global error handler.  With WithUnitInference, conventional
+// instruments created without a unit are given the unit of their
+// convention instead.
+//
+// This package is currently in a pre-GA phase. Backwards incompatible changes
+// may be introduced in subsequent minor version releases as we work to track the
+// evolving OpenTelemetry specification and user feedback.
+package semconvcheck // import "go.opentelemetry.io/otel/sdk/metric/semconvcheck"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/metric/unit"
+	sdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// ErrConvention is reported for an instrument whose kind or unit differs
+// from its semantic convention.
+var ErrConvention = errors.New("instrument does not follow its semantic convention")
+
+// Convention is the semantic convention of an instrument.
+type Convention struct {
+	// Name is the name of the conventional instrument.
+	Name string
+	// Kinds are the kinds of instrument the convention allows.
+	Kinds []sdkapi.InstrumentKind
+	// Unit is the unit of the conventional instrument.
+	Unit unit.Unit
+}
+
+var (
+	histogram     = []sdkapi.InstrumentKind{sdkapi.HistogramInstrumentKind}
+	counter       = []sdkapi.InstrumentKind{sdkapi.CounterInstrumentKind, sdkapi.CounterObserverInstrumentKind}
+	upDownCounter = []sdkapi.InstrumentKind{sdkapi.UpDownCounterInstrumentKind, sdkapi.UpDownCounterObserverInstrumentKind}
+)
+
+// defaultConventions are the instruments of the HTTP, RPC, database,
+// process and system metric semantic conventions.
+var defaultConventions = []Convention{
+	{"http.server.duration", histogram, unit.Milliseconds},
+	{"http.server.request.size", histogram, unit.Bytes},
+	{"http.server.response.size", histogram, unit.Bytes},
+	{"http.server.active_requests", upDownCounter, "{requests}"},
+	{"http.client.duration", histogram, unit.Milliseconds},
+	{"http.client.request.size", histogram, unit.Bytes},
+	{"http.client.response.size", histogram, unit.Bytes},
+	{"rpc.server.duration", histogram, unit.Milliseconds},
+	{"rpc.client.duration", histogram, unit.Milliseconds},
+	{"db.client.connections.usage", upDownCounter, "{connections}"},
+	{"process.cpu.time", counter, unit.Seconds},
+	{"process.memory.usage", upDownCounter, unit.Bytes},
+	{"system.cpu.time", counter, unit.Seconds},
+	{"system.memory.usage", upDownCounter, unit.Bytes},
+	{"system.network.io", counter, unit.Bytes},
+}
+
+// DefaultConventions returns the conventions checked by a Checker
+// configured without WithConventions.
+func DefaultConventions() []Convention {
+	return append([]Convention(nil), defaultConventions...)
+}
+
+// Checker checks instruments against semantic conventions.
+type Checker struct {
+	conventions map[string]Convention
+	inferUnits  bool
+}
+
+var _ sdk.InstrumentChecker = &Checker{}
+
+// config contains the configuration of a Checker.
+type config struct {
+	conventions []Convention
+	inferUnits  bool
+}
+
+// Option configures a Checker.
+type Option interface {
+	apply(config) config
+}
+
+type optionFunc func(config) config
+
+func (fn optionFunc) apply(cfg config) config {
+	return fn(cfg)
+}
+
+// WithConventions checks instruments against the given conventions in
+// addition to the default ones, replacing the default conventions of the
+// same name.
+func WithConventions(conventions ...Convention) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.conventions = append(cfg.conventions, conventions...)
+		return cfg
+	})
+}
+
+// WithUnitInference gives conventional instruments created without a
+// unit the unit of their convention.
+func WithUnitInference() Option {
+	return optionFunc(func(cfg config) config {
+		cfg.inferUnits = true
+		return cfg
+	})
+}
+
+// New returns a Checker of the default conventions configured with opts.
+func New(opts ...Option) *Checker {
+	cfg := config{
+		conventions: DefaultConventions(),
+	}
+	for _, opt := range opts {
+		cfg = opt.apply(cfg)
+	}
+	c := &Checker{
+		conventions: map[string]Convention{},
+		inferUnits:  cfg.inferUnits,
+	}
+	for _, conv := range cfg.conventions {
+		c.conventions[conv.Name] = conv
+	}
+	return c
+}
+
+// CheckInstrument implements sdk.InstrumentChecker.  It returns an error
+// wrapping ErrConvention for an instrument whose kind or unit differs
+// from its convention.
+func (c *Checker) CheckInstrument(desc sdkapi.Descriptor) (sdkapi.Descriptor, error) {
+	conv, ok := c.conventions[desc.Name()]
+	if !ok {
+		return desc, nil
+	}
+
+	var problems []string
+	if !allowed(conv.Kinds, desc.InstrumentKind()) {
+		kinds := make([]string, len(conv.Kinds))
+		for i, k := range conv.Kinds {
+			kinds[i] = k.String()
+		}
+		problems = append(problems, fmt.Sprintf("is a %v, convention is %s", desc.InstrumentKind(), strings.Join(kinds, " or ")))
+	}
+	switch {
+	case desc.Unit() == conv.Unit:
+	case desc.Unit() == "" && c.inferUnits:
+		desc = sdkapi.NewDescriptorWithAttributeKeys(
+			desc.Name(),
+			desc.InstrumentKind(),
+			desc.NumberKind(),
+			desc.Description(),
+			conv.Unit,
+			desc.AttributeKeys(),
+		)
+	default:
+		problems = append(problems, fmt.Sprintf("has unit %q, convention is %q", desc.Unit(), conv.Unit))
+	}
+	if problems != nil {
+		return desc, fmt.Errorf("%w: %s %s", ErrConvention, desc.Name(), strings.Join(problems, " and "))
+	}
+	return desc, nil
+}
+
+func allowed(kinds []sdkapi.InstrumentKind, kind sdkapi.InstrumentKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semconvcheck_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/unit"
+	sdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/export"
+	"go.opentelemetry.io/otel/sdk/metric/number"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+	"go.opentelemetry.io/otel/sdk/metric/selector/simple"
+	"go.opentelemetry.io/otel/sdk/metric/semconvcheck"
+)
+
+type errorCatcher struct {
+	lock   sync.Mutex
+	errors []error
+}
+
+func (e *errorCatcher) Handle(err error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.errors = append(e.errors, err)
+}
+
+func TestCheckInstrument(t *testing.T) {
+	checker := semconvcheck.New(semconvcheck.WithConventions(semconvcheck.Convention{
+		Name:  "queue.depth",
+		Kinds: []sdkapi.InstrumentKind{sdkapi.GaugeObserverInstrumentKind},
+		Unit:  "{messages}",
+	}))
+
+	for _, tc := range []struct {
+		name  string
+		kind  sdkapi.InstrumentKind
+		unit  unit.Unit
+		error string
+	}{
+		{"http.server.duration", sdkapi.HistogramInstrumentKind, unit.Milliseconds, ""},
+		{"http.server.duration", sdkapi.CounterInstrumentKind, unit.Seconds,
+			`instrument does not follow its semantic convention: http.server.duration is a CounterInstrumentKind, convention is HistogramInstrumentKind and has unit "s", convention is "ms"`},
+		{"http.server.duration", sdkapi.HistogramInstrumentKind, "",
+			`instrument does not follow its semantic convention: http.server.duration has unit "", convention is "ms"`},
+		{"system.cpu.time", sdkapi.CounterObserverInstrumentKind, unit.Seconds, ""},
+		{"system.cpu.time", sdkapi.GaugeObserverInstrumentKind, unit.Seconds,
+			`instrument does not follow its semantic convention: system.cpu.time is a GaugeObserverInstrumentKind, convention is CounterInstrumentKind or CounterObserverInstrumentKind`},
+		{"queue.depth", sdkapi.GaugeObserverInstrumentKind, "{messages}", ""},
+		{"queue.depth", sdkapi.GaugeObserverInstrumentKind, unit.Dimensionless,
+			`instrument does not follow its semantic convention: queue.depth has unit "1", convention is "{messages}"`},
+		{"app.duration", sdkapi.CounterInstrumentKind, unit.Seconds, ""},
+	} {
+		desc := sdkapi.NewDescriptor(tc.name, tc.kind, number.Float64Kind, "", tc.unit)
+		checked, err := checker.CheckInstrument(desc)
+		require.Equal(t, desc, checked)
+		if tc.error == "" {
+			require.NoError(t, err, tc.name)
+			continue
+		}
+		require.ErrorIs(t, err, semconvcheck.ErrConvention)
+		require.EqualError(t, err, tc.error)
+	}
+}
+
+func TestUnitInference(t *testing.T) {
+	checker := semconvcheck.New(semconvcheck.WithUnitInference())
+
+	desc := sdkapi.NewDescriptor("rpc.client.duration", sdkapi.HistogramInstrumentKind, number.Float64Kind, "RPC latency", "")
+	checked, err := checker.CheckInstrument(desc)
+	require.NoError(t, err)
+	require.Equal(t, unit.Milliseconds, checked.Unit())
+	require.Equal(t, "RPC latency", checked.Description())
+
+	// A wrong unit is reported rather than replaced.
+	desc = sdkapi.NewDescriptor("rpc.client.duration", sdkapi.HistogramInstrumentKind, number.Float64Kind, "", unit.Seconds)
+	checked, err = checker.CheckInstrument(desc)
+	require.ErrorIs(t, err, semconvcheck.ErrConvention)
+	require.Equal(t, unit.Seconds, checked.Unit())
+}
+
+// unitProcessor records the units of the instruments it processes.
+type unitProcessor struct {
+	export.AggregatorSelector
+	units map[string]unit.Unit
+}
+
+func (p *unitProcessor) Process(accum export.Accumulation) error {
+	p.units[accum.Descriptor().Name()] = accum.Descriptor().Unit()
+	return nil
+}
+
+func TestAccumulator(t *testing.T) {
+	ctx := context.Background()
+	h := &errorCatcher{}
+	otel.SetErrorHandler(h)
+
+	proc := &unitProcessor{
+		AggregatorSelector: simple.NewWithHistogramDistribution(),
+		units:              map[string]unit.Unit{},
+	}
+	accum := sdk.NewAccumulator(
+		proc,
+		sdk.WithInstrumentChecker(semconvcheck.New(semconvcheck.WithUnitInference())),
+	)
+	meter := sdkapi.WrapMeterImpl(accum)
+
+	counter, err := meter.SyncFloat64().Counter("http.server.duration", instrument.WithUnit(unit.Seconds))
+	require.NoError(t, err)
+	require.Len(t, h.errors, 1)
+	require.ErrorIs(t, h.errors[0], semconvcheck.ErrConvention)
+	counter.Add(ctx, 1)
+
+	histogram, err := meter.SyncInt64().Histogram("http.server.request.size")
+	require.NoError(t, err)
+	require.Len(t, h.errors, 1)
+	histogram.Record(ctx, 100)
+
+	accum.Collect(ctx)
+	require.Equal(t, map[string]unit.Unit{
+		"http.server.duration":     unit.Seconds,
+		"http.server.request.size": unit.Bytes,
+	}, proc.units)
+}
@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+type (
+	// InstrumentUsage reports how often a synchronous instrument
+	// was updated, see WithUsageSampling.
+	InstrumentUsage struct {
+		Descriptor sdkapi.Descriptor
+		// Updates is the number of measurements made with the
+		// instrument.  It is zero for unused instruments.
+		Updates uint64
+		// CallSites are the code locations of the sampled
+		// measurements, the most frequent first.
+		CallSites []CallSite
+	}
+
+	// CallSite is the code location of sampled measurements.
+	CallSite struct {
+		Function string
+		File     string
+		Line     int
+		// Samples is the number of sampled measurements made at
+		// the location.
+		Samples uint64
+	}
+
+	// instrumentUsage counts the measurements of a synchronous
+	// instrument.
+	instrumentUsage struct {
+		// updates is accessed atomically and kept first for
+		// alignment.
+		updates uint64
+		every   uint64
+		inst    *syncInstrument
+
+		lock  sync.Mutex
+		sites map[CallSite]uint64
+	}
+)
+
+// usagePrefixes are the prefixes of the functions of the API and SDK,
+// which are skipped to find the call site of a measurement.
+var usagePrefixes = []string{
+	"go.opentelemetry.io/otel/sdk/metric.",
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi.",
+	"go.opentelemetry.io/otel/sdk/metric/registry.",
+	"go.opentelemetry.io/otel/metric.",
+	"go.opentelemetry.io/otel/metric/",
+	"go.opentelemetry.io/otel/internal/metric/",
+}
+
+// WithUsageSampling enables usage statistics of synchronous instruments,
+// reported by Accumulator.Usage.  Every measurement is counted, and the
+// call site of one in every `every` measurements of an instrument is
+// sampled, so that unused instruments and the hottest call sites can be
+// found to guide the cleanup of instrumentation.  A value of zero, the
+// default, disables the statistics.
+func WithUsageSampling(every int) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.usageSampling = every
+		return cfg
+	})
+}
+
+// trackUsage starts counting the measurements of inst.
+func (m *Accumulator) trackUsage(inst *syncInstrument) {
+	if m.usageSampling <= 0 {
+		return
+	}
+	inst.usage = &instrumentUsage{
+		every: uint64(m.usageSampling),
+		inst:  inst,
+		sites: map[CallSite]uint64{},
+	}
+
+	m.usageLock.Lock()
+	defer m.usageLock.Unlock()
+	m.usage = append(m.usage, inst.usage)
+}
+
+// Usage returns the usage statistics of the synchronous instruments of
+// the Accumulator, the most updated first, or nil unless
+// WithUsageSampling is configured.
+func (m *Accumulator) Usage() []InstrumentUsage {
+	m.usageLock.Lock()
+	tracked := append([]*instrumentUsage(nil), m.usage...)
+	m.usageLock.Unlock()
+
+	var usage []InstrumentUsage
+	for _, u := range tracked {
+		usage = append(usage, u.report())
+	}
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].Updates != usage[j].Updates {
+			return usage[i].Updates > usage[j].Updates
+		}
+		return usage[i].Descriptor.Name() < usage[j].Descriptor.Name()
+	})
+	return usage
+}
+
+// record counts n measurements, sampling the call site when the count
+// reaches a multiple of the sampling interval.
+func (u *instrumentUsage) record(n int) {
+	if n == 0 {
+		return
+	}
+	count := atomic.AddUint64(&u.updates, uint64(n))
+	if (count-uint64(n))/u.every == count/u.every {
+		return
+	}
+	site, ok := callSite()
+	if !ok {
+		return
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.sites[site]++
+}
+
+func (u *instrumentUsage) report() InstrumentUsage {
+	r := InstrumentUsage{
+		Descriptor: u.inst.descriptor,
+		Updates:    atomic.LoadUint64(&u.updates),
+	}
+
+	u.lock.Lock()
+	for site, samples := range u.sites {
+		site.Samples = samples
+		r.CallSites = append(r.CallSites, site)
+	}
+	u.lock.Unlock()
+
+	sort.Slice(r.CallSites, func(i, j int) bool {
+		a, b := r.CallSites[i], r.CallSites[j]
+		if a.Samples != b.Samples {
+			return a.Samples > b.Samples
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return r
+}
+
+// callSite returns the location of the first caller outside of the API
+// and SDK.
+func callSite() (CallSite, bool) {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !internalFrame(frame.Function) {
+			return CallSite{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			}, true
+		}
+		if !more {
+			return CallSite{}, false
+		}
+	}
+}
+
+func internalFrame(function string) bool {
+	for _, prefix := range usagePrefixes {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
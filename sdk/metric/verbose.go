@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric // import "go.opentelemetry.io/otel/sdk/metric"
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/sdk/metric/sdkapi"
+)
+
+// VerbosePrefix is prepended to the name of a synchronous instrument to name
+// its verbose view.  A prefix keeps the name suffix that selectors may use
+// to choose an aggregation.
+const VerbosePrefix = "verbose."
+
+// DefaultVerboseBaggageKey is the conventional baggage member that requests
+// verbose recording, see WithVerboseBaggage.
+const DefaultVerboseBaggageKey = "otel.metrics.verbose"
+
+// WithVerboseBaggage enables the verbose view of synchronous instruments.
+// A measurement made in a context whose baggage has the member key with the
+// value "1" or "true" is recorded by its instrument as usual, and
+// additionally by the verbose view of the instrument.  The verbose view is an
+// instrument of the same kind named with VerbosePrefix, which does not carry
+// the attribute keys advised for the original instrument.  The
+// AggregatorSelector and attribute filters of the export pipeline may use
+// IsVerbose to keep more attributes or select finer aggregations for it,
+// enabling a deep dive into the measurements of selected requests.
+//
+// This is experimental, the verbose view only exists while measurements
+// requesting it are made.
+func WithVerboseBaggage(key string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.verboseKey = key
+		return cfg
+	})
+}
+
+// IsVerbose returns true if desc describes the verbose view of an
+// instrument.
+func IsVerbose(desc *sdkapi.Descriptor) bool {
+	return strings.HasPrefix(desc.Name(), VerbosePrefix)
+}
+
+// verboseDescriptor returns the descriptor of the verbose view of the
+// instrument described by desc.
+func verboseDescriptor(desc sdkapi.Descriptor) sdkapi.Descriptor {
+	return sdkapi.NewDescriptor(
+		VerbosePrefix+desc.Name(),
+		desc.InstrumentKind(),
+		desc.NumberKind(),
+		desc.Description(),
+		desc.Unit(),
+	)
+}
+
+// verbose returns true if the measurements made in ctx are requested to be
+// recorded by the verbose views of their instruments.
+func (m *Accumulator) verbose(ctx context.Context) bool {
+	if m.verboseKey == "" {
+		return false
+	}
+	switch baggage.FromContext(ctx).Member(m.verboseKey).Value() {
+	case "1", "true":
+		return true
+	}
+	return false
+}